@@ -0,0 +1,29 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/teo/aversome/backend/internal/alert"
+	"github.com/teo/aversome/backend/pkg/timeutil"
+)
+
+// alerts evaluates the alert rules after every ingest (its AfterSave hook).
+var alerts *alert.Engine
+
+// GET /api/alerts/:date - Alerts fired for a date and their delivery state
+func handleGetAlerts(c *gin.Context) {
+	date := c.Param("date")
+	if _, err := timeutil.ParseDate(date); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	day, err := alerts.Load(date)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load alerts", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, day)
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/teo/aversome/backend/internal/generation"
+	"github.com/teo/aversome/backend/pkg/timeutil"
+)
+
+// GET /api/carbon/:area/:date - Average and merit-order carbon intensity per
+// interval of the generation mix (?timescale=hourly averages 30min data)
+func handleGetCarbon(c *gin.Context) {
+	a, ok := parseAreaParam(c)
+	if !ok {
+		return
+	}
+	factors, ok := parseEmissionFactors(c)
+	if !ok {
+		return
+	}
+	timescale := c.Query("timescale")
+	if timescale != "" {
+		if err := timeutil.ValidateTimescale(timescale); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	data, err := loadGeneration(a, c.Param("date"))
+	if err != nil {
+		writeLoadError(c, "generation", err)
+		return
+	}
+	gen := &generation.Response{}
+	if err := json.Unmarshal(data, gen); err != nil {
+		writeLoadError(c, "generation", err)
+		return
+	}
+	gen.SetEmissionFactors(factors)
+
+	if timescale != "" {
+		if gen, err = gen.Resample(timescale); err != nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{
+				"error":   fmt.Sprintf("Cannot provide carbon intensity at timescale %s", timescale),
+				"details": err.Error(),
+			})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, gen.CarbonIntensity())
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/teo/aversome/backend/internal/areas"
+	"github.com/teo/aversome/backend/internal/curtailment"
+	"github.com/teo/aversome/backend/internal/generation"
+	"github.com/teo/aversome/backend/internal/storage"
+)
+
+// GET /api/curtailment/:area/:date - Retrieve renewable output curtailment events (出力制御)
+func handleGetCurtailment(c *gin.Context) {
+	a, ok := parseAreaParam(c)
+	if !ok {
+		return
+	}
+
+	data, err := loadCurtailment(a, c.Param("date"))
+	if err != nil {
+		writeLoadError(c, "curtailment", err)
+		return
+	}
+
+	c.Data(http.StatusOK, "application/json", data)
+}
+
+// loadCurtailment returns the stored curtailment document for area/date, fetching it first if missing.
+func loadCurtailment(a areas.Area, date string) ([]byte, error) {
+	return loadOrFetch(storage.DatasetCurtailment, string(a.Code), date, func() error {
+		_, err := pipe.FetchCurtailment(a, date)
+		return err
+	})
+}
+
+// joinCurtailment adds the area's curtailed solar and wind output to a
+// stored generation document (?curtailment=true on the generation endpoint).
+func joinCurtailment(a areas.Area, date string, data []byte) ([]byte, error) {
+	curtailed, err := loadCurtailment(a, date)
+	if err != nil {
+		return nil, err
+	}
+
+	var gen generation.Response
+	if err := json.Unmarshal(data, &gen); err != nil {
+		return nil, err
+	}
+	var resp curtailment.Response
+	if err := json.Unmarshal(curtailed, &resp); err != nil {
+		return nil, err
+	}
+	if err := resp.Join(&gen); err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(&gen)
+}
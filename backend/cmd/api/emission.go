@@ -0,0 +1,64 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/teo/aversome/backend/internal/emission"
+)
+
+// emissionFactors holds the selectable emission factor sets; its default set
+// is used unless a request selects another. Set with EMISSION_FACTORS (JSON
+// data file) and EMISSION_FACTOR_SET (default set ID).
+var (
+	emissionFactors   = emission.DefaultRegistry()
+	emissionFactorSet = emission.DefaultSet()
+)
+
+// openEmissionFactors loads the factor registry and the server's default set.
+func openEmissionFactors() error {
+	registry, err := emission.OpenRegistry(os.Getenv("EMISSION_FACTORS"))
+	if err != nil {
+		return err
+	}
+	id := registry.Default
+	if env := os.Getenv("EMISSION_FACTOR_SET"); env != "" {
+		id = env
+	}
+	set, ok := registry.Lookup(id)
+	if !ok {
+		return fmt.Errorf("unknown emission factor set %q (must be one of %s)", id, strings.Join(registry.IDs(), ", "))
+	}
+
+	emissionFactors, emissionFactorSet = registry, set
+	return nil
+}
+
+// parseEmissionFactors resolves ?factors= to a set of the server's registry,
+// defaulting to the configured set.
+func parseEmissionFactors(c *gin.Context) (*emission.FactorSet, bool) {
+	id := c.Query("factors")
+	if id == "" {
+		return emissionFactorSet, true
+	}
+	if set, ok := emissionFactors.Lookup(id); ok {
+		return set, true
+	}
+
+	c.JSON(http.StatusBadRequest, gin.H{
+		"error": fmt.Sprintf("unknown emission factor set %q (must be one of %s)", id, strings.Join(emissionFactors.IDs(), ", ")),
+	})
+	return nil, false
+}
+
+// GET /api/emission-factors - Selectable emission factor sets
+func handleGetEmissionFactors(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"version": emissionFactors.Version,
+		"default": emissionFactorSet.ID,
+		"sets":    emissionFactors.Sets,
+	})
+}
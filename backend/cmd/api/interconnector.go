@@ -0,0 +1,82 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/teo/aversome/backend/internal/interconnector"
+	"github.com/teo/aversome/backend/internal/storage"
+)
+
+// GET /api/interconnectors - List the interconnector registry
+func handleGetInterconnectors(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"lines": interconnector.Lines()})
+}
+
+// GET /api/interconnectors/:date - Retrieve interconnector flows (連系線潮流)
+// ?line= keeps one line (ID or OCCTO name); ?congested=true keeps only congested intervals
+func handleGetInterconnectorFlows(c *gin.Context) {
+	date := c.Param("date")
+
+	var line *interconnector.Line
+	if s := c.Query("line"); s != "" {
+		l, err := interconnector.ParseLine(s)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		line = &l
+	}
+
+	congestedOnly := false
+	if s := c.Query("congested"); s != "" {
+		v, err := strconv.ParseBool(s)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "congested must be true or false"})
+			return
+		}
+		congestedOnly = v
+	}
+
+	data, err := loadOrFetch(storage.DatasetInterconnector, "", date, func() error {
+		_, err := pipe.FetchInterconnector(date)
+		return err
+	})
+	if err != nil {
+		writeLoadError(c, "interconnector", err)
+		return
+	}
+	if line == nil && !congestedOnly {
+		c.Data(http.StatusOK, "application/json", data)
+		return
+	}
+
+	var resp interconnector.Response
+	if err := json.Unmarshal(data, &resp); err != nil {
+		writeLoadError(c, "interconnector", err)
+		return
+	}
+
+	lines := make([]interconnector.LineFlows, 0, len(resp.Lines))
+	for _, l := range resp.Lines {
+		if line != nil && l.Line != line.ID {
+			continue
+		}
+		if congestedOnly {
+			// Summary fields still describe the whole day
+			series := make([]interconnector.FlowPoint, 0, l.CongestedIntervals)
+			for _, p := range l.Series {
+				if p.Congested {
+					series = append(series, p)
+				}
+			}
+			l.Series = series
+		}
+		lines = append(lines, l)
+	}
+	resp.Lines = lines
+
+	c.JSON(http.StatusOK, resp)
+}
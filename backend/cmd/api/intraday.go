@@ -0,0 +1,91 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/teo/aversome/backend/internal/areas"
+	"github.com/teo/aversome/backend/internal/jepx"
+	"github.com/teo/aversome/backend/internal/storage"
+)
+
+// GET /api/intraday/:date - Retrieve JEPX intraday (時間前市場) results per 30-minute product
+func handleGetIntraday(c *gin.Context) {
+	data, err := loadIntraday(c.Param("date"))
+	if err != nil {
+		writeLoadError(c, "JEPX intraday", err)
+		return
+	}
+
+	c.Data(http.StatusOK, "application/json", data)
+}
+
+// GET /api/intraday/:date/compare?area=system|tokyo|...
+// Intraday weighted average and close against the day-ahead price of the same
+// period (the system price unless an area is given).
+func handleGetIntradayCompare(c *gin.Context) {
+	date := c.Param("date")
+
+	area := jepx.SystemArea
+	if s := c.Query("area"); s != "" && !strings.EqualFold(s, jepx.SystemArea) {
+		a, err := areas.Parse(s)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if !a.HasJEPXPrice() {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("area %s has no JEPX spot price", a.Code)})
+			return
+		}
+		area = string(a.Code)
+	}
+
+	data, err := loadIntraday(date)
+	if err != nil {
+		writeLoadError(c, "JEPX intraday", err)
+		return
+	}
+	var intraday jepx.IntradayResponse
+	if err := json.Unmarshal(data, &intraday); err != nil {
+		writeLoadError(c, "JEPX intraday", err)
+		return
+	}
+
+	data, err = loadOrFetch(storage.DatasetJEPXMarket, "", date, func() error {
+		_, err := pipe.FetchJEPXMarket(date, date, nil)
+		return err
+	})
+	if err != nil {
+		writeLoadError(c, "JEPX market", err)
+		return
+	}
+	var market jepx.MarketResponse
+	if err := json.Unmarshal(data, &market); err != nil {
+		writeLoadError(c, "JEPX market", err)
+		return
+	}
+
+	dayAhead, err := market.AreaResponse(area)
+	if err != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "Failed to compare intraday prices", "details": err.Error()})
+		return
+	}
+	cmp, err := jepx.Compare(&intraday, dayAhead)
+	if err != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "Failed to compare intraday prices", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, cmp)
+}
+
+// loadIntraday returns the stored intraday document for date, fetching it first if missing.
+func loadIntraday(date string) ([]byte, error) {
+	return loadOrFetch(storage.DatasetIntraday, "", date, func() error {
+		_, err := pipe.FetchJEPXIntraday(date)
+		return err
+	})
+}
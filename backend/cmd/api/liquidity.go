@@ -0,0 +1,118 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/teo/aversome/backend/internal/areas"
+	"github.com/teo/aversome/backend/internal/jepx"
+	"github.com/teo/aversome/backend/internal/storage"
+)
+
+// GET /api/liquidity/:area/:date - Per-period JEPX volumes and liquidity metrics
+// (area "system" weights volumes with the system price)
+func handleGetLiquidity(c *gin.Context) {
+	area, ok := parseLiquidityArea(c)
+	if !ok {
+		return
+	}
+	date := c.Param("date")
+
+	var resp *jepx.Response
+	if area == jepx.SystemArea {
+		data, err := loadOrFetch(storage.DatasetJEPXMarket, "", date, func() error {
+			_, err := pipe.FetchJEPXMarket(date, date, nil)
+			return err
+		})
+		if err != nil {
+			writeLoadError(c, "JEPX market", err)
+			return
+		}
+		var market jepx.MarketResponse
+		if err := json.Unmarshal(data, &market); err != nil {
+			writeLoadError(c, "JEPX market", err)
+			return
+		}
+		if resp, err = market.AreaResponse(jepx.SystemArea); err != nil {
+			writeLoadError(c, "JEPX market", err)
+			return
+		}
+	} else {
+		a, _ := areas.Lookup(areas.Code(area)) // Validated by parseLiquidityArea
+		data, err := loadJEPX(a, date)
+		if err != nil {
+			writeLoadError(c, "JEPX", err)
+			return
+		}
+		if err := json.Unmarshal(data, &resp); err != nil {
+			writeLoadError(c, "JEPX", err)
+			return
+		}
+	}
+
+	liq, err := resp.Liquidity()
+	if err != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "No volume data", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, liq)
+}
+
+// GET /api/liquidity/:area?from=&to= - Daily JEPX liquidity over a date range
+func handleGetLiquidityRange(c *gin.Context) {
+	area, ok := parseLiquidityArea(c)
+	if !ok {
+		return
+	}
+
+	var days []*jepx.Response
+	if area == jepx.SystemArea {
+		q, ok := loadRange(c, storage.DatasetJEPXMarket, "")
+		if !ok {
+			return
+		}
+		markets, err := decodeDocs[jepx.MarketResponse](q.Docs)
+		if err != nil {
+			writeRangeError(c, "liquidity", http.StatusInternalServerError, err)
+			return
+		}
+		for _, m := range markets {
+			day, err := m.AreaResponse(jepx.SystemArea)
+			if err != nil {
+				writeRangeError(c, "liquidity", http.StatusInternalServerError, err)
+				return
+			}
+			days = append(days, day)
+		}
+		c.JSON(http.StatusOK, jepx.NewLiquidityRange(area, q.From, q.To, days, q.Gaps))
+		return
+	}
+
+	q, ok := loadRange(c, storage.DatasetJEPX, area)
+	if !ok {
+		return
+	}
+	days, err := decodeDocs[jepx.Response](q.Docs)
+	if err != nil {
+		writeRangeError(c, "liquidity", http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, jepx.NewLiquidityRange(area, q.From, q.To, days, q.Gaps))
+}
+
+// parseLiquidityArea accepts "system" or an area with a JEPX spot price.
+// Writes a 400 response and returns false otherwise.
+func parseLiquidityArea(c *gin.Context) (string, bool) {
+	if strings.EqualFold(c.Param("area"), jepx.SystemArea) {
+		return jepx.SystemArea, true
+	}
+	a, ok := parseJEPXAreaParam(c)
+	if !ok {
+		return "", false
+	}
+	return string(a.Code), true
+}
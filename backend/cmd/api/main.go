@@ -1,19 +1,30 @@
 // Package main provides a lightweight API server for data refresh operations.
 // Usage: go run cmd/api/main.go
 // Endpoint: POST /api/data/refresh
+// Storage: STORAGE_BACKEND=filesystem (default, public/data/jp) or postgres (DATABASE_URL)
 package main
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
-	"os/exec"
-	"path/filepath"
+	"strconv"
 	"time"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
+	"github.com/teo/aversome/backend/internal/alert"
+	"github.com/teo/aversome/backend/internal/areas"
+	"github.com/teo/aversome/backend/internal/demand"
+	"github.com/teo/aversome/backend/internal/generation"
+	"github.com/teo/aversome/backend/internal/jepx"
+	"github.com/teo/aversome/backend/internal/pipeline"
+	"github.com/teo/aversome/backend/internal/reserve"
+	"github.com/teo/aversome/backend/internal/storage"
+	"github.com/teo/aversome/backend/pkg/timeutil"
 )
 
 type RefreshRequest struct {
@@ -28,19 +39,56 @@ type RefreshResponse struct {
 }
 
 type DataFetchResult struct {
-	Source   string `json:"source"`   // e.g. "tokyo-demand", "kansai-jepx"
-	Status   string `json:"status"`   // "success", "error"
+	Source   string `json:"source"` // e.g. "tokyo-demand", "kansai-jepx"
+	Status   string `json:"status"` // "success", "error"
 	FilePath string `json:"file_path,omitempty"`
+	Mode     string `json:"mode,omitempty"`    // "http", "testdata" (HTTP fallback)
+	Warning  string `json:"warning,omitempty"` // Data quality warning
 	Error    string `json:"error,omitempty"`
+	Stage    string `json:"stage,omitempty"` // Pipeline stage that failed
 	Duration string `json:"duration"`
 }
 
+// pipe runs fetch jobs in-process and owns the storage the GET routes read.
+var pipe *pipeline.Pipeline
+
 func main() {
 	// Set Gin to release mode for production
 	if os.Getenv("GIN_MODE") == "" {
 		gin.SetMode(gin.ReleaseMode)
 	}
 
+	// Open storage backend (STORAGE_BACKEND=filesystem|postgres)
+	store, err := storage.Open(storage.LoadConfig())
+	if err != nil {
+		log.Fatalf("Failed to open storage: %v", err)
+	}
+	defer store.Close()
+
+	// Reserve status tiers (RESERVE_POLICY=default|jp-alert|path/to/policy.json)
+	if reservePolicy, err = reserve.OpenPolicy(os.Getenv("RESERVE_POLICY")); err != nil {
+		log.Fatalf("Failed to load reserve policy: %v", err)
+	}
+
+	// Carbon intensity factors (EMISSION_FACTORS=path/to/factors.json, EMISSION_FACTOR_SET=id)
+	if err := openEmissionFactors(); err != nil {
+		log.Fatalf("Failed to load emission factors: %v", err)
+	}
+
+	// Alert rules, webhooks and email (ALERT_CONFIG, ALERT_WEBHOOK_URLS, ALERT_EMAIL_TO, SMTP_*)
+	if alerts, err = alert.OpenEngine(store); err != nil {
+		log.Fatalf("Failed to load alert config: %v", err)
+	}
+
+	// In-process data pipeline (live HTTP with testdata fallback), alerting on every save
+	pipe = pipeline.New(pipeline.Config{
+		UseHTTP:         true,
+		Store:           store,
+		ReservePolicy:   reservePolicy,
+		EmissionFactors: emissionFactorSet,
+		AfterSave:       alerts.AfterSave,
+	})
+
 	router := gin.Default()
 
 	// CORS configuration for frontend
@@ -54,13 +102,23 @@ func main() {
 	config.AllowHeaders = []string{"Origin", "Content-Type"}
 	router.Use(cors.New(config))
 
-	// Health check endpoint
-	router.GET("/api/health", func(c *gin.Context) {
-		c.JSON(http.StatusOK, gin.H{
-			"status": "ok",
-			"time":   time.Now().Format(time.RFC3339),
-		})
-	})
+	// Health check endpoint (includes storage status)
+	router.GET("/api/health", handleHealth)
+
+	// Storage stats
+	router.GET("/api/stats", handleStats)
+
+	// Area registry
+	router.GET("/api/areas", handleGetAreas)
+
+	// Emission factor sets for generation carbon intensity (?factors=)
+	router.GET("/api/emission-factors", handleGetEmissionFactors)
+
+	// Per-interval average and merit-order carbon intensity (?factors=, ?timescale=)
+	router.GET("/api/carbon/:area/:date", handleGetCarbon)
+
+	// Marginal emission factors per hour of day, estimated from 28 days of generation (?factors=)
+	router.GET("/api/marginal/:area/:date", handleGetMarginal)
 
 	// Data refresh endpoint
 	router.POST("/api/data/refresh", handleRefresh)
@@ -70,6 +128,39 @@ func main() {
 	router.GET("/api/jepx/:area/:date", handleGetJEPX)
 	router.GET("/api/reserve/:date", handleGetReserve)
 	router.GET("/api/generation/:area/:date", handleGetGeneration)
+	router.GET("/api/imbalance/:area/:date", handleGetImbalance)
+	router.GET("/api/curtailment/:area/:date", handleGetCurtailment)
+
+	// Date-range endpoints (?from=YYYY-MM-DD&to=YYYY-MM-DD, stored data only)
+	router.GET("/api/demand/:area", handleGetDemandRange)
+	router.GET("/api/jepx/:area", handleGetJEPXRange)
+	router.GET("/api/reserve", handleGetReserveRange)
+	router.GET("/api/generation/:area", handleGetGenerationRange)
+
+	// Market splitting and inter-area spreads
+	router.GET("/api/spreads", handleGetSpreads)
+
+	// JEPX traded volumes and liquidity (area or "system")
+	router.GET("/api/liquidity/:area/:date", handleGetLiquidity)
+	router.GET("/api/liquidity/:area", handleGetLiquidityRange)
+
+	// JEPX intraday market and comparison with the day-ahead price
+	router.GET("/api/intraday/:date", handleGetIntraday)
+	router.GET("/api/intraday/:date/compare", handleGetIntradayCompare)
+
+	// OCCTO reserve outlooks (?horizon=next_day|weekly) and forecast vs actual
+	router.GET("/api/reserve/:date/forecast", handleGetReserveForecast)
+	router.GET("/api/reserve/:date/compare", handleGetReserveCompare)
+
+	// OCCTO interconnector flows (?line=, ?congested=true)
+	router.GET("/api/interconnectors", handleGetInterconnectors)
+	router.GET("/api/interconnectors/:date", handleGetInterconnectorFlows)
+
+	// Supply-tightness and price alerts fired per date
+	router.GET("/api/alerts/:date", handleGetAlerts)
+
+	// Settlement calculation
+	router.POST("/api/settlements/run", handleRunSettlement)
 
 	port := os.Getenv("PORT")
 	if port == "" {
@@ -77,13 +168,48 @@ func main() {
 	}
 
 	log.Printf("🚀 API server starting on http://localhost:%s", port)
+	log.Printf("🗄️  Storage mode: %s", store.Backend())
+	log.Printf("📏 Reserve policy: %s", reservePolicy.ID)
+	log.Printf("🏭 Emission factors: %s (%s)", emissionFactorSet.ID, emissionFactors.Version)
+	log.Printf("🚨 Alerts: %s", alerts.Summary())
 	log.Printf("📊 Data refresh endpoint: POST /api/data/refresh")
+	log.Printf("💴 Settlement endpoint: POST /api/settlements/run")
 
 	if err := router.Run(":" + port); err != nil {
 		log.Fatalf("Failed to start server: %v", err)
 	}
 }
 
+// GET /api/health - Liveness and storage status
+func handleHealth(c *gin.Context) {
+	resp := gin.H{
+		"status":  "ok",
+		"time":    time.Now().Format(time.RFC3339),
+		"storage": pipe.Store().Backend(),
+	}
+
+	// Database-backed stores report connectivity
+	if hc, ok := pipe.Store().(interface{ HealthCheck() error }); ok {
+		resp["database"] = hc.HealthCheck() == nil
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// GET /api/stats - Storage statistics
+func handleStats(c *gin.Context) {
+	count, err := pipe.Store().Count()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count records", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"total_records": count,
+		"storage_type":  pipe.Store().Backend(),
+	})
+}
+
 func handleRefresh(c *gin.Context) {
 	var req RefreshRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -104,19 +230,32 @@ func handleRefresh(c *gin.Context) {
 		req.Areas = []string{"tokyo", "kansai"}
 	}
 
+	requested, err := parseAreaList(req.Areas)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	log.Printf("📥 Refresh request: date=%s, areas=%v", req.Date, req.Areas)
 
 	var results []DataFetchResult
 
 	// Fetch demand data for each area
-	for _, area := range req.Areas {
+	var jepxAreas []areas.Area
+	for _, a := range requested {
 		// Demand data
-		demandResult := fetchDemand(area, req.Date)
+		demandResult := fetchDemand(a, req.Date)
 		results = append(results, demandResult)
 
-		// JEPX data
-		jepxResult := fetchJEPX(area, req.Date)
-		results = append(results, jepxResult)
+		// JEPX data (Okinawa has no spot market)
+		if a.HasJEPXPrice() {
+			jepxAreas = append(jepxAreas, a)
+		}
+	}
+
+	// JEPX data for every area from one CSV download
+	if len(jepxAreas) > 0 {
+		results = append(results, fetchJEPX(jepxAreas, req.Date)...)
 	}
 
 	// Fetch reserve data (system-wide)
@@ -145,257 +284,394 @@ func handleRefresh(c *gin.Context) {
 	}
 }
 
-func fetchDemand(area, date string) DataFetchResult {
+func fetchDemand(a areas.Area, date string) DataFetchResult {
 	start := time.Now()
-	source := fmt.Sprintf("%s-demand", area)
-
-	cmd := exec.Command(
-		"./fetch-demand",
-		"-area", area,
-		"-date", date,
-		"--use-http",
-	)
-
-	output, err := cmd.CombinedOutput()
-	duration := time.Since(start)
-
+	res, err := pipe.FetchDemand(a, date)
 	if err != nil {
-		return DataFetchResult{
-			Source:   source,
-			Status:   "error",
-			Error:    fmt.Sprintf("%v: %s", err, string(output)),
-			Duration: duration.String(),
-		}
-	}
-
-	// Parse output path from command output
-	outputPath := filepath.Join("public", "data", "jp", area, fmt.Sprintf("demand-%s.json", date))
-
-	return DataFetchResult{
-		Source:   source,
-		Status:   "success",
-		FilePath: outputPath,
-		Duration: duration.String(),
+		return newFetchResult(fmt.Sprintf("%s-demand", a.Code), nil, err, start)
 	}
+	return newFetchResult(fmt.Sprintf("%s-demand", a.Code), &res.Result, nil, start)
 }
 
-func fetchJEPX(area, date string) DataFetchResult {
+func fetchJEPX(selected []areas.Area, date string) []DataFetchResult {
 	start := time.Now()
-	source := fmt.Sprintf("%s-jepx", area)
+	res, err := pipe.FetchJEPXRange(date, date, selected, nil)
 
-	cmd := exec.Command(
-		"./fetch-jepx",
-		"-area", area,
-		"-date", date,
-		"--use-http",
-	)
-
-	output, err := cmd.CombinedOutput()
-	duration := time.Since(start)
+	byArea := make(map[string]*pipeline.Result, len(res))
+	for _, r := range res {
+		byArea[r.Area] = &r.Result
+	}
 
-	if err != nil {
-		return DataFetchResult{
-			Source:   source,
-			Status:   "error",
-			Error:    fmt.Sprintf("%v: %s", err, string(output)),
-			Duration: duration.String(),
+	out := make([]DataFetchResult, 0, len(selected))
+	for _, a := range selected {
+		source := fmt.Sprintf("%s-jepx", a.Code)
+		switch r := byArea[string(a.Code)]; {
+		case err != nil:
+			out = append(out, newFetchResult(source, nil, err, start))
+		case r == nil:
+			out = append(out, newFetchResult(source, nil, fmt.Errorf("no JEPX prices for %s on %s", a.Code, date), start))
+		default:
+			out = append(out, newFetchResult(source, r, nil, start))
 		}
 	}
+	return out
+}
 
-	outputPath := filepath.Join("public", "data", "jp", "jepx", fmt.Sprintf("spot-%s-%s.json", area, date))
+func fetchReserve(date string) DataFetchResult {
+	start := time.Now()
+	res, err := pipe.FetchReserve(date)
+	if err != nil {
+		return newFetchResult("reserve", nil, err, start)
+	}
+	return newFetchResult("reserve", &res.Result, nil, start)
+}
 
-	return DataFetchResult{
+// newFetchResult converts a pipeline outcome into a refresh result entry.
+func newFetchResult(source string, res *pipeline.Result, err error, start time.Time) DataFetchResult {
+	out := DataFetchResult{
 		Source:   source,
-		Status:   "success",
-		FilePath: outputPath,
-		Duration: duration.String(),
+		Duration: time.Since(start).String(),
 	}
-}
 
-func fetchReserve(date string) DataFetchResult {
-	start := time.Now()
-	source := "reserve"
+	if err != nil {
+		out.Status = "error"
+		out.Error = err.Error()
+		var pipeErr *pipeline.Error
+		if errors.As(err, &pipeErr) {
+			out.Stage = string(pipeErr.Stage)
+		}
+		return out
+	}
 
-	cmd := exec.Command(
-		"./fetch-reserve",
-		"-date", date,
-	)
+	out.Status = "success"
+	out.FilePath = res.Location
+	out.Mode = string(res.Mode)
+	out.Warning = res.Warning
+	return out
+}
 
-	output, err := cmd.CombinedOutput()
-	duration := time.Since(start)
+// GET /api/areas - List the supply area registry
+func handleGetAreas(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"areas": areas.All()})
+}
 
+// parseAreaParam validates the :area path parameter against the area registry.
+// Writes a 400 response and returns false if the area is unknown.
+func parseAreaParam(c *gin.Context) (areas.Area, bool) {
+	a, err := areas.Parse(c.Param("area"))
 	if err != nil {
-		return DataFetchResult{
-			Source:   source,
-			Status:   "error",
-			Error:    fmt.Sprintf("%v: %s", err, string(output)),
-			Duration: duration.String(),
-		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return areas.Area{}, false
 	}
+	return a, true
+}
 
-	outputPath := filepath.Join("public", "data", "jp", "system", fmt.Sprintf("reserve-%s.json", date))
+// parseJEPXAreaParam is parseAreaParam restricted to areas with a JEPX spot price.
+func parseJEPXAreaParam(c *gin.Context) (areas.Area, bool) {
+	a, ok := parseAreaParam(c)
+	if !ok {
+		return areas.Area{}, false
+	}
+	if !a.HasJEPXPrice() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("area %s has no JEPX spot price", a.Code)})
+		return areas.Area{}, false
+	}
+	return a, true
+}
 
-	return DataFetchResult{
-		Source:   source,
-		Status:   "success",
-		FilePath: outputPath,
-		Duration: duration.String(),
+// parseAreaList validates and normalizes a list of area names from a request body.
+func parseAreaList(names []string) ([]areas.Area, error) {
+	out := make([]areas.Area, 0, len(names))
+	for _, name := range names {
+		a, err := areas.Parse(name)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, a)
 	}
+	return out, nil
 }
 
 // GET /api/demand/:area/:date - Retrieve demand data
 func handleGetDemand(c *gin.Context) {
-	area := c.Param("area")
+	a, ok := parseAreaParam(c)
+	if !ok {
+		return
+	}
 	date := c.Param("date")
 
-	// Validate area
-	if area != "tokyo" && area != "kansai" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid area. Must be 'tokyo' or 'kansai'"})
+	data, err := loadOrFetch(storage.DatasetDemand, string(a.Code), date, func() error {
+		_, err := pipe.FetchDemand(a, date)
+		return err
+	})
+	if err != nil {
+		writeLoadError(c, "demand", err)
 		return
 	}
 
-	// Construct file path
-	filePath := filepath.Join("public", "data", "jp", area, fmt.Sprintf("demand-%s.json", date))
+	writeSeries(c, "demand", data)
+}
+
+// GET /api/jepx/:area/:date - Retrieve JEPX spot price data
+// (area "all": system price plus every area price per period)
+func handleGetJEPX(c *gin.Context) {
+	if c.Param("area") == "all" {
+		handleGetJEPXMarket(c)
+		return
+	}
 
-	// Check if file exists
-	if _, err := os.Stat(filePath); os.IsNotExist(err) {
-		// File doesn't exist - fetch fresh data
-		log.Printf("[GET /api/demand] File not found, fetching fresh data for %s/%s", area, date)
-		result := fetchDemand(area, date)
+	a, ok := parseJEPXAreaParam(c)
+	if !ok {
+		return
+	}
 
-		if result.Status != "success" {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error":   "Failed to fetch demand data",
-				"details": result.Error,
-			})
-			return
-		}
+	data, err := loadJEPX(a, c.Param("date"))
+	if err != nil {
+		writeLoadError(c, "JEPX", err)
+		return
 	}
 
-	// Read and return file
-	data, err := os.ReadFile(filePath)
+	writeSeries(c, "jepx", data)
+}
+
+// GET /api/jepx/all/:date - Retrieve the all-area JEPX market data
+func handleGetJEPXMarket(c *gin.Context) {
+	date := c.Param("date")
+
+	data, err := loadOrFetch(storage.DatasetJEPXMarket, "", date, func() error {
+		_, err := pipe.FetchJEPXMarket(date, date, nil)
+		return err
+	})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read data file"})
+		writeLoadError(c, "JEPX market", err)
 		return
 	}
 
-	c.Data(http.StatusOK, "application/json", data)
+	writeSeries(c, "jepx_market", data)
 }
 
-// GET /api/jepx/:area/:date - Retrieve JEPX spot price data
-func handleGetJEPX(c *gin.Context) {
-	area := c.Param("area")
+// loadJEPX returns the stored JEPX spot document for area/date, fetching it first if missing.
+func loadJEPX(a areas.Area, date string) ([]byte, error) {
+	return loadOrFetch(storage.DatasetJEPX, string(a.Code), date, func() error {
+		_, err := pipe.FetchJEPX(a, date)
+		return err
+	})
+}
+
+// GET /api/reserve/:date - Retrieve reserve margin data (?policy= selects the status tiers)
+func handleGetReserve(c *gin.Context) {
 	date := c.Param("date")
+	policy, ok := parseReservePolicy(c)
+	if !ok {
+		return
+	}
 
-	// Validate area
-	if area != "tokyo" && area != "kansai" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid area. Must be 'tokyo' or 'kansai'"})
+	data, err := loadOrFetch(storage.DatasetReserve, "", date, func() error {
+		_, err := pipe.FetchReserve(date)
+		return err
+	})
+	if err != nil {
+		writeLoadError(c, "reserve", err)
 		return
 	}
 
-	// Construct file path
-	filePath := filepath.Join("public", "data", "jp", "jepx", fmt.Sprintf("spot-%s-%s.json", area, date))
+	var resp reserve.Response
+	if err := json.Unmarshal(data, &resp); err != nil {
+		writeLoadError(c, "reserve", err)
+		return
+	}
+	policy.Apply(&resp)
 
-	// Check if file exists
-	if _, err := os.Stat(filePath); os.IsNotExist(err) {
-		// File doesn't exist - fetch fresh data
-		log.Printf("[GET /api/jepx] File not found, fetching fresh data for %s/%s", area, date)
-		result := fetchJEPX(area, date)
+	c.JSON(http.StatusOK, resp)
+}
 
-		if result.Status != "success" {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error":   "Failed to fetch JEPX data",
-				"details": result.Error,
-			})
-			return
-		}
+// GET /api/imbalance/:area/:date - Retrieve imbalance prices (インバランス料金)
+func handleGetImbalance(c *gin.Context) {
+	a, ok := parseAreaParam(c)
+	if !ok {
+		return
 	}
 
-	// Read and return file
-	data, err := os.ReadFile(filePath)
+	data, err := loadImbalance(a, c.Param("date"))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read data file"})
+		writeLoadError(c, "imbalance", err)
 		return
 	}
 
 	c.Data(http.StatusOK, "application/json", data)
 }
 
-// GET /api/reserve/:date - Retrieve reserve margin data
-func handleGetReserve(c *gin.Context) {
-	date := c.Param("date")
+// loadImbalance returns the stored imbalance price document for area/date, fetching it first if missing.
+func loadImbalance(a areas.Area, date string) ([]byte, error) {
+	return loadOrFetch(storage.DatasetImbalance, string(a.Code), date, func() error {
+		_, err := pipe.FetchImbalance(a, date)
+		return err
+	})
+}
 
-	// Construct file path
-	filePath := filepath.Join("public", "data", "jp", "system", fmt.Sprintf("reserve-%s.json", date))
+// loadGeneration returns the stored generation mix for area/date, estimating
+// it first (and fetching its demand and JEPX inputs) if missing.
+func loadGeneration(a areas.Area, date string) ([]byte, error) {
+	return loadOrFetch(storage.DatasetGeneration, string(a.Code), date, func() error {
+		// Estimate from demand + JEPX data
+		_, err := pipe.EstimateGeneration(a, date)
+		if errors.Is(err, storage.ErrNotFound) {
+			// Inputs missing - fetch them, then estimate again
+			if _, err := pipe.FetchDemand(a, date); err != nil {
+				return err
+			}
+			if _, err := pipe.FetchJEPX(a, date); err != nil {
+				return err
+			}
+			_, err = pipe.EstimateGeneration(a, date)
+		}
+		return err
+	})
+}
 
-	// Check if file exists
-	if _, err := os.Stat(filePath); os.IsNotExist(err) {
-		// File doesn't exist - fetch fresh data
-		log.Printf("[GET /api/reserve] File not found, fetching fresh data for %s", date)
-		result := fetchReserve(date)
+// GET /api/generation/:area/:date - Retrieve estimated generation mix data
+// (?curtailment=true adds the curtailed solar and wind output, ?factors=
+// selects the emission factors of the carbon intensity)
+func handleGetGeneration(c *gin.Context) {
+	a, ok := parseAreaParam(c)
+	if !ok {
+		return
+	}
+	date := c.Param("date")
+	factors, ok := parseEmissionFactors(c)
+	if !ok {
+		return
+	}
 
-		if result.Status != "success" {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error":   "Failed to fetch reserve data",
-				"details": result.Error,
-			})
+	withCurtailment := false
+	if s := c.Query("curtailment"); s != "" {
+		v, err := strconv.ParseBool(s)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "curtailment must be true or false"})
 			return
 		}
+		withCurtailment = v
 	}
 
-	// Read and return file
-	data, err := os.ReadFile(filePath)
+	data, err := loadGeneration(a, date)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read data file"})
+		writeLoadError(c, "generation", err)
 		return
 	}
 
-	c.Data(http.StatusOK, "application/json", data)
-}
-
-// GET /api/generation/:area/:date - Retrieve estimated generation mix data
-func handleGetGeneration(c *gin.Context) {
-	area := c.Param("area")
-	date := c.Param("date")
+	if withCurtailment {
+		if data, err = joinCurtailment(a, date, data); err != nil {
+			writeLoadError(c, "curtailment", err)
+			return
+		}
+	}
 
-	// Validate area
-	if area != "tokyo" && area != "kansai" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid area. Must be 'tokyo' or 'kansai'"})
+	var resp generation.Response
+	if err := json.Unmarshal(data, &resp); err != nil {
+		writeLoadError(c, "generation", err)
 		return
 	}
+	resp.SetEmissionFactors(factors)
 
-	// Construct file path
-	filePath := filepath.Join("public", "data", "jp", area, fmt.Sprintf("generation-%s.json", date))
+	timescale := c.Query("timescale")
+	if timescale == "" {
+		c.JSON(http.StatusOK, &resp)
+		return
+	}
+	if err := timeutil.ValidateTimescale(timescale); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	out, err := resp.Resample(timescale)
+	if err != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error":   fmt.Sprintf("Cannot provide generation data at timescale %s", timescale),
+			"details": err.Error(),
+		})
+		return
+	}
 
-	// Check if file exists
-	if _, err := os.Stat(filePath); os.IsNotExist(err) {
-		// File doesn't exist - estimate from demand + JEPX data
-		log.Printf("[GET /api/generation] File not found, estimating generation mix for %s/%s", area, date)
+	c.JSON(http.StatusOK, out)
+}
 
-		cmd := exec.Command(
-			"./estimate-generation",
-			"-area", area,
-			"-date", date,
-		)
+// loadOrFetch returns the stored document, running fetch first if none is stored.
+func loadOrFetch(dataset storage.Dataset, area, date string, fetch func() error) ([]byte, error) {
+	// Validate date before it is used in a storage key
+	if _, err := timeutil.ParseDate(date); err != nil {
+		return nil, &pipeline.Error{Stage: pipeline.StageValidate, Dataset: dataset, Area: area, Date: date, Err: err}
+	}
 
-		output, err := cmd.CombinedOutput()
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error":   "Failed to estimate generation mix",
-				"details": fmt.Sprintf("%v: %s", err, string(output)),
-			})
-			return
+	data, err := pipe.Store().Load(dataset, area, date)
+	if errors.Is(err, storage.ErrNotFound) {
+		log.Printf("[GET /api/%s] Data not found, fetching fresh data for %s %s", dataset, area, date)
+		if err := fetch(); err != nil {
+			return nil, err
 		}
+		data, err = pipe.Store().Load(dataset, area, date)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// writeLoadError reports a loadOrFetch failure: 400 for invalid input, 500 otherwise.
+func writeLoadError(c *gin.Context, what string, err error) {
+	status := http.StatusInternalServerError
+	var pipeErr *pipeline.Error
+	if errors.As(err, &pipeErr) && pipeErr.Stage == pipeline.StageValidate {
+		status = http.StatusBadRequest
+	}
+
+	c.JSON(status, gin.H{
+		"error":   fmt.Sprintf("Failed to load %s data", what),
+		"details": err.Error(),
+	})
+}
 
-		log.Printf("[GET /api/generation] Successfully estimated generation mix for %s/%s", area, date)
+// writeSeries writes a stored demand/jepx/jepx_market document, resampled
+// when the request carries ?timescale=hourly|30min. Without the parameter the
+// document is returned at its stored (native) resolution.
+func writeSeries(c *gin.Context, kind string, data []byte) {
+	timescale := c.Query("timescale")
+	if timescale == "" {
+		c.Data(http.StatusOK, "application/json", data)
+		return
+	}
+	if err := timeutil.ValidateTimescale(timescale); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
 	}
 
-	// Read and return file
-	data, err := os.ReadFile(filePath)
+	var out any
+	var err error
+	switch kind {
+	case "demand":
+		var resp demand.Response
+		if err = json.Unmarshal(data, &resp); err == nil {
+			out, err = resp.Resample(demand.Timescale(timescale))
+		}
+	case "jepx":
+		var resp jepx.Response
+		if err = json.Unmarshal(data, &resp); err == nil {
+			out, err = resp.Resample(timescale)
+		}
+	case "jepx_market":
+		var resp jepx.MarketResponse
+		if err = json.Unmarshal(data, &resp); err == nil {
+			out, err = resp.Resample(timescale)
+		}
+	default:
+		err = fmt.Errorf("unsupported data type %q", kind)
+	}
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read data file"})
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error":   fmt.Sprintf("Cannot provide %s data at timescale %s", kind, timescale),
+			"details": err.Error(),
+		})
 		return
 	}
 
-	c.Data(http.StatusOK, "application/json", data)
+	c.JSON(http.StatusOK, out)
 }
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/teo/aversome/backend/internal/areas"
+	"github.com/teo/aversome/backend/internal/emission"
+	"github.com/teo/aversome/backend/internal/marginal"
+	"github.com/teo/aversome/backend/internal/storage"
+)
+
+// GET /api/marginal/:area/:date - Marginal emission factors per hour of day
+// with confidence bounds, estimated from the generation mix history
+// (?factors= selects the emission factors)
+func handleGetMarginal(c *gin.Context) {
+	a, ok := parseAreaParam(c)
+	if !ok {
+		return
+	}
+	factors, ok := parseEmissionFactors(c)
+	if !ok {
+		return
+	}
+
+	resp, err := loadMarginal(a, c.Param("date"), factors)
+	if err != nil {
+		writeLoadError(c, "marginal emission", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// loadMarginal returns the stored marginal emission factors for area/date
+// under factors, estimating them first if missing. The date's generation mix
+// is loaded (or estimated) first so the window is never empty. A stored
+// estimate is re-estimated when it was made under another factor set (only
+// the server's default set is stored) or generation mixes were stored in its
+// window since.
+func loadMarginal(a areas.Area, date string, factors *emission.FactorSet) (*marginal.Response, error) {
+	area := string(a.Code)
+	estimate := func() (*marginal.Response, error) {
+		if _, err := loadGeneration(a, date); err != nil {
+			return nil, err
+		}
+		res, err := pipe.EstimateMarginal(a, date, factors)
+		if err != nil {
+			return nil, err
+		}
+		return res.Response, nil
+	}
+
+	if factors.ID != emissionFactorSet.ID {
+		return estimate()
+	}
+
+	data, err := loadOrFetch(storage.DatasetMarginal, area, date, func() error {
+		_, err := estimate()
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var resp marginal.Response
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, fmt.Errorf("parse %s %s %s JSON: %w", storage.DatasetMarginal, area, date, err)
+	}
+
+	if resp.EmissionFactorSet != factors.ID {
+		log.Printf("[GET /api/marginal] Estimate for %s %s used factors %s, re-estimating", area, date, resp.EmissionFactorSet)
+		return estimate()
+	}
+	stale, err := pipe.MarginalStale(&resp)
+	if err != nil {
+		return nil, err
+	}
+	if !stale {
+		return &resp, nil
+	}
+	log.Printf("[GET /api/marginal] Estimate for %s %s used %d days, re-estimating", area, date, resp.DaysUsed)
+	return estimate()
+}
@@ -0,0 +1,191 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/teo/aversome/backend/internal/demand"
+	"github.com/teo/aversome/backend/internal/generation"
+	"github.com/teo/aversome/backend/internal/jepx"
+	"github.com/teo/aversome/backend/internal/reserve"
+	"github.com/teo/aversome/backend/internal/series"
+	"github.com/teo/aversome/backend/internal/storage"
+	"github.com/teo/aversome/backend/pkg/timeutil"
+)
+
+// rangeQuery is a validated ?from=&to= range and its stored documents.
+type rangeQuery struct {
+	From      string
+	To        string
+	Timescale string
+	Docs      []storage.Document
+	Gaps      []series.Gap
+}
+
+// loadRange validates ?from=&to=(&timescale=) and loads the stored documents.
+// Range routes only read storage; days that were never fetched are reported as gaps.
+// Writes an error response and returns false on failure.
+func loadRange(c *gin.Context, dataset storage.Dataset, area string) (*rangeQuery, bool) {
+	q := &rangeQuery{From: c.Query("from"), To: c.Query("to"), Timescale: c.Query("timescale")}
+	if q.From == "" || q.To == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "from and to query parameters are required (YYYY-MM-DD)"})
+		return nil, false
+	}
+
+	dates, err := series.Dates(q.From, q.To)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid date range", "details": err.Error()})
+		return nil, false
+	}
+	if q.Timescale != "" {
+		if err := timeutil.ValidateTimescale(q.Timescale); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return nil, false
+		}
+	}
+
+	q.Docs, err = pipe.Store().LoadRange(dataset, area, q.From, q.To)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   fmt.Sprintf("Failed to load %s data", dataset),
+			"details": err.Error(),
+		})
+		return nil, false
+	}
+
+	have := make(map[string]bool, len(q.Docs))
+	for _, doc := range q.Docs {
+		have[doc.Date] = true
+	}
+	q.Gaps = series.FindGaps(dates, have)
+
+	return q, true
+}
+
+// decodeDocs unmarshals every document of a range into T.
+func decodeDocs[T any](docs []storage.Document) ([]*T, error) {
+	out := make([]*T, 0, len(docs))
+	for _, doc := range docs {
+		v := new(T)
+		if err := json.Unmarshal(doc.Data, v); err != nil {
+			return nil, fmt.Errorf("%s: %w", doc.Date, err)
+		}
+		out = append(out, v)
+	}
+	return out, nil
+}
+
+// writeRangeError reports a failure to build a range response:
+// 500 for corrupt documents, 422 when a day cannot be resampled.
+func writeRangeError(c *gin.Context, what string, status int, err error) {
+	c.JSON(status, gin.H{
+		"error":   fmt.Sprintf("Failed to build %s range", what),
+		"details": err.Error(),
+	})
+}
+
+// GET /api/demand/:area?from=&to= - Demand series over a date range
+func handleGetDemandRange(c *gin.Context) {
+	a, ok := parseAreaParam(c)
+	if !ok {
+		return
+	}
+	q, ok := loadRange(c, storage.DatasetDemand, string(a.Code))
+	if !ok {
+		return
+	}
+
+	days, err := decodeDocs[demand.Response](q.Docs)
+	if err != nil {
+		writeRangeError(c, "demand", http.StatusInternalServerError, err)
+		return
+	}
+	resp, err := demand.NewRangeResponse(demand.Area(a.Code), q.From, q.To, days, q.Gaps, demand.Timescale(q.Timescale))
+	if err != nil {
+		writeRangeError(c, "demand", http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// GET /api/jepx/:area?from=&to= - JEPX spot prices over a date range
+func handleGetJEPXRange(c *gin.Context) {
+	a, ok := parseJEPXAreaParam(c)
+	if !ok {
+		return
+	}
+	q, ok := loadRange(c, storage.DatasetJEPX, string(a.Code))
+	if !ok {
+		return
+	}
+
+	days, err := decodeDocs[jepx.Response](q.Docs)
+	if err != nil {
+		writeRangeError(c, "JEPX", http.StatusInternalServerError, err)
+		return
+	}
+	resp, err := jepx.NewRangeResponse(string(a.Code), q.From, q.To, days, q.Gaps, q.Timescale)
+	if err != nil {
+		writeRangeError(c, "JEPX", http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// GET /api/reserve?from=&to= - Reserve margins over a date range (?policy= selects the status tiers)
+func handleGetReserveRange(c *gin.Context) {
+	policy, ok := parseReservePolicy(c)
+	if !ok {
+		return
+	}
+	q, ok := loadRange(c, storage.DatasetReserve, "")
+	if !ok {
+		return
+	}
+
+	days, err := decodeDocs[reserve.Response](q.Docs)
+	if err != nil {
+		writeRangeError(c, "reserve", http.StatusInternalServerError, err)
+		return
+	}
+	for _, d := range days {
+		policy.Apply(d)
+	}
+
+	resp := reserve.NewRangeResponse(q.From, q.To, days, q.Gaps)
+	resp.Policy = policy
+	c.JSON(http.StatusOK, resp)
+}
+
+// GET /api/generation/:area?from=&to= - Generation mix over a date range (?factors= selects the emission factors)
+func handleGetGenerationRange(c *gin.Context) {
+	a, ok := parseAreaParam(c)
+	if !ok {
+		return
+	}
+	factors, ok := parseEmissionFactors(c)
+	if !ok {
+		return
+	}
+	q, ok := loadRange(c, storage.DatasetGeneration, string(a.Code))
+	if !ok {
+		return
+	}
+
+	days, err := decodeDocs[generation.Response](q.Docs)
+	if err != nil {
+		writeRangeError(c, "generation", http.StatusInternalServerError, err)
+		return
+	}
+	resp, err := generation.NewRangeResponse(string(a.Code), q.From, q.To, days, q.Gaps, q.Timescale, factors)
+	if err != nil {
+		writeRangeError(c, "generation", http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/teo/aversome/backend/internal/pipeline"
+	"github.com/teo/aversome/backend/internal/reserve"
+	"github.com/teo/aversome/backend/internal/storage"
+	"github.com/teo/aversome/backend/pkg/timeutil"
+)
+
+// reservePolicy classifies reserve margins unless a request selects another
+// policy. Set with RESERVE_POLICY (built-in ID or JSON file).
+var reservePolicy = reserve.DefaultPolicy()
+
+// parseReservePolicy resolves ?policy= to a built-in policy or the server's
+// configured one, defaulting to the latter. Files are never read per request.
+func parseReservePolicy(c *gin.Context) (*reserve.Policy, bool) {
+	id := c.Query("policy")
+	if id == "" || id == reservePolicy.ID {
+		return reservePolicy, true
+	}
+	if p, ok := reserve.LookupPolicy(id); ok {
+		return p, true
+	}
+
+	ids := reserve.PolicyIDs()
+	if _, builtin := reserve.LookupPolicy(reservePolicy.ID); !builtin {
+		ids = append(ids, reservePolicy.ID)
+	}
+	c.JSON(http.StatusBadRequest, gin.H{
+		"error": fmt.Sprintf("unknown reserve policy %q (must be one of %s)", id, strings.Join(ids, ", ")),
+	})
+	return nil, false
+}
+
+// GET /api/reserve/:date/forecast?horizon=next_day|weekly - OCCTO reserve outlook for a target date
+func handleGetReserveForecast(c *gin.Context) {
+	date := c.Param("date")
+	horizon, ok := parseHorizon(c)
+	if !ok {
+		return
+	}
+	policy, ok := parseReservePolicy(c)
+	if !ok {
+		return
+	}
+
+	f, err := loadReserveForecast(horizon, date)
+	if err != nil {
+		writeLoadError(c, "reserve forecast", err)
+		return
+	}
+	policy.Apply(&f.Response)
+
+	c.JSON(http.StatusOK, f)
+}
+
+// GET /api/reserve/:date/compare?horizon=next_day|weekly
+// Forecast against actual margin per area: tightest interval and, for the
+// next-day outlook, every interval. 404 until the actuals are available.
+func handleGetReserveCompare(c *gin.Context) {
+	date := c.Param("date")
+	horizon, ok := parseHorizon(c)
+	if !ok {
+		return
+	}
+	policy, ok := parseReservePolicy(c)
+	if !ok {
+		return
+	}
+
+	f, err := loadReserveForecast(horizon, date)
+	if err != nil {
+		writeLoadError(c, "reserve forecast", err)
+		return
+	}
+
+	data, err := loadOrFetch(storage.DatasetReserve, "", date, func() error {
+		_, err := pipe.FetchReserve(date)
+		return err
+	})
+	var pipeErr *pipeline.Error
+	if errors.As(err, &pipeErr) && (pipeErr.Stage == pipeline.StageFetch || pipeErr.Stage == pipeline.StageParse) {
+		c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("No actual reserve data for %s yet", date), "details": err.Error()})
+		return
+	}
+	if err != nil {
+		writeLoadError(c, "reserve", err)
+		return
+	}
+	var actual reserve.Response
+	if err := json.Unmarshal(data, &actual); err != nil {
+		writeLoadError(c, "reserve", err)
+		return
+	}
+
+	policy.Apply(&f.Response)
+	policy.Apply(&actual)
+
+	cmp, err := reserve.Compare(f, &actual)
+	if err != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "Failed to compare reserve forecast", "details": err.Error()})
+		return
+	}
+	if cmp.Meta == nil {
+		cmp.Meta = &reserve.Meta{}
+	}
+	cmp.Meta.Policy = policy
+
+	c.JSON(http.StatusOK, cmp)
+}
+
+// parseHorizon reads ?horizon=, defaulting to the next-day outlook.
+func parseHorizon(c *gin.Context) (reserve.Horizon, bool) {
+	s := c.Query("horizon")
+	if s == "" {
+		return reserve.HorizonNextDay, true
+	}
+	h, err := reserve.ParseHorizon(s)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return "", false
+	}
+	return h, true
+}
+
+// loadReserveForecast returns the stored forecast of a horizon for a target
+// date, fetching it first if missing. A missing weekly forecast is fetched
+// from the outlook published the day before.
+func loadReserveForecast(h reserve.Horizon, date string) (*reserve.Forecast, error) {
+	var data []byte
+	var err error
+
+	switch h {
+	case reserve.HorizonWeekly:
+		data, err = loadOrFetch(storage.DatasetReserveWeekly, "", date, func() error {
+			target, _ := timeutil.ParseDate(date)
+			issued := timeutil.FormatDate(target.AddDate(0, 0, -1))
+			results, err := pipe.FetchReserveWeekly(issued)
+			if err != nil {
+				return err
+			}
+			for _, res := range results {
+				if res.Date == date {
+					return nil
+				}
+			}
+			return &pipeline.Error{Stage: pipeline.StageParse, Dataset: storage.DatasetReserveWeekly, Date: issued,
+				Err: fmt.Errorf("weekly outlook does not cover %s", date)}
+		})
+	default:
+		data, err = loadOrFetch(storage.DatasetReserveNextDay, "", date, func() error {
+			_, err := pipe.FetchReserveNextDay(date)
+			return err
+		})
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var f reserve.Forecast
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("failed to decode stored %s forecast: %w", h, err)
+	}
+	return &f, nil
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/teo/aversome/backend/internal/areas"
+	"github.com/teo/aversome/backend/internal/imbalance"
+	"github.com/teo/aversome/backend/internal/jepx"
+	"github.com/teo/aversome/backend/internal/settlement"
+)
+
+// POST /api/settlements/run - Calculate settlement cost for a consumption profile.
+// Prices are loaded through the same loader as GET /api/jepx ("mode": "imbalance":
+// GET /api/imbalance, charging actual − planned).
+func handleRunSettlement(c *gin.Context) {
+	var req settlement.Request
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	// Validate request
+	if err := req.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid settlement request",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	// Validate and normalize area
+	a, err := areas.Parse(req.Prices.Area)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	req.Prices.Area = string(a.Code)
+
+	if req.Mode == settlement.ModeImbalance {
+		runImbalanceSettlement(c, a, &req)
+		return
+	}
+	if !a.HasJEPXPrice() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("area %s has no JEPX spot price", a.Code)})
+		return
+	}
+
+	log.Printf("💴 Settlement request: area=%s, date=%s, points=%d", req.Prices.Area, req.Prices.Date, len(req.Profile))
+
+	// Load JEPX prices
+	data, err := loadJEPX(a, req.Prices.Date)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to load JEPX prices",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	var prices jepx.Response
+	if err := json.Unmarshal(data, &prices); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to parse JEPX data"})
+		return
+	}
+
+	// Match price resolution to the profile (e.g., hourly profile against 30-min prices)
+	pricesResp := &prices
+	if ts := settlement.ProfileTimescale(req.Profile); ts != pricesResp.Timescale {
+		pricesResp, err = pricesResp.Resample(ts)
+		if err != nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{
+				"error":   fmt.Sprintf("Profile is %s but JEPX prices are %s", ts, prices.Timescale),
+				"details": err.Error(),
+			})
+			return
+		}
+	}
+
+	// Calculate settlement
+	resp, err := settlement.Calculate(&req, pricesResp.PriceYenPerKwh, pricesResp.Source)
+	if err != nil {
+		writeSettlementError(c, "JEPX", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// runImbalanceSettlement charges actual − planned at the imbalance prices of the area.
+func runImbalanceSettlement(c *gin.Context, a areas.Area, req *settlement.Request) {
+	log.Printf("💴 Imbalance settlement request: area=%s, date=%s, points=%d", req.Prices.Area, req.Prices.Date, len(req.Profile))
+
+	// Load imbalance prices
+	data, err := loadImbalance(a, req.Prices.Date)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to load imbalance prices",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	var prices imbalance.Response
+	if err := json.Unmarshal(data, &prices); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to parse imbalance data"})
+		return
+	}
+
+	resp, err := settlement.CalculateImbalance(req, prices.Prices, prices.Source)
+	if err != nil {
+		writeSettlementError(c, "imbalance", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// writeSettlementError reports a calculation failure: 422 with the
+// timestamps that have no price, 400 otherwise.
+func writeSettlementError(c *gin.Context, prices string, err error) {
+	var missingErr *settlement.MissingPriceError
+	if errors.As(err, &missingErr) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error":              fmt.Sprintf("No %s price for some profile timestamps", prices),
+			"area":               missingErr.Area,
+			"date":               missingErr.Date,
+			"missing_timestamps": missingErr.Timestamps,
+		})
+		return
+	}
+	c.JSON(http.StatusBadRequest, gin.H{
+		"error":   "Settlement calculation failed",
+		"details": err.Error(),
+	})
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/teo/aversome/backend/internal/areas"
+	"github.com/teo/aversome/backend/internal/jepx"
+	"github.com/teo/aversome/backend/internal/series"
+	"github.com/teo/aversome/backend/internal/spread"
+	"github.com/teo/aversome/backend/internal/storage"
+)
+
+// gapRetryAfter is how long a day the JEPX CSV did not have is reported as a
+// gap before the CSV is downloaded again for it.
+const gapRetryAfter = time.Hour
+
+// unfilledGaps remembers when days were last looked for in the JEPX CSV, so
+// gaps it cannot fill do not download the whole file on every request.
+var unfilledGaps = struct {
+	sync.Mutex
+	tried map[string]time.Time
+}{tried: make(map[string]time.Time)}
+
+// GET /api/spreads?from=&to=&pair=tokyo,kansai&tolerance=0.005&periods=true
+// Market splitting and inter-area spread analytics from the all-area JEPX data.
+// Missing days are fetched with one download of the JEPX CSV; days still
+// missing are reported as gaps and not looked for again for gapRetryAfter.
+func handleGetSpreads(c *gin.Context) {
+	opts := spread.Options{IncludePeriods: c.Query("periods") == "true"}
+	if s := c.Query("tolerance"); s != "" {
+		tol, err := strconv.ParseFloat(s, 64)
+		if err != nil || tol < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("invalid tolerance %q", s)})
+			return
+		}
+		opts.Tolerance = tol
+	}
+	if s := c.Query("pair"); s != "" {
+		pair, err := parsePair(s)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		opts.Pair = pair
+	}
+
+	q, ok := loadRange(c, storage.DatasetJEPXMarket, "")
+	if !ok {
+		return
+	}
+
+	// Fill missing days from a single download, then reload
+	if missing := gapsToFetch(q.Gaps); len(missing) > 0 {
+		want := make(map[string]bool, len(missing))
+		for _, d := range missing {
+			want[d] = true
+		}
+		_, err := pipe.FetchJEPXMarket(missing[0], missing[len(missing)-1], func(date string) bool {
+			return want[date]
+		})
+		if err != nil {
+			log.Printf("[GET /api/spreads] Failed to fetch missing market data: %v", err)
+		}
+		if q, ok = loadRange(c, storage.DatasetJEPXMarket, ""); !ok {
+			return
+		}
+	}
+
+	days, err := decodeDocs[jepx.MarketResponse](q.Docs)
+	if err != nil {
+		writeRangeError(c, "spread", http.StatusInternalServerError, err)
+		return
+	}
+
+	// Analyze at a common timescale
+	timescales := make([]string, len(days))
+	for i, d := range days {
+		timescales[i] = d.Timescale
+	}
+	target := series.CommonTimescale(q.Timescale, timescales)
+	for i, d := range days {
+		if days[i], err = d.Resample(target); err != nil {
+			writeRangeError(c, "spread", http.StatusUnprocessableEntity, err)
+			return
+		}
+	}
+
+	resp, err := spread.Analyze(q.From, q.To, days, q.Gaps, opts)
+	if err != nil {
+		writeRangeError(c, "spread", http.StatusUnprocessableEntity, err)
+		return
+	}
+	if resp.Timescale == "" {
+		resp.Timescale = target
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// gapsToFetch returns the dates of gaps, ascending, that were not looked for
+// in the JEPX CSV within gapRetryAfter, and records them as looked for now.
+func gapsToFetch(gaps []series.Gap) []string {
+	now := time.Now()
+
+	unfilledGaps.Lock()
+	defer unfilledGaps.Unlock()
+
+	var dates []string
+	for _, g := range gaps {
+		days, _ := series.Dates(g.From, g.To)
+		for _, d := range days {
+			if tried, ok := unfilledGaps.tried[d]; ok && now.Sub(tried) < gapRetryAfter {
+				continue
+			}
+			unfilledGaps.tried[d] = now
+			dates = append(dates, d)
+		}
+	}
+
+	// Forget expired attempts so the map stays small
+	for d, tried := range unfilledGaps.tried {
+		if now.Sub(tried) >= gapRetryAfter {
+			delete(unfilledGaps.tried, d)
+		}
+	}
+
+	sort.Strings(dates)
+	return dates
+}
+
+// parsePair parses "tokyo,kansai" (either side may be "system").
+func parsePair(s string) ([2]string, error) {
+	parts := strings.Split(s, ",")
+	if len(parts) != 2 {
+		return [2]string{}, fmt.Errorf("pair must be two areas separated by a comma, got %q", s)
+	}
+
+	var pair [2]string
+	for i, name := range parts {
+		name = strings.TrimSpace(name)
+		if strings.EqualFold(name, jepx.SystemArea) {
+			pair[i] = jepx.SystemArea
+			continue
+		}
+		a, err := areas.Parse(name)
+		if err != nil {
+			return [2]string{}, err
+		}
+		if !a.HasJEPXPrice() {
+			return [2]string{}, fmt.Errorf("area %s has no JEPX spot price", a.Code)
+		}
+		pair[i] = string(a.Code)
+	}
+	return pair, nil
+}
@@ -0,0 +1,149 @@
+// Package main backfills stored data over a date range.
+// Usage: go run cmd/backfill/main.go -from 2025-11-01 -to 2025-11-30 -datasets demand,jepx,reserve -areas tokyo,kansai
+// Only days that are missing from storage or carry a data quality warning are fetched.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/teo/aversome/backend/internal/alert"
+	"github.com/teo/aversome/backend/internal/areas"
+	"github.com/teo/aversome/backend/internal/pipeline"
+	"github.com/teo/aversome/backend/internal/storage"
+	"github.com/teo/aversome/backend/pkg/logger"
+)
+
+func main() {
+	var from, to, datasetList, areaList string
+	var concurrency int
+	var refetch, dryRun, verbose, jsonLog bool
+
+	flag.StringVar(&from, "from", "", "First date in YYYY-MM-DD format (required)")
+	flag.StringVar(&to, "to", "", "Last date in YYYY-MM-DD format (defaults to -from)")
+	flag.StringVar(&datasetList, "datasets", "demand,jepx,reserve", "Comma-separated datasets (demand, jepx, jepx_market, reserve, generation)")
+	flag.StringVar(&areaList, "areas", "tokyo,kansai", "Comma-separated areas, or \"all\"")
+	flag.IntVar(&concurrency, "concurrency", pipeline.DefaultBackfillConcurrency, "Maximum concurrent fetch jobs")
+	flag.BoolVar(&refetch, "refetch", false, "Also refetch days that are already stored")
+	flag.BoolVar(&dryRun, "dry-run", false, "Only report what would be fetched")
+	flag.BoolVar(&verbose, "v", false, "List skipped items in the report")
+	flag.BoolVar(&jsonLog, "json-log", false, "Enable JSON structured logging")
+	flag.Parse()
+
+	if from == "" {
+		log.Fatalf("-from is required")
+	}
+	if to == "" {
+		to = from
+	}
+
+	datasets, err := parseDatasets(datasetList)
+	if err != nil {
+		log.Fatalf("Invalid datasets: %v", err)
+	}
+	selected, err := areas.ParseList(areaList)
+	if err != nil {
+		log.Fatalf("Invalid areas: %v", err)
+	}
+
+	// Open storage (STORAGE_BACKEND)
+	store, err := storage.Open(storage.LoadConfig())
+	if err != nil {
+		log.Fatalf("Failed to open storage: %v", err)
+	}
+	defer store.Close()
+
+	// Alert rules and delivery (ALERT_CONFIG, ALERT_WEBHOOK_URLS, ALERT_EMAIL_TO, SMTP_*)
+	alerts, err := alert.OpenEngine(store)
+	if err != nil {
+		log.Fatalf("Failed to load alert config: %v", err)
+	}
+
+	p := pipeline.New(pipeline.Config{
+		UseHTTP:    true, // Always live: testdata mode would store sample data as real days
+		NoFallback: true, // Never backfill sample data into real days
+		Store:      store,
+		Logger:     logger.New(jsonLog),
+		AfterSave:  alerts.AfterSave,
+	})
+
+	log.Printf("🔄 Backfilling %s → %s (%s; %s; storage: %s)", from, to, datasetList, areaList, store.Backend())
+
+	report, err := p.Backfill(pipeline.BackfillConfig{
+		From:        from,
+		To:          to,
+		Datasets:    datasets,
+		Areas:       selected,
+		Concurrency: concurrency,
+		Refetch:     refetch,
+		DryRun:      dryRun,
+	})
+	if err != nil {
+		log.Fatalf("Backfill failed: %v", err)
+	}
+
+	printReport(report, dryRun, verbose)
+
+	if report.Failed > 0 {
+		os.Exit(1)
+	}
+}
+
+// parseDatasets splits a comma-separated dataset list.
+func parseDatasets(list string) ([]storage.Dataset, error) {
+	var out []storage.Dataset
+	for _, name := range strings.Split(list, ",") {
+		name = strings.TrimSpace(strings.ToLower(name))
+		if name == "" {
+			continue
+		}
+		out = append(out, storage.Dataset(name))
+	}
+	if len(out) == 0 {
+		return nil, fmt.Errorf("no datasets in %q", list)
+	}
+	return out, nil
+}
+
+// printReport writes the per-item table and the totals.
+func printReport(report *pipeline.BackfillReport, dryRun, verbose bool) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "STATUS\tDATASET\tAREA\tDATE\tREASON\tDETAILS")
+
+	for _, item := range report.Items {
+		if item.Status == pipeline.BackfillSkipped && !verbose {
+			continue
+		}
+
+		area := item.Area
+		if area == "" {
+			area = "-"
+		}
+
+		var details string
+		switch {
+		case item.Err != nil:
+			details = item.Err.Error()
+		case item.Warning != "":
+			details = fmt.Sprintf("%s; warning: %s", item.Mode, item.Warning)
+		default:
+			details = string(item.Mode)
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", item.Status, item.Dataset, area, item.Date, item.Reason, details)
+	}
+	w.Flush()
+
+	fmt.Println()
+	if dryRun {
+		fmt.Printf("📋 Dry run: %d to fetch, %d already stored\n", report.Planned, report.Skipped)
+		return
+	}
+	fmt.Printf("📊 Backfill complete in %s: %d filled, %d skipped, %d failed\n",
+		report.Duration.Round(time.Millisecond), report.Filled, report.Skipped, report.Failed)
+}
@@ -4,23 +4,20 @@
 package main
 
 import (
-	"encoding/json"
 	"flag"
-	"fmt"
 	"log"
-	"os"
-	"path/filepath"
 	"time"
 
-	"github.com/teo/aversome/backend/internal/demand"
-	"github.com/teo/aversome/backend/internal/generation"
-	"github.com/teo/aversome/backend/internal/jepx"
+	"github.com/teo/aversome/backend/internal/alert"
+	"github.com/teo/aversome/backend/internal/areas"
+	"github.com/teo/aversome/backend/internal/pipeline"
+	"github.com/teo/aversome/backend/internal/storage"
 	"github.com/teo/aversome/backend/pkg/timeutil"
 )
 
 func main() {
 	var area, date, outputPath string
-	flag.StringVar(&area, "area", "tokyo", "Area: tokyo or kansai")
+	flag.StringVar(&area, "area", "tokyo", "Area code (hokkaido, tohoku, tokyo, chubu, hokuriku, kansai, chugoku, shikoku, kyushu, okinawa)")
 	flag.StringVar(&date, "date", "", "Date in YYYY-MM-DD format (defaults to today)")
 	flag.StringVar(&outputPath, "output", "", "Output file path (defaults to public/data/jp/{area}/generation-{date}.json)")
 	flag.Parse()
@@ -30,84 +27,39 @@ func main() {
 		date = timeutil.FormatDate(time.Now())
 	}
 
-	// Validate date format
-	parsedDate, err := timeutil.ParseDate(date)
+	// Validate and normalize area
+	areaInfo, err := areas.Parse(area)
 	if err != nil {
-		log.Fatalf("Invalid date format: %v", err)
+		log.Fatalf("Invalid area: %v", err)
 	}
 
-	// Validate area
-	if area != "tokyo" && area != "kansai" {
-		log.Fatalf("Invalid area: %s (must be tokyo or kansai)", area)
-	}
-
-	log.Printf("Estimating generation mix for %s/%s...", area, date)
+	log.Printf("Estimating generation mix for %s/%s...", areaInfo.Code, date)
 
-	// Load demand data
-	demandPath := filepath.Join("public", "data", "jp", area, fmt.Sprintf("demand-%s.json", date))
-	demandData, err := os.ReadFile(demandPath)
+	// Open storage (STORAGE_BACKEND); -output writes a single file instead
+	store, err := storage.OpenOutput(storage.LoadConfig(), outputPath)
 	if err != nil {
-		log.Fatalf("Failed to read demand data: %v (file: %s)", err, demandPath)
+		log.Fatalf("Failed to open storage: %v", err)
 	}
+	defer store.Close()
 
-	var demandResp demand.Response
-	if err := json.Unmarshal(demandData, &demandResp); err != nil {
-		log.Fatalf("Failed to parse demand JSON: %v", err)
-	}
-
-	log.Printf("✓ Loaded demand data: %d points", len(demandResp.Series))
-
-	// Load JEPX price data
-	jepxPath := filepath.Join("public", "data", "jp", "jepx", fmt.Sprintf("spot-%s-%s.json", area, date))
-	jepxData, err := os.ReadFile(jepxPath)
+	// Alert rules and delivery (ALERT_CONFIG, ALERT_WEBHOOK_URLS, ALERT_EMAIL_TO, SMTP_*)
+	alerts, err := alert.OpenEngine(store)
 	if err != nil {
-		log.Fatalf("Failed to read JEPX data: %v (file: %s)", err, jepxPath)
+		log.Fatalf("Failed to load alert config: %v", err)
 	}
 
-	var jepxResp jepx.Response
-	if err := json.Unmarshal(jepxData, &jepxResp); err != nil {
-		log.Fatalf("Failed to parse JEPX JSON: %v", err)
-	}
-
-	log.Printf("✓ Loaded JEPX data: %d price points", len(jepxResp.PriceYenPerKwh))
-
-	// Estimate generation mix
-	estimator := generation.NewEstimator()
-	genResp, err := estimator.EstimateFromDemandAndPrice(&demandResp, &jepxResp)
+	p := pipeline.New(pipeline.Config{Store: store, AfterSave: alerts.AfterSave})
+	res, err := p.EstimateGeneration(areaInfo, date)
 	if err != nil {
 		log.Fatalf("Failed to estimate generation mix: %v", err)
 	}
 
-	// Apply seasonal adjustment
-	genResp = estimator.EstimateWithSeasonalAdjustment(genResp, parsedDate)
-
-	log.Printf("✓ Estimated generation mix: %d points", len(genResp.Series))
-	if genResp.Meta != nil {
-		log.Printf("  Renewable penetration: %.1f%%", genResp.Meta.AvgRenewablePct)
-		log.Printf("  Carbon intensity: %.1f gCO2/kWh", genResp.Meta.AvgCarbonGCO2KWh)
-		log.Printf("  Peak solar: %.1f MW", genResp.Meta.PeakSolarMW)
-	}
-
-	// Determine output path
-	if outputPath == "" {
-		outputPath = filepath.Join("public", "data", "jp", area, fmt.Sprintf("generation-%s.json", date))
-	}
-
-	// Ensure output directory exists
-	outputDir := filepath.Dir(outputPath)
-	if err := os.MkdirAll(outputDir, 0755); err != nil {
-		log.Fatalf("Failed to create output directory: %v", err)
-	}
-
-	// Write JSON output
-	jsonData, err := json.MarshalIndent(genResp, "", "  ")
-	if err != nil {
-		log.Fatalf("Failed to marshal JSON: %v", err)
-	}
-
-	if err := os.WriteFile(outputPath, jsonData, 0644); err != nil {
-		log.Fatalf("Failed to write output file: %v", err)
+	log.Printf("✓ Estimated generation mix: %d points", res.Points)
+	if meta := res.Response.Meta; meta != nil {
+		log.Printf("  Renewable penetration: %.1f%%", meta.AvgRenewablePct)
+		log.Printf("  Carbon intensity: %.1f gCO2/kWh", meta.AvgCarbonGCO2KWh)
+		log.Printf("  Peak solar: %.1f MW", meta.PeakSolarMW)
 	}
 
-	log.Printf("✓ Successfully wrote estimated generation mix to %s", outputPath)
+	log.Printf("✓ Successfully wrote estimated generation mix to %s", res.Location)
 }
@@ -4,24 +4,21 @@
 package main
 
 import (
-	"encoding/json"
 	"flag"
-	"fmt"
-	"io"
 	"log"
-	"os"
-	"path/filepath"
 	"time"
 
-	"github.com/teo/aversome/backend/internal/adapters"
-	pkghttp "github.com/teo/aversome/backend/pkg/http"
+	"github.com/teo/aversome/backend/internal/alert"
+	"github.com/teo/aversome/backend/internal/areas"
+	"github.com/teo/aversome/backend/internal/pipeline"
+	"github.com/teo/aversome/backend/internal/storage"
 	"github.com/teo/aversome/backend/pkg/timeutil"
 )
 
 func main() {
 	var area, date, outputPath string
 	var useHTTP bool
-	flag.StringVar(&area, "area", "tokyo", "Area: tokyo or kansai")
+	flag.StringVar(&area, "area", "tokyo", "Area code (hokkaido, tohoku, tokyo, chubu, hokuriku, kansai, chugoku, shikoku, kyushu, okinawa)")
 	flag.StringVar(&date, "date", "", "Date in YYYY-MM-DD format (defaults to today)")
 	flag.StringVar(&outputPath, "output", "", "Output file path (defaults to public/data/jp/{area}/generation-{date}.json)")
 	flag.BoolVar(&useHTTP, "use-http", false, "Use real HTTP fetching (default: testdata)")
@@ -32,87 +29,39 @@ func main() {
 		date = timeutil.FormatDate(time.Now())
 	}
 
-	// Validate date format
-	parsedDate, err := timeutil.ParseDate(date)
+	// Validate and normalize area
+	areaInfo, err := areas.Parse(area)
 	if err != nil {
-		log.Fatalf("Invalid date format: %v", err)
+		log.Fatalf("Invalid area: %v", err)
 	}
 
-	// Validate area
-	if area != "tokyo" && area != "kansai" {
-		log.Fatalf("Invalid area: %s (must be tokyo or kansai)", area)
-	}
-
-	log.Printf("Fetching OCCTO generation mix data for %s/%s (HTTP: %v)...", area, date, useHTTP)
-
-	// Get data source
-	var reader io.ReadCloser
-	var sourceName string
-
-	if useHTTP {
-		// HTTP fetch from OCCTO public API
-		fetcher := pkghttp.NewFetcher(pkghttp.BrowserConfig())
+	log.Printf("Fetching OCCTO generation mix data for %s/%s (HTTP: %v)...", areaInfo.Code, date, useHTTP)
 
-		// OCCTO CSV download URL
-		// jhSybt=03: 電源種別供給力 (generation capacity by fuel type)
-		// Format: ?jhSybt=03&tgtYmdFrom=YYYY/MM/DD&tgtYmdTo=YYYY/MM/DD
-		dateFormatted := parsedDate.Format("2006/01/02") // YYYY/MM/DD
-		url := fmt.Sprintf(
-			"https://web-kohyo.occto.or.jp/kks-web-public/download/downloadCsv?jhSybt=03&tgtYmdFrom=%s&tgtYmdTo=%s",
-			dateFormatted, dateFormatted,
-		)
-		sourceName = "OCCTO"
-
-		log.Printf("Attempting HTTP fetch from %s", url)
-
-		data, err := fetcher.Fetch(url)
-		if err != nil {
-			log.Fatalf("HTTP fetch failed: %v", err)
-		}
-
-		log.Printf("✓ HTTP fetch successful")
-		reader = data
-		defer reader.Close()
-	} else {
-		log.Fatalf("Testdata mode not implemented for generation mix (use --use-http)")
-	}
-
-	// Parse CSV using OCCTO adapter
-	adapter := adapters.NewOCCTOAdapter()
-	resp, err := adapter.ParseGenerationMixCSV(reader, date, area)
+	// Open storage (STORAGE_BACKEND); -output writes a single file instead
+	store, err := storage.OpenOutput(storage.LoadConfig(), outputPath)
 	if err != nil {
-		log.Fatalf("Failed to parse OCCTO CSV: %v", err)
-	}
-
-	resp.Source.Name = sourceName
-
-	log.Printf("Parsed %d generation points", len(resp.Series))
-	if resp.Meta != nil {
-		log.Printf("Renewable penetration: %.1f%%", resp.Meta.AvgRenewablePct)
-		log.Printf("Carbon intensity: %.1f gCO2/kWh", resp.Meta.AvgCarbonGCO2KWh)
-		log.Printf("Peak solar: %.1f MW", resp.Meta.PeakSolarMW)
+		log.Fatalf("Failed to open storage: %v", err)
 	}
+	defer store.Close()
 
-	// Determine output path
-	if outputPath == "" {
-		outputPath = filepath.Join("public", "data", "jp", area, fmt.Sprintf("generation-%s.json", date))
-	}
-
-	// Ensure output directory exists
-	outputDir := filepath.Dir(outputPath)
-	if err := os.MkdirAll(outputDir, 0755); err != nil {
-		log.Fatalf("Failed to create output directory: %v", err)
+	// Alert rules and delivery (ALERT_CONFIG, ALERT_WEBHOOK_URLS, ALERT_EMAIL_TO, SMTP_*)
+	alerts, err := alert.OpenEngine(store)
+	if err != nil {
+		log.Fatalf("Failed to load alert config: %v", err)
 	}
 
-	// Write JSON output
-	jsonData, err := json.MarshalIndent(resp, "", "  ")
+	p := pipeline.New(pipeline.Config{UseHTTP: useHTTP, Store: store, AfterSave: alerts.AfterSave})
+	res, err := p.FetchGeneration(areaInfo, date)
 	if err != nil {
-		log.Fatalf("Failed to marshal JSON: %v", err)
+		log.Fatalf("Failed to fetch generation mix: %v", err)
 	}
 
-	if err := os.WriteFile(outputPath, jsonData, 0644); err != nil {
-		log.Fatalf("Failed to write output file: %v", err)
+	log.Printf("Parsed %d generation points", res.Points)
+	if meta := res.Response.Meta; meta != nil {
+		log.Printf("Renewable penetration: %.1f%%", meta.AvgRenewablePct)
+		log.Printf("Carbon intensity: %.1f gCO2/kWh", meta.AvgCarbonGCO2KWh)
+		log.Printf("Peak solar: %.1f MW", meta.PeakSolarMW)
 	}
 
-	log.Printf("✓ Successfully wrote generation mix data to %s", outputPath)
+	log.Printf("✓ Successfully wrote generation mix data to %s", res.Location)
 }
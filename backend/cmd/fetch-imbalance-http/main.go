@@ -0,0 +1,73 @@
+// Package main provides HTTP-based imbalance price (インバランス料金) fetching with fallback to testdata.
+// Usage: go run main.go -area tokyo -date 2025-10-23 --use-http
+// Output: /public/data/jp/{area}/imbalance-YYYY-MM-DD.json
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/teo/aversome/backend/internal/alert"
+	"github.com/teo/aversome/backend/internal/areas"
+	"github.com/teo/aversome/backend/internal/pipeline"
+	"github.com/teo/aversome/backend/internal/storage"
+	"github.com/teo/aversome/backend/pkg/logger"
+	"github.com/teo/aversome/backend/pkg/timeutil"
+)
+
+func main() {
+	var date, area, outputPath string
+	var useHTTP, jsonLog bool
+
+	flag.StringVar(&date, "date", "", "Date in YYYY-MM-DD format (defaults to today)")
+	flag.StringVar(&area, "area", "tokyo", "Area code (hokkaido through okinawa)")
+	flag.StringVar(&outputPath, "output", "", "Output file path (defaults to public/data/jp/{area}/imbalance-{date}.json)")
+	flag.BoolVar(&useHTTP, "use-http", false, "Use real HTTP fetching (default: testdata)")
+	flag.BoolVar(&jsonLog, "json-log", false, "Enable JSON structured logging")
+	flag.Parse()
+
+	// Initialize logger
+	lgr := logger.New(jsonLog)
+
+	// Default to today if no date provided
+	if date == "" {
+		date = timeutil.FormatDate(time.Now())
+	}
+
+	// Validate and normalize area
+	areaInfo, err := areas.Parse(area)
+	if err != nil {
+		log.Fatalf("Invalid area: %v", err)
+	}
+
+	// Open storage (STORAGE_BACKEND); -output writes a single file instead
+	store, err := storage.OpenOutput(storage.LoadConfig(), outputPath)
+	if err != nil {
+		log.Fatalf("Failed to open storage: %v", err)
+	}
+	defer store.Close()
+
+	// Alert rules and delivery (ALERT_CONFIG, ALERT_WEBHOOK_URLS, ALERT_EMAIL_TO, SMTP_*)
+	alerts, err := alert.OpenEngine(store)
+	if err != nil {
+		log.Fatalf("Failed to load alert config: %v", err)
+	}
+
+	p := pipeline.New(pipeline.Config{UseHTTP: useHTTP, Store: store, Logger: lgr, AfterSave: alerts.AfterSave})
+
+	lgr.Info(fmt.Sprintf("Fetching imbalance prices for %s area on %s (HTTP: %v)", areaInfo.Code, date, useHTTP))
+
+	res, err := p.FetchImbalance(areaInfo, date)
+	if err != nil {
+		log.Fatalf("Failed to fetch imbalance prices: %v", err)
+	}
+
+	lgr.Info(fmt.Sprintf("Parsed %d imbalance prices", res.Points))
+	if res.Warning != "" {
+		lgr.Info(fmt.Sprintf("Warning: %s", res.Warning))
+	}
+
+	log.Printf("✓ Successfully wrote %s", res.Location)
+}
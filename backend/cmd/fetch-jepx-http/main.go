@@ -1,30 +1,31 @@
 // Package main provides HTTP-based JEPX spot price data fetching with fallback to testdata.
 // Usage: go run main.go -area tokyo -date 2025-10-24 --use-http
+// Range: go run main.go -area all -from 2025-11-01 -to 2025-11-30 --use-http
 // Output: /public/data/jp/jepx/spot-{area}-YYYY-MM-DD.json
 package main
 
 import (
-	"encoding/json"
 	"flag"
 	"fmt"
-	"io"
 	"log"
-	"os"
-	"path/filepath"
 	"time"
 
-	"github.com/teo/aversome/backend/internal/adapters"
-	pkghttp "github.com/teo/aversome/backend/pkg/http"
+	"github.com/teo/aversome/backend/internal/alert"
+	"github.com/teo/aversome/backend/internal/areas"
+	"github.com/teo/aversome/backend/internal/pipeline"
+	"github.com/teo/aversome/backend/internal/storage"
 	"github.com/teo/aversome/backend/pkg/logger"
 	"github.com/teo/aversome/backend/pkg/timeutil"
 )
 
 func main() {
-	var date, area, outputPath string
+	var date, from, to, area, outputPath string
 	var useHTTP, jsonLog bool
 
 	flag.StringVar(&date, "date", "", "Date in YYYY-MM-DD format (defaults to today)")
-	flag.StringVar(&area, "area", "tokyo", "Area: tokyo or kansai")
+	flag.StringVar(&from, "from", "", "First date of a range (one download covers every date and area)")
+	flag.StringVar(&to, "to", "", "Last date of a range (defaults to -from)")
+	flag.StringVar(&area, "area", "tokyo", "Area code (any JEPX area: hokkaido through kyushu), comma-separated list, or \"all\"")
 	flag.StringVar(&outputPath, "output", "", "Output file path (defaults to public/data/jp/jepx/spot-{area}-{date}.json)")
 	flag.BoolVar(&useHTTP, "use-http", false, "Use real HTTP fetching (default: testdata)")
 	flag.BoolVar(&jsonLog, "json-log", false, "Enable JSON structured logging")
@@ -38,121 +39,78 @@ func main() {
 		date = timeutil.FormatDate(time.Now())
 	}
 
-	// Validate date format
-	if _, err := timeutil.ParseDate(date); err != nil {
-		log.Fatalf("Invalid date format: %v", err)
+	// Validate and normalize areas; JEPX has no Okinawa price
+	listed, err := areas.ParseList(area)
+	if err != nil {
+		log.Fatalf("Invalid area: %v", err)
 	}
-
-	// Validate area
-	if area != "tokyo" && area != "kansai" {
-		log.Fatalf("Invalid area: %s (must be 'tokyo' or 'kansai')", area)
+	var selected []areas.Area
+	for _, a := range listed {
+		if !a.HasJEPXPrice() {
+			lgr.Info(fmt.Sprintf("Skipping %s: no JEPX spot price", a.Code))
+			continue
+		}
+		selected = append(selected, a)
+	}
+	if len(selected) == 0 {
+		log.Fatalf("Invalid area: no JEPX area in %q", area)
 	}
 
-	lgr.Info(fmt.Sprintf("Fetching JEPX spot prices for %s area on %s (HTTP: %v)", area, date, useHTTP))
-
-	// Get data source
-	var reader io.ReadCloser
-	var sourceName string
-	var fetchDuration time.Duration
-
-	if useHTTP {
-		// Attempt HTTP fetch
-		start := time.Now()
-
-		fetcher := pkghttp.NewFetcher(pkghttp.DefaultConfig())
-
-		// JEPX official site doesn't provide direct CSV downloads
-		// Using third-party source: japanesepower.org (includes historical data)
-		// Official: https://www.jepx.jp/ (web interface only)
-		// Third-party: https://japanesepower.org/jepxSpot.csv (all dates, all regions)
-		url := "https://japanesepower.org/jepxSpot.csv"
-		sourceName = "JEPX (japanesepower.org)"
+	// Open storage (STORAGE_BACKEND); -output writes a single file instead
+	multi := from != "" || len(selected) > 1
+	if multi && outputPath != "" {
+		log.Fatalf("-output cannot be used with -from/-to or several areas")
+	}
+	store, err := storage.OpenOutput(storage.LoadConfig(), outputPath)
+	if err != nil {
+		log.Fatalf("Failed to open storage: %v", err)
+	}
+	defer store.Close()
 
-		lgr.Info(fmt.Sprintf("Attempting HTTP fetch from %s", url))
+	// Alert rules and delivery (ALERT_CONFIG, ALERT_WEBHOOK_URLS, ALERT_EMAIL_TO, SMTP_*)
+	alerts, err := alert.OpenEngine(store)
+	if err != nil {
+		log.Fatalf("Failed to load alert config: %v", err)
+	}
 
-		data, err := fetcher.Fetch(url)
-		fetchDuration = time.Since(start)
+	p := pipeline.New(pipeline.Config{UseHTTP: useHTTP, Store: store, Logger: lgr, AfterSave: alerts.AfterSave})
 
-		if err != nil {
-			lgr.LogFetch(sourceName, "failure", "", "HTTP fetch failed, falling back to testdata", fetchDuration, err)
-			useHTTP = false // Fallback to testdata
-		} else {
-			lgr.LogFetch(sourceName, "success", "", "HTTP fetch successful", fetchDuration, nil)
-			reader = data
-			defer reader.Close()
+	if multi {
+		if from == "" {
+			from = date
+		}
+		if to == "" {
+			to = from
 		}
-	}
-
-	// Fallback to testdata if HTTP failed or not requested
-	if !useHTTP {
-		csvPath := filepath.Join("internal", "adapters", "testdata", "jepx-sample.csv")
-		sourceName = "JEPX (testdata)"
 
-		lgr.Info(fmt.Sprintf("Using testdata: %s", csvPath))
+		lgr.Info(fmt.Sprintf("Fetching JEPX spot prices for %d area(s) from %s to %s (HTTP: %v)", len(selected), from, to, useHTTP))
 
-		f, err := os.Open(csvPath)
+		results, err := p.FetchJEPXRange(from, to, selected, nil)
 		if err != nil {
-			log.Fatalf("Failed to open testdata CSV: %v", err)
+			log.Fatalf("Failed to fetch JEPX data: %v", err)
+		}
+		for _, res := range results {
+			if res.Warning != "" {
+				lgr.Info(fmt.Sprintf("Warning (%s %s): %s", res.Area, res.Date, res.Warning))
+			}
 		}
-		defer f.Close()
-		reader = f
-	}
-
-	// Parse CSV using JEPX adapter
-	parseStart := time.Now()
-
-	adapter := adapters.NewJEPXAdapter()
-	resp, err := adapter.ParseCSV(reader, date, area)
-
-	parseDuration := time.Since(parseStart)
 
-	if err != nil {
-		lgr.LogFetch(sourceName, "failure", "", "CSV parsing failed", parseDuration, err)
-		log.Fatalf("Failed to parse JEPX CSV: %v", err)
+		log.Printf("✓ Successfully wrote %d area-days from one download", len(results))
+		return
 	}
 
-	lgr.Info(fmt.Sprintf("Parsed %d hourly price points", len(resp.PriceYenPerKwh)))
-	if resp.Meta != nil && resp.Meta.Warning != "" {
-		lgr.Info(fmt.Sprintf("Warning: %s", resp.Meta.Warning))
-	}
+	areaInfo := selected[0]
+	lgr.Info(fmt.Sprintf("Fetching JEPX spot prices for %s area on %s (HTTP: %v)", areaInfo.Code, date, useHTTP))
 
-	// Marshal to JSON with indentation
-	jsonData, err := json.MarshalIndent(resp, "", "  ")
+	res, err := p.FetchJEPX(areaInfo, date)
 	if err != nil {
-		log.Fatalf("Failed to marshal JSON: %v", err)
+		log.Fatalf("Failed to fetch JEPX data: %v", err)
 	}
 
-	// Determine output path
-	if outputPath == "" {
-		// Default path
-		outputDir := filepath.Join("public", "data", "jp", "jepx")
-		outputPath = filepath.Join(outputDir, fmt.Sprintf("spot-%s-%s.json", area, date))
+	lgr.Info(fmt.Sprintf("Parsed %d price points (%s)", res.Points, res.Response.Timescale))
+	if res.Warning != "" {
+		lgr.Info(fmt.Sprintf("Warning: %s", res.Warning))
 	}
 
-	// Create output directory
-	outputDir := filepath.Dir(outputPath)
-	if err := os.MkdirAll(outputDir, 0755); err != nil {
-		log.Fatalf("Failed to create output directory: %v", err)
-	}
-
-	// Write JSON file
-	if err := os.WriteFile(outputPath, jsonData, 0644); err != nil {
-		log.Fatalf("Failed to write JSON file: %v", err)
-	}
-
-	mode := "testdata"
-	if useHTTP {
-		mode = "HTTP"
-	}
-
-	lgr.LogFetch(
-		sourceName,
-		"success",
-		outputPath,
-		fmt.Sprintf("Successfully wrote JEPX spot price data (%s mode)", mode),
-		fetchDuration+parseDuration,
-		nil,
-	)
-
-	log.Printf("✓ Successfully wrote %s (%d bytes)", outputPath, len(jsonData))
+	log.Printf("✓ Successfully wrote %s", res.Location)
 }
@@ -0,0 +1,65 @@
+// Package main provides HTTP-based JEPX intraday (時間前市場) data fetching with fallback to testdata.
+// Usage: go run main.go -date 2025-10-24 --use-http
+// Output: /public/data/jp/jepx/intraday-YYYY-MM-DD.json
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/teo/aversome/backend/internal/alert"
+	"github.com/teo/aversome/backend/internal/pipeline"
+	"github.com/teo/aversome/backend/internal/storage"
+	"github.com/teo/aversome/backend/pkg/logger"
+	"github.com/teo/aversome/backend/pkg/timeutil"
+)
+
+func main() {
+	var date, outputPath string
+	var useHTTP, jsonLog bool
+
+	flag.StringVar(&date, "date", "", "Delivery date in YYYY-MM-DD format (defaults to today)")
+	flag.StringVar(&outputPath, "output", "", "Output file path (defaults to public/data/jp/jepx/intraday-{date}.json)")
+	flag.BoolVar(&useHTTP, "use-http", false, "Use real HTTP fetching (default: testdata)")
+	flag.BoolVar(&jsonLog, "json-log", false, "Enable JSON structured logging")
+	flag.Parse()
+
+	// Initialize logger
+	lgr := logger.New(jsonLog)
+
+	// Default to today if no date provided
+	if date == "" {
+		date = timeutil.FormatDate(time.Now())
+	}
+
+	// Open storage (STORAGE_BACKEND); -output writes a single file instead
+	store, err := storage.OpenOutput(storage.LoadConfig(), outputPath)
+	if err != nil {
+		log.Fatalf("Failed to open storage: %v", err)
+	}
+	defer store.Close()
+
+	// Alert rules and delivery (ALERT_CONFIG, ALERT_WEBHOOK_URLS, ALERT_EMAIL_TO, SMTP_*)
+	alerts, err := alert.OpenEngine(store)
+	if err != nil {
+		log.Fatalf("Failed to load alert config: %v", err)
+	}
+
+	p := pipeline.New(pipeline.Config{UseHTTP: useHTTP, Store: store, Logger: lgr, AfterSave: alerts.AfterSave})
+
+	lgr.Info(fmt.Sprintf("Fetching JEPX intraday results for %s (HTTP: %v)", date, useHTTP))
+
+	res, err := p.FetchJEPXIntraday(date)
+	if err != nil {
+		log.Fatalf("Failed to fetch JEPX intraday data: %v", err)
+	}
+
+	lgr.Info(fmt.Sprintf("Parsed %d intraday products", res.Points))
+	if res.Warning != "" {
+		lgr.Info(fmt.Sprintf("Warning: %s", res.Warning))
+	}
+
+	log.Printf("✓ Successfully wrote %s", res.Location)
+}
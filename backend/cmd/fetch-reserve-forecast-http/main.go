@@ -0,0 +1,89 @@
+// Package main provides a pipeline job to fetch OCCTO reserve outlooks (next-day or weekly) and normalize to JSON.
+// Usage: go run main.go -horizon next_day -date 2025-10-24 --use-http
+// Weekly: go run main.go -horizon weekly -date 2025-10-20 --use-http
+// Output: /public/data/jp/system/reserve-{nextday,weekly}-YYYY-MM-DD.json (by target date)
+package main
+
+import (
+	"flag"
+	"log"
+	"time"
+
+	"github.com/teo/aversome/backend/internal/alert"
+	"github.com/teo/aversome/backend/internal/pipeline"
+	"github.com/teo/aversome/backend/internal/reserve"
+	"github.com/teo/aversome/backend/internal/storage"
+	"github.com/teo/aversome/backend/pkg/timeutil"
+)
+
+func main() {
+	var date, outputPath, horizonName, policyRef string
+	var useHTTP bool
+	flag.StringVar(&date, "date", "", "Target date (next_day) or publication date (weekly) in YYYY-MM-DD format (defaults to today)")
+	flag.StringVar(&horizonName, "horizon", string(reserve.HorizonNextDay), "Outlook: next_day or weekly")
+	flag.StringVar(&outputPath, "output", "", "Output file path, next_day only (defaults to public/data/jp/system/reserve-nextday-{date}.json)")
+	flag.BoolVar(&useHTTP, "use-http", false, "Use real HTTP fetching (default: testdata)")
+	flag.StringVar(&policyRef, "policy", "", "Reserve status policy: default, jp-alert or a policy JSON file")
+	flag.Parse()
+
+	// Default to today if no date provided
+	if date == "" {
+		date = timeutil.FormatDate(time.Now())
+	}
+
+	horizon, err := reserve.ParseHorizon(horizonName)
+	if err != nil {
+		log.Fatalf("Invalid horizon: %v", err)
+	}
+	if horizon == reserve.HorizonWeekly && outputPath != "" {
+		log.Fatalf("-output is not supported for the weekly outlook (one file per target date)")
+	}
+
+	policy, err := reserve.OpenPolicy(policyRef)
+	if err != nil {
+		log.Fatalf("Failed to load reserve policy: %v", err)
+	}
+
+	log.Printf("Fetching OCCTO %s reserve outlook for %s (HTTP: %v)...", horizon, date, useHTTP)
+
+	// Open storage (STORAGE_BACKEND); -output writes a single file instead
+	store, err := storage.OpenOutput(storage.LoadConfig(), outputPath)
+	if err != nil {
+		log.Fatalf("Failed to open storage: %v", err)
+	}
+	defer store.Close()
+
+	// Alert rules and delivery (ALERT_CONFIG, ALERT_WEBHOOK_URLS, ALERT_EMAIL_TO, SMTP_*)
+	alerts, err := alert.OpenEngine(store)
+	if err != nil {
+		log.Fatalf("Failed to load alert config: %v", err)
+	}
+
+	p := pipeline.New(pipeline.Config{UseHTTP: useHTTP, Store: store, ReservePolicy: policy, AfterSave: alerts.AfterSave})
+
+	if horizon == reserve.HorizonWeekly {
+		results, err := p.FetchReserveWeekly(date)
+		if err != nil {
+			log.Fatalf("Failed to fetch weekly reserve outlook: %v", err)
+		}
+		for _, res := range results {
+			if res.Warning != "" {
+				log.Printf("Warning (%s): %s", res.Date, res.Warning)
+			}
+			log.Printf("✓ Successfully wrote %s", res.Location)
+		}
+		return
+	}
+
+	res, err := p.FetchReserveNextDay(date)
+	if err != nil {
+		log.Fatalf("Failed to fetch next-day reserve outlook: %v", err)
+	}
+
+	log.Printf("Parsed %d areas (policy: %s)", res.Points, policy.ID)
+	if res.Warning != "" {
+		log.Printf("Warning: %s", res.Warning)
+	}
+
+	log.Printf("✓ Successfully wrote %s", res.Location)
+}
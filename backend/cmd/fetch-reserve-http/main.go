@@ -4,26 +4,24 @@
 package main
 
 import (
-	"encoding/json"
 	"flag"
-	"fmt"
-	"io"
 	"log"
-	"os"
-	"path/filepath"
 	"time"
 
-	"github.com/teo/aversome/backend/internal/adapters"
-	pkghttp "github.com/teo/aversome/backend/pkg/http"
+	"github.com/teo/aversome/backend/internal/alert"
+	"github.com/teo/aversome/backend/internal/pipeline"
+	"github.com/teo/aversome/backend/internal/reserve"
+	"github.com/teo/aversome/backend/internal/storage"
 	"github.com/teo/aversome/backend/pkg/timeutil"
 )
 
 func main() {
-	var date, outputPath string
+	var date, outputPath, policyRef string
 	var useHTTP bool
 	flag.StringVar(&date, "date", "", "Date in YYYY-MM-DD format (defaults to today)")
 	flag.StringVar(&outputPath, "output", "", "Output file path (defaults to public/data/jp/system/reserve-{date}.json)")
 	flag.BoolVar(&useHTTP, "use-http", false, "Use real HTTP fetching (default: testdata)")
+	flag.StringVar(&policyRef, "policy", "", "Reserve status policy: default, jp-alert or a policy JSON file")
 	flag.Parse()
 
 	// Default to today if no date provided
@@ -31,96 +29,36 @@ func main() {
 		date = timeutil.FormatDate(time.Now())
 	}
 
-	// Validate date format
-	parsedDate, err := timeutil.ParseDate(date)
-	if err != nil {
-		log.Fatalf("Invalid date format: %v", err)
-	}
-
 	log.Printf("Fetching OCCTO reserve margin data for %s (HTTP: %v)...", date, useHTTP)
 
-	// Get data source
-	var reader io.ReadCloser
-	var sourceName string
-
-	if useHTTP {
-		// HTTP fetch from OCCTO public API
-		fetcher := pkghttp.NewFetcher(pkghttp.BrowserConfig())
-
-		// OCCTO CSV download URL
-		// Format: ?jhSybt=02&tgtYmdFrom=YYYY/MM/DD&tgtYmdTo=YYYY/MM/DD
-		dateFormatted := parsedDate.Format("2006/01/02") // YYYY/MM/DD
-		url := fmt.Sprintf(
-			"https://web-kohyo.occto.or.jp/kks-web-public/download/downloadCsv?jhSybt=02&tgtYmdFrom=%s&tgtYmdTo=%s",
-			dateFormatted, dateFormatted,
-		)
-		sourceName = "OCCTO"
-
-		log.Printf("Attempting HTTP fetch from %s", url)
-
-		data, err := fetcher.Fetch(url)
-		if err != nil {
-			log.Printf("⚠️  HTTP fetch failed, falling back to testdata: %v", err)
-			useHTTP = false // Fallback
-		} else {
-			log.Printf("✓ HTTP fetch successful")
-			reader = data
-			defer reader.Close()
-		}
-	}
-
-	// Fallback to testdata if HTTP failed or not requested
-	if !useHTTP {
-		csvPath := filepath.Join("internal", "adapters", "testdata", "occto-sample.csv")
-		sourceName = "OCCTO (testdata)"
-
-		log.Printf("Using testdata: %s", csvPath)
-
-		f, err := os.Open(csvPath)
-		if err != nil {
-			log.Fatalf("Failed to open testdata CSV: %v", err)
-		}
-		defer f.Close()
-		reader = f
-	}
-
-	// Parse CSV using OCCTO adapter
-	adapter := adapters.NewOCCTOAdapter()
-	resp, err := adapter.ParseCSV(reader, date)
+	policy, err := reserve.OpenPolicy(policyRef)
 	if err != nil {
-		log.Fatalf("Failed to parse OCCTO CSV: %v", err)
+		log.Fatalf("Failed to load reserve policy: %v", err)
 	}
 
-	resp.Source.Name = sourceName
-
-	log.Printf("Parsed %d areas", len(resp.Areas))
-	if resp.Meta != nil && resp.Meta.Warning != "" {
-		log.Printf("Warning: %s", resp.Meta.Warning)
-	}
-
-	// Marshal to JSON with indentation
-	jsonData, err := json.MarshalIndent(resp, "", "  ")
+	// Open storage (STORAGE_BACKEND); -output writes a single file instead
+	store, err := storage.OpenOutput(storage.LoadConfig(), outputPath)
 	if err != nil {
-		log.Fatalf("Failed to marshal JSON: %v", err)
+		log.Fatalf("Failed to open storage: %v", err)
 	}
+	defer store.Close()
 
-	// Determine output path
-	if outputPath == "" {
-		// Default path (system-level data)
-		outputDir := filepath.Join("public", "data", "jp", "system")
-		outputPath = filepath.Join(outputDir, fmt.Sprintf("reserve-%s.json", date))
+	// Alert rules and delivery (ALERT_CONFIG, ALERT_WEBHOOK_URLS, ALERT_EMAIL_TO, SMTP_*)
+	alerts, err := alert.OpenEngine(store)
+	if err != nil {
+		log.Fatalf("Failed to load alert config: %v", err)
 	}
 
-	// Create output directory
-	outputDir := filepath.Dir(outputPath)
-	if err := os.MkdirAll(outputDir, 0755); err != nil {
-		log.Fatalf("Failed to create output directory: %v", err)
+	p := pipeline.New(pipeline.Config{UseHTTP: useHTTP, Store: store, ReservePolicy: policy, AfterSave: alerts.AfterSave})
+	res, err := p.FetchReserve(date)
+	if err != nil {
+		log.Fatalf("Failed to fetch reserve data: %v", err)
 	}
 
-	// Write JSON file
-	if err := os.WriteFile(outputPath, jsonData, 0644); err != nil {
-		log.Fatalf("Failed to write JSON file: %v", err)
+	log.Printf("Parsed %d areas (policy: %s)", res.Points, policy.ID)
+	if res.Warning != "" {
+		log.Printf("Warning: %s", res.Warning)
 	}
 
-	log.Printf("✓ Successfully wrote %s (%d bytes)", outputPath, len(jsonData))
+	log.Printf("✓ Successfully wrote %s", res.Location)
 }
@@ -1,5 +1,6 @@
 // Package main fetches solar radiation forecast from Open-Meteo API.
 // Usage: go run main.go -area tokyo -date 2025-11-07 -output forecast.json
+// Output: public/data/jp/{area}/weather-YYYY-MM-DD.json (without -output)
 package main
 
 import (
@@ -8,8 +9,10 @@ import (
 	"fmt"
 	"log"
 	"net/http"
-	"os"
 	"time"
+
+	"github.com/teo/aversome/backend/internal/areas"
+	"github.com/teo/aversome/backend/internal/storage"
 )
 
 type OpenMeteoResponse struct {
@@ -49,15 +52,6 @@ type SolarForecast struct {
 	Data                []SolarDataPoint `json:"data"`
 }
 
-var cityCoordinates = map[string]struct {
-	name string
-	lat  float64
-	lon  float64
-}{
-	"tokyo":  {"Tokyo", 35.6895, 139.6917},
-	"kansai": {"Osaka", 34.6937, 135.5023},
-}
-
 const (
 	pvEfficiency       = 0.20 // 20% panel efficiency
 	performanceRatio   = 0.75 // 75% system performance ratio
@@ -66,25 +60,27 @@ const (
 
 func main() {
 	var area, date, outputPath string
-	flag.StringVar(&area, "area", "tokyo", "Area: tokyo or kansai")
+	flag.StringVar(&area, "area", "tokyo", "Area code (hokkaido, tohoku, tokyo, chubu, hokuriku, kansai, chugoku, shikoku, kyushu, okinawa)")
 	flag.StringVar(&date, "date", "", "Date in YYYY-MM-DD format")
-	flag.StringVar(&outputPath, "output", "", "Output file path")
+	flag.StringVar(&outputPath, "output", "", "Output file path (defaults to public/data/jp/{area}/weather-{date}.json)")
 	flag.Parse()
 
 	if date == "" {
 		date = time.Now().Format("2006-01-02")
 	}
 
-	coords, ok := cityCoordinates[area]
-	if !ok {
-		log.Fatalf("Invalid area: %s (must be tokyo or kansai)", area)
+	// Coordinates come from the area's representative city in the registry
+	areaInfo, err := areas.Parse(area)
+	if err != nil {
+		log.Fatalf("Invalid area: %v", err)
 	}
+	area = string(areaInfo.Code)
 
-	log.Printf("Fetching solar forecast for %s on %s...", coords.name, date)
+	log.Printf("Fetching solar forecast for %s on %s...", areaInfo.NameEN, date)
 
 	// Build API URL
 	url := fmt.Sprintf("%s?latitude=%f&longitude=%f&start_date=%s&end_date=%s&hourly=shortwave_radiation,direct_radiation,diffuse_radiation,cloud_cover,temperature_2m&timezone=Asia/Tokyo",
-		openMeteoBaseURL, coords.lat, coords.lon, date, date)
+		openMeteoBaseURL, areaInfo.Latitude, areaInfo.Longitude, date, date)
 
 	// Fetch data
 	resp, err := http.Get(url)
@@ -147,7 +143,7 @@ func main() {
 	totalRadiationKWhM2 := totalGHI / 1000
 
 	forecast := SolarForecast{
-		Location:            coords.name,
+		Location:            areaInfo.NameEN,
 		Latitude:            apiResp.Latitude,
 		Longitude:           apiResp.Longitude,
 		Date:                date,
@@ -158,21 +154,20 @@ func main() {
 		Data:                solarData,
 	}
 
-	// Write output
-	output, err := json.MarshalIndent(forecast, "", "  ")
+	// Open storage (STORAGE_BACKEND); -output writes a single file instead
+	store, err := storage.OpenOutput(storage.LoadConfig(), outputPath)
 	if err != nil {
-		log.Fatalf("Failed to marshal JSON: %v", err)
+		log.Fatalf("Failed to open storage: %v", err)
 	}
+	defer store.Close()
 
-	if outputPath == "" {
-		outputPath = fmt.Sprintf("weather-%s-%s.json", area, date)
-	}
-
-	if err := os.WriteFile(outputPath, output, 0644); err != nil {
+	// Write output
+	location, err := store.Save(storage.DatasetWeather, area, date, forecast)
+	if err != nil {
 		log.Fatalf("Failed to write output: %v", err)
 	}
 
-	log.Printf("✅ Solar forecast saved to %s", outputPath)
+	log.Printf("✅ Solar forecast saved to %s", location)
 	log.Printf("   Peak radiation: %.0f W/m² at %d:00", maxGHI, peakIdx)
 	log.Printf("   Daily total: %.2f kWh/m²", totalRadiationKWhM2)
 }
@@ -1,5 +1,6 @@
 // Package main provides a CLI tool to run settlement calculations.
 // Usage: go run main.go -profile profile.json -area tokyo -date 2025-10-23 -pv 0.15
+// Imbalance: go run main.go -mode imbalance -profile actual.json -planned planned.json -area tokyo -date 2025-10-23
 // Output: settlement-result.json
 package main
 
@@ -10,16 +11,20 @@ import (
 	"log"
 	"os"
 
+	"github.com/teo/aversome/backend/internal/areas"
+	"github.com/teo/aversome/backend/internal/imbalance"
 	"github.com/teo/aversome/backend/internal/jepx"
 	"github.com/teo/aversome/backend/internal/settlement"
 )
 
 func main() {
-	var profilePath, area, date string
+	var profilePath, plannedPath, area, date, mode string
 	var pvOffset float64
 
-	flag.StringVar(&profilePath, "profile", "", "Path to consumption profile JSON file")
-	flag.StringVar(&area, "area", "tokyo", "Area for JEPX prices: tokyo or kansai")
+	flag.StringVar(&profilePath, "profile", "", "Path to consumption profile JSON file (actual consumption in imbalance mode)")
+	flag.StringVar(&plannedPath, "planned", "", "Path to planned (nominated) profile JSON file, imbalance mode only")
+	flag.StringVar(&mode, "mode", string(settlement.ModeSpot), "Settlement mode: spot or imbalance")
+	flag.StringVar(&area, "area", "tokyo", "Area for JEPX prices (any JEPX area: hokkaido through kyushu)")
 	flag.StringVar(&date, "date", "", "Date for JEPX prices (YYYY-MM-DD)")
 	flag.Float64Var(&pvOffset, "pv", 0.0, "PV offset percentage (0.0-1.0, e.g., 0.15 for 15%)")
 	flag.Parse()
@@ -31,9 +36,22 @@ func main() {
 		log.Fatal("Error: -date is required")
 	}
 
-	// Validate area
-	if area != "tokyo" && area != "kansai" {
-		log.Fatalf("Error: invalid area %q (must be 'tokyo' or 'kansai')", area)
+	// Validate and normalize area
+	areaInfo, err := areas.Parse(area)
+	if err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+	area = string(areaInfo.Code)
+
+	if settlement.Mode(mode) == settlement.ModeImbalance {
+		runImbalance(profilePath, plannedPath, area, date)
+		return
+	}
+	if mode != string(settlement.ModeSpot) {
+		log.Fatalf("Error: -mode must be spot or imbalance, got %q", mode)
+	}
+	if !areaInfo.HasJEPXPrice() {
+		log.Fatalf("Error: area %s has no JEPX spot price", areaInfo.Code)
 	}
 
 	// Validate PV offset
@@ -52,7 +70,7 @@ func main() {
 	if err != nil {
 		log.Fatalf("Failed to load profile: %v", err)
 	}
-	log.Printf("Loaded %d hourly profile points", len(profile))
+	log.Printf("Loaded %d profile points", len(profile))
 
 	// Load JEPX prices from generated JSON
 	pricesPath := fmt.Sprintf("../public/data/jp/jepx/spot-%s-%s.json", area, date)
@@ -60,7 +78,16 @@ func main() {
 	if err != nil {
 		log.Fatalf("Failed to load JEPX prices: %v\nHint: Run 'go run cmd/fetch-jepx/main.go --date %s --area %s' first", err, date, area)
 	}
-	log.Printf("Loaded %d hourly price points", len(pricesResp.PriceYenPerKwh))
+	log.Printf("Loaded %d price points (%s)", len(pricesResp.PriceYenPerKwh), pricesResp.Timescale)
+
+	// Match price resolution to the profile (e.g., hourly profile against 30-min prices)
+	if ts := settlement.ProfileTimescale(profile); ts != pricesResp.Timescale {
+		pricesResp, err = pricesResp.Resample(ts)
+		if err != nil {
+			log.Fatalf("Failed to resample JEPX prices to %s: %v", ts, err)
+		}
+		log.Printf("Resampled prices to %s (%d points)", ts, len(pricesResp.PriceYenPerKwh))
+	}
 
 	// Build settlement request
 	req := &settlement.Request{
@@ -83,7 +110,68 @@ func main() {
 	log.Printf("  Total kWh: %.1f", resp.Totals.KWh)
 	log.Printf("  Total Cost: ¥%.1f", resp.Totals.CostYen)
 
-	// Write result to JSON
+	writeResult(resp)
+}
+
+// runImbalance settles actual − planned at the area's imbalance prices.
+func runImbalance(profilePath, plannedPath, area, date string) {
+	if plannedPath == "" {
+		log.Fatal("Error: -planned is required in imbalance mode")
+	}
+
+	log.Printf("Running imbalance settlement...")
+	log.Printf("  Actual: %s", profilePath)
+	log.Printf("  Planned: %s", plannedPath)
+	log.Printf("  Area: %s", area)
+	log.Printf("  Date: %s", date)
+
+	// Load actual and planned profiles
+	profile, err := loadProfile(profilePath)
+	if err != nil {
+		log.Fatalf("Failed to load profile: %v", err)
+	}
+	planned, err := loadProfile(plannedPath)
+	if err != nil {
+		log.Fatalf("Failed to load planned profile: %v", err)
+	}
+
+	req := &settlement.Request{
+		Profile: profile,
+		Planned: planned,
+		Prices: settlement.PricesRequest{
+			Area: area,
+			Date: date,
+		},
+		Mode: settlement.ModeImbalance,
+	}
+	if err := req.Validate(); err != nil {
+		log.Fatalf("Invalid imbalance settlement: %v", err)
+	}
+
+	// Load imbalance prices from generated JSON
+	pricesPath := fmt.Sprintf("../public/data/jp/%s/imbalance-%s.json", area, date)
+	prices, err := loadImbalancePrices(pricesPath)
+	if err != nil {
+		log.Fatalf("Failed to load imbalance prices: %v\nHint: Run 'go run cmd/fetch-imbalance-http/main.go --date %s --area %s' first", err, date, area)
+	}
+	log.Printf("Loaded %d imbalance prices", len(prices.Prices))
+
+	resp, err := settlement.CalculateImbalance(req, prices.Prices, prices.Source)
+	if err != nil {
+		log.Fatalf("Imbalance settlement failed: %v", err)
+	}
+
+	log.Printf("✓ Imbalance settlement calculated successfully")
+	log.Printf("  Period: %s to %s", resp.Period.From, resp.Period.To)
+	log.Printf("  Shortage: %.1f kWh (¥%.1f)", resp.ImbalanceTotals.ShortageKWh, resp.ImbalanceTotals.ShortageYen)
+	log.Printf("  Surplus: %.1f kWh (¥%.1f)", resp.ImbalanceTotals.SurplusKWh, resp.ImbalanceTotals.SurplusYen)
+	log.Printf("  Net Cost: ¥%.1f", resp.Totals.CostYen)
+
+	writeResult(resp)
+}
+
+// writeResult writes the settlement result to settlement-result.json.
+func writeResult(resp *settlement.Response) {
 	outputPath := "settlement-result.json"
 	jsonData, err := json.MarshalIndent(resp, "", "  ")
 	if err != nil {
@@ -126,3 +214,18 @@ func loadJEPXPrices(path string) (*jepx.Response, error) {
 
 	return &resp, nil
 }
+
+// loadImbalancePrices loads imbalance price data from generated JSON artifact.
+func loadImbalancePrices(path string) (*imbalance.Response, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	var resp imbalance.Response
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON: %w", err)
+	}
+
+	return &resp, nil
+}
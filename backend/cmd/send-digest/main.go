@@ -0,0 +1,121 @@
+// Package main emails the daily digest of a day (default: yesterday) from stored data.
+// Usage: go run main.go -date 2025-10-23 -areas tokyo,kansai -lang ja -to ops@example.com
+// Preview: go run main.go -dry-run (prints the text body instead of sending)
+// SMTP: SMTP_HOST, SMTP_PORT, SMTP_USERNAME, SMTP_PASSWORD, SMTP_FROM; recipients: -to or DIGEST_EMAIL_TO
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/teo/aversome/backend/internal/alert"
+	"github.com/teo/aversome/backend/internal/areas"
+	"github.com/teo/aversome/backend/internal/digest"
+	"github.com/teo/aversome/backend/internal/pipeline"
+	"github.com/teo/aversome/backend/internal/reserve"
+	"github.com/teo/aversome/backend/internal/storage"
+	"github.com/teo/aversome/backend/pkg/mail"
+	"github.com/teo/aversome/backend/pkg/timeutil"
+)
+
+func main() {
+	var date, areaList, langName, toList, policyRef string
+	var fetch, dryRun bool
+	flag.StringVar(&date, "date", "", "Day to summarize in YYYY-MM-DD format (defaults to yesterday, Asia/Tokyo)")
+	flag.StringVar(&areaList, "areas", "tokyo,kansai", "Comma-separated areas, or \"all\"")
+	flag.StringVar(&langName, "lang", os.Getenv("DIGEST_LANG"), "Email language: ja (default) or en")
+	flag.StringVar(&toList, "to", os.Getenv("DIGEST_EMAIL_TO"), "Comma-separated recipients")
+	flag.StringVar(&policyRef, "policy", os.Getenv("RESERVE_POLICY"), "Reserve status policy: default, jp-alert or a policy JSON file")
+	flag.BoolVar(&fetch, "fetch", true, "Fetch missing demand, JEPX and reserve data over HTTP first")
+	flag.BoolVar(&dryRun, "dry-run", false, "Print the text body instead of sending")
+	flag.Parse()
+
+	// Default to yesterday in Japan time
+	if date == "" {
+		date = timeutil.FormatDate(time.Now().In(timeutil.TokyoLocation).AddDate(0, 0, -1))
+	}
+
+	selected, err := areas.ParseList(areaList)
+	if err != nil {
+		log.Fatalf("Invalid areas: %v", err)
+	}
+	lang, err := mail.ParseLang(langName)
+	if err != nil {
+		log.Fatalf("Invalid language: %v", err)
+	}
+	policy, err := reserve.OpenPolicy(policyRef)
+	if err != nil {
+		log.Fatalf("Failed to load reserve policy: %v", err)
+	}
+
+	var to []string
+	for _, addr := range strings.Split(toList, ",") {
+		if addr = strings.TrimSpace(addr); addr != "" {
+			to = append(to, addr)
+		}
+	}
+	if len(to) == 0 && !dryRun {
+		log.Fatalf("No recipients (-to or DIGEST_EMAIL_TO)")
+	}
+
+	// Open storage (STORAGE_BACKEND)
+	store, err := storage.Open(storage.LoadConfig())
+	if err != nil {
+		log.Fatalf("Failed to open storage: %v", err)
+	}
+	defer store.Close()
+
+	if fetch {
+		// Alert rules and delivery (ALERT_CONFIG, ALERT_WEBHOOK_URLS, ALERT_EMAIL_TO, SMTP_*)
+		alerts, err := alert.OpenEngine(store)
+		if err != nil {
+			log.Fatalf("Failed to load alert config: %v", err)
+		}
+
+		p := pipeline.New(pipeline.Config{UseHTTP: true, NoFallback: true, Store: store, ReservePolicy: policy, AfterSave: alerts.AfterSave})
+		report, err := p.Backfill(pipeline.BackfillConfig{
+			From:     date,
+			To:       date,
+			Datasets: []storage.Dataset{storage.DatasetDemand, storage.DatasetJEPX, storage.DatasetReserve},
+			Areas:    selected,
+		})
+		if err != nil {
+			log.Fatalf("Fetch failed: %v", err)
+		}
+		// Missing data is shown as such in the digest
+		for _, item := range report.Items {
+			if item.Status == pipeline.BackfillFailed {
+				log.Printf("⚠️  %s %s: %v", item.Dataset, item.Area, item.Err)
+			}
+		}
+	}
+
+	d, err := digest.Build(store, date, selected, policy)
+	if err != nil {
+		log.Fatalf("Failed to build digest: %v", err)
+	}
+	msg, err := digest.Render(d, lang)
+	if err != nil {
+		log.Fatalf("Failed to render digest: %v", err)
+	}
+
+	if dryRun {
+		fmt.Printf("Subject: %s\n\n%s", msg.Subject, msg.Text)
+		return
+	}
+
+	smtpCfg, err := mail.LoadConfig()
+	if err != nil {
+		log.Fatalf("Invalid SMTP configuration: %v", err)
+	}
+	msg.To = to
+	if err := mail.NewSender(smtpCfg).Send(msg); err != nil {
+		log.Fatalf("Failed to send digest: %v", err)
+	}
+
+	log.Printf("✓ Sent %s digest for %s to %s", lang, date, strings.Join(to, ", "))
+}
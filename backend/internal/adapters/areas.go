@@ -0,0 +1,29 @@
+package adapters
+
+import (
+	"strings"
+
+	"github.com/teo/aversome/backend/internal/areas"
+)
+
+// sourceArea maps an area name as a data source writes it to its canonical
+// code. Besides the exact forms areas.Normalize accepts, it matches names
+// decorated with a suffix by prefix ("Tokyo Area", "東京電力パワーグリッド").
+// User input goes through areas.Parse, which does not.
+func sourceArea(s string) (areas.Code, bool) {
+	if code, ok := areas.Normalize(s); ok {
+		return code, true
+	}
+
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return "", false
+	}
+	lower := strings.ToLower(s)
+	for _, a := range areas.All() {
+		if strings.HasPrefix(lower, string(a.Code)) || strings.HasPrefix(s, a.NameJA) {
+			return a.Code, true
+		}
+	}
+	return "", false
+}
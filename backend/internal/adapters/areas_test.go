@@ -0,0 +1,34 @@
+package adapters
+
+import (
+	"testing"
+
+	"github.com/teo/aversome/backend/internal/areas"
+)
+
+func TestSourceArea(t *testing.T) {
+	tests := []struct {
+		input  string
+		want   areas.Code
+		wantOK bool
+	}{
+		{"tokyo", areas.Tokyo, true},
+		{"九州", areas.Kyushu, true},
+		{"Kyushu Yen/kWh", areas.Kyushu, true},
+		{"Tokyo Area", areas.Tokyo, true},
+		{"東京電力パワーグリッド", areas.Tokyo, true},
+		{" 関西電力送配電 ", areas.Kansai, true},
+		{"", "", false},
+		{"合計", "", false},
+		{"osaka", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, ok := sourceArea(tt.input)
+			if ok != tt.wantOK || got != tt.want {
+				t.Errorf("sourceArea(%q) = %q, %v; want %q, %v", tt.input, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
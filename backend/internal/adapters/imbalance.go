@@ -0,0 +1,189 @@
+package adapters
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/teo/aversome/backend/internal/areas"
+	"github.com/teo/aversome/backend/internal/imbalance"
+	"github.com/teo/aversome/backend/pkg/timeutil"
+	"golang.org/x/text/encoding/japanese"
+	"golang.org/x/text/transform"
+)
+
+// ImbalanceAdapter normalizes imbalance price (インバランス料金) data.
+type ImbalanceAdapter struct {
+	sourceURL string
+}
+
+// NewImbalanceAdapter creates a new imbalance price adapter.
+func NewImbalanceAdapter() *ImbalanceAdapter {
+	return &ImbalanceAdapter{
+		sourceURL: "https://www.imbalanceprices-cs.jp/",
+	}
+}
+
+// ParseCSV parses an imbalance price CSV into imbalance.Response for one area.
+// CSV format (Shift-JIS, one row per area and koma):
+//   対象日,時刻コード,時刻,エリア,余剰インバランス料金単価(円/kWh),不足インバランス料金単価(円/kWh)
+//   2025/10/23,1,0:00-0:30,東京,25.12,25.12
+//
+// Notes:
+// - Rows of other dates and areas are skipped (the published file covers every area)
+// - A file with a single インバランス料金 column uses it for both sides
+// - Prices are in JPY/kWh
+func (a *ImbalanceAdapter) ParseCSV(reader io.Reader, date string, area areas.Area) (*imbalance.Response, error) {
+	// Convert from Shift-JIS to UTF-8
+	utf8Reader := transform.NewReader(reader, japanese.ShiftJIS.NewDecoder())
+
+	csvReader := csv.NewReader(utf8Reader)
+	csvReader.TrimLeadingSpace = true
+	csvReader.FieldsPerRecord = -1 // Allow variable number of fields
+
+	// Read header
+	header, err := csvReader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+
+	// Auto-detect column indices
+	colIndices := a.detectColumns(header)
+	for _, col := range []string{"date", "period", "area"} {
+		if colIndices[col] == -1 {
+			return nil, fmt.Errorf("required column %s not found in header: %v", col, header)
+		}
+	}
+	if colIndices["surplus"] == -1 && colIndices["shortage"] == -1 {
+		return nil, fmt.Errorf("no imbalance price column found in header: %v", header)
+	}
+
+	baseDate, err := timeutil.ParseDate(date)
+	if err != nil {
+		return nil, fmt.Errorf("invalid date: %w", err)
+	}
+
+	resp := imbalance.NewResponse(date, string(area.Code))
+	resp.Source = imbalance.Source{
+		Name: "Imbalance Price Portal",
+		URL:  a.sourceURL,
+	}
+
+	jepxAdapter := NewJEPXAdapter() // Shared date normalization
+	lineNum := 1
+	seen := make(map[int]bool)
+
+	// Read data rows
+	for {
+		record, err := csvReader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error reading CSV line %d: %w", lineNum, err)
+		}
+		lineNum++
+
+		if len(record) <= colIndices["area"] || jepxAdapter.normalizeDate(record[colIndices["date"]]) != date {
+			continue
+		}
+		if code, ok := sourceArea(record[colIndices["area"]]); !ok || code != area.Code {
+			continue // Other area (or a total row)
+		}
+
+		// Period number (コマ 1-48)
+		periodStr := strings.TrimSpace(record[colIndices["period"]])
+		period, err := strconv.Atoi(periodStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid period at line %d: %s", lineNum, periodStr)
+		}
+		slot, err := timeutil.SlotFromPeriod(period)
+		if err != nil || seen[slot] {
+			continue // Skip out-of-range or duplicate koma
+		}
+
+		point := imbalance.PricePoint{
+			Timestamp: timeutil.FormatISO8601(timeutil.SlotTime(baseDate, slot)),
+			Period:    period,
+		}
+		if point.SurplusPrice, err = a.parsePrice(record, colIndices["surplus"]); err != nil {
+			return nil, fmt.Errorf("invalid surplus price at line %d: %w", lineNum, err)
+		}
+		if point.ShortagePrice, err = a.parsePrice(record, colIndices["shortage"]); err != nil {
+			return nil, fmt.Errorf("invalid shortage price at line %d: %w", lineNum, err)
+		}
+
+		// Single-price files: same unit price for both sides
+		if colIndices["surplus"] == -1 {
+			point.SurplusPrice = point.ShortagePrice
+		}
+		if colIndices["shortage"] == -1 {
+			point.ShortagePrice = point.SurplusPrice
+		}
+
+		resp.Prices = append(resp.Prices, point)
+		seen[slot] = true
+	}
+
+	// Validate we have data
+	if len(resp.Prices) == 0 {
+		return nil, fmt.Errorf("no imbalance prices found for date %s and area %s", date, area.Code)
+	}
+
+	// Add warning if missing koma
+	if len(seen) < timeutil.SlotsPerDay {
+		resp.Meta = &imbalance.Meta{
+			Warning: fmt.Sprintf("Data for %d periods available (expected %d)", len(seen), timeutil.SlotsPerDay),
+		}
+	}
+
+	return resp, nil
+}
+
+// detectColumns finds column indices by header names.
+// Returns map with keys: date, period, area, surplus, shortage.
+func (a *ImbalanceAdapter) detectColumns(header []string) map[string]int {
+	indices := map[string]int{
+		"date":     -1,
+		"period":   -1,
+		"area":     -1,
+		"surplus":  -1,
+		"shortage": -1,
+	}
+
+	for i, col := range header {
+		col = strings.TrimSpace(strings.TrimPrefix(col, "\ufeff"))
+		colLower := strings.ToLower(col)
+
+		switch {
+		case col == "対象日" || col == "年月日" || colLower == "date":
+			indices["date"] = i
+		case col == "時刻コード" || col == "コマ" || colLower == "period":
+			indices["period"] = i
+		case col == "エリア" || colLower == "area":
+			indices["area"] = i
+		case strings.HasPrefix(col, "余剰インバランス料金") || strings.HasPrefix(colLower, "surplus"):
+			indices["surplus"] = i
+		case strings.HasPrefix(col, "不足インバランス料金") || strings.HasPrefix(colLower, "shortage"):
+			indices["shortage"] = i
+		case strings.HasPrefix(col, "インバランス料金") || strings.HasPrefix(colLower, "imbalance price"):
+			// Single price column
+			indices["shortage"] = i
+		}
+	}
+
+	return indices
+}
+
+// parsePrice parses a JPY/kWh field; a missing column parses as 0.
+func (a *ImbalanceAdapter) parsePrice(record []string, idx int) (float64, error) {
+	if idx == -1 {
+		return 0, nil
+	}
+	if idx >= len(record) {
+		return 0, fmt.Errorf("missing field")
+	}
+	return strconv.ParseFloat(strings.ReplaceAll(strings.TrimSpace(record[idx]), ",", ""), 64)
+}
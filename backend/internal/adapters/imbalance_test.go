@@ -0,0 +1,101 @@
+package adapters
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/teo/aversome/backend/internal/areas"
+	"golang.org/x/text/encoding/japanese"
+)
+
+func TestImbalanceAdapter_ParseCSV(t *testing.T) {
+	tokyo, _ := areas.Lookup(areas.Tokyo)
+	okinawa, _ := areas.Lookup(areas.Okinawa)
+
+	tests := []struct {
+		name         string
+		area         areas.Area
+		date         string
+		wantErr      bool
+		wantShortage float64 // Shortage price of period 36 (17:30)
+		wantSurplus  float64 // Surplus price of period 36
+	}{
+		{
+			name:         "Tokyo with scarcity correction",
+			area:         tokyo,
+			date:         "2025-10-23",
+			wantShortage: 47.02,
+			wantSurplus:  32.02,
+		},
+		{
+			name:         "Okinawa",
+			area:         okinawa,
+			date:         "2025-10-23",
+			wantShortage: 39.17,
+			wantSurplus:  39.17,
+		},
+		{
+			name:    "date not in file",
+			area:    tokyo,
+			date:    "2025-10-24",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := os.Open("testdata/imbalance-sample.csv")
+			if err != nil {
+				t.Fatalf("Failed to open test CSV: %v", err)
+			}
+			defer f.Close()
+
+			resp, err := NewImbalanceAdapter().ParseCSV(f, tt.date, tt.area)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseCSV() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if len(resp.Prices) != 48 || resp.Meta != nil {
+				t.Fatalf("Got %d prices (meta %+v), want 48 without warning", len(resp.Prices), resp.Meta)
+			}
+			if resp.Area != string(tt.area.Code) || resp.Timescale != "30min" {
+				t.Errorf("Area/Timescale = %s/%s", resp.Area, resp.Timescale)
+			}
+
+			p := resp.Prices[35]
+			if p.Period != 36 || p.Timestamp != "2025-10-23T17:30:00+09:00" {
+				t.Errorf("Prices[35] = %+v, want period 36 at 17:30", p)
+			}
+			if p.ShortagePrice != tt.wantShortage || p.SurplusPrice != tt.wantSurplus {
+				t.Errorf("Prices[35] = %v/%v, want shortage %v surplus %v",
+					p.ShortagePrice, p.SurplusPrice, tt.wantShortage, tt.wantSurplus)
+			}
+		})
+	}
+}
+
+func TestImbalanceAdapter_ParseCSV_SinglePrice(t *testing.T) {
+	csvData := "対象日,時刻コード,エリア,インバランス料金単価(円/kWh)\n" +
+		"2025/10/23,1,関西,12.5\n" +
+		"2025/10/23,1,東京,14.0\n"
+	encoded, err := japanese.ShiftJIS.NewEncoder().String(csvData)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	kansai, _ := areas.Lookup(areas.Kansai)
+	resp, err := NewImbalanceAdapter().ParseCSV(strings.NewReader(encoded), "2025-10-23", kansai)
+	if err != nil {
+		t.Fatalf("ParseCSV() error = %v", err)
+	}
+	if len(resp.Prices) != 1 || resp.Prices[0].SurplusPrice != 12.5 || resp.Prices[0].ShortagePrice != 12.5 {
+		t.Errorf("Prices = %+v, want one 12.5/12.5 point", resp.Prices)
+	}
+	if resp.Meta == nil || !strings.Contains(resp.Meta.Warning, "1 periods") {
+		t.Errorf("expected missing-periods warning, got %+v", resp.Meta)
+	}
+}
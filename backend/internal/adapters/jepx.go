@@ -9,7 +9,9 @@ import (
 	"strings"
 	"time"
 
+	"github.com/teo/aversome/backend/internal/areas"
 	"github.com/teo/aversome/backend/internal/jepx"
+	"github.com/teo/aversome/backend/pkg/timeutil"
 )
 
 // JEPXAdapter normalizes JEPX (Japan Electric Power Exchange) day-ahead spot price data.
@@ -32,36 +34,157 @@ func NewJEPXAdapter() *JEPXAdapter {
 //   ...
 //
 // Notes:
-// - 30-minute intervals (48 periods/day) - we extract only hourly (:00:00)
+// - 30-minute intervals (48 periods/day) are kept at native resolution (timescale "30min")
+// - Legacy exports with a Date/Hour layout produce hourly data (timescale "hourly")
 // - Prices are in JPY/kWh
 // - Multiple areas in columns (Tokyo, Kansai, etc.)
 func (a *JEPXAdapter) ParseCSV(reader io.Reader, date, area string) (*jepx.Response, error) {
+	// Resolve area against the registry ("Tokyo", "東京" → "tokyo")
+	info, err := areas.Parse(area)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp *jepx.Response
+	err = a.ParseCSVBatch(reader, date, date, []areas.Area{info}, func(r *jepx.Response) error {
+		resp = r
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Validate we have data
+	if resp == nil {
+		return nil, fmt.Errorf("no data found for date %s and area %s", date, info.Code)
+	}
+
+	return resp, nil
+}
+
+// ParseCSVBatch parses a multi-date JEPX CSV (e.g. the all-history
+// japanesepower.org file) in a single streaming pass and calls emit with one
+// jepx.Response per (date, area) for every date between from and to
+// (YYYY-MM-DD, inclusive; "" leaves that end open). Only one day of prices is
+// held in memory: a day is emitted, in file order and then in the order of
+// selected, as soon as the next date starts. Rows must be grouped by date, as
+// in every JEPX export. Dates without rows are not emitted.
+//
+// selected lists the areas to extract; nil means every JEPX area with a price
+// column in the header. An error returned by emit stops parsing.
+func (a *JEPXAdapter) ParseCSVBatch(reader io.Reader, from, to string, selected []areas.Area, emit func(*jepx.Response) error) error {
+	return a.parseMarket(reader, from, to, selected, false, func(market *jepx.MarketResponse) error {
+		for _, area := range market.Areas {
+			resp, err := market.AreaResponse(area)
+			if err != nil {
+				return err
+			}
+			if err := emit(resp); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// ParseMarketCSV parses the system price and every area price of one date.
+func (a *JEPXAdapter) ParseMarketCSV(reader io.Reader, date string) (*jepx.MarketResponse, error) {
+	var resp *jepx.MarketResponse
+	err := a.ParseMarketCSVBatch(reader, date, date, func(r *jepx.MarketResponse) error {
+		resp = r
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Validate we have data
+	if resp == nil {
+		return nil, fmt.Errorf("no data found for date %s", date)
+	}
+
+	return resp, nil
+}
+
+// ParseMarketCSVBatch is ParseCSVBatch for all-area responses: it emits one
+// jepx.MarketResponse (system price plus every area price column in the
+// header) per date between from and to. The system price column is required.
+func (a *JEPXAdapter) ParseMarketCSVBatch(reader io.Reader, from, to string, emit func(*jepx.MarketResponse) error) error {
+	return a.parseMarket(reader, from, to, nil, true, emit)
+}
+
+// parseMarket is the single streaming pass behind the batch parsers. It
+// builds one MarketResponse per date and emits it when the next date starts.
+// The system price is only parsed with requireSystem. Volumes are optional:
+// a blank or invalid volume cell leaves the period without volume and is
+// reported in the day's warning.
+func (a *JEPXAdapter) parseMarket(reader io.Reader, from, to string, selected []areas.Area, requireSystem bool, emit func(*jepx.MarketResponse) error) error {
 	csvReader := csv.NewReader(reader)
 	csvReader.TrimLeadingSpace = true
 
 	// Read header
 	header, err := csvReader.Read()
 	if err != nil {
-		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+		return fmt.Errorf("failed to read CSV header: %w", err)
 	}
 
-	// Auto-detect column indices
-	colIndices := a.detectColumns(header, area)
-	if colIndices["datetime"] == -1 && colIndices["date"] == -1 {
-		return nil, fmt.Errorf("datetime or date column not found in header: %v", header)
+	// Auto-detect column indices (price columns per area)
+	colIndices, priceCols, err := a.detectLayout(header, selected)
+	if err != nil {
+		return err
 	}
-	if colIndices["price"] == -1 {
-		return nil, fmt.Errorf("price column for area %s not found in header: %v", area, header)
+	if requireSystem && colIndices["system"] == -1 {
+		return fmt.Errorf("system price column not found in header: %v", header)
 	}
 
-	resp := jepx.NewResponse(date, area)
-	resp.Source = jepx.Source{
-		Name: "JEPX",
-		URL:  a.sourceURL,
+	areaCodes := make([]string, len(priceCols))
+	for i, pc := range priceCols {
+		areaCodes[i] = string(pc.area)
+	}
+
+	// Hour-only exports are hourly; everything else carries 30-minute コマ
+	timescale := jepx.Timescale30Min
+	if colIndices["datetime"] == -1 && colIndices["period"] == -1 {
+		timescale = jepx.TimescaleHourly
+	}
+
+	// Prices of the date being read
+	var day *jepx.MarketResponse
+	slotsSeen := make(map[int]bool)
+	emitted := make(map[string]bool)
+	badVolumes := 0
+
+	flush := func() error {
+		if day == nil {
+			return nil
+		}
+		emitted[day.Date] = true
+
+		unit := "periods"
+		if timescale == jepx.TimescaleHourly {
+			unit = "hours"
+		}
+		var warnings []string
+
+		// Add warning if missing periods
+		if expected := timeutil.PointsPerDay(timescale); len(slotsSeen) < expected {
+			warnings = append(warnings, fmt.Sprintf("Data for %d %s available (expected %d)", len(slotsSeen), unit, expected))
+		}
+		if badVolumes > 0 {
+			warnings = append(warnings, fmt.Sprintf("Invalid volume data for %d %s", badVolumes, unit))
+		}
+		if len(warnings) > 0 {
+			day.Meta = &jepx.Meta{Warning: strings.Join(warnings, "; ")}
+		}
+
+		err := emit(day)
+		day = nil
+		slotsSeen = make(map[int]bool)
+		badVolumes = 0
+		return err
 	}
 
 	lineNum := 1
-	hoursSeen := make(map[int]bool)
 
 	// Read data rows
 	for {
@@ -70,113 +193,231 @@ func (a *JEPXAdapter) ParseCSV(reader io.Reader, date, area string) (*jepx.Respo
 			break
 		}
 		if err != nil {
-			return nil, fmt.Errorf("error reading CSV line %d: %w", lineNum, err)
+			return fmt.Errorf("error reading CSV line %d: %w", lineNum, err)
 		}
 		lineNum++
 
-		// Extract datetime or date+hour
-		var rowDate string
-		var hour int
-
-		if colIndices["datetime"] != -1 {
-			// Parse datetime column (format: "2025-11-03 00:00:00")
-			datetimeStr := strings.TrimSpace(record[colIndices["datetime"]])
-
-			// Extract date and hour from datetime
-			parts := strings.Split(datetimeStr, " ")
-			if len(parts) >= 2 {
-				rowDate = a.normalizeDate(parts[0])
-
-				// Extract hour from time (HH:MM:SS)
-				timeParts := strings.Split(parts[1], ":")
-				if len(timeParts) >= 1 {
-					hour, err = strconv.Atoi(timeParts[0])
-					if err != nil {
-						continue // Skip invalid rows
-					}
-
-					// Only include hourly data (ignore :30:00 intervals)
-					if len(timeParts) >= 2 {
-						minute := timeParts[1]
-						if minute != "00" {
-							continue // Skip 30-minute intervals
-						}
-					}
-				}
-			} else {
-				continue // Skip malformed datetime
+		rowDate, slot, ok, err := a.parseRowSlot(record, colIndices, lineNum)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue // Skip malformed or out-of-range rows
+		}
+
+		// Only include rows within the requested range
+		if (from != "" && rowDate < from) || (to != "" && rowDate > to) {
+			continue
+		}
+
+		if day == nil || rowDate != day.Date {
+			if err := flush(); err != nil {
+				return err
+			}
+			if emitted[rowDate] {
+				return fmt.Errorf("rows for %s are not contiguous (line %d)", rowDate, lineNum)
+			}
+
+			day = jepx.NewMarketResponse(rowDate, timescale, areaCodes)
+			day.Source = jepx.Source{
+				Name: "JEPX",
+				URL:  a.sourceURL,
 			}
+		}
+
+		// Build timestamp in Asia/Tokyo timezone
+		point := jepx.MarketPoint{AreaPrices: make(map[string]float64, len(priceCols))}
+		if timescale == jepx.Timescale30Min {
+			point.Timestamp, point.Period = a.buildSlotTimestamp(rowDate, slot), slot+1
 		} else {
-			// Fallback: separate date and hour columns
-			rowDate = strings.TrimSpace(record[colIndices["date"]])
-			rowDate = a.normalizeDate(rowDate)
+			point.Timestamp = a.buildTimestamp(rowDate, slot)
+		}
 
-			hourStr := strings.TrimSpace(record[colIndices["hour"]])
-			hour, err = strconv.Atoi(hourStr)
+		// Parse prices (JPY/kWh)
+		if requireSystem {
+			priceStr := strings.TrimSpace(record[colIndices["system"]])
+			if point.SystemPrice, err = strconv.ParseFloat(priceStr, 64); err != nil {
+				return fmt.Errorf("invalid system price at line %d: %s", lineNum, priceStr)
+			}
+		}
+		for _, pc := range priceCols {
+			priceStr := strings.TrimSpace(record[pc.index])
+			price, err := strconv.ParseFloat(priceStr, 64)
 			if err != nil {
-				return nil, fmt.Errorf("invalid hour at line %d: %s", lineNum, hourStr)
+				return fmt.Errorf("invalid %s price at line %d: %s", pc.area, lineNum, priceStr)
 			}
+			point.AreaPrices[string(pc.area)] = price
 		}
 
-		// Only include rows matching the requested date
-		if rowDate != date {
-			continue
+		// Parse market-wide volumes (kWh in JEPX exports, stored as MWh)
+		if point.Volume, err = a.parseVolume(record, colIndices, header, lineNum); err != nil {
+			point.Volume = nil
+			badVolumes++
 		}
 
-		// Validate hour range
-		if hour < 0 || hour > 23 {
-			continue // Skip out-of-range hours
+		day.Series = append(day.Series, point)
+		slotsSeen[slot] = true
+	}
+
+	return flush()
+}
+
+// volumeColumns maps detectColumns keys to the Volume field they fill.
+var volumeColumns = []string{"sell", "buy", "contracted"}
+
+// parseVolume reads the bid and contracted volume columns of a row, or
+// returns nil if the export has none. Volumes are converted to MWh: JEPX
+// publishes kWh, unless the header says MWh.
+func (a *JEPXAdapter) parseVolume(record []string, colIndices map[string]int, header []string, lineNum int) (*jepx.Volume, error) {
+	var v jepx.Volume
+	found := false
+
+	for _, key := range volumeColumns {
+		idx := colIndices[key]
+		if idx == -1 {
+			continue
 		}
+		found = true
 
-		// Parse price (JPY/kWh)
-		priceStr := strings.TrimSpace(record[colIndices["price"]])
-		price, err := strconv.ParseFloat(priceStr, 64)
+		valueStr := strings.ReplaceAll(strings.TrimSpace(record[idx]), ",", "")
+		value, err := strconv.ParseFloat(valueStr, 64)
 		if err != nil {
-			return nil, fmt.Errorf("invalid price at line %d: %s", lineNum, priceStr)
+			return nil, fmt.Errorf("invalid %s volume at line %d: %s", key, lineNum, valueStr)
+		}
+		if !strings.Contains(strings.ToLower(header[idx]), "mwh") {
+			value /= 1000 // kWh → MWh
 		}
 
-		// Build timestamp in Asia/Tokyo timezone
-		timestamp := a.buildTimestamp(date, hour)
+		switch key {
+		case "sell":
+			v.SellBidMWh = value
+		case "buy":
+			v.BuyBidMWh = value
+		case "contracted":
+			v.ContractedMWh = value
+		}
+	}
+
+	if !found {
+		return nil, nil
+	}
+	return &v, nil
+}
 
-		pricePoint := jepx.PricePoint{
-			Timestamp: timestamp,
-			Price:     price,
+// priceColumn is the CSV column holding an area's spot price.
+type priceColumn struct {
+	area  areas.Code
+	index int
+}
+
+// detectLayout resolves the date/slot/system columns and the price column of
+// every selected area (every JEPX area present in the header if selected is nil).
+func (a *JEPXAdapter) detectLayout(header []string, selected []areas.Area) (map[string]int, []priceColumn, error) {
+	all := selected == nil
+	if all {
+		for _, info := range areas.All() {
+			if info.HasJEPXPrice() {
+				selected = append(selected, info)
+			}
 		}
-		resp.PriceYenPerKwh = append(resp.PriceYenPerKwh, pricePoint)
-		hoursSeen[hour] = true
 	}
 
-	// Validate we have data
-	if len(resp.PriceYenPerKwh) == 0 {
-		return nil, fmt.Errorf("no data found for date %s and area %s", date, area)
+	var colIndices map[string]int
+	priceCols := make([]priceColumn, 0, len(selected))
+
+	for _, info := range selected {
+		if !info.HasJEPXPrice() {
+			return nil, nil, fmt.Errorf("area %s has no JEPX spot price", info.Code)
+		}
+
+		colIndices = a.detectColumns(header, info)
+		if colIndices["price"] == -1 {
+			if all {
+				continue // Area not in this export
+			}
+			return nil, nil, fmt.Errorf("price column for area %s not found in header: %v", info.Code, header)
+		}
+		priceCols = append(priceCols, priceColumn{area: info.Code, index: colIndices["price"]})
+	}
+
+	if colIndices == nil || len(priceCols) == 0 {
+		return nil, nil, fmt.Errorf("no area price column found in header: %v", header)
+	}
+	if colIndices["datetime"] == -1 && colIndices["date"] == -1 {
+		return nil, nil, fmt.Errorf("datetime or date column not found in header: %v", header)
+	}
+	if colIndices["datetime"] == -1 && colIndices["period"] == -1 && colIndices["hour"] == -1 {
+		return nil, nil, fmt.Errorf("datetime, period or hour column not found in header: %v", header)
 	}
 
-	// Add warning if missing hours
-	if len(hoursSeen) < 24 {
-		resp.Meta = &jepx.Meta{
-			Warning: fmt.Sprintf("Data for %d hours available (expected 24)", len(hoursSeen)),
+	return colIndices, priceCols, nil
+}
+
+// parseRowSlot extracts the date and slot (0-47 for 30min, 0-23 for hourly)
+// of a data row. ok is false for rows that should be skipped.
+func (a *JEPXAdapter) parseRowSlot(record []string, colIndices map[string]int, lineNum int) (rowDate string, slot int, ok bool, err error) {
+	switch {
+	case colIndices["datetime"] != -1:
+		// Parse datetime column (format: "2025-11-03 00:30:00")
+		datetimeStr := strings.TrimSpace(record[colIndices["datetime"]])
+		parts := strings.Split(datetimeStr, " ")
+		if len(parts) < 2 {
+			return "", 0, false, nil // Skip malformed datetime
+		}
+		rowDate = a.normalizeDate(parts[0])
+		slot, err = timeutil.ParseSlot(parts[1])
+		if err != nil {
+			return "", 0, false, nil // Skip invalid rows
+		}
+
+	case colIndices["period"] != -1:
+		// Date + period number (コマ 1-48)
+		rowDate = a.normalizeDate(record[colIndices["date"]])
+		periodStr := strings.TrimSpace(record[colIndices["period"]])
+		period, err := strconv.Atoi(periodStr)
+		if err != nil {
+			return "", 0, false, fmt.Errorf("invalid period at line %d: %s", lineNum, periodStr)
+		}
+		slot, err = timeutil.SlotFromPeriod(period)
+		if err != nil {
+			return "", 0, false, nil // Skip out-of-range periods
+		}
+
+	default:
+		// Fallback: separate date and hour columns
+		rowDate = a.normalizeDate(record[colIndices["date"]])
+		hourStr := strings.TrimSpace(record[colIndices["hour"]])
+		slot, err = strconv.Atoi(hourStr)
+		if err != nil {
+			return "", 0, false, fmt.Errorf("invalid hour at line %d: %s", lineNum, hourStr)
+		}
+		if slot < 0 || slot > 23 {
+			return "", 0, false, nil // Skip out-of-range hours
 		}
 	}
 
-	return resp, nil
+	return rowDate, slot, true, nil
 }
 
 // detectColumns finds column indices by header names.
-// Returns map with keys: datetime, date, hour, price.
-func (a *JEPXAdapter) detectColumns(header []string, area string) map[string]int {
+// Returns map with keys: datetime, date, hour, period, system, price,
+// sell, buy, contracted.
+func (a *JEPXAdapter) detectColumns(header []string, area areas.Area) map[string]int {
 	indices := map[string]int{
-		"datetime": -1,
-		"date":     -1,
-		"hour":     -1,
-		"price":    -1,
+		"datetime":   -1,
+		"date":       -1,
+		"hour":       -1,
+		"period":     -1,
+		"system":     -1,
+		"price":      -1,
+		"sell":       -1,
+		"buy":        -1,
+		"contracted": -1,
 	}
 
-	// Determine area-specific price column patterns
-	// Format: "Tokyo Yen/kWh", "Kansai Yen/kWh"
-	areaCapitalized := strings.Title(strings.ToLower(area))
-
 	for i, col := range header {
-		colLower := strings.ToLower(strings.TrimSpace(col))
+		col = strings.TrimSpace(col)
+		colLower := strings.ToLower(col)
 
 		switch {
 		case colLower == "datetime":
@@ -185,12 +426,26 @@ func (a *JEPXAdapter) detectColumns(header []string, area string) map[string]int
 			indices["date"] = i
 		case colLower == "hour" || col == "時" || col == "時刻":
 			indices["hour"] = i
-		case strings.Contains(colLower, strings.ToLower(areaCapitalized)) && strings.Contains(colLower, "yen/kwh"):
-			// Match "Tokyo Yen/kWh", "Kansai Yen/kWh"
+		case colLower == "periodid" || colLower == "period" || col == "時刻コード":
+			indices["period"] = i
+		case strings.HasPrefix(colLower, "system price") || strings.HasPrefix(col, "システムプライス"):
+			// japanesepower.org: "System Price Yen/kWh"; JEPX: "システムプライス(円/kWh)"
+			indices["system"] = i
+		case strings.HasPrefix(colLower, "sell bid") || strings.HasPrefix(col, "売り入札量"):
+			// japanesepower.org: "Sell Bid Qty kWh"; JEPX: "売り入札量(kWh)"
+			indices["sell"] = i
+		case strings.HasPrefix(colLower, "buy bid") || strings.HasPrefix(col, "買い入札量"):
+			indices["buy"] = i
+		case strings.HasPrefix(colLower, "contracted") || strings.HasPrefix(col, "約定総量"):
+			indices["contracted"] = i
+		case strings.EqualFold(col, area.JEPXColumn):
+			// japanesepower.org: "Tokyo Yen/kWh", "Kyushu Yen/kWh"
 			indices["price"] = i
-		case col == "東京価格" && area == "tokyo":
+		case col == area.NameJA+"価格":
+			// Legacy export: "東京価格", "関西価格"
 			indices["price"] = i
-		case col == "関西価格" && area == "kansai":
+		case strings.HasPrefix(col, "エリアプライス"+area.NameJA):
+			// Official JEPX export: "エリアプライス東京(円/kWh)"
 			indices["price"] = i
 		}
 	}
@@ -241,3 +496,14 @@ func (a *JEPXAdapter) buildTimestamp(date string, hour int) string {
 
 	return timestamp.Format(time.RFC3339)
 }
+
+// buildSlotTimestamp creates the ISO8601 start time of a 0-based half-hour slot.
+// Example: slot 31 → "2025-10-23T15:30:00+09:00"
+func (a *JEPXAdapter) buildSlotTimestamp(date string, slot int) string {
+	d, err := timeutil.ParseDate(date)
+	if err != nil {
+		// Fallback to current date if parsing fails
+		d = time.Now()
+	}
+	return timeutil.SlotTime(d, slot).Format(time.RFC3339)
+}
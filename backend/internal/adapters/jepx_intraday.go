@@ -0,0 +1,220 @@
+package adapters
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/teo/aversome/backend/internal/jepx"
+	"github.com/teo/aversome/backend/pkg/timeutil"
+	"golang.org/x/text/encoding/japanese"
+	"golang.org/x/text/transform"
+)
+
+// JEPXIntradayAdapter normalizes JEPX intraday market (時間前市場) results.
+type JEPXIntradayAdapter struct {
+	sourceURL string
+}
+
+// NewJEPXIntradayAdapter creates a new JEPX intraday data adapter.
+func NewJEPXIntradayAdapter() *JEPXIntradayAdapter {
+	return &JEPXIntradayAdapter{
+		sourceURL: "https://www.jepx.jp/electricpower/market-data/intraday/",
+	}
+}
+
+// ParseCSV parses a JEPX intraday trade summary CSV into jepx.IntradayResponse.
+// CSV format (Shift-JIS, one row per 30-minute product):
+//   年月日,時刻コード,始値(円/kWh),高値(円/kWh),安値(円/kWh),終値(円/kWh),平均(円/kWh),約定量合計(MWh/h),約定件数
+//   2025/10/24,1,23.85,24.85,22.65,24.58,24.03,653.2,118
+//
+// Notes:
+// - The yearly file holds every delivery date; rows of other dates are skipped
+// - Products without trades have empty prices and are kept with nil prices
+// - Volume is kept as published (MWh/h)
+func (a *JEPXIntradayAdapter) ParseCSV(reader io.Reader, date string) (*jepx.IntradayResponse, error) {
+	// Convert from Shift-JIS to UTF-8
+	utf8Reader := transform.NewReader(reader, japanese.ShiftJIS.NewDecoder())
+
+	csvReader := csv.NewReader(utf8Reader)
+	csvReader.TrimLeadingSpace = true
+	csvReader.FieldsPerRecord = -1 // Allow variable number of fields
+
+	// Read header
+	header, err := csvReader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+
+	// Auto-detect column indices
+	colIndices := a.detectColumns(header)
+	for _, col := range []string{"date", "period", "avg", "volume"} {
+		if colIndices[col] == -1 {
+			return nil, fmt.Errorf("required column %s not found in header: %v", col, header)
+		}
+	}
+
+	baseDate, err := timeutil.ParseDate(date)
+	if err != nil {
+		return nil, fmt.Errorf("invalid date: %w", err)
+	}
+
+	resp := jepx.NewIntradayResponse(date)
+	resp.Source = jepx.Source{
+		Name: "JEPX Intraday",
+		URL:  a.sourceURL,
+	}
+
+	jepxAdapter := NewJEPXAdapter() // Shared date normalization
+	lineNum := 1
+	seen := make(map[int]bool)
+	untraded := 0
+
+	// Read data rows
+	for {
+		record, err := csvReader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error reading CSV line %d: %w", lineNum, err)
+		}
+		lineNum++
+
+		if len(record) <= colIndices["date"] || jepxAdapter.normalizeDate(record[colIndices["date"]]) != date {
+			continue
+		}
+
+		// Period number (コマ 1-48)
+		periodStr := a.field(record, colIndices["period"])
+		period, err := strconv.Atoi(periodStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid period at line %d: %s", lineNum, periodStr)
+		}
+		slot, err := timeutil.SlotFromPeriod(period)
+		if err != nil || seen[slot] {
+			continue // Skip out-of-range or duplicate products
+		}
+
+		point := jepx.IntradayPoint{
+			Timestamp: timeutil.FormatISO8601(timeutil.SlotTime(baseDate, slot)),
+			Period:    period,
+		}
+
+		// Prices are empty for untraded products
+		for col, dst := range map[string]**float64{
+			"open":  &point.Open,
+			"high":  &point.High,
+			"low":   &point.Low,
+			"close": &point.Close,
+			"avg":   &point.WeightedAvg,
+		} {
+			if *dst, err = a.parseOptional(record, colIndices[col]); err != nil {
+				return nil, fmt.Errorf("invalid %s price at line %d: %w", col, lineNum, err)
+			}
+		}
+
+		volume, err := a.parseOptional(record, colIndices["volume"])
+		if err != nil {
+			return nil, fmt.Errorf("invalid volume at line %d: %w", lineNum, err)
+		}
+		if volume != nil {
+			point.VolumeMWh = *volume
+		}
+		if trades, err := a.parseOptional(record, colIndices["trades"]); err == nil && trades != nil {
+			point.Trades = int(*trades)
+		}
+
+		if point.WeightedAvg == nil {
+			untraded++
+		}
+		resp.Products = append(resp.Products, point)
+		seen[slot] = true
+	}
+
+	// Validate we have data
+	if len(resp.Products) == 0 {
+		return nil, fmt.Errorf("no intraday data found for date %s", date)
+	}
+
+	// Add warning if products are missing or untraded
+	var warnings []string
+	if len(seen) < timeutil.SlotsPerDay {
+		warnings = append(warnings, fmt.Sprintf("Data for %d products available (expected %d)", len(seen), timeutil.SlotsPerDay))
+	}
+	if untraded > 0 {
+		warnings = append(warnings, fmt.Sprintf("%d products without trades", untraded))
+	}
+	if len(warnings) > 0 {
+		resp.Meta = &jepx.Meta{Warning: strings.Join(warnings, "; ")}
+	}
+
+	return resp, nil
+}
+
+// detectColumns finds column indices by header names.
+// Returns map with keys: date, period, open, high, low, close, avg, volume, trades.
+func (a *JEPXIntradayAdapter) detectColumns(header []string) map[string]int {
+	indices := map[string]int{
+		"date":   -1,
+		"period": -1,
+		"open":   -1,
+		"high":   -1,
+		"low":    -1,
+		"close":  -1,
+		"avg":    -1,
+		"volume": -1,
+		"trades": -1,
+	}
+
+	for i, col := range header {
+		col = strings.TrimSpace(strings.TrimPrefix(col, "\ufeff"))
+		colLower := strings.ToLower(col)
+
+		switch {
+		case col == "年月日" || col == "受渡日" || colLower == "date":
+			indices["date"] = i
+		case col == "時刻コード" || colLower == "period" || colLower == "periodid":
+			indices["period"] = i
+		case strings.HasPrefix(col, "始値") || strings.HasPrefix(colLower, "open"):
+			indices["open"] = i
+		case strings.HasPrefix(col, "高値") || strings.HasPrefix(colLower, "high"):
+			indices["high"] = i
+		case strings.HasPrefix(col, "安値") || strings.HasPrefix(colLower, "low"):
+			indices["low"] = i
+		case strings.HasPrefix(col, "終値") || strings.HasPrefix(colLower, "close"):
+			indices["close"] = i
+		case strings.HasPrefix(col, "平均") || strings.HasPrefix(colLower, "average"):
+			indices["avg"] = i
+		case strings.HasPrefix(col, "約定量") || strings.HasPrefix(colLower, "volume"):
+			indices["volume"] = i
+		case strings.HasPrefix(col, "約定件数") || strings.HasPrefix(colLower, "trades"):
+			indices["trades"] = i
+		}
+	}
+
+	return indices
+}
+
+// field returns a trimmed CSV field, or "" if the row is short or the column missing.
+func (a *JEPXIntradayAdapter) field(record []string, idx int) string {
+	if idx < 0 || idx >= len(record) {
+		return ""
+	}
+	return strings.TrimSpace(record[idx])
+}
+
+// parseOptional parses a numeric field; empty fields return nil.
+func (a *JEPXIntradayAdapter) parseOptional(record []string, idx int) (*float64, error) {
+	s := a.field(record, idx)
+	if s == "" || s == "-" {
+		return nil, nil
+	}
+	v, err := strconv.ParseFloat(strings.ReplaceAll(s, ",", ""), 64)
+	if err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
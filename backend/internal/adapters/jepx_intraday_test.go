@@ -0,0 +1,117 @@
+package adapters
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/teo/aversome/backend/internal/jepx"
+)
+
+func TestJEPXIntradayAdapter_ParseCSV(t *testing.T) {
+	tests := []struct {
+		name         string
+		date         string
+		wantErr      bool
+		wantProducts int
+	}{
+		{name: "valid intraday CSV", date: "2025-10-24", wantProducts: 48},
+		{name: "date not in file", date: "2025-10-25", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := os.Open("testdata/jepx-intraday-sample.csv")
+			if err != nil {
+				t.Fatalf("Failed to open test CSV: %v", err)
+			}
+			defer f.Close()
+
+			resp, err := NewJEPXIntradayAdapter().ParseCSV(f, tt.date)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseCSV() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if len(resp.Products) != tt.wantProducts {
+				t.Fatalf("Got %d products, want %d", len(resp.Products), tt.wantProducts)
+			}
+			if resp.Timescale != "30min" {
+				t.Errorf("Timescale = %v, want 30min", resp.Timescale)
+			}
+
+			first := resp.Products[0]
+			if first.Period != 1 || first.Timestamp != "2025-10-24T00:00:00+09:00" {
+				t.Errorf("First product = %d at %s", first.Period, first.Timestamp)
+			}
+			if first.Open == nil || *first.Open != 23.85 || *first.High != 24.85 || *first.Low != 22.65 ||
+				*first.Close != 24.58 || *first.WeightedAvg != 24.03 {
+				t.Errorf("First product OHLC/avg = %+v", first)
+			}
+			if first.VolumeMWh != 653.2 || first.Trades != 118 {
+				t.Errorf("First product volume = %v, trades = %d", first.VolumeMWh, first.Trades)
+			}
+
+			// Period 48 has no trades
+			last := resp.Products[47]
+			if last.Period != 48 || last.WeightedAvg != nil || last.Close != nil || last.VolumeMWh != 0 {
+				t.Errorf("Last product = %+v, want untraded", last)
+			}
+			if resp.Meta == nil || !strings.Contains(resp.Meta.Warning, "1 products without trades") {
+				t.Errorf("expected untraded warning, got %+v", resp.Meta)
+			}
+		})
+	}
+}
+
+func TestJEPXIntraday_Compare(t *testing.T) {
+	f, err := os.Open("testdata/jepx-intraday-sample.csv")
+	if err != nil {
+		t.Fatalf("Failed to open test CSV: %v", err)
+	}
+	defer f.Close()
+	intraday, err := NewJEPXIntradayAdapter().ParseCSV(f, "2025-10-24")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g, err := os.Open("testdata/jepx-sample.csv")
+	if err != nil {
+		t.Fatalf("Failed to open test CSV: %v", err)
+	}
+	defer g.Close()
+	market, err := NewJEPXAdapter().ParseMarketCSV(g, "2025-10-24")
+	if err != nil {
+		t.Fatal(err)
+	}
+	system, err := market.AreaResponse(jepx.SystemArea)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cmp, err := jepx.Compare(intraday, system)
+	if err != nil {
+		t.Fatalf("Compare() error = %v", err)
+	}
+	if cmp.Area != "system" || len(cmp.Periods) != 48 || cmp.Meta.TradedProducts != 47 {
+		t.Fatalf("Compare() area=%s periods=%d traded=%d", cmp.Area, len(cmp.Periods), cmp.Meta.TradedProducts)
+	}
+
+	first := cmp.Periods[0]
+	if first.Premium == nil || *first.Premium < 24.03-first.DayAheadPrice-1e-9 || *first.Premium > 24.03-first.DayAheadPrice+1e-9 {
+		t.Errorf("Period 1 premium = %v, day-ahead %v", first.Premium, first.DayAheadPrice)
+	}
+	if cmp.Periods[47].Premium != nil {
+		t.Error("untraded period should have no premium")
+	}
+	if cmp.Meta.TotalVolumeMWh <= 0 || cmp.Meta.VolumeWeightedAvg <= 0 {
+		t.Errorf("Meta = %+v", cmp.Meta)
+	}
+
+	hourly, _ := system.Resample("hourly")
+	if _, err := jepx.Compare(intraday, hourly); err == nil {
+		t.Error("expected error comparing with hourly day-ahead prices")
+	}
+}
@@ -4,6 +4,9 @@ import (
 	"os"
 	"strings"
 	"testing"
+
+	"github.com/teo/aversome/backend/internal/areas"
+	"github.com/teo/aversome/backend/internal/jepx"
 )
 
 func TestJEPXAdapter_ParseCSV(t *testing.T) {
@@ -13,9 +16,9 @@ func TestJEPXAdapter_ParseCSV(t *testing.T) {
 		date        string
 		area        string
 		wantErr     bool
-		wantHours   int
-		wantPrice0  float64 // Price at hour 0
-		wantPrice23 float64 // Price at hour 23
+		wantPeriods int
+		wantPrice0  float64 // Price at period 1 (00:00)
+		wantPrice23 float64 // Price at period 48 (23:30)
 	}{
 		{
 			name:        "valid CSV with Tokyo prices",
@@ -23,7 +26,7 @@ func TestJEPXAdapter_ParseCSV(t *testing.T) {
 			date:        "2025-10-23",
 			area:        "tokyo",
 			wantErr:     false,
-			wantHours:   24,
+			wantPeriods: 48,
 			wantPrice0:  24.32,
 			wantPrice23: 25.60,
 		},
@@ -33,10 +36,27 @@ func TestJEPXAdapter_ParseCSV(t *testing.T) {
 			date:        "2025-10-23",
 			area:        "kansai",
 			wantErr:     false,
-			wantHours:   24,
+			wantPeriods: 48,
+			wantPrice0:  23.15,
+			wantPrice23: 24.80,
+		},
+		{
+			name:        "valid CSV with Kyushu prices",
+			csvPath:     "testdata/jepx-sample.csv",
+			date:        "2025-10-23",
+			area:        "kyushu",
+			wantErr:     false,
+			wantPeriods: 48,
 			wantPrice0:  23.15,
 			wantPrice23: 24.80,
 		},
+		{
+			name:    "Okinawa has no spot price",
+			csvPath: "testdata/jepx-sample.csv",
+			date:    "2025-10-23",
+			area:    "okinawa",
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -68,13 +88,13 @@ func TestJEPXAdapter_ParseCSV(t *testing.T) {
 				t.Errorf("Area = %v, want %v", resp.Area, tt.area)
 			}
 
-			if resp.Timescale != "hourly" {
-				t.Errorf("Timescale = %v, want hourly", resp.Timescale)
+			if resp.Timescale != "30min" {
+				t.Errorf("Timescale = %v, want 30min", resp.Timescale)
 			}
 
 			// Validate number of price points
-			if len(resp.PriceYenPerKwh) != tt.wantHours {
-				t.Errorf("Got %d price points, want %d", len(resp.PriceYenPerKwh), tt.wantHours)
+			if len(resp.PriceYenPerKwh) != tt.wantPeriods {
+				t.Errorf("Got %d price points, want %d", len(resp.PriceYenPerKwh), tt.wantPeriods)
 			}
 
 			// Validate first price point
@@ -98,6 +118,12 @@ func TestJEPXAdapter_ParseCSV(t *testing.T) {
 				if last.Price != tt.wantPrice23 {
 					t.Errorf("Last price = %v, want %v", last.Price, tt.wantPrice23)
 				}
+				if !strings.Contains(last.Timestamp, "T23:30:00") {
+					t.Errorf("Last timestamp = %v, want 23:30 slot", last.Timestamp)
+				}
+				if last.Period != 48 {
+					t.Errorf("Last period = %d, want 48", last.Period)
+				}
 			}
 
 			// Validate source attribution
@@ -111,6 +137,288 @@ func TestJEPXAdapter_ParseCSV(t *testing.T) {
 	}
 }
 
+func TestJEPXAdapter_ParseCSVBatch(t *testing.T) {
+	f, err := os.Open("testdata/jepx-sample.csv")
+	if err != nil {
+		t.Fatalf("Failed to open test CSV: %v", err)
+	}
+	defer f.Close()
+
+	// All areas, all dates in one pass
+	var got []string
+	err = NewJEPXAdapter().ParseCSVBatch(f, "", "", nil, func(r *jepx.Response) error {
+		if len(r.PriceYenPerKwh) != 48 || r.Meta != nil {
+			t.Errorf("%s/%s: %d points, meta %+v", r.Date, r.Area, len(r.PriceYenPerKwh), r.Meta)
+		}
+		got = append(got, r.Date+"/"+r.Area)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ParseCSVBatch() error = %v", err)
+	}
+	if len(got) != 27 {
+		t.Fatalf("Got %d responses, want 27 (3 dates × 9 areas)", len(got))
+	}
+	if got[0] != "2025-10-22/hokkaido" || got[26] != "2025-10-24/kyushu" {
+		t.Errorf("Emit order = %s ... %s", got[0], got[26])
+	}
+
+	// Range and area subset
+	f.Seek(0, 0)
+	got = nil
+	kansai, _ := areas.Lookup(areas.Kansai)
+	tokyo, _ := areas.Lookup(areas.Tokyo)
+	selected := []areas.Area{kansai, tokyo}
+	err = NewJEPXAdapter().ParseCSVBatch(f, "2025-10-23", "2025-10-30", selected, func(r *jepx.Response) error {
+		got = append(got, r.Date+"/"+r.Area)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ParseCSVBatch() error = %v", err)
+	}
+	want := []string{"2025-10-23/kansai", "2025-10-23/tokyo", "2025-10-24/kansai", "2025-10-24/tokyo"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("Emitted %v, want %v", got, want)
+	}
+}
+
+func TestJEPXAdapter_ParseCSVBatch_NotContiguous(t *testing.T) {
+	csvData := "Date,Hour,Tokyo Yen/kWh\n" +
+		"2025-10-23,0,10.5\n" +
+		"2025-10-24,0,11.5\n" +
+		"2025-10-23,1,12.5\n"
+
+	err := NewJEPXAdapter().ParseCSVBatch(strings.NewReader(csvData), "", "", nil, func(*jepx.Response) error { return nil })
+	if err == nil || !strings.Contains(err.Error(), "not contiguous") {
+		t.Errorf("ParseCSVBatch() error = %v, want not contiguous", err)
+	}
+}
+
+func TestJEPXAdapter_ParseMarketCSV(t *testing.T) {
+	f, err := os.Open("testdata/jepx-sample.csv")
+	if err != nil {
+		t.Fatalf("Failed to open test CSV: %v", err)
+	}
+	defer f.Close()
+
+	market, err := NewJEPXAdapter().ParseMarketCSV(f, "2025-10-23")
+	if err != nil {
+		t.Fatalf("ParseMarketCSV() error = %v", err)
+	}
+
+	if len(market.Areas) != 9 || market.Areas[0] != "hokkaido" || market.Areas[8] != "kyushu" {
+		t.Errorf("Areas = %v, want hokkaido..kyushu", market.Areas)
+	}
+	if len(market.Series) != 48 || market.Meta != nil {
+		t.Fatalf("Got %d periods (meta %+v), want 48", len(market.Series), market.Meta)
+	}
+
+	first := market.Series[0]
+	if first.Period != 1 || first.SystemPrice != 23.56 || first.AreaPrices["tokyo"] != 24.32 || first.AreaPrices["kansai"] != 23.15 {
+		t.Errorf("Period 1 = %+v, want system 23.56, tokyo 24.32, kansai 23.15", first)
+	}
+
+	// Area extraction matches the single-area parser
+	tokyo, err := market.AreaResponse("tokyo")
+	if err != nil {
+		t.Fatalf("AreaResponse(tokyo) error = %v", err)
+	}
+	if tokyo.Area != "tokyo" || len(tokyo.PriceYenPerKwh) != 48 || tokyo.PriceYenPerKwh[47].Price != 25.60 {
+		t.Errorf("AreaResponse(tokyo) = %s with %d points", tokyo.Area, len(tokyo.PriceYenPerKwh))
+	}
+	system, err := market.AreaResponse(jepx.SystemArea)
+	if err != nil || system.PriceYenPerKwh[0].Price != 23.56 {
+		t.Errorf("AreaResponse(system) = %+v, %v", system, err)
+	}
+	if _, err := market.AreaResponse("okinawa"); err == nil {
+		t.Error("expected error for area without prices")
+	}
+
+	// Hour 0 averages periods 1 and 2
+	hourly, err := market.Resample("hourly")
+	if err != nil {
+		t.Fatalf("Resample() error = %v", err)
+	}
+	if len(hourly.Series) != 24 {
+		t.Fatalf("Got %d hourly points, want 24", len(hourly.Series))
+	}
+	if got := hourly.Series[0].SystemPrice; got < 23.414 || got > 23.416 {
+		t.Errorf("Hour 0 system price = %v, want 23.415", got)
+	}
+	if got := hourly.Series[0].AreaPrices["tokyo"]; got < 24.169 || got > 24.171 {
+		t.Errorf("Hour 0 tokyo price = %v, want 24.17", got)
+	}
+}
+
+func TestJEPXAdapter_ParseMarketCSV_NoSystemColumn(t *testing.T) {
+	csvData := "Date,Hour,Tokyo Yen/kWh\n" +
+		"2025-10-23,0,10.5\n"
+
+	if _, err := NewJEPXAdapter().ParseMarketCSV(strings.NewReader(csvData), "2025-10-23"); err == nil {
+		t.Error("expected error without a system price column")
+	}
+}
+
+func TestJEPXAdapter_ParseCSV_HourlyLayout(t *testing.T) {
+	csvData := "Date,Hour,Tokyo Yen/kWh\n" +
+		"2025-10-23,0,10.5\n" +
+		"2025-10-23,1,11.5\n"
+
+	adapter := NewJEPXAdapter()
+	resp, err := adapter.ParseCSV(strings.NewReader(csvData), "2025-10-23", "tokyo")
+	if err != nil {
+		t.Fatalf("ParseCSV() error = %v", err)
+	}
+
+	if resp.Timescale != "hourly" {
+		t.Errorf("Timescale = %v, want hourly", resp.Timescale)
+	}
+	if len(resp.PriceYenPerKwh) != 2 {
+		t.Fatalf("Got %d price points, want 2", len(resp.PriceYenPerKwh))
+	}
+	if resp.Meta == nil || !strings.Contains(resp.Meta.Warning, "2 hours") {
+		t.Errorf("expected missing-hours warning, got %+v", resp.Meta)
+	}
+}
+
+func TestJEPXAdapter_Resample(t *testing.T) {
+	f, err := os.Open("testdata/jepx-sample.csv")
+	if err != nil {
+		t.Fatalf("Failed to open test CSV: %v", err)
+	}
+	defer f.Close()
+
+	resp, err := NewJEPXAdapter().ParseCSV(f, "2025-10-23", "tokyo")
+	if err != nil {
+		t.Fatalf("ParseCSV() error = %v", err)
+	}
+
+	hourly, err := resp.Resample("hourly")
+	if err != nil {
+		t.Fatalf("Resample() error = %v", err)
+	}
+	if len(hourly.PriceYenPerKwh) != 24 {
+		t.Fatalf("Got %d hourly points, want 24", len(hourly.PriceYenPerKwh))
+	}
+
+	// Hour 23 averages 23:00 and 23:30 (both 25.60)
+	last := hourly.PriceYenPerKwh[23]
+	if last.Price != 25.60 || !strings.Contains(last.Timestamp, "T23:00:00+09:00") {
+		t.Errorf("Hour 23 = %+v, want 25.60 at 23:00", last)
+	}
+	if resp.Timescale != "30min" {
+		t.Error("Resample() modified the receiver")
+	}
+
+	if _, err := hourly.Resample("30min"); err == nil {
+		t.Error("expected error upsampling hourly data")
+	}
+}
+
+func TestJEPXAdapter_ParseCSV_Volumes(t *testing.T) {
+	f, err := os.Open("testdata/jepx-sample.csv")
+	if err != nil {
+		t.Fatalf("Failed to open test CSV: %v", err)
+	}
+	defer f.Close()
+
+	resp, err := NewJEPXAdapter().ParseCSV(f, "2025-10-23", "kansai")
+	if err != nil {
+		t.Fatalf("ParseCSV() error = %v", err)
+	}
+
+	// kWh in the CSV, MWh in the response
+	first := resp.PriceYenPerKwh[0].Volume
+	want := jepx.Volume{SellBidMWh: 17960.4, BuyBidMWh: 14796, ContractedMWh: 12808.8}
+	if first == nil || *first != want {
+		t.Fatalf("period 1 volume = %+v, want %+v", first, want)
+	}
+
+	hourly, err := resp.Resample("hourly")
+	if err != nil {
+		t.Fatalf("Resample() error = %v", err)
+	}
+	if got := hourly.PriceYenPerKwh[0].Volume.ContractedMWh; got < 25703.6 || got > 25703.8 {
+		t.Errorf("hour 0 contracted = %v, want 25703.7 (sum of both periods)", got)
+	}
+
+	liq, err := resp.Liquidity()
+	if err != nil {
+		t.Fatalf("Liquidity() error = %v", err)
+	}
+	if len(liq.Periods) != 48 || liq.Meta != nil {
+		t.Errorf("Liquidity() = %d periods, meta %+v; want 48 without warning", len(liq.Periods), liq.Meta)
+	}
+	if p := liq.Periods[0]; p.BidImbalance >= 0 || p.BuyFilledPct <= p.SellFilledPct {
+		t.Errorf("period 1 = %+v, want sell-heavy bids", p)
+	}
+	day := liq.Day
+	if day.Periods != 48 || day.VWAP <= 0 || day.MinContracted.ContractedMWh > day.MaxContracted.ContractedMWh {
+		t.Errorf("daily stats = %+v", day)
+	}
+}
+
+func TestJEPXAdapter_Liquidity_NoVolumes(t *testing.T) {
+	csvData := "Date,Hour,Tokyo Yen/kWh\n" +
+		"2025-10-23,0,10.5\n"
+
+	resp, err := NewJEPXAdapter().ParseCSV(strings.NewReader(csvData), "2025-10-23", "tokyo")
+	if err != nil {
+		t.Fatalf("ParseCSV() error = %v", err)
+	}
+	if resp.PriceYenPerKwh[0].Volume != nil {
+		t.Errorf("Volume = %+v, want nil without volume columns", resp.PriceYenPerKwh[0].Volume)
+	}
+	if _, err := resp.Liquidity(); err == nil {
+		t.Error("expected error without volume data")
+	}
+}
+
+func TestJEPXAdapter_ParseCSV_BlankSystemPrice(t *testing.T) {
+	csvData := "Date,Hour,System Price Yen/kWh,Tokyo Yen/kWh\n" +
+		"2025-10-23,0,9.5,10.5\n" +
+		"2025-10-23,1,,11.5\n"
+
+	// Area prices do not need the system price
+	resp, err := NewJEPXAdapter().ParseCSV(strings.NewReader(csvData), "2025-10-23", "tokyo")
+	if err != nil {
+		t.Fatalf("ParseCSV() error = %v", err)
+	}
+	if len(resp.PriceYenPerKwh) != 2 || resp.PriceYenPerKwh[1].Price != 11.5 {
+		t.Errorf("prices = %+v, want 2 hours", resp.PriceYenPerKwh)
+	}
+
+	// Market data does
+	_, err = NewJEPXAdapter().ParseMarketCSV(strings.NewReader(csvData), "2025-10-23")
+	if err == nil || !strings.Contains(err.Error(), "invalid system price at line 3") {
+		t.Errorf("ParseMarketCSV() error = %v, want invalid system price", err)
+	}
+}
+
+func TestJEPXAdapter_ParseCSV_BlankVolume(t *testing.T) {
+	csvData := "Date,Hour,Tokyo Yen/kWh,Sell Bid Qty kWh,Buy Bid Qty kWh,Contracted Qty kWh\n" +
+		"2025-10-23,0,10.5,18000000,14000000,12000000\n" +
+		"2025-10-23,1,11.5,,14000000,12000000\n" +
+		"2025-10-23,2,12.5,18000000,-,12000000\n"
+
+	resp, err := NewJEPXAdapter().ParseCSV(strings.NewReader(csvData), "2025-10-23", "tokyo")
+	if err != nil {
+		t.Fatalf("ParseCSV() error = %v", err)
+	}
+	p := resp.PriceYenPerKwh
+	if len(p) != 3 || p[0].Volume == nil || p[1].Volume != nil || p[2].Volume != nil {
+		t.Fatalf("prices = %+v, want 3 hours with volume for hour 0 only", p)
+	}
+	if resp.Meta == nil || !strings.Contains(resp.Meta.Warning, "Invalid volume data for 2 hours") {
+		t.Errorf("Meta = %+v, want invalid volume warning", resp.Meta)
+	}
+
+	liq, err := resp.Liquidity()
+	if err != nil || len(liq.Periods) != 1 {
+		t.Errorf("Liquidity() = %+v, %v; want the hour with volume", liq, err)
+	}
+}
+
 func TestJEPXAdapter_normalizeDate(t *testing.T) {
 	adapter := NewJEPXAdapter()
 
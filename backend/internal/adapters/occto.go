@@ -5,13 +5,16 @@ import (
 	"encoding/csv"
 	"fmt"
 	"io"
+	"math"
+	"sort"
 	"strconv"
 	"strings"
-	"time"
 
+	"github.com/teo/aversome/backend/internal/areas"
 	"github.com/teo/aversome/backend/internal/demand"
 	"github.com/teo/aversome/backend/internal/generation"
 	"github.com/teo/aversome/backend/internal/reserve"
+	"github.com/teo/aversome/backend/pkg/timeutil"
 )
 
 // OCCTOAdapter normalizes OCCTO (Organization for Cross-regional Coordination of
@@ -36,7 +39,10 @@ func NewOCCTOAdapter() *OCCTOAdapter {
 //
 // Notes:
 // - First line is timestamp (skip it)
-// - 30-minute intervals (we aggregate to daily averages)
+// - 30-minute intervals: kept as a per-area series (時刻 = interval start) and
+//   aggregated to the daily average; the tightest interval is the daily minimum
+// - ブロックNo groups the areas OCCTO coupled into a wide-area block for the
+//   interval; 広域ブロック需要/供給力 give the block margin (Response.Blocks)
 // - エリア名 = area name, エリア需要(MW) = demand, エリア供給力(MW) = capacity
 // - Reserve margin calculated: (capacity - demand) / capacity * 100
 func (a *OCCTOAdapter) ParseCSV(reader io.Reader, date string) (*reserve.Response, error) {
@@ -51,6 +57,12 @@ func (a *OCCTOAdapter) ParseCSV(reader io.Reader, date string) (*reserve.Respons
 		return nil, fmt.Errorf("failed to read first line: %w", err)
 	}
 
+	return a.parseReserve(csvReader, date)
+}
+
+// parseReserve parses the header and rows of an OCCTO reserve CSV (actual
+// or next-day outlook) after the UPDATE line.
+func (a *OCCTOAdapter) parseReserve(csvReader *csv.Reader, date string) (*reserve.Response, error) {
 	// Read header (second line)
 	header, err := csvReader.Read()
 	if err != nil {
@@ -72,13 +84,31 @@ func (a *OCCTOAdapter) ParseCSV(reader io.Reader, date string) (*reserve.Respons
 		URL:  a.sourceURL,
 	}
 
+	// Per-interval series need the 時刻 column and a valid date
+	baseDate, dateErr := timeutil.ParseDate(date)
+	withSeries := colIndices["time"] != -1 && dateErr == nil
+	if withSeries {
+		resp.Timescale = timeutil.Timescale30Min
+	}
+
+	// Wide-area blocks need the series plus ブロックNo and the block totals
+	withBlocks := withSeries && colIndices["block"] != -1 &&
+		colIndices["block_demand"] != -1 && colIndices["block_capacity"] != -1
+
 	lineNum := 1
 	// Aggregate data by area (multiple 30-min intervals per area)
-	areaData := make(map[string]struct {
+	type areaAgg struct {
 		demandSum   float64
 		capacitySum float64
 		count       int
-	})
+		slots       map[int]reserve.ReservePoint
+	}
+	areaData := make(map[string]*areaAgg)
+
+	// Block totals by interval and block number, and each area's block per interval
+	type blockKey struct{ slot, no int }
+	blockData := make(map[blockKey]reserve.Block)
+	blockOf := make(map[int]map[string]int)
 
 	// Normalize date format (2025/11/03 → 2025-11-03)
 	normalizedDate := strings.ReplaceAll(date, "-", "/")
@@ -125,15 +155,79 @@ func (a *OCCTOAdapter) ParseCSV(reader io.Reader, date string) (*reserve.Respons
 
 		// Aggregate by area
 		data := areaData[area]
+		if data == nil {
+			data = &areaAgg{slots: make(map[int]reserve.ReservePoint)}
+			areaData[area] = data
+		}
 		data.demandSum += demand
 		data.capacitySum += capacity
 		data.count++
-		areaData[area] = data
+
+		// Keep the interval for the series (time is the interval start)
+		if !withSeries {
+			continue
+		}
+		slot, err := timeutil.ParseSlot(strings.TrimSpace(record[colIndices["time"]]))
+		if err != nil {
+			continue
+		}
+		data.slots[slot] = reserve.NewPoint(timeutil.SlotTime(baseDate, slot), demand, capacity)
+
+		// Block totals repeat on every area row of the block; keep the first
+		if withBlocks {
+			no, errNo := strconv.Atoi(strings.TrimSpace(record[colIndices["block"]]))
+			blockDemand, errDemand := strconv.ParseFloat(strings.TrimSpace(record[colIndices["block_demand"]]), 64)
+			blockCapacity, errCapacity := strconv.ParseFloat(strings.TrimSpace(record[colIndices["block_capacity"]]), 64)
+			if errNo != nil || errDemand != nil || errCapacity != nil {
+				continue
+			}
+			key := blockKey{slot, no}
+			if _, seen := blockData[key]; !seen {
+				blockData[key] = reserve.NewBlock(no, blockDemand, blockCapacity)
+			}
+			if blockOf[slot] == nil {
+				blockOf[slot] = make(map[string]int)
+			}
+			blockOf[slot][area] = no
+		}
+	}
+
+	// Group areas into blocks per interval (registry order within a block)
+	if withBlocks {
+		for slot := 0; slot < timeutil.SlotsPerDay; slot++ {
+			if len(blockOf[slot]) == 0 {
+				continue
+			}
+			var blocks []reserve.Block
+			index := make(map[int]int)
+			for _, code := range areas.Codes() {
+				no, ok := blockOf[slot][string(code)]
+				if !ok {
+					continue
+				}
+				i, ok := index[no]
+				if !ok {
+					i = len(blocks)
+					index[no] = i
+					blocks = append(blocks, blockData[blockKey{slot, no}])
+				}
+				blocks[i].Areas = append(blocks[i].Areas, string(code))
+			}
+			sort.Slice(blocks, func(i, j int) bool { return blocks[i].No < blocks[j].No })
+
+			resp.Blocks = append(resp.Blocks, reserve.BlockInterval{
+				Timestamp: timeutil.FormatISO8601(timeutil.SlotTime(baseDate, slot)),
+				Blocks:    blocks,
+			})
+		}
 	}
 
-	// Calculate averages and reserve margins
-	for area, data := range areaData {
-		if data.count == 0 {
+	// Calculate averages and reserve margins (in registry order for stable output)
+	var partial []string
+	for _, code := range areas.Codes() {
+		area := string(code)
+		data, exists := areaData[area]
+		if !exists || data.count == 0 {
 			continue
 		}
 
@@ -141,10 +235,7 @@ func (a *OCCTOAdapter) ParseCSV(reader io.Reader, date string) (*reserve.Respons
 		avgCapacity := data.capacitySum / float64(data.count)
 
 		// Calculate reserve margin: (capacity - demand) / capacity * 100
-		reservePct := 0.0
-		if avgCapacity > 0 {
-			reservePct = ((avgCapacity - avgDemand) / avgCapacity) * 100
-		}
+		reservePct := reserve.MarginPct(avgDemand, avgCapacity)
 
 		// Derive status from percentage
 		status := reserve.DeriveStatus(reservePct)
@@ -154,9 +245,36 @@ func (a *OCCTOAdapter) ParseCSV(reader io.Reader, date string) (*reserve.Respons
 			ReserveMarginPct: reservePct,
 			Status:           status,
 		}
+
+		// Series in slot order, minimum over the series
+		if withSeries {
+			for slot := 0; slot < timeutil.SlotsPerDay; slot++ {
+				p, ok := data.slots[slot]
+				if !ok {
+					continue
+				}
+				if no, ok := blockOf[slot][area]; ok {
+					blockPct := blockData[blockKey{slot, no}].ReserveMarginPct
+					p.Block, p.BlockMarginPct = no, &blockPct
+				}
+				areaReserve.Series = append(areaReserve.Series, p)
+			}
+			areaReserve.Minimum = reserve.FindMinimum(areaReserve.Series)
+			if n := len(areaReserve.Series); n < timeutil.SlotsPerDay {
+				partial = append(partial, fmt.Sprintf("%s %d/%d", area, n, timeutil.SlotsPerDay))
+			}
+		}
+
 		resp.Areas = append(resp.Areas, areaReserve)
 	}
 
+	// Add warning if intervals are missing
+	if len(partial) > 0 {
+		resp.Meta = &reserve.Meta{
+			Warning: fmt.Sprintf("Incomplete intervals: %s", strings.Join(partial, ", ")),
+		}
+	}
+
 	// Validate we have data
 	if len(resp.Areas) == 0 {
 		return nil, fmt.Errorf("no data found for date %s", date)
@@ -166,9 +284,15 @@ func (a *OCCTOAdapter) ParseCSV(reader io.Reader, date string) (*reserve.Respons
 }
 
 // ParseDemandCSV parses OCCTO CSV data into demand.Response for a specific area.
-// Uses the same CSV format as ParseCSV but extracts hourly demand time-series.
-// Aggregates 30-minute intervals into hourly averages.
+// Uses the same CSV format as ParseCSV but extracts the demand time-series.
+// Keeps OCCTO's native 30-minute resolution (timescale "30min", 48 points);
+// use Response.Resample for hourly data.
 func (a *OCCTOAdapter) ParseDemandCSV(reader io.Reader, date string, targetArea demand.Area) (*demand.Response, error) {
+	target := a.normalizeArea(string(targetArea))
+	if target == "" {
+		return nil, fmt.Errorf("unknown area: %s", targetArea)
+	}
+
 	csvReader := csv.NewReader(reader)
 	csvReader.TrimLeadingSpace = true
 	csvReader.LazyQuotes = true
@@ -192,7 +316,12 @@ func (a *OCCTOAdapter) ParseDemandCSV(reader io.Reader, date string, targetArea
 		return nil, fmt.Errorf("required columns not found in header: %v", header)
 	}
 
-	resp := demand.NewResponse(targetArea, date)
+	baseDate, err := timeutil.ParseDate(date)
+	if err != nil {
+		return nil, fmt.Errorf("invalid date: %w", err)
+	}
+
+	resp := demand.NewResponseWithTimescale(demand.Area(target), date, demand.Timescale30Min)
 	resp.Source = demand.Source{
 		Name: "OCCTO",
 		URL:  a.sourceURL,
@@ -201,11 +330,8 @@ func (a *OCCTOAdapter) ParseDemandCSV(reader io.Reader, date string, targetArea
 	// Normalize date format (2025-11-03 → 2025/11/03)
 	normalizedDate := strings.ReplaceAll(date, "-", "/")
 
-	// Aggregate 30-minute intervals into hourly data
-	hourlyData := make(map[int]struct {
-		demandSum float64
-		count     int
-	})
+	// Demand by 30-minute slot (0-47)
+	slotData := make(map[int]float64)
 
 	lineNum := 1
 	for {
@@ -229,19 +355,13 @@ func (a *OCCTOAdapter) ParseDemandCSV(reader io.Reader, date string, targetArea
 		area := a.normalizeArea(areaStr)
 
 		// Only process the target area
-		if string(targetArea) != area {
+		if target != area {
 			continue
 		}
 
 		// Extract time (format: "00:30", "01:00", etc.)
 		timeStr := strings.TrimSpace(record[colIndices["time"]])
-
-		// Parse hour from time
-		timeParts := strings.Split(timeStr, ":")
-		if len(timeParts) < 1 {
-			continue
-		}
-		hour, err := strconv.Atoi(timeParts[0])
+		slot, err := timeutil.ParseSlot(timeStr)
 		if err != nil {
 			continue
 		}
@@ -253,33 +373,19 @@ func (a *OCCTOAdapter) ParseDemandCSV(reader io.Reader, date string, targetArea
 			continue
 		}
 
-		// Aggregate into hourly buckets
-		data := hourlyData[hour]
-		data.demandSum += demandVal
-		data.count++
-		hourlyData[hour] = data
+		slotData[slot] = demandVal
 	}
 
-	// Convert aggregated data to SeriesPoints
-	location, _ := time.LoadLocation("Asia/Tokyo")
-	baseDate, _ := time.Parse("2006-01-02", date)
-
-	for hour := 0; hour < 24; hour++ {
-		data, exists := hourlyData[hour]
-		if !exists || data.count == 0 {
+	// Convert slot data to SeriesPoints
+	for slot := 0; slot < timeutil.SlotsPerDay; slot++ {
+		demandVal, exists := slotData[slot]
+		if !exists {
 			continue
 		}
 
-		avgDemand := data.demandSum / float64(data.count)
-
-		timestamp := time.Date(
-			baseDate.Year(), baseDate.Month(), baseDate.Day(),
-			hour, 0, 0, 0, location,
-		)
-
 		point := demand.SeriesPoint{
-			Timestamp:  timestamp,
-			DemandMW:   avgDemand,
+			Timestamp:  timeutil.SlotTime(baseDate, slot),
+			DemandMW:   demandVal,
 			ForecastMW: nil, // OCCTO doesn't provide forecasts
 		}
 		resp.Series = append(resp.Series, point)
@@ -321,13 +427,17 @@ func (a *OCCTOAdapter) detectDemandColumns(header []string) map[string]int {
 }
 
 // detectColumns finds column indices by header names.
-// Returns map with keys: date, area, demand, capacity.
+// Returns map with keys: date, time, area, demand, capacity.
 func (a *OCCTOAdapter) detectColumns(header []string) map[string]int {
 	indices := map[string]int{
-		"date":     -1,
-		"area":     -1,
-		"demand":   -1,
-		"capacity": -1,
+		"date":           -1,
+		"time":           -1,
+		"area":           -1,
+		"demand":         -1,
+		"capacity":       -1,
+		"block":          -1,
+		"block_demand":   -1,
+		"block_capacity": -1,
 	}
 
 	for i, col := range header {
@@ -338,6 +448,10 @@ func (a *OCCTOAdapter) detectColumns(header []string) map[string]int {
 		case strings.Contains(colTrimmed, "対象年月日") || strings.Contains(colTrimmed, "date"):
 			indices["date"] = i
 
+		// Time: "時刻" (interval start)
+		case colTrimmed == "時刻" || colTrimmed == "対象時刻" || colTrimmed == "time":
+			indices["time"] = i
+
 		// Area: "エリア名"
 		case colTrimmed == "エリア名" || strings.Contains(colTrimmed, "area"):
 			indices["area"] = i
@@ -349,6 +463,14 @@ func (a *OCCTOAdapter) detectColumns(header []string) map[string]int {
 		// Capacity: "エリア供給力(MW)" (NOT "広域ブロック供給力")
 		case colTrimmed == "エリア供給力(MW)":
 			indices["capacity"] = i
+
+		// Wide-area block: "ブロックNo", "広域ブロック需要(MW)", "広域ブロック供給力(MW)"
+		case colTrimmed == "ブロックNo":
+			indices["block"] = i
+		case colTrimmed == "広域ブロック需要(MW)":
+			indices["block_demand"] = i
+		case colTrimmed == "広域ブロック供給力(MW)":
+			indices["block_capacity"] = i
 		}
 	}
 
@@ -356,25 +478,25 @@ func (a *OCCTOAdapter) detectColumns(header []string) map[string]int {
 }
 
 // normalizeArea converts various area representations to canonical form.
-// Handles both English and Japanese names.
+// Handles English and Japanese names via the areas registry (see sourceArea).
+// Returns an empty string for areas outside the registry.
 func (a *OCCTOAdapter) normalizeArea(areaStr string) string {
-	areaStr = strings.ToLower(strings.TrimSpace(areaStr))
-
-	switch {
-	case areaStr == "tokyo" || areaStr == "東京" || strings.Contains(areaStr, "tokyo"):
-		return "tokyo"
-	case areaStr == "kansai" || areaStr == "関西" || strings.Contains(areaStr, "kansai"):
-		return "kansai"
-	default:
-		// Return as-is for other areas (future expansion)
-		return areaStr
+	code, ok := sourceArea(areaStr)
+	if !ok {
+		return ""
 	}
+	return string(code)
 }
 
 // ParseGenerationMixCSV parses OCCTO jhSybt=03 CSV data into generation.Response.
 // CSV format: generation capacity breakdown by fuel type (solar, wind, nuclear, LNG, coal, hydro).
-// Similar structure to demand CSV with 30-minute intervals, aggregated to hourly.
+// Similar structure to demand CSV; keeps the native 30-minute resolution (timescale "30min").
 func (a *OCCTOAdapter) ParseGenerationMixCSV(reader io.Reader, date string, targetArea string) (*generation.Response, error) {
+	target := a.normalizeArea(targetArea)
+	if target == "" {
+		return nil, fmt.Errorf("unknown area: %s", targetArea)
+	}
+
 	csvReader := csv.NewReader(reader)
 	csvReader.TrimLeadingSpace = true
 	csvReader.LazyQuotes = true
@@ -398,7 +520,12 @@ func (a *OCCTOAdapter) ParseGenerationMixCSV(reader io.Reader, date string, targ
 		return nil, fmt.Errorf("required columns not found in header: %v", header)
 	}
 
-	resp := generation.NewResponse(targetArea, date)
+	baseDate, err := timeutil.ParseDate(date)
+	if err != nil {
+		return nil, fmt.Errorf("invalid date: %w", err)
+	}
+
+	resp := generation.NewResponseWithTimescale(target, date, generation.Timescale30Min)
 	resp.Source = generation.Source{
 		Name: "OCCTO",
 		URL:  a.sourceURL,
@@ -407,17 +534,8 @@ func (a *OCCTOAdapter) ParseGenerationMixCSV(reader io.Reader, date string, targ
 	// Normalize date format (2025-11-03 → 2025/11/03)
 	normalizedDate := strings.ReplaceAll(date, "-", "/")
 
-	// Aggregate 30-minute intervals into hourly data
-	hourlyData := make(map[int]struct {
-		solar   float64
-		wind    float64
-		hydro   float64
-		nuclear float64
-		lng     float64
-		coal    float64
-		other   float64
-		count   int
-	})
+	// Generation by 30-minute slot (0-47)
+	slotData := make(map[int]generation.GenerationPoint)
 
 	lineNum := 1
 	for {
@@ -441,86 +559,59 @@ func (a *OCCTOAdapter) ParseGenerationMixCSV(reader io.Reader, date string, targ
 		area := a.normalizeArea(areaStr)
 
 		// Only process the target area
-		if targetArea != area {
+		if target != area {
 			continue
 		}
 
 		// Extract time (format: "00:30", "01:00", etc.)
 		timeStr := strings.TrimSpace(record[colIndices["time"]])
-		timeParts := strings.Split(timeStr, ":")
-		if len(timeParts) < 1 {
-			continue
-		}
-		hour, err := strconv.Atoi(timeParts[0])
+		slot, err := timeutil.ParseSlot(timeStr)
 		if err != nil {
 			continue
 		}
 
 		// Parse generation values (MW) for each fuel type
-		data := hourlyData[hour]
-
-		if idx := colIndices["solar"]; idx != -1 {
-			val, _ := strconv.ParseFloat(strings.TrimSpace(record[idx]), 64)
-			data.solar += val
-		}
-		if idx := colIndices["wind"]; idx != -1 {
-			val, _ := strconv.ParseFloat(strings.TrimSpace(record[idx]), 64)
-			data.wind += val
-		}
-		if idx := colIndices["hydro"]; idx != -1 {
-			val, _ := strconv.ParseFloat(strings.TrimSpace(record[idx]), 64)
-			data.hydro += val
-		}
-		if idx := colIndices["nuclear"]; idx != -1 {
-			val, _ := strconv.ParseFloat(strings.TrimSpace(record[idx]), 64)
-			data.nuclear += val
-		}
-		if idx := colIndices["lng"]; idx != -1 {
-			val, _ := strconv.ParseFloat(strings.TrimSpace(record[idx]), 64)
-			data.lng += val
-		}
-		if idx := colIndices["coal"]; idx != -1 {
-			val, _ := strconv.ParseFloat(strings.TrimSpace(record[idx]), 64)
-			data.coal += val
-		}
-		if idx := colIndices["other"]; idx != -1 {
-			val, _ := strconv.ParseFloat(strings.TrimSpace(record[idx]), 64)
-			data.other += val
+		value := func(key string) float64 {
+			idx := colIndices[key]
+			if idx == -1 || idx >= len(record) {
+				return 0
+			}
+			val, _ := strconv.ParseFloat(strings.ReplaceAll(strings.TrimSpace(record[idx]), ",", ""), 64)
+			return val
 		}
 
-		data.count++
-		hourlyData[hour] = data
+		point := generation.GenerationPoint{
+			Timestamp:        timeutil.SlotTime(baseDate, slot),
+			SolarMW:          value("solar"),
+			WindMW:           value("wind"),
+			HydroMW:          value("hydro"),
+			NuclearMW:        value("nuclear"),
+			LNGMW:            value("lng"),
+			CoalMW:           value("coal"),
+			OilMW:            value("oil"),
+			OtherMW:          value("other_thermal") + value("other"),
+			BiomassMW:        value("biomass"),
+			GeothermalMW:     value("geothermal"),
+			PumpedStorageMW:  value("pumped_storage"),
+			BatteryMW:        value("battery"),
+			InterconnectorMW: value("interconnector"),
+			CurtailedSolarMW: value("solar_curtailed"),
+			CurtailedWindMW:  value("wind_curtailed"),
+		}
+
+		// Split columns: pumping and charging are load, whatever their published sign
+		point.PumpedStorageMW += value("pumped_storage_gen") - math.Abs(value("pumping"))
+		point.BatteryMW += value("battery_discharge") - math.Abs(value("battery_charge"))
+		point.SumMW()
+
+		slotData[slot] = point
 	}
 
-	// Convert aggregated data to GenerationPoints
-	location, _ := time.LoadLocation("Asia/Tokyo")
-	baseDate, _ := time.Parse("2006-01-02", date)
-
-	for hour := 0; hour < 24; hour++ {
-		data, exists := hourlyData[hour]
-		if !exists || data.count == 0 {
-			continue
-		}
-
-		count := float64(data.count)
-		timestamp := time.Date(
-			baseDate.Year(), baseDate.Month(), baseDate.Day(),
-			hour, 0, 0, 0, location,
-		)
-
-		point := generation.GenerationPoint{
-			Timestamp: timestamp,
-			SolarMW:   data.solar / count,
-			WindMW:    data.wind / count,
-			HydroMW:   data.hydro / count,
-			NuclearMW: data.nuclear / count,
-			LNGMW:     data.lng / count,
-			CoalMW:    data.coal / count,
-			OtherMW:   data.other / count,
+	// Emit points in slot order
+	for slot := 0; slot < timeutil.SlotsPerDay; slot++ {
+		if point, exists := slotData[slot]; exists {
+			resp.Series = append(resp.Series, point)
 		}
-		point.TotalMW = point.SolarMW + point.WindMW + point.HydroMW + point.NuclearMW + point.LNGMW + point.CoalMW + point.OtherMW
-
-		resp.Series = append(resp.Series, point)
 	}
 
 	// Validate we have data
@@ -536,20 +627,36 @@ func (a *OCCTOAdapter) ParseGenerationMixCSV(reader io.Reader, date string, targ
 
 // detectGenerationColumns finds column indices for generation mix parsing.
 // OCCTO jhSybt=03 column names (Japanese):
-// - 太陽光 (solar), 風力 (wind), 水力 (hydro), 原子力 (nuclear)
-// - 火力(LNG), 火力(石炭), その他
+// - 太陽光 (solar), 風力 (wind), 水力 (hydro), 原子力 (nuclear), 地熱, バイオマス
+// - 火力(LNG), 火力(石炭), 火力(石油), 火力(その他), その他
+// - 揚水 (signed) or 揚水(発電) and 揚水動力; 蓄電池 (signed) or 蓄電池(放電) and 蓄電池(充電)
+// - 連系線 (net imports); 太陽光出力制御量 and 風力出力制御量 (curtailed output)
+// The 合計 column is ignored; TotalMW is the sum of the categories.
 func (a *OCCTOAdapter) detectGenerationColumns(header []string) map[string]int {
 	indices := map[string]int{
-		"date":    -1,
-		"time":    -1,
-		"area":    -1,
-		"solar":   -1,
-		"wind":    -1,
-		"hydro":   -1,
-		"nuclear": -1,
-		"lng":     -1,
-		"coal":    -1,
-		"other":   -1,
+		"date":               -1,
+		"time":               -1,
+		"area":               -1,
+		"solar":              -1,
+		"solar_curtailed":    -1,
+		"wind":               -1,
+		"wind_curtailed":     -1,
+		"hydro":              -1,
+		"geothermal":         -1,
+		"biomass":            -1,
+		"nuclear":            -1,
+		"lng":                -1,
+		"coal":               -1,
+		"oil":                -1,
+		"other_thermal":      -1,
+		"other":              -1,
+		"pumped_storage":     -1,
+		"pumped_storage_gen": -1,
+		"pumping":            -1,
+		"battery":            -1,
+		"battery_discharge":  -1,
+		"battery_charge":     -1,
+		"interconnector":     -1,
 	}
 
 	for i, col := range header {
@@ -562,18 +669,44 @@ func (a *OCCTOAdapter) detectGenerationColumns(header []string) map[string]int {
 			indices["time"] = i
 		case colTrimmed == "エリア名":
 			indices["area"] = i
+		case strings.Contains(colTrimmed, "太陽光") && strings.Contains(colTrimmed, "制御"):
+			indices["solar_curtailed"] = i
 		case strings.Contains(colTrimmed, "太陽光"):
 			indices["solar"] = i
+		case strings.Contains(colTrimmed, "風力") && strings.Contains(colTrimmed, "制御"):
+			indices["wind_curtailed"] = i
 		case strings.Contains(colTrimmed, "風力"):
 			indices["wind"] = i
+		case strings.Contains(colTrimmed, "揚水") && (strings.Contains(colTrimmed, "動力") || strings.Contains(colTrimmed, "(揚水)")):
+			indices["pumping"] = i
+		case strings.Contains(colTrimmed, "揚水") && strings.Contains(colTrimmed, "発電"):
+			indices["pumped_storage_gen"] = i
+		case strings.Contains(colTrimmed, "揚水"):
+			indices["pumped_storage"] = i
 		case strings.Contains(colTrimmed, "水力"):
 			indices["hydro"] = i
+		case strings.Contains(colTrimmed, "地熱"):
+			indices["geothermal"] = i
+		case strings.Contains(colTrimmed, "バイオマス"):
+			indices["biomass"] = i
 		case strings.Contains(colTrimmed, "原子力"):
 			indices["nuclear"] = i
 		case strings.Contains(colTrimmed, "火力") && strings.Contains(colTrimmed, "LNG"):
 			indices["lng"] = i
 		case strings.Contains(colTrimmed, "火力") && strings.Contains(colTrimmed, "石炭"):
 			indices["coal"] = i
+		case strings.Contains(colTrimmed, "火力") && strings.Contains(colTrimmed, "石油"):
+			indices["oil"] = i
+		case strings.Contains(colTrimmed, "火力") && strings.Contains(colTrimmed, "その他"):
+			indices["other_thermal"] = i
+		case strings.Contains(colTrimmed, "蓄電池") && strings.Contains(colTrimmed, "充電"):
+			indices["battery_charge"] = i
+		case strings.Contains(colTrimmed, "蓄電池") && strings.Contains(colTrimmed, "放電"):
+			indices["battery_discharge"] = i
+		case strings.Contains(colTrimmed, "蓄電池"):
+			indices["battery"] = i
+		case strings.Contains(colTrimmed, "連系線"):
+			indices["interconnector"] = i
 		case strings.Contains(colTrimmed, "その他"):
 			indices["other"] = i
 		}
@@ -0,0 +1,235 @@
+package adapters
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/teo/aversome/backend/internal/areas"
+	"github.com/teo/aversome/backend/internal/curtailment"
+	"github.com/teo/aversome/backend/pkg/timeutil"
+)
+
+// ParseCurtailmentCSV parses OCCTO renewable output curtailment results
+// (再エネ出力制御実績) into curtailment.Response for one area.
+// CSV format (one row per order, every area in one file):
+//
+//	"2025/10/24 18:00 UPDATE"  ← optional, skipped
+//	"対象年月日","エリア","開始時刻","終了時刻","電源種別","出力制御量(MW)","制御理由"
+//	"2025/10/24","九州","09:00","15:00","太陽光",1850,"下げ調整力不足"
+//
+// Notes:
+//   - An end time of 24:00 is midnight of the next day
+//   - Rows of other dates and areas are skipped; a day without rows for the
+//     area is a day without curtailment (no events, not an error)
+//   - Rows of other fuels are skipped with a warning
+func (a *OCCTOAdapter) ParseCurtailmentCSV(reader io.Reader, date string, area areas.Area) (*curtailment.Response, error) {
+	csvReader := csv.NewReader(reader)
+	csvReader.TrimLeadingSpace = true
+	csvReader.LazyQuotes = true
+	csvReader.FieldsPerRecord = -1
+
+	baseDate, err := timeutil.ParseDate(date)
+	if err != nil {
+		return nil, fmt.Errorf("invalid date: %w", err)
+	}
+
+	// Read header, skipping the UPDATE line
+	header, err := csvReader.Read()
+	if err == nil && len(header) == 1 && strings.HasSuffix(strings.TrimSpace(header[0]), "UPDATE") {
+		header, err = csvReader.Read()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+
+	colIndices := a.detectCurtailmentColumns(header)
+	for _, col := range []string{"date", "area", "start", "end", "fuel", "mw"} {
+		if colIndices[col] == -1 {
+			return nil, fmt.Errorf("required column %s not found in header: %v", col, header)
+		}
+	}
+
+	resp := curtailment.NewResponse(date, string(area.Code))
+	resp.Source = curtailment.Source{
+		Name: "OCCTO",
+		URL:  a.sourceURL,
+	}
+
+	// Normalize date format (2025-10-24 → 2025/10/24)
+	normalizedDate := strings.ReplaceAll(date, "-", "/")
+
+	otherFuels := make(map[string]bool)
+	lineNum := 1
+
+	// Read data rows
+	for {
+		record, err := csvReader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error reading CSV line %d: %w", lineNum, err)
+		}
+		lineNum++
+
+		if len(record) <= colIndices["mw"] {
+			continue
+		}
+		rowDate := strings.TrimSpace(record[colIndices["date"]])
+		if rowDate != normalizedDate && rowDate != date {
+			continue
+		}
+		if code, ok := sourceArea(record[colIndices["area"]]); !ok || code != area.Code {
+			continue // Other area
+		}
+
+		fuel, err := curtailment.ParseFuel(record[colIndices["fuel"]])
+		if err != nil {
+			otherFuels[strings.TrimSpace(record[colIndices["fuel"]])] = true
+			continue
+		}
+
+		start, err := parseClockOn(baseDate, record[colIndices["start"]])
+		if err != nil {
+			return nil, fmt.Errorf("invalid start time at line %d: %w", lineNum, err)
+		}
+		end, err := parseClockOn(baseDate, record[colIndices["end"]])
+		if err != nil {
+			return nil, fmt.Errorf("invalid end time at line %d: %w", lineNum, err)
+		}
+		if !end.After(start) {
+			return nil, fmt.Errorf("end before start at line %d", lineNum)
+		}
+
+		mwStr := strings.ReplaceAll(strings.TrimSpace(record[colIndices["mw"]]), ",", "")
+		mw, err := strconv.ParseFloat(mwStr, 64)
+		if err != nil || mw < 0 {
+			return nil, fmt.Errorf("invalid curtailed MW at line %d: %s", lineNum, mwStr)
+		}
+
+		event := curtailment.Event{
+			Start:       timeutil.FormatISO8601(start),
+			End:         timeutil.FormatISO8601(end),
+			Fuel:        fuel,
+			CurtailedMW: mw,
+			Reason:      curtailment.ReasonOther,
+		}
+		if idx := colIndices["reason"]; idx != -1 && idx < len(record) {
+			event.ReasonText = strings.TrimSpace(record[idx])
+			event.Reason = curtailment.ParseReason(event.ReasonText)
+		}
+		resp.Events = append(resp.Events, event)
+	}
+
+	sort.SliceStable(resp.Events, func(i, j int) bool { return resp.Events[i].Start < resp.Events[j].Start })
+	if err := resp.Calculate(); err != nil {
+		return nil, err
+	}
+
+	if len(otherFuels) > 0 {
+		names := make([]string, 0, len(otherFuels))
+		for name := range otherFuels {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		resp.Meta = &curtailment.Meta{Warning: "Rows of other fuels skipped: " + strings.Join(names, ", ")}
+	}
+
+	return resp, nil
+}
+
+// CurtailmentCoversDate reports whether the curtailment CSV has a row of any
+// area for the date. Over HTTP a file without such rows is a day without
+// curtailment; the bundled sample only covers the dates it lists.
+func (a *OCCTOAdapter) CurtailmentCoversDate(reader io.Reader, date string) (bool, error) {
+	csvReader := csv.NewReader(reader)
+	csvReader.TrimLeadingSpace = true
+	csvReader.LazyQuotes = true
+	csvReader.FieldsPerRecord = -1
+
+	header, err := csvReader.Read()
+	if err == nil && len(header) == 1 && strings.HasSuffix(strings.TrimSpace(header[0]), "UPDATE") {
+		header, err = csvReader.Read()
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+	dateCol := a.detectCurtailmentColumns(header)["date"]
+	if dateCol == -1 {
+		return false, fmt.Errorf("required column date not found in header: %v", header)
+	}
+
+	normalizedDate := strings.ReplaceAll(date, "-", "/")
+	for {
+		record, err := csvReader.Read()
+		if err == io.EOF {
+			return false, nil
+		}
+		if err != nil {
+			return false, fmt.Errorf("error reading CSV: %w", err)
+		}
+		if len(record) <= dateCol {
+			continue
+		}
+		if rowDate := strings.TrimSpace(record[dateCol]); rowDate == normalizedDate || rowDate == date {
+			return true, nil
+		}
+	}
+}
+
+// detectCurtailmentColumns finds column indices by header names.
+// Returns map with keys: date, area, start, end, fuel, mw, reason.
+func (a *OCCTOAdapter) detectCurtailmentColumns(header []string) map[string]int {
+	indices := map[string]int{
+		"date":   -1,
+		"area":   -1,
+		"start":  -1,
+		"end":    -1,
+		"fuel":   -1,
+		"mw":     -1,
+		"reason": -1,
+	}
+
+	for i, col := range header {
+		col = strings.TrimSpace(strings.TrimPrefix(col, "\ufeff"))
+
+		switch {
+		case col == "対象年月日" || col == "年月日":
+			indices["date"] = i
+		case col == "エリア" || col == "エリア名":
+			indices["area"] = i
+		case strings.HasPrefix(col, "開始"):
+			indices["start"] = i
+		case strings.HasPrefix(col, "終了"):
+			indices["end"] = i
+		case col == "電源種別" || col == "電源":
+			indices["fuel"] = i
+		case strings.Contains(col, "制御量"):
+			indices["mw"] = i
+		case strings.Contains(col, "理由"):
+			indices["reason"] = i
+		}
+	}
+
+	return indices
+}
+
+// parseClockOn returns the clock time ("9:00", "13:30") on date in Asia/Tokyo.
+// "24:00" is midnight of the next day.
+func parseClockOn(date time.Time, clock string) (time.Time, error) {
+	parts := strings.Split(strings.TrimSpace(clock), ":")
+	if len(parts) < 2 {
+		return time.Time{}, fmt.Errorf("invalid time format: %q", clock)
+	}
+	hour, errH := strconv.Atoi(parts[0])
+	minute, errM := strconv.Atoi(parts[1])
+	if errH != nil || errM != nil || hour < 0 || minute < 0 || minute > 59 || hour > 24 || (hour == 24 && minute > 0) {
+		return time.Time{}, fmt.Errorf("invalid time: %q", clock)
+	}
+	return time.Date(date.Year(), date.Month(), date.Day(), hour, minute, 0, 0, timeutil.TokyoLocation), nil
+}
@@ -0,0 +1,102 @@
+package adapters
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/teo/aversome/backend/internal/areas"
+	"github.com/teo/aversome/backend/internal/curtailment"
+)
+
+func TestOCCTOAdapter_ParseCurtailmentCSV(t *testing.T) {
+	parse := func(t *testing.T, area areas.Code) *curtailment.Response {
+		t.Helper()
+		f, err := os.Open("testdata/occto-curtailment-sample.csv")
+		if err != nil {
+			t.Fatalf("Failed to open test file: %v", err)
+		}
+		defer f.Close()
+
+		a, _ := areas.Lookup(area)
+		resp, err := NewOCCTOAdapter().ParseCurtailmentCSV(f, "2025-10-24", a)
+		if err != nil {
+			t.Fatalf("ParseCurtailmentCSV(%s) error = %v", area, err)
+		}
+		return resp
+	}
+
+	kyushu := parse(t, areas.Kyushu)
+	if len(kyushu.Events) != 3 || len(kyushu.Series) != 48 {
+		t.Fatalf("kyushu: %d events, %d intervals; want 3 and 48", len(kyushu.Events), len(kyushu.Series))
+	}
+	if e := kyushu.Events[0]; e.Start != "2025-10-24T09:00:00+09:00" || e.End != "2025-10-24T15:00:00+09:00" ||
+		e.Fuel != curtailment.FuelSolar || e.CurtailedMW != 1850 || e.Reason != curtailment.ReasonOversupply {
+		t.Errorf("first event = %+v, want 1850 MW solar oversupply 09:00-15:00", e)
+	}
+	if e := kyushu.Events[2]; e.Reason != curtailment.ReasonGridConstraint || e.ReasonText != "系統制約（局所）" {
+		t.Errorf("local event = %+v, want grid constraint", e)
+	}
+
+	// 11:15-12:00 covers half of the 11:00 interval
+	if p := kyushu.Series[22]; p.SolarMW != 2000 || p.WindMW != 120 {
+		t.Errorf("11:00 = %+v, want 2000 MW solar and 120 MW wind", p)
+	}
+	if s := kyushu.Summary; s.SolarMWh != 11325 || s.WindMWh != 480 || s.PeakCurtailedMW != 2270 ||
+		s.PeakAt != "2025-10-24T11:30:00+09:00" || s.CurtailedIntervals != 12 {
+		t.Errorf("summary = %+v", s)
+	}
+	if kyushu.Meta == nil || !strings.Contains(kyushu.Meta.Warning, "バイオマス") {
+		t.Errorf("Meta = %+v, want skipped fuel warning", kyushu.Meta)
+	}
+
+	// No rows for the area: a day without curtailment
+	tokyo := parse(t, areas.Tokyo)
+	if len(tokyo.Events) != 0 || len(tokyo.Series) != 48 || tokyo.Summary.CurtailedMWh != 0 || tokyo.Meta != nil {
+		t.Errorf("tokyo = %+v, want no curtailment", tokyo.Summary)
+	}
+}
+
+func TestOCCTOAdapter_ParseCurtailmentCSV_Invalid(t *testing.T) {
+	kyushu, _ := areas.Lookup(areas.Kyushu)
+	tests := []struct {
+		name string
+		csv  string
+	}{
+		{"missing column", "\"対象年月日\",\"エリア\",\"開始時刻\",\"終了時刻\",\"電源種別\"\n"},
+		{"end before start", "対象年月日,エリア,開始時刻,終了時刻,電源種別,出力制御量(MW)\n2025/10/24,九州,15:00,09:00,太陽光,100\n"},
+		{"bad MW", "対象年月日,エリア,開始時刻,終了時刻,電源種別,出力制御量(MW)\n2025/10/24,九州,09:00,15:00,太陽光,-5\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewOCCTOAdapter().ParseCurtailmentCSV(strings.NewReader(tt.csv), "2025-10-24", kyushu); err == nil {
+				t.Error("ParseCurtailmentCSV() expected error")
+			}
+		})
+	}
+
+	// 24:00 ends at midnight
+	csv := "対象年月日,エリア,開始時刻,終了時刻,電源種別,出力制御量(MW)\n2025/10/24,九州,23:00,24:00,風力,60\n"
+	resp, err := NewOCCTOAdapter().ParseCurtailmentCSV(strings.NewReader(csv), "2025-10-24", kyushu)
+	if err != nil {
+		t.Fatalf("ParseCurtailmentCSV(24:00) error = %v", err)
+	}
+	if resp.Events[0].End != "2025-10-25T00:00:00+09:00" || resp.Summary.WindMWh != 60 || resp.Events[0].Reason != curtailment.ReasonOther {
+		t.Errorf("24:00 event = %+v, summary %+v", resp.Events[0], resp.Summary)
+	}
+}
+
+func TestOCCTOAdapter_CurtailmentCoversDate(t *testing.T) {
+	for date, want := range map[string]bool{"2025-10-24": true, "2025-10-23": true, "2025-10-22": false} {
+		f, err := os.Open("testdata/occto-curtailment-sample.csv")
+		if err != nil {
+			t.Fatalf("Failed to open test file: %v", err)
+		}
+		got, err := NewOCCTOAdapter().CurtailmentCoversDate(f, date)
+		f.Close()
+		if err != nil || got != want {
+			t.Errorf("CurtailmentCoversDate(%s) = %v, %v; want %v", date, got, err, want)
+		}
+	}
+}
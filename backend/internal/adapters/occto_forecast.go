@@ -0,0 +1,178 @@
+package adapters
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/teo/aversome/backend/internal/areas"
+	"github.com/teo/aversome/backend/internal/reserve"
+	"github.com/teo/aversome/backend/pkg/timeutil"
+)
+
+// ParseNextDayCSV parses an OCCTO next-day supply-demand outlook (翌日の需給見通し)
+// into a reserve.Forecast. The CSV has the same layout as the actuals read by
+// ParseCSV; the UPDATE line gives the publication time:
+//   "2025/10/23 17:00 UPDATE"
+//   "対象年月日","時刻","ブロックNo","エリア名","広域ブロック需要(MW)",...,"エリア需要(MW)","エリア供給力(MW)","エリア予備力(MW)"
+//   "2025/10/24","00:00","1","北海道",48599,53950,...,3015,3650,635
+func (a *OCCTOAdapter) ParseNextDayCSV(reader io.Reader, date string) (*reserve.Forecast, error) {
+	csvReader := csv.NewReader(reader)
+	csvReader.TrimLeadingSpace = true
+	csvReader.LazyQuotes = true
+	csvReader.FieldsPerRecord = -1
+
+	// First line: publication time
+	first, err := csvReader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read first line: %w", err)
+	}
+
+	resp, err := a.parseReserve(csvReader, date)
+	if err != nil {
+		return nil, err
+	}
+
+	return &reserve.Forecast{Response: *resp, Horizon: reserve.HorizonNextDay, IssuedAt: parseUpdateLine(first)}, nil
+}
+
+// ParseWeeklyCSV parses an OCCTO weekly supply-demand outlook (週間需給見通し)
+// into one reserve.Forecast per target date, ascending. Each row is an area's
+// tightest interval of a day:
+//   "2025/10/20 10:00 UPDATE"
+//   "対象年月日","時刻","ブロックNo","エリア名","エリア需要(MW)","エリア供給力(MW)","エリア予備力(MW)","エリア予備率(%)"
+//   "2025/10/21","17:30","4","東京",38588,40000,1412,3.66
+//
+// The margin is recalculated as (capacity - demand) / capacity * 100, like
+// the actuals; OCCTO's エリア予備率 column divides by demand and is ignored.
+func (a *OCCTOAdapter) ParseWeeklyCSV(reader io.Reader) ([]*reserve.Forecast, error) {
+	csvReader := csv.NewReader(reader)
+	csvReader.TrimLeadingSpace = true
+	csvReader.LazyQuotes = true
+	csvReader.FieldsPerRecord = -1
+
+	// First line: publication time
+	first, err := csvReader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read first line: %w", err)
+	}
+	issuedAt := parseUpdateLine(first)
+
+	header, err := csvReader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+
+	colIndices := a.detectColumns(header)
+	for _, col := range []string{"date", "time", "area", "demand", "capacity"} {
+		if colIndices[col] == -1 {
+			return nil, fmt.Errorf("required columns (date, time, area, demand, capacity) not found in header: %v", header)
+		}
+	}
+
+	// Tightest interval by target date and area
+	byDate := make(map[string]map[string]reserve.AreaReserve)
+
+	lineNum := 1
+	for {
+		record, err := csvReader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error reading CSV line %d: %w", lineNum, err)
+		}
+		lineNum++
+
+		// Normalize date (2025/10/21 → 2025-10-21)
+		date := strings.ReplaceAll(strings.TrimSpace(record[colIndices["date"]]), "/", "-")
+		baseDate, err := timeutil.ParseDate(date)
+		if err != nil {
+			continue
+		}
+
+		area := a.normalizeArea(strings.TrimSpace(record[colIndices["area"]]))
+		if area == "" {
+			continue // Skip unknown areas
+		}
+
+		slot, err := timeutil.ParseSlot(strings.TrimSpace(record[colIndices["time"]]))
+		if err != nil {
+			continue
+		}
+		demand, err := strconv.ParseFloat(strings.TrimSpace(record[colIndices["demand"]]), 64)
+		if err != nil {
+			continue
+		}
+		capacity, err := strconv.ParseFloat(strings.TrimSpace(record[colIndices["capacity"]]), 64)
+		if err != nil {
+			continue
+		}
+
+		p := reserve.NewPoint(timeutil.SlotTime(baseDate, slot), demand, capacity)
+		if colIndices["block"] != -1 {
+			p.Block, _ = strconv.Atoi(strings.TrimSpace(record[colIndices["block"]]))
+		}
+
+		if byDate[date] == nil {
+			byDate[date] = make(map[string]reserve.AreaReserve)
+		}
+		byDate[date][area] = reserve.AreaReserve{
+			Area:             area,
+			ReserveMarginPct: p.ReserveMarginPct,
+			Status:           p.Status,
+			Minimum:          reserve.FindMinimum([]reserve.ReservePoint{p}),
+		}
+	}
+
+	if len(byDate) == 0 {
+		return nil, fmt.Errorf("no data found in weekly outlook")
+	}
+
+	dates := make([]string, 0, len(byDate))
+	for date := range byDate {
+		dates = append(dates, date)
+	}
+	sort.Strings(dates)
+
+	out := make([]*reserve.Forecast, 0, len(dates))
+	for _, date := range dates {
+		f := reserve.NewForecast(date, reserve.HorizonWeekly)
+		f.IssuedAt = issuedAt
+		f.Source = reserve.Source{Name: "OCCTO", URL: a.sourceURL}
+
+		// Registry order for stable output
+		for _, code := range areas.Codes() {
+			if ar, ok := byDate[date][string(code)]; ok {
+				f.Areas = append(f.Areas, ar)
+			}
+		}
+		if n := len(f.Areas); n < len(areas.Codes()) {
+			f.Meta = &reserve.Meta{Warning: fmt.Sprintf("Outlook covers %d of %d areas", n, len(areas.Codes()))}
+		}
+		out = append(out, f)
+	}
+
+	return out, nil
+}
+
+// parseUpdateLine reads the publication time from an OCCTO "YYYY/MM/DD HH:MM UPDATE"
+// first line, or returns "" if it does not parse.
+func parseUpdateLine(record []string) string {
+	if len(record) == 0 {
+		return ""
+	}
+	fields := strings.Fields(strings.TrimPrefix(record[0], "\ufeff"))
+	if len(fields) < 2 {
+		return ""
+	}
+	t, err := time.ParseInLocation("2006/01/02 15:04", fields[0]+" "+fields[1], timeutil.TokyoLocation)
+	if err != nil {
+		return ""
+	}
+	return timeutil.FormatISO8601(t)
+}
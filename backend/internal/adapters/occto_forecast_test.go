@@ -0,0 +1,71 @@
+package adapters
+
+import (
+	"os"
+	"testing"
+
+	"github.com/teo/aversome/backend/internal/reserve"
+)
+
+func TestOCCTOAdapter_ParseNextDayCSV(t *testing.T) {
+	f, err := os.Open("testdata/occto-nextday-sample.csv")
+	if err != nil {
+		t.Fatalf("Failed to open test file: %v", err)
+	}
+	defer f.Close()
+
+	forecast, err := NewOCCTOAdapter().ParseNextDayCSV(f, "2025-10-24")
+	if err != nil {
+		t.Fatalf("ParseNextDayCSV() error = %v", err)
+	}
+	if forecast.Horizon != reserve.HorizonNextDay || forecast.IssuedAt != "2025-10-23T17:00:00+09:00" {
+		t.Errorf("horizon/issued = %s/%s", forecast.Horizon, forecast.IssuedAt)
+	}
+	if len(forecast.Areas) != 10 || len(forecast.Blocks) != 48 {
+		t.Fatalf("got %d areas and %d block intervals, want 10 and 48", len(forecast.Areas), len(forecast.Blocks))
+	}
+	tokyo := forecast.Areas[2]
+	if tokyo.Area != "tokyo" || len(tokyo.Series) != 48 || tokyo.Minimum == nil {
+		t.Fatalf("tokyo = %s with %d points, want a 48-point series and a minimum", tokyo.Area, len(tokyo.Series))
+	}
+	if got := tokyo.Series[35]; got.DemandMW != 38161 || got.CapacityMW != 40000 {
+		t.Errorf("tokyo 17:30 forecast = %v/%v MW, want 38161/40000", got.DemandMW, got.CapacityMW)
+	}
+}
+
+func TestOCCTOAdapter_ParseWeeklyCSV(t *testing.T) {
+	f, err := os.Open("testdata/occto-weekly-sample.csv")
+	if err != nil {
+		t.Fatalf("Failed to open test file: %v", err)
+	}
+	defer f.Close()
+
+	forecasts, err := NewOCCTOAdapter().ParseWeeklyCSV(f)
+	if err != nil {
+		t.Fatalf("ParseWeeklyCSV() error = %v", err)
+	}
+	if len(forecasts) != 7 || forecasts[0].Date != "2025-10-21" || forecasts[6].Date != "2025-10-27" {
+		t.Fatalf("got %d forecasts, want 2025-10-21..2025-10-27", len(forecasts))
+	}
+
+	day := forecasts[3]
+	if day.Date != "2025-10-24" || day.Horizon != reserve.HorizonWeekly || day.IssuedAt != "2025-10-20T10:00:00+09:00" {
+		t.Errorf("forecast[3] = %s %s %s", day.Date, day.Horizon, day.IssuedAt)
+	}
+	if len(day.Areas) != 10 || day.Meta != nil {
+		t.Errorf("2025-10-24 covers %d areas (meta %+v), want all 10", len(day.Areas), day.Meta)
+	}
+
+	// Tokyo: 38206 of 40000 MW at 17:30, margin recalculated over capacity
+	tokyo := day.Areas[2]
+	min := tokyo.Minimum
+	if tokyo.Area != "tokyo" || min == nil || min.Timestamp != "2025-10-24T17:30:00+09:00" || min.Block != 4 {
+		t.Fatalf("tokyo minimum = %+v, want block 4 at 17:30", min)
+	}
+	if want := reserve.MarginPct(38206, 40000); tokyo.ReserveMarginPct != want || min.ReserveMarginPct != want {
+		t.Errorf("tokyo margin = %v / %v, want %v", tokyo.ReserveMarginPct, min.ReserveMarginPct, want)
+	}
+	if tokyo.Status != reserve.StatusTight || len(tokyo.Series) != 0 {
+		t.Errorf("tokyo status = %s with %d points, want tight without a series", tokyo.Status, len(tokyo.Series))
+	}
+}
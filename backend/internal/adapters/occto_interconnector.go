@@ -0,0 +1,231 @@
+package adapters
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/teo/aversome/backend/internal/interconnector"
+	"github.com/teo/aversome/backend/pkg/timeutil"
+)
+
+// ParseInterconnectorCSV parses OCCTO interconnector flows (連系線潮流) into
+// interconnector.Response.
+// CSV format (from web-kohyo.occto.or.jp, one row per line and interval):
+//
+//	"2025/10/24 23:59 UPDATE"  ← optional, skipped
+//	"対象年月日","時刻","連系線","計画潮流(MW)","潮流実績(MW)","運用容量(順方向)(MW)","運用容量(逆方向)(MW)"
+//	"2025/10/24","17:30","東北東京間連系線",5650,5650,5730,3000
+//
+// Notes:
+//   - Flows are signed: positive in the line's forward direction (順方向)
+//   - 潮流実績 is empty until published; margin and congestion then use the plan
+//   - Lines are matched against the interconnector registry; unknown lines are
+//     skipped with a warning
+func (a *OCCTOAdapter) ParseInterconnectorCSV(reader io.Reader, date string) (*interconnector.Response, error) {
+	csvReader := csv.NewReader(reader)
+	csvReader.TrimLeadingSpace = true
+	csvReader.LazyQuotes = true
+	csvReader.FieldsPerRecord = -1
+
+	baseDate, err := timeutil.ParseDate(date)
+	if err != nil {
+		return nil, fmt.Errorf("invalid date: %w", err)
+	}
+
+	// Read header, skipping the UPDATE line
+	header, err := csvReader.Read()
+	if err == nil && len(header) == 1 && strings.HasSuffix(strings.TrimSpace(header[0]), "UPDATE") {
+		header, err = csvReader.Read()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+
+	colIndices := a.detectInterconnectorColumns(header)
+	for _, col := range []string{"date", "time", "line", "cap_forward", "cap_reverse"} {
+		if colIndices[col] == -1 {
+			return nil, fmt.Errorf("required column %s not found in header: %v", col, header)
+		}
+	}
+	if colIndices["planned"] == -1 && colIndices["actual"] == -1 {
+		return nil, fmt.Errorf("no flow column found in header: %v", header)
+	}
+
+	resp := interconnector.NewResponse(date)
+	resp.Source = interconnector.Source{
+		Name: "OCCTO",
+		URL:  a.sourceURL,
+	}
+
+	// Normalize date format (2025/10/24 → 2025-10-24)
+	normalizedDate := strings.ReplaceAll(date, "-", "/")
+
+	series := make(map[string]map[int]interconnector.FlowPoint) // line ID → slot → point
+	unknown := make(map[string]bool)
+	lineNum := 1
+
+	// Read data rows
+	for {
+		record, err := csvReader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error reading CSV line %d: %w", lineNum, err)
+		}
+		lineNum++
+
+		if len(record) <= colIndices["line"] {
+			continue
+		}
+		rowDate := strings.TrimSpace(record[colIndices["date"]])
+		if rowDate != normalizedDate && rowDate != date {
+			continue
+		}
+
+		name := strings.TrimSpace(record[colIndices["line"]])
+		line, err := interconnector.ParseLine(name)
+		if err != nil {
+			unknown[name] = true
+			continue
+		}
+
+		slot, err := timeutil.ParseSlot(record[colIndices["time"]])
+		if err != nil {
+			return nil, fmt.Errorf("invalid time at line %d: %w", lineNum, err)
+		}
+
+		planned, err := parseOptionalMW(record, colIndices["planned"])
+		if err != nil {
+			return nil, fmt.Errorf("invalid planned flow at line %d: %w", lineNum, err)
+		}
+		actual, err := parseOptionalMW(record, colIndices["actual"])
+		if err != nil {
+			return nil, fmt.Errorf("invalid actual flow at line %d: %w", lineNum, err)
+		}
+		if planned == nil && actual == nil {
+			continue // Nothing published for the interval yet
+		}
+		capForward, err := parseOptionalMW(record, colIndices["cap_forward"])
+		if err != nil || capForward == nil {
+			return nil, fmt.Errorf("invalid forward capacity at line %d", lineNum)
+		}
+		capReverse, err := parseOptionalMW(record, colIndices["cap_reverse"])
+		if err != nil || capReverse == nil {
+			return nil, fmt.Errorf("invalid reverse capacity at line %d", lineNum)
+		}
+
+		if series[line.ID] == nil {
+			series[line.ID] = make(map[int]interconnector.FlowPoint)
+		}
+		ts := timeutil.FormatISO8601(timeutil.SlotTime(baseDate, slot))
+		series[line.ID][slot] = interconnector.NewFlowPoint(ts, planned, actual, *capForward, *capReverse)
+	}
+
+	// Lines in registry order, intervals in time order
+	incomplete := 0
+	for _, line := range interconnector.Lines() {
+		slots := series[line.ID]
+		if len(slots) == 0 {
+			continue
+		}
+
+		flows := interconnector.LineFlows{
+			Line:   line.ID,
+			Name:   line.NameJA,
+			From:   string(line.From),
+			To:     string(line.To),
+			Series: make([]interconnector.FlowPoint, 0, len(slots)),
+		}
+		for slot := 0; slot < timeutil.SlotsPerDay; slot++ {
+			if p, ok := slots[slot]; ok {
+				flows.Series = append(flows.Series, p)
+			}
+		}
+		flows.Summarize()
+		if len(flows.Series) < timeutil.SlotsPerDay {
+			incomplete++
+		}
+		resp.Lines = append(resp.Lines, flows)
+	}
+
+	// Validate we have data
+	if len(resp.Lines) == 0 {
+		return nil, fmt.Errorf("no interconnector flows found for date %s", date)
+	}
+
+	var warnings []string
+	if incomplete > 0 {
+		warnings = append(warnings, fmt.Sprintf("%d lines have fewer than %d intervals", incomplete, timeutil.SlotsPerDay))
+	}
+	if len(unknown) > 0 {
+		names := make([]string, 0, len(unknown))
+		for name := range unknown {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		warnings = append(warnings, "Unknown lines skipped: "+strings.Join(names, ", "))
+	}
+	if len(warnings) > 0 {
+		resp.Meta = &interconnector.Meta{Warning: strings.Join(warnings, "; ")}
+	}
+
+	return resp, nil
+}
+
+// detectInterconnectorColumns finds column indices by header names.
+// Returns map with keys: date, time, line, planned, actual, cap_forward, cap_reverse.
+func (a *OCCTOAdapter) detectInterconnectorColumns(header []string) map[string]int {
+	indices := map[string]int{
+		"date":        -1,
+		"time":        -1,
+		"line":        -1,
+		"planned":     -1,
+		"actual":      -1,
+		"cap_forward": -1,
+		"cap_reverse": -1,
+	}
+
+	for i, col := range header {
+		col = strings.TrimSpace(strings.TrimPrefix(col, "\ufeff"))
+
+		switch {
+		case col == "対象年月日" || col == "年月日":
+			indices["date"] = i
+		case col == "時刻":
+			indices["time"] = i
+		case col == "連系線" || col == "連系線名":
+			indices["line"] = i
+		case strings.HasPrefix(col, "計画潮流"):
+			indices["planned"] = i
+		case strings.HasPrefix(col, "潮流実績") || strings.HasPrefix(col, "実績潮流"):
+			indices["actual"] = i
+		case strings.HasPrefix(col, "運用容量") && strings.Contains(col, "逆方向"):
+			indices["cap_reverse"] = i
+		case strings.HasPrefix(col, "運用容量"):
+			indices["cap_forward"] = i
+		}
+	}
+
+	return indices
+}
+
+// parseOptionalMW parses a MW field; an empty field or missing column is nil.
+func parseOptionalMW(record []string, idx int) (*float64, error) {
+	if idx == -1 || idx >= len(record) {
+		return nil, nil
+	}
+	s := strings.ReplaceAll(strings.TrimSpace(record[idx]), ",", "")
+	if s == "" || s == "-" {
+		return nil, nil
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
@@ -0,0 +1,91 @@
+package adapters
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/teo/aversome/backend/internal/interconnector"
+)
+
+func TestOCCTOAdapter_ParseInterconnectorCSV(t *testing.T) {
+	f, err := os.Open("testdata/occto-interconnector-sample.csv")
+	if err != nil {
+		t.Fatalf("Failed to open test file: %v", err)
+	}
+	defer f.Close()
+
+	resp, err := NewOCCTOAdapter().ParseInterconnectorCSV(f, "2025-10-24")
+	if err != nil {
+		t.Fatalf("ParseInterconnectorCSV() error = %v", err)
+	}
+	if len(resp.Lines) != 10 || resp.Meta != nil {
+		t.Fatalf("got %d lines (meta %+v), want all 10 without warning", len(resp.Lines), resp.Meta)
+	}
+	for _, l := range resp.Lines {
+		if len(l.Series) != 48 {
+			t.Errorf("%s has %d intervals, want 48", l.Line, len(l.Series))
+		}
+	}
+
+	// 東北東京間 runs at its forward capacity through the evening peak
+	tt := resp.Line("tohoku-tokyo")
+	if tt == nil || tt.CongestedIntervals != 5 {
+		t.Fatalf("tohoku-tokyo = %+v, want 5 congested intervals", tt)
+	}
+	if p := tt.Series[34]; !p.Congested || p.Timestamp != "2025-10-24T17:00:00+09:00" || p.MarginMW != 80 {
+		t.Errorf("tohoku-tokyo 17:00 = %+v, want congested with 80 MW margin", p)
+	}
+	if tt.Series[33].Congested || tt.Series[39].Congested {
+		t.Error("tohoku-tokyo congested outside 17:00-19:00")
+	}
+
+	// 周波数変換設備 resolves to the FC line, flowing Chubu → Tokyo at capacity
+	fc := resp.Line("tokyo-chubu")
+	if fc == nil || fc.Name != "東京中部間" || fc.CongestedIntervals != 6 {
+		t.Fatalf("tokyo-chubu = %+v, want 6 congested intervals", fc)
+	}
+	if p := fc.Series[35]; p.Direction != interconnector.DirectionReverse || p.MarginMW != 0 || p.UtilizationPct != 100 {
+		t.Errorf("FC 17:30 = %+v, want reverse at full capacity", p)
+	}
+
+	// Solar surplus fills 中国九州間 towards Chugoku at midday
+	ck := resp.Line("chugoku-kyushu")
+	if ck == nil || ck.CongestedIntervals != 7 || !ck.Series[24].Congested {
+		t.Errorf("chugoku-kyushu = %+v, want 7 congested intervals including 12:00", ck)
+	}
+
+	// Actual flows take precedence over the plan
+	ckp := resp.Line("chubu-kansai").Series[35]
+	if *ckp.PlannedMW != 1100 || *ckp.ActualMW != 1103 || ckp.MarginMW != 1397 || ckp.Congested {
+		t.Errorf("chubu-kansai 17:30 = %+v, want 1103 of 2500 MW forward", ckp)
+	}
+}
+
+func TestOCCTOAdapter_ParseInterconnectorCSV_Partial(t *testing.T) {
+	csv := `"対象年月日","時刻","連系線","計画潮流(MW)","潮流実績(MW)","運用容量(順方向)(MW)","運用容量(逆方向)(MW)"
+"2025/10/24","00:00","東北東京間連系線",4000,,5730,3000
+"2025/10/24","00:30","東北東京間連系線",5700,,5730,3000
+"2025/10/24","00:00","北海道東北間",100,100,900,900
+"2025/10/25","00:00","東北東京間連系線",1,1,5730,3000
+`
+	resp, err := NewOCCTOAdapter().ParseInterconnectorCSV(strings.NewReader(csv), "2025-10-24")
+	if err != nil {
+		t.Fatalf("ParseInterconnectorCSV() error = %v", err)
+	}
+	if len(resp.Lines) != 1 || len(resp.Lines[0].Series) != 2 {
+		t.Fatalf("got %+v, want tohoku-tokyo with 2 intervals", resp.Lines)
+	}
+
+	// Without an actual flow, congestion follows the plan
+	if p := resp.Lines[0].Series[1]; p.ActualMW != nil || !p.Congested {
+		t.Errorf("00:30 = %+v, want congested on the planned flow", p)
+	}
+	if resp.Meta == nil || !strings.Contains(resp.Meta.Warning, "1 lines have fewer than 48") || !strings.Contains(resp.Meta.Warning, "北海道東北間") {
+		t.Errorf("Meta = %+v, want incomplete and unknown line warnings", resp.Meta)
+	}
+
+	if _, err := NewOCCTOAdapter().ParseInterconnectorCSV(strings.NewReader(csv), "2025-10-26"); err == nil {
+		t.Error("ParseInterconnectorCSV(other date) expected error")
+	}
+}
@@ -2,8 +2,11 @@ package adapters
 
 import (
 	"os"
+	"reflect"
+	"strings"
 	"testing"
 
+	"github.com/teo/aversome/backend/internal/demand"
 	"github.com/teo/aversome/backend/internal/reserve"
 )
 
@@ -17,10 +20,10 @@ func TestOCCTOAdapter_ParseCSV(t *testing.T) {
 		wantError   bool
 	}{
 		{
-			name:      "valid CSV with Tokyo and Kansai",
+			name:      "valid CSV with all ten areas",
 			csvFile:   "testdata/occto-sample.csv",
 			date:      "2025-10-24",
-			wantAreas: 2,
+			wantAreas: 10,
 		},
 	}
 
@@ -66,13 +69,20 @@ func TestOCCTOAdapter_ParseCSV(t *testing.T) {
 				t.Error("Source.URL is empty")
 			}
 
+			// Areas are returned in registry order (north to south)
+			if len(resp.Areas) > 0 && resp.Areas[0].Area != "hokkaido" {
+				t.Errorf("First area = %v, want hokkaido", resp.Areas[0].Area)
+			}
+
 			// Check specific area data
-			if len(resp.Areas) >= 1 {
-				// First area should be Tokyo
-				tokyo := resp.Areas[0]
-				if tokyo.Area != "tokyo" {
-					t.Errorf("First area = %v, want tokyo", tokyo.Area)
-				}
+			byArea := make(map[string]reserve.AreaReserve)
+			for _, ar := range resp.Areas {
+				byArea[ar.Area] = ar
+			}
+
+			if tokyo, ok := byArea["tokyo"]; !ok {
+				t.Error("tokyo missing from response")
+			} else {
 				if tokyo.ReserveMarginPct != 5.1 {
 					t.Errorf("Tokyo ReserveMarginPct = %v, want 5.1", tokyo.ReserveMarginPct)
 				}
@@ -80,14 +90,26 @@ func TestOCCTOAdapter_ParseCSV(t *testing.T) {
 				if tokyo.Status != reserve.StatusWatch {
 					t.Errorf("Tokyo Status = %v, want %v", tokyo.Status, reserve.StatusWatch)
 				}
-			}
 
-			if len(resp.Areas) >= 2 {
-				// Second area should be Kansai
-				kansai := resp.Areas[1]
-				if kansai.Area != "kansai" {
-					t.Errorf("Second area = %v, want kansai", kansai.Area)
+				// The average hides the evening peak: 39180 of 40000 MW at 17:30
+				if len(tokyo.Series) != 48 {
+					t.Fatalf("Tokyo series length = %d, want 48", len(tokyo.Series))
+				}
+				if first := tokyo.Series[0]; first.Timestamp != "2025-10-24T00:00:00+09:00" || first.DemandMW != 37195 {
+					t.Errorf("Tokyo series[0] = %+v, want 37195 MW at 00:00", first)
+				}
+				min := tokyo.Minimum
+				if min == nil || min.Timestamp != "2025-10-24T17:30:00+09:00" || min.Hour != 17 {
+					t.Fatalf("Tokyo Minimum = %+v, want 17:30", min)
+				}
+				if min.ReserveMarginPct < 2.049 || min.ReserveMarginPct > 2.051 || min.Status != reserve.StatusTight {
+					t.Errorf("Tokyo Minimum = %v%% %v, want 2.05%% tight", min.ReserveMarginPct, min.Status)
 				}
+			}
+
+			if kansai, ok := byArea["kansai"]; !ok {
+				t.Error("kansai missing from response")
+			} else {
 				if kansai.ReserveMarginPct != 8.9 {
 					t.Errorf("Kansai ReserveMarginPct = %v, want 8.9", kansai.ReserveMarginPct)
 				}
@@ -106,6 +128,104 @@ func TestOCCTOAdapter_ParseCSV(t *testing.T) {
 	}
 }
 
+func TestOCCTOAdapter_ParseCSV_Blocks(t *testing.T) {
+	f, err := os.Open("testdata/occto-sample.csv")
+	if err != nil {
+		t.Fatalf("Failed to open test file: %v", err)
+	}
+	defer f.Close()
+
+	resp, err := NewOCCTOAdapter().ParseCSV(f, "2025-10-24")
+	if err != nil {
+		t.Fatalf("ParseCSV() error = %v", err)
+	}
+	if len(resp.Blocks) != 48 {
+		t.Fatalf("Blocks length = %d, want 48", len(resp.Blocks))
+	}
+
+	// 00:00: Tokyo shares reserve with Hokkaido and Tohoku
+	night := resp.Blocks[0]
+	if night.Timestamp != "2025-10-24T00:00:00+09:00" || len(night.Blocks) != 3 {
+		t.Fatalf("Blocks[0] = %+v, want 3 blocks at 00:00", night)
+	}
+	east := night.Blocks[0]
+	if east.No != 1 || !reflect.DeepEqual(east.Areas, []string{"hokkaido", "tohoku", "tokyo"}) {
+		t.Errorf("block 1 at 00:00 = %d %v, want hokkaido, tohoku, tokyo", east.No, east.Areas)
+	}
+	if east.DemandMW != 49073 || east.CapacityMW != 53950 || east.ReserveMW != 4877 {
+		t.Errorf("block 1 totals = %v/%v/%v, want 49073/53950/4877", east.DemandMW, east.CapacityMW, east.ReserveMW)
+	}
+
+	// 17:30: Tokyo is split into its own block, so the block margin is Tokyo's
+	peak := resp.Blocks[35]
+	var nos []int
+	for _, b := range peak.Blocks {
+		nos = append(nos, b.No)
+	}
+	if !reflect.DeepEqual(nos, []int{1, 2, 3, 4}) {
+		t.Fatalf("blocks at 17:30 = %v, want [1 2 3 4]", nos)
+	}
+	if tokyoBlock := peak.Blocks[3]; !reflect.DeepEqual(tokyoBlock.Areas, []string{"tokyo"}) || tokyoBlock.Status != reserve.StatusTight {
+		t.Errorf("block 4 at 17:30 = %v %v, want tokyo alone, tight", tokyoBlock.Areas, tokyoBlock.Status)
+	}
+
+	// Area series points carry their block and its margin
+	var tokyo reserve.AreaReserve
+	for _, ar := range resp.Areas {
+		if ar.Area == "tokyo" {
+			tokyo = ar
+		}
+	}
+	first := tokyo.Series[0]
+	if first.Block != 1 || first.BlockMarginPct == nil || *first.BlockMarginPct != reserve.MarginPct(49073, 53950) {
+		t.Errorf("Tokyo series[0] block = %d %v, want block 1 margin", first.Block, first.BlockMarginPct)
+	}
+	if min := tokyo.Minimum; min.Block != 4 || min.BlockMarginPct == nil || *min.BlockMarginPct != min.ReserveMarginPct {
+		t.Errorf("Tokyo Minimum block = %d %v, want block 4 at the area margin", min.Block, min.BlockMarginPct)
+	}
+}
+
+func TestOCCTOAdapter_ParseDemandCSV(t *testing.T) {
+	adapter := NewOCCTOAdapter()
+
+	f, err := os.Open("testdata/occto-sample.csv")
+	if err != nil {
+		t.Fatalf("failed to open test file: %v", err)
+	}
+	defer f.Close()
+
+	resp, err := adapter.ParseDemandCSV(f, "2025-10-24", demand.AreaKyushu)
+	if err != nil {
+		t.Fatalf("ParseDemandCSV() error = %v", err)
+	}
+
+	if resp.Timescale != demand.Timescale30Min {
+		t.Errorf("Timescale = %q, want %q", resp.Timescale, demand.Timescale30Min)
+	}
+	if len(resp.Series) != 48 {
+		t.Fatalf("Series length = %d, want 48", len(resp.Series))
+	}
+	if got := resp.Series[1].Timestamp.Format("15:04"); got != "00:30" {
+		t.Errorf("Series[1] time = %s, want 00:30", got)
+	}
+
+	// Resample to hourly averages each pair of half-hour slots
+	hourly, err := resp.Resample(demand.TimescaleHourly)
+	if err != nil {
+		t.Fatalf("Resample() error = %v", err)
+	}
+	if len(hourly.Series) != 24 {
+		t.Fatalf("hourly Series length = %d, want 24", len(hourly.Series))
+	}
+	want := (resp.Series[0].DemandMW + resp.Series[1].DemandMW) / 2
+	if hourly.Series[0].DemandMW != want {
+		t.Errorf("hourly Series[0] = %v, want %v", hourly.Series[0].DemandMW, want)
+	}
+	if resp.Timescale != demand.Timescale30Min || len(resp.Series) != 48 {
+		t.Error("Resample() modified the receiver")
+	}
+}
+
 func TestOCCTOAdapter_normalizeArea(t *testing.T) {
 	adapter := NewOCCTOAdapter()
 
@@ -119,6 +239,10 @@ func TestOCCTOAdapter_normalizeArea(t *testing.T) {
 		{"関西", "kansai"},
 		{"Kansai", "kansai"},
 		{"KANSAI", "kansai"},
+		{"九州", "kyushu"},
+		{"北海道", "hokkaido"},
+		{"沖縄", "okinawa"},
+		{"unknown", ""},
 	}
 
 	for _, tt := range tests {
@@ -153,3 +277,62 @@ func TestReserveDeriveStatus(t *testing.T) {
 		})
 	}
 }
+
+func TestOCCTOAdapter_ParseGenerationMixCSV(t *testing.T) {
+	tests := []struct {
+		name        string
+		csv         string
+		wantPumped  float64
+		wantBattery float64
+	}{
+		{
+			name: "signed storage columns",
+			csv: `"2025/10/24 UPDATE"
+"対象年月日","対象時刻","エリア名","原子力","火力(LNG)","火力(石炭)","火力(石油)","火力(その他)","水力","地熱","バイオマス","太陽光発電実績","太陽光出力制御量","風力発電実績","風力出力制御量","揚水","蓄電池","連系線","その他","合計"
+"2025/10/24","12:00","九州",4000,1500,1000,100,50,600,150,300,"7,000",800,200,20,-1800,-150,-1500,30,11580
+`,
+			wantPumped:  -1800,
+			wantBattery: -150,
+		},
+		{
+			name: "split storage columns",
+			csv: `"2025/10/24 UPDATE"
+"対象年月日","対象時刻","エリア名","原子力","火力(LNG)","火力(石炭)","火力(石油)","火力(その他)","水力","地熱","バイオマス","太陽光発電実績","太陽光出力制御量","風力発電実績","風力出力制御量","揚水(発電)","揚水動力","蓄電池(放電)","蓄電池(充電)","連系線","その他"
+"2025/10/24","12:00","九州",4000,1500,1000,100,50,600,150,300,7000,800,200,20,0,1800,0,150,-1500,30
+`,
+			wantPumped:  -1800,
+			wantBattery: -150,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := NewOCCTOAdapter().ParseGenerationMixCSV(strings.NewReader(tt.csv), "2025-10-24", "kyushu")
+			if err != nil {
+				t.Fatalf("ParseGenerationMixCSV() error = %v", err)
+			}
+			if len(resp.Series) != 1 {
+				t.Fatalf("got %d points, want 1", len(resp.Series))
+			}
+
+			p := resp.Series[0]
+			if p.SolarMW != 7000 || p.CurtailedSolarMW != 800 || p.WindMW != 200 || p.CurtailedWindMW != 20 {
+				t.Errorf("solar/wind = %v (-%v) / %v (-%v), want 7000 (-800) / 200 (-20)", p.SolarMW, p.CurtailedSolarMW, p.WindMW, p.CurtailedWindMW)
+			}
+			if p.OilMW != 100 || p.OtherMW != 80 || p.GeothermalMW != 150 || p.BiomassMW != 300 || p.HydroMW != 600 {
+				t.Errorf("oil/other/geothermal/biomass/hydro = %v/%v/%v/%v/%v", p.OilMW, p.OtherMW, p.GeothermalMW, p.BiomassMW, p.HydroMW)
+			}
+			if p.PumpedStorageMW != tt.wantPumped || p.BatteryMW != tt.wantBattery || p.InterconnectorMW != -1500 {
+				t.Errorf("pumped/battery/interconnector = %v/%v/%v", p.PumpedStorageMW, p.BatteryMW, p.InterconnectorMW)
+			}
+
+			// Generation 14930 MW, less 1800 pumping, 150 charging and 1500 exports
+			if p.GeneratedMW() != 14930 || p.TotalMW != 11480 {
+				t.Errorf("generated/total = %v/%v, want 14930/11480", p.GeneratedMW(), p.TotalMW)
+			}
+			if resp.Meta.PumpingMWh != 900 {
+				t.Errorf("PumpingMWh = %v, want 900", resp.Meta.PumpingMWh)
+			}
+		})
+	}
+}
@@ -39,6 +39,9 @@ func NewTEPCOAdapter() *TEPCOAdapter {
 // - Header detection by column names (order may vary)
 // - Units: 万kW (10,000 kW) = 10 MW → convert to MW
 // - Forecast may be empty for some rows
+// - The 5-minute section (当日実績(5分間隔値)) is averaged into 48 half-hour
+//   points (timescale 30min, hourly forecast on both halves); incomplete half
+//   hours are left out. Files without it give hourly points
 func (a *TEPCOAdapter) ParseCSV(reader io.Reader, date string) (*demand.Response, error) {
 	// Convert from Shift-JIS to UTF-8
 	// TEPCO CSV files are encoded in Shift-JIS (Japanese encoding)
@@ -81,10 +84,43 @@ func (a *TEPCOAdapter) ParseCSV(reader io.Reader, date string) (*demand.Response
 		return nil, fmt.Errorf("invalid date: %w", err)
 	}
 
+	normalizedDate := a.normalizeDate(date)
 	hasForecast := false
 	lineNum := 1
 	seenHours := make(map[int]bool) // Track hours to avoid duplicates
 
+	// Hourly points and forecasts come from the hourly section; the 5-minute
+	// section (当日実績(5分間隔値)), when present, is averaged into 30-minute points
+	var hourly []demand.SeriesPoint
+	forecastByHour := make(map[int]*float64)
+	fiveMinute := make([][]float64, timeutil.SlotsPerDay) // Demand MW of each 5-minute row per slot
+
+	// Rows are buffered per section (each starts with a DATE header): a section
+	// is the 5-minute one if any of its rows is off the hour
+	var section []tepcoRow
+	sectionFiveMinute := false
+	flush := func() {
+		for _, row := range section {
+			if sectionFiveMinute {
+				slot := row.hour*2 + row.minutes/30
+				fiveMinute[slot] = append(fiveMinute[slot], row.demandMW)
+				continue
+			}
+			// Skip non-hourly and duplicate rows (CSV contains multiple blocks with same hours)
+			if row.minutes != 0 || seenHours[row.hour] {
+				continue
+			}
+			seenHours[row.hour] = true
+			forecastByHour[row.hour] = row.forecastMW
+			hourly = append(hourly, demand.SeriesPoint{
+				Timestamp:  time.Date(baseDate.Year(), baseDate.Month(), baseDate.Day(), row.hour, 0, 0, 0, timeutil.TokyoLocation),
+				DemandMW:   row.demandMW,
+				ForecastMW: row.forecastMW,
+			})
+		}
+		section, sectionFiveMinute = nil, false
+	}
+
 	// Read data rows
 	for {
 		record, err := csvReader.Read()
@@ -96,68 +132,84 @@ func (a *TEPCOAdapter) ParseCSV(reader io.Reader, date string) (*demand.Response
 		}
 		lineNum++
 
+		// A new section starts with its own header
+		if len(record) > 0 && strings.ToUpper(strings.TrimSpace(record[0])) == "DATE" {
+			flush()
+			if cols := a.detectColumns(record); cols["date"] != -1 && cols["time"] != -1 && cols["actual"] != -1 {
+				colIndices = cols
+			}
+			continue
+		}
+		if len(record) <= colIndices["actual"] || len(record) <= colIndices["time"] || len(record) <= colIndices["date"] {
+			continue
+		}
+
 		// Extract date and time
 		rowDate := strings.TrimSpace(record[colIndices["date"]])
 		rowTime := strings.TrimSpace(record[colIndices["time"]])
 
-		// Normalize date format (2025/11/1 → 2025-11-01)
-		normalizedRowDate := a.normalizeDate(rowDate)
-		normalizedDate := a.normalizeDate(date)
-
-		// Only include rows matching the requested date
-		if normalizedRowDate != normalizedDate {
+		// Only include rows matching the requested date (2025/11/1 → 2025-11-01)
+		if a.normalizeDate(rowDate) != normalizedDate {
 			continue
 		}
 
-		// Parse hour from time string (e.g., "0:00", "13:00")
-		// Filter only hourly data (skip 5-minute intervals like "0:05", "0:10")
+		// Parse hour and minutes from time string (e.g., "0:00", "13:00", "0:05")
 		hour, minutes, err := a.parseTime(rowTime)
 		if err != nil {
 			return nil, fmt.Errorf("invalid time format at line %d: %s", lineNum, rowTime)
 		}
-
-		// Skip non-hourly data points (only include :00 minutes)
 		if minutes != 0 {
-			continue
+			sectionFiveMinute = true
 		}
 
-		// Skip duplicate hours (CSV contains multiple blocks with same hours)
-		if seenHours[hour] {
+		// Parse actual demand (万kW → MW); empty values are not measured yet
+		actualStr := strings.TrimSpace(record[colIndices["actual"]])
+		if actualStr == "" && sectionFiveMinute {
 			continue
 		}
-		seenHours[hour] = true
-
-		// Create timestamp
-		ts := time.Date(baseDate.Year(), baseDate.Month(), baseDate.Day(), hour, 0, 0, 0, timeutil.TokyoLocation)
-
-		// Parse actual demand (万kW → MW)
-		actualStr := strings.TrimSpace(record[colIndices["actual"]])
 		actual, err := strconv.ParseFloat(actualStr, 64)
 		if err != nil {
 			return nil, fmt.Errorf("invalid actual value at line %d: %s", lineNum, actualStr)
 		}
-		demandMW := actual * 10.0 // 万kW to MW
+		row := tepcoRow{hour: hour, minutes: minutes, demandMW: actual * 10.0} // 万kW to MW
 
 		// Parse forecast if column exists and value is present
-		var forecastMW *float64
 		if colIndices["forecast"] != -1 && colIndices["forecast"] < len(record) {
 			forecastStr := strings.TrimSpace(record[colIndices["forecast"]])
 			if forecastStr != "" {
 				forecast, err := strconv.ParseFloat(forecastStr, 64)
 				if err == nil {
 					fVal := forecast * 10.0
-					forecastMW = &fVal
+					row.forecastMW = &fVal
 					hasForecast = true
 				}
 			}
 		}
 
-		point := demand.SeriesPoint{
-			Timestamp:  ts,
-			DemandMW:   demandMW,
-			ForecastMW: forecastMW,
+		section = append(section, row)
+	}
+	flush()
+
+	// Average complete half hours (six 5-minute values) of the 5-minute
+	// section; the hourly forecast applies to both halves of its hour
+	for slot, values := range fiveMinute {
+		if len(values) < 6 {
+			continue // Not measured yet
+		}
+		sum := 0.0
+		for _, v := range values {
+			sum += v
 		}
-		resp.Series = append(resp.Series, point)
+		resp.Series = append(resp.Series, demand.SeriesPoint{
+			Timestamp:  timeutil.SlotTime(baseDate, slot),
+			DemandMW:   sum / float64(len(values)),
+			ForecastMW: forecastByHour[slot/2],
+		})
+	}
+	if len(resp.Series) > 0 {
+		resp.Timescale = demand.Timescale30Min
+	} else {
+		resp.Series = hourly
 	}
 
 	// Validate we have data
@@ -175,6 +227,13 @@ func (a *TEPCOAdapter) ParseCSV(reader io.Reader, date string) (*demand.Response
 	return resp, nil
 }
 
+// tepcoRow is one parsed data row of a TEPCO CSV section.
+type tepcoRow struct {
+	hour, minutes int
+	demandMW      float64
+	forecastMW    *float64
+}
+
 // detectColumns finds column indices by header names.
 // Returns map with keys: date, time, actual, forecast.
 func (a *TEPCOAdapter) detectColumns(header []string) map[string]int {
@@ -192,8 +251,8 @@ func (a *TEPCOAdapter) detectColumns(header []string) map[string]int {
 			indices["date"] = i
 		case strings.Contains(col, "time") || col == "時刻":
 			indices["time"] = i
-		case strings.Contains(col, "実績") || strings.Contains(col, "actual"):
-			indices["actual"] = i
+		case indices["actual"] == -1 && (strings.Contains(col, "実績") || strings.Contains(col, "actual")):
+			indices["actual"] = i // 当日実績 before 太陽光発電実績
 		case strings.Contains(col, "予測") || strings.Contains(col, "予想") || strings.Contains(col, "forecast"):
 			indices["forecast"] = i
 		}
@@ -1,7 +1,9 @@
 package adapters
 
 import (
+	"math"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/teo/aversome/backend/internal/demand"
@@ -22,7 +24,7 @@ func TestTEPCOAdapter_ParseCSV(t *testing.T) {
 			csvFile:    "testdata/tepco-sample.csv",
 			date:       "2025-10-24",
 			wantArea:   demand.AreaTokyo,
-			wantSeries: 24,
+			wantSeries: 48,
 		},
 	}
 
@@ -66,18 +68,25 @@ func TestTEPCOAdapter_ParseCSV(t *testing.T) {
 			if len(resp.Series) != tt.wantSeries {
 				t.Errorf("Series length = %d, want %d", len(resp.Series), tt.wantSeries)
 			}
+			if resp.Timescale != demand.Timescale30Min {
+				t.Errorf("Timescale = %v, want 30min", resp.Timescale)
+			}
 
 			// Check first and last points
 			if len(resp.Series) > 0 {
 				first := resp.Series[0]
-				if first.DemandMW != 26654.0 { // 2665.4 * 10
-					t.Errorf("First DemandMW = %v, want 26654.0", first.DemandMW)
+				if math.Abs(first.DemandMW-26495.33) > 0.01 { // Average of 0:00-0:25, x10
+					t.Errorf("First DemandMW = %v, want 26495.33", first.DemandMW)
 				}
 				if first.ForecastMW == nil {
 					t.Error("First ForecastMW is nil, expected value")
 				} else if *first.ForecastMW != 27010.0 { // 2701.0 * 10
 					t.Errorf("First ForecastMW = %v, want 27010.0", *first.ForecastMW)
 				}
+				if second := resp.Series[1]; second.ForecastMW == nil || *second.ForecastMW != 27010.0 ||
+					second.Timestamp.Format("15:04") != "00:30" {
+					t.Errorf("Second point = %+v, want 00:30 with the 0:00 forecast", second)
+				}
 
 				last := resp.Series[len(resp.Series)-1]
 				if last.DemandMW != 28346.0 { // 2834.6 * 10
@@ -102,7 +111,37 @@ func TestTEPCOAdapter_ParseCSV(t *testing.T) {
 	}
 }
 
-func TestTEPCOAdapter_ParseHour(t *testing.T) {
+func TestTEPCOAdapter_ParseCSV_Sections(t *testing.T) {
+	hourly := "DATE,TIME,ACTUAL,FORECAST\n2025-10-24,0:00,2665.4,2701.0\n2025-10-24,1:00,2589.2,2630.5\n"
+
+	// Without a 5-minute section the hourly rows are kept
+	resp, err := NewTEPCOAdapter().ParseCSV(strings.NewReader(hourly), "2025-10-24")
+	if err != nil {
+		t.Fatalf("ParseCSV(hourly) error = %v", err)
+	}
+	if len(resp.Series) != 2 || resp.Timescale != demand.TimescaleHourly || resp.Series[1].DemandMW != 25892.0 {
+		t.Errorf("hourly = %d points at %s, want 2 hourly points", len(resp.Series), resp.Timescale)
+	}
+
+	// A day in progress: only complete half hours are averaged
+	fiveMinute := "DATE,TIME,ACTUAL(5MIN),SOLAR ACTUAL(5MIN)\n"
+	for _, clock := range []string{"0:00", "0:05", "0:10", "0:15", "0:20", "0:25", "0:30", "0:35"} {
+		fiveMinute += "2025-10-24," + clock + ",2600.0,0\n"
+	}
+	fiveMinute += "2025-10-24,0:40,,\n"
+	resp, err = NewTEPCOAdapter().ParseCSV(strings.NewReader(hourly+"\n"+fiveMinute), "2025-10-24")
+	if err != nil {
+		t.Fatalf("ParseCSV(5-minute) error = %v", err)
+	}
+	if len(resp.Series) != 1 || resp.Timescale != demand.Timescale30Min {
+		t.Fatalf("5-minute = %d points at %s, want 1 30min point", len(resp.Series), resp.Timescale)
+	}
+	if p := resp.Series[0]; p.DemandMW != 26000.0 || p.ForecastMW == nil || *p.ForecastMW != 27010.0 {
+		t.Errorf("00:00 = %+v, want 26000 MW with the 0:00 forecast", p)
+	}
+}
+
+func TestTEPCOAdapter_ParseTime(t *testing.T) {
 	adapter := NewTEPCOAdapter()
 
 	tests := []struct {
@@ -120,12 +159,12 @@ func TestTEPCOAdapter_ParseHour(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.input, func(t *testing.T) {
-			hour, err := adapter.parseHour(tt.input)
+			hour, _, err := adapter.parseTime(tt.input)
 			if (err != nil) != tt.wantError {
-				t.Errorf("parseHour(%q) error = %v, wantError %v", tt.input, err, tt.wantError)
+				t.Errorf("parseTime(%q) error = %v, wantError %v", tt.input, err, tt.wantError)
 			}
 			if !tt.wantError && hour != tt.wantHour {
-				t.Errorf("parseHour(%q) = %d, want %d", tt.input, hour, tt.wantHour)
+				t.Errorf("parseTime(%q) = %d, want %d", tt.input, hour, tt.wantHour)
 			}
 		})
 	}
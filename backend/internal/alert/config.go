@@ -0,0 +1,118 @@
+package alert
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/teo/aversome/backend/pkg/mail"
+)
+
+// WebhookConfig configures one JSON webhook.
+type WebhookConfig struct {
+	URL     string            `json:"url"`
+	Headers map[string]string `json:"headers,omitempty"`
+}
+
+// Config holds the alert rules and delivery targets.
+type Config struct {
+	Rules    []Rule          `json:"rules"`
+	Webhooks []WebhookConfig `json:"webhooks,omitempty"`
+	Email    *EmailConfig    `json:"email,omitempty"`
+	SMTP     mail.Config     `json:"-"` // SMTP server for Email (SMTP_* variables)
+}
+
+// LoadConfig reads the alert configuration from environment variables:
+//   - ALERT_CONFIG: JSON file with "rules" and "webhooks" (default: DefaultRules, no webhooks)
+//   - ALERT_WEBHOOK_URLS: comma-separated webhook URLs, added to the file's webhooks
+//   - ALERT_EMAIL_TO: comma-separated recipients, added to the file's email.to
+//   - ALERT_EMAIL_LANG: email language, "ja" (default) or "en"
+//
+// Email delivery also needs the SMTP_* variables (see mail.LoadConfig).
+func LoadConfig() (Config, error) {
+	var cfg Config
+
+	if path := os.Getenv("ALERT_CONFIG"); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return Config{}, fmt.Errorf("failed to read alert config: %w", err)
+		}
+		if err := json.Unmarshal(data, &cfg); err != nil {
+			return Config{}, fmt.Errorf("failed to parse alert config %s: %w", path, err)
+		}
+	}
+	if len(cfg.Rules) == 0 {
+		cfg.Rules = DefaultRules()
+	}
+	if err := ValidateRules(cfg.Rules); err != nil {
+		return Config{}, err
+	}
+
+	for _, url := range strings.Split(os.Getenv("ALERT_WEBHOOK_URLS"), ",") {
+		if url = strings.TrimSpace(url); url != "" {
+			cfg.Webhooks = append(cfg.Webhooks, WebhookConfig{URL: url})
+		}
+	}
+	for _, w := range cfg.Webhooks {
+		if !strings.HasPrefix(w.URL, "http://") && !strings.HasPrefix(w.URL, "https://") {
+			return Config{}, fmt.Errorf("webhook URL %q must be http or https", w.URL)
+		}
+	}
+
+	if err := cfg.loadEmail(); err != nil {
+		return Config{}, err
+	}
+
+	return cfg, nil
+}
+
+// loadEmail applies the ALERT_EMAIL_* variables and, when email has
+// recipients, loads and validates the SMTP server.
+func (c *Config) loadEmail() error {
+	var to []string
+	for _, addr := range strings.Split(os.Getenv("ALERT_EMAIL_TO"), ",") {
+		if addr = strings.TrimSpace(addr); addr != "" {
+			to = append(to, addr)
+		}
+	}
+	if len(to) > 0 {
+		if c.Email == nil {
+			c.Email = &EmailConfig{}
+		}
+		c.Email.To = append(c.Email.To, to...)
+	}
+	if c.Email == nil || len(c.Email.To) == 0 {
+		c.Email = nil
+		return nil
+	}
+
+	lang := string(c.Email.Lang)
+	if s := os.Getenv("ALERT_EMAIL_LANG"); s != "" {
+		lang = s
+	}
+	var err error
+	if c.Email.Lang, err = mail.ParseLang(lang); err != nil {
+		return fmt.Errorf("alert email: %w", err)
+	}
+
+	if c.SMTP, err = mail.LoadConfig(); err != nil {
+		return err
+	}
+	if err := c.SMTP.Validate(); err != nil {
+		return fmt.Errorf("alert email: %w", err)
+	}
+	return nil
+}
+
+// Notifiers builds the configured notifiers.
+func (c Config) Notifiers() []Notifier {
+	out := make([]Notifier, 0, len(c.Webhooks)+1)
+	for _, w := range c.Webhooks {
+		out = append(out, NewWebhook(w))
+	}
+	if c.Email != nil {
+		out = append(out, NewEmail(mail.NewSender(c.SMTP), *c.Email))
+	}
+	return out
+}
@@ -0,0 +1,146 @@
+package alert
+
+import (
+	"embed"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/teo/aversome/backend/internal/areas"
+	"github.com/teo/aversome/backend/pkg/mail"
+	"github.com/teo/aversome/backend/pkg/timeutil"
+)
+
+//go:embed templates/*
+var templateFS embed.FS
+
+// emailTemplates renders alerts.{ja,en}.{txt,html}.
+var emailTemplates = mail.MustParseTemplates(templateFS, "templates/alerts", templateFuncs)
+
+// EmailConfig configures alert email delivery; the SMTP server comes from
+// mail.LoadConfig.
+type EmailConfig struct {
+	To   []string  `json:"to"`
+	Lang mail.Lang `json:"lang,omitempty"` // "ja" (default) or "en"
+}
+
+// Email sends each delivery of alerts as one email.
+type Email struct {
+	sender *mail.Sender
+	to     []string
+	lang   mail.Lang
+}
+
+// NewEmail creates an email notifier.
+func NewEmail(sender *mail.Sender, cfg EmailConfig) *Email {
+	lang := cfg.Lang
+	if lang == "" {
+		lang = mail.LangJA
+	}
+	return &Email{sender: sender, to: cfg.To, lang: lang}
+}
+
+// Name identifies the notifier in delivery errors.
+func (e *Email) Name() string {
+	return "email " + strings.Join(e.to, ",")
+}
+
+// Notify sends the alerts in one message.
+func (e *Email) Notify(alerts []Alert) error {
+	msg, err := RenderEmail(alerts, e.lang)
+	if err != nil {
+		return err
+	}
+	msg.To = e.to
+	return e.sender.Send(msg)
+}
+
+// emailData is the template data of an alert email.
+type emailData struct {
+	Alerts   []Alert
+	Critical int // Number of critical alerts
+}
+
+// RenderEmail renders the alert email in lang, without recipients.
+func RenderEmail(alerts []Alert, lang mail.Lang) (mail.Message, error) {
+	data := emailData{Alerts: alerts}
+	for _, a := range alerts {
+		if a.Severity == SeverityCritical {
+			data.Critical++
+		}
+	}
+	return emailTemplates.Render(lang, data)
+}
+
+// templateFuncs returns the localized helpers of the alert templates:
+// area (area name), severity (label), at (HH:MM of an ISO8601 timestamp)
+// and describe (one-line description of an alert).
+func templateFuncs(lang mail.Lang) map[string]any {
+	return map[string]any{
+		"area": func(code string) string { return areaName(code, lang) },
+		"severity": func(s Severity) string {
+			if lang == mail.LangEN {
+				return strings.ToUpper(string(s))
+			}
+			return map[Severity]string{SeverityInfo: "情報", SeverityWarning: "警告", SeverityCritical: "重大"}[s]
+		},
+		"at":       clock,
+		"describe": func(a Alert) string { return Describe(a, lang) },
+	}
+}
+
+// areaName localizes an area code ("system" is the JEPX system price).
+func areaName(code string, lang mail.Lang) string {
+	if code == SystemArea {
+		if lang == mail.LangEN {
+			return "System"
+		}
+		return "システム"
+	}
+	a, ok := areas.Lookup(areas.Code(code))
+	if !ok {
+		return code
+	}
+	if lang == mail.LangEN {
+		return a.NameEN
+	}
+	return a.NameJA
+}
+
+// clock formats an ISO8601 timestamp as HH:MM (Asia/Tokyo), or "" if invalid.
+func clock(ts string) string {
+	t, err := time.Parse(time.RFC3339, ts)
+	if err != nil {
+		return ""
+	}
+	return t.In(timeutil.TokyoLocation).Format("15:04")
+}
+
+// Describe returns the alert's one-line description in lang: Alert.Message
+// in English, or its Japanese counterpart.
+func Describe(a Alert, lang mail.Lang) string {
+	if lang == mail.LangEN {
+		return a.Message
+	}
+	return describeJA(a)
+}
+
+// describeJA is the Japanese counterpart of Alert.Message.
+func describeJA(a Alert) string {
+	at := ""
+	if c := clock(a.Timestamp); c != "" {
+		at = "（" + c + "）"
+	}
+	name := areaName(a.Area, mail.LangJA)
+
+	switch a.Kind {
+	case KindReserveMargin:
+		return fmt.Sprintf("%s %s の予備率 %.2f%%%sが閾値 %.2f%% を下回りました", a.Date, name, a.Value, at, a.Threshold)
+	case KindSpotPrice:
+		return fmt.Sprintf("%s %s のスポット価格 %.2f円/kWh%sが閾値 %.2f円/kWh を超えました", a.Date, name, a.Value, at, a.Threshold)
+	case KindSpread:
+		return fmt.Sprintf("%s JEPX エリア間値差 %.2f円/kWh%sが閾値 %.2f円/kWh を超えました", a.Date, a.Value, at, a.Threshold)
+	default:
+		return fmt.Sprintf("%s %s の需要が予測から %.1f%%%s乖離しました（閾値 %.1f%%）", a.Date, name, a.Value, at, a.Threshold)
+	}
+}
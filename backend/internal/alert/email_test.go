@@ -0,0 +1,82 @@
+package alert
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/teo/aversome/backend/pkg/mail"
+)
+
+func TestRenderEmail(t *testing.T) {
+	alerts := []Alert{
+		{RuleID: "reserve-critical", Kind: KindReserveMargin, Severity: SeverityCritical, Area: "tokyo", Date: "2025-10-24",
+			Timestamp: "2025-10-24T17:30:00+09:00", Value: 2.05, Threshold: 3,
+			Message: "tokyo reserve margin 2.05% at 17:30 on 2025-10-24 is below 3.00%"},
+		{RuleID: "spread-wide", Kind: KindSpread, Severity: SeverityWarning, Area: SystemArea, Date: "2025-10-24",
+			Timestamp: "2025-10-24T18:00:00+09:00", Value: 7, Threshold: 5,
+			Message: "JEPX area price spread 7.00 JPY/kWh at 18:00 on 2025-10-24 is above 5.00 JPY/kWh"},
+	}
+
+	tests := []struct {
+		lang        mail.Lang
+		wantSubject string
+		wantText    []string
+		wantHTML    []string
+	}{
+		{mail.LangJA, "【需給アラート】2件（重大 1件）",
+			[]string{"[重大] 2025-10-24 東京 の予備率 2.05%（17:30）が閾値 3.00% を下回りました", "[警告] 2025-10-24 JEPX エリア間値差 7.00円/kWh（18:00）"},
+			[]string{"<td>東京</td><td>17:30</td>", "<td>システム</td>"}},
+		{mail.LangEN, "[Energy alert] 2 new alerts (1 critical)",
+			[]string{"[CRITICAL] tokyo reserve margin 2.05% at 17:30 on 2025-10-24 is below 3.00%", "[WARNING] JEPX area price spread"},
+			[]string{"<td>Tokyo</td><td>17:30</td>", "<td>System</td>"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.lang), func(t *testing.T) {
+			msg, err := RenderEmail(alerts, tt.lang)
+			if err != nil {
+				t.Fatalf("RenderEmail() error = %v", err)
+			}
+			if msg.Subject != tt.wantSubject {
+				t.Errorf("Subject = %q, want %q", msg.Subject, tt.wantSubject)
+			}
+			for _, s := range tt.wantText {
+				if !strings.Contains(msg.Text, s) {
+					t.Errorf("text body missing %q:\n%s", s, msg.Text)
+				}
+			}
+			for _, s := range tt.wantHTML {
+				if !strings.Contains(msg.HTML, s) {
+					t.Errorf("HTML body missing %q:\n%s", s, msg.HTML)
+				}
+			}
+		})
+	}
+}
+
+func TestLoadConfig_Email(t *testing.T) {
+	t.Setenv("ALERT_CONFIG", "")
+	t.Setenv("ALERT_WEBHOOK_URLS", "")
+	t.Setenv("ALERT_EMAIL_TO", "ops@example.com, manager@example.com")
+	t.Setenv("ALERT_EMAIL_LANG", "en")
+	t.Setenv("SMTP_HOST", "localhost")
+	t.Setenv("SMTP_PORT", "1025")
+	t.Setenv("SMTP_FROM", "dashboard@example.com")
+
+	cfg, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if cfg.Email == nil || len(cfg.Email.To) != 2 || cfg.Email.Lang != mail.LangEN || cfg.SMTP.Port != 1025 {
+		t.Fatalf("email = %+v, smtp = %+v", cfg.Email, cfg.SMTP)
+	}
+	if n := cfg.Notifiers(); len(n) != 1 || n[0].Name() != "email ops@example.com,manager@example.com" {
+		t.Errorf("notifiers = %v", n)
+	}
+
+	// Recipients without an SMTP server are a configuration error
+	t.Setenv("SMTP_HOST", "")
+	if _, err := LoadConfig(); err == nil || !strings.Contains(err.Error(), "SMTP_HOST") {
+		t.Errorf("LoadConfig() error = %v, want missing SMTP_HOST", err)
+	}
+}
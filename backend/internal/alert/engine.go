@@ -0,0 +1,123 @@
+package alert
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/teo/aversome/backend/internal/storage"
+)
+
+// Engine evaluates rules against ingested documents, records fired alerts
+// per date in the store (dataset "alerts") and delivers new ones.
+type Engine struct {
+	rules     []Rule
+	store     storage.Store
+	notifiers []Notifier
+	now       func() time.Time
+
+	mu sync.Mutex // Serializes the load-append-save of alert records
+}
+
+// NewEngine creates an Engine.
+func NewEngine(rules []Rule, store storage.Store, notifiers []Notifier) *Engine {
+	return &Engine{rules: rules, store: store, notifiers: notifiers, now: time.Now}
+}
+
+// Observe evaluates an ingested document and returns the alerts that fired
+// for the first time. New alerts, and alerts of the same date whose earlier
+// delivery failed, are sent to every notifier (at least once); failures keep
+// them pending and are returned joined.
+func (e *Engine) Observe(doc any) ([]Alert, error) {
+	obs := observe(doc)
+	if len(obs) == 0 {
+		return nil, nil
+	}
+	date := obs[0].date
+	fired := evaluate(e.rules, obs)
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	day, err := e.Load(date)
+	if err != nil {
+		return nil, err
+	}
+
+	// De-duplicate against the alerts already recorded for the date
+	seen := make(map[string]bool, len(day.Alerts))
+	for _, a := range day.Alerts {
+		seen[a.Key] = true
+	}
+	var fresh []Alert
+	firedAt := e.now().Format(time.RFC3339)
+	for _, a := range fired {
+		if seen[a.Key] {
+			continue
+		}
+		seen[a.Key] = true
+		a.FiredAt = firedAt
+		a.Pending = len(e.notifiers) > 0
+		fresh = append(fresh, a)
+	}
+	day.Alerts = append(day.Alerts, fresh...)
+
+	var pending []int
+	for i, a := range day.Alerts {
+		if a.Pending {
+			pending = append(pending, i)
+		}
+	}
+	if len(fresh) == 0 && len(pending) == 0 {
+		return nil, nil
+	}
+
+	var deliverErr error
+	if len(pending) > 0 {
+		batch := make([]Alert, 0, len(pending))
+		for _, i := range pending {
+			a := day.Alerts[i]
+			a.Pending = false
+			batch = append(batch, a)
+		}
+		deliverErr = e.deliver(batch)
+		for _, i := range pending {
+			day.Alerts[i].Pending = deliverErr != nil
+		}
+	}
+
+	if _, err := e.store.Save(storage.DatasetAlerts, "", date, day); err != nil {
+		return fresh, errors.Join(deliverErr, fmt.Errorf("failed to save alerts: %w", err))
+	}
+	return fresh, deliverErr
+}
+
+// Load returns the alerts recorded for a date (empty if none).
+func (e *Engine) Load(date string) (*Day, error) {
+	day := &Day{Date: date, Alerts: []Alert{}}
+
+	data, err := e.store.Load(storage.DatasetAlerts, "", date)
+	if errors.Is(err, storage.ErrNotFound) {
+		return day, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, day); err != nil {
+		return nil, fmt.Errorf("failed to decode stored alerts: %w", err)
+	}
+	return day, nil
+}
+
+// deliver sends alerts to every notifier, joining their errors.
+func (e *Engine) deliver(alerts []Alert) error {
+	var errs []error
+	for _, n := range e.notifiers {
+		if err := n.Notify(alerts); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", n.Name(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
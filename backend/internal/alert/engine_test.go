@@ -0,0 +1,214 @@
+package alert
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/teo/aversome/backend/internal/reserve"
+	"github.com/teo/aversome/backend/internal/storage"
+)
+
+func tightDay(date string, pct float64) *reserve.Response {
+	return &reserve.Response{
+		Date:  date,
+		Areas: []reserve.AreaReserve{{Area: "tokyo", ReserveMarginPct: pct}},
+	}
+}
+
+func TestEngine_Observe(t *testing.T) {
+	var received []WebhookPayload
+	var fail atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			t.Errorf("missing webhook header")
+		}
+		if fail.Load() {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		var p WebhookPayload
+		if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+			t.Errorf("failed to decode payload: %v", err)
+		}
+		received = append(received, p)
+	}))
+	defer server.Close()
+
+	store := storage.NewFileStore(t.TempDir())
+	hook := NewWebhook(WebhookConfig{URL: server.URL, Headers: map[string]string{"Authorization": "Bearer secret"}})
+	engine := NewEngine(DefaultRules(), store, []Notifier{hook})
+
+	// First ingest fires and delivers reserve-tight
+	fired, err := engine.Observe(tightDay("2025-10-23", 4.0))
+	if err != nil {
+		t.Fatalf("Observe() error = %v", err)
+	}
+	if len(fired) != 1 || fired[0].RuleID != "reserve-tight" {
+		t.Fatalf("fired = %+v, want reserve-tight", fired)
+	}
+	if len(received) != 1 || received[0].Source != "japan-energy-dashboard" || len(received[0].Alerts) != 1 {
+		t.Fatalf("webhook received %+v", received)
+	}
+
+	// Re-ingest of the same day does not fire again
+	fired, err = engine.Observe(tightDay("2025-10-23", 3.5))
+	if err != nil || len(fired) != 0 || len(received) != 1 {
+		t.Fatalf("re-ingest: fired = %+v, err = %v, deliveries = %d", fired, err, len(received))
+	}
+
+	// A worse margin fires reserve-critical; delivery fails and stays pending
+	fail.Store(true)
+	fired, err = engine.Observe(tightDay("2025-10-23", 2.0))
+	if err == nil || !strings.Contains(err.Error(), "HTTP 502") {
+		t.Fatalf("Observe() error = %v, want HTTP 502", err)
+	}
+	if len(fired) != 1 || fired[0].RuleID != "reserve-critical" {
+		t.Fatalf("fired = %+v, want reserve-critical", fired)
+	}
+
+	day, err := engine.Load("2025-10-23")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(day.Alerts) != 2 || day.Alerts[0].Pending || !day.Alerts[1].Pending {
+		t.Fatalf("stored alerts = %+v, want reserve-critical pending", day.Alerts)
+	}
+
+	// The next ingest of the date retries the pending alert only
+	fail.Store(false)
+	if _, err := engine.Observe(tightDay("2025-10-23", 2.0)); err != nil {
+		t.Fatalf("retry: Observe() error = %v", err)
+	}
+	if len(received) != 2 || len(received[1].Alerts) != 1 || received[1].Alerts[0].RuleID != "reserve-critical" {
+		t.Fatalf("retry delivered %+v", received)
+	}
+	if day, _ := engine.Load("2025-10-23"); day.Alerts[1].Pending {
+		t.Error("reserve-critical still pending after retry")
+	}
+}
+
+// TestEngine_UpsertStore runs the engine twice against a store that upserts
+// by dataset, area and date like Postgres: the second run, as after a
+// restart, delivers nothing.
+func TestEngine_UpsertStore(t *testing.T) {
+	var deliveries atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		deliveries.Add(1)
+	}))
+	defer server.Close()
+
+	store := storage.NewMemoryStore()
+	hook := NewWebhook(WebhookConfig{URL: server.URL})
+
+	for run := 1; run <= 2; run++ {
+		engine := NewEngine(DefaultRules(), store, []Notifier{hook})
+		fired, err := engine.Observe(tightDay("2025-10-23", 4.0))
+		if err != nil {
+			t.Fatalf("run %d: Observe() error = %v", run, err)
+		}
+		if want := 2 - run; len(fired) != want {
+			t.Fatalf("run %d: fired = %+v, want %d alerts", run, fired, want)
+		}
+	}
+
+	if n := deliveries.Load(); n != 1 {
+		t.Errorf("deliveries = %d, want 1", n)
+	}
+	if n, _ := store.Count(); n != 1 {
+		t.Errorf("stored documents = %d, want 1", n)
+	}
+	day, err := NewEngine(DefaultRules(), store, nil).Load("2025-10-23")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(day.Alerts) != 1 || day.Alerts[0].Pending {
+		t.Errorf("stored alerts = %+v, want one delivered alert", day.Alerts)
+	}
+}
+
+func TestEngine_NoNotifiers(t *testing.T) {
+	engine := NewEngine(DefaultRules(), storage.NewFileStore(t.TempDir()), nil)
+
+	if _, err := engine.Observe(tightDay("2025-10-23", 4.0)); err != nil {
+		t.Fatalf("Observe() error = %v", err)
+	}
+	day, err := engine.Load("2025-10-23")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(day.Alerts) != 1 || day.Alerts[0].Pending || day.Alerts[0].FiredAt == "" {
+		t.Errorf("stored alerts = %+v, want one delivered alert", day.Alerts)
+	}
+
+	// Dates without alerts load empty
+	if day, err := engine.Load("2025-10-24"); err != nil || len(day.Alerts) != 0 {
+		t.Errorf("Load(empty) = %+v, %v", day, err)
+	}
+}
+
+func TestValidateRules(t *testing.T) {
+	tests := []struct {
+		name    string
+		rules   []Rule
+		wantErr string
+	}{
+		{"defaults", DefaultRules(), ""},
+		{"empty", nil, "no alert rules"},
+		{"missing id", []Rule{{Kind: KindSpread, Severity: SeverityInfo}}, "id is required"},
+		{"duplicate id", []Rule{
+			{ID: "a", Kind: KindSpread, Severity: SeverityInfo},
+			{ID: "a", Kind: KindSpotPrice, Severity: SeverityInfo},
+		}, "duplicate id"},
+		{"unknown kind", []Rule{{ID: "a", Kind: "frequency", Severity: SeverityInfo}}, "unknown kind"},
+		{"bad severity", []Rule{{ID: "a", Kind: KindSpread, Severity: "page"}}, "severity must be"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateRules(tt.rules)
+			if tt.wantErr == "" && err != nil {
+				t.Errorf("ValidateRules() error = %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Errorf("ValidateRules() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "alerts.json")
+	data := `{"rules":[{"id":"tokyo-tight","kind":"reserve_margin","areas":["tokyo"],"threshold":6,"severity":"warning"}],
+		"webhooks":[{"url":"https://hooks.example.com/a","headers":{"Authorization":"Bearer x"}}]}`
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("ALERT_CONFIG", path)
+	t.Setenv("ALERT_WEBHOOK_URLS", " https://hooks.example.com/b ,")
+
+	cfg, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if len(cfg.Rules) != 1 || cfg.Rules[0].ID != "tokyo-tight" {
+		t.Errorf("rules = %+v", cfg.Rules)
+	}
+	if len(cfg.Webhooks) != 2 || cfg.Webhooks[1].URL != "https://hooks.example.com/b" {
+		t.Errorf("webhooks = %+v", cfg.Webhooks)
+	}
+	if n := cfg.Notifiers(); len(n) != 2 || n[0].Name() != "webhook https://hooks.example.com/a" {
+		t.Errorf("notifiers = %v", n)
+	}
+
+	t.Setenv("ALERT_WEBHOOK_URLS", "ftp://hooks.example.com")
+	if _, err := LoadConfig(); err == nil {
+		t.Error("LoadConfig() accepted a non-HTTP webhook")
+	}
+}
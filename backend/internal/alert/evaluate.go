@@ -0,0 +1,145 @@
+package alert
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/teo/aversome/backend/internal/demand"
+	"github.com/teo/aversome/backend/internal/jepx"
+	"github.com/teo/aversome/backend/internal/reserve"
+	"github.com/teo/aversome/backend/internal/spread"
+	"github.com/teo/aversome/backend/pkg/timeutil"
+)
+
+// observation is the worst value of one kind for one area and date.
+type observation struct {
+	kind      Kind
+	area      string
+	date      string
+	timestamp string
+	value     float64
+}
+
+// Evaluate returns the alerts the rules fire on doc: a *reserve.Response,
+// *jepx.Response, *jepx.MarketResponse or *demand.Response. Other documents
+// yield none. Each rule is checked against the worst interval of the day.
+func Evaluate(rules []Rule, doc any) []Alert {
+	return evaluate(rules, observe(doc))
+}
+
+// evaluate checks every rule against the observations.
+func evaluate(rules []Rule, observations []observation) []Alert {
+	var alerts []Alert
+	for _, obs := range observations {
+		for _, r := range rules {
+			if r.Kind != obs.kind || !r.appliesTo(obs.area) || !r.fires(obs.value) {
+				continue
+			}
+			alerts = append(alerts, Alert{
+				Key:       fmt.Sprintf("%s/%s/%s", r.ID, obs.area, obs.date),
+				RuleID:    r.ID,
+				Kind:      r.Kind,
+				Severity:  r.Severity,
+				Area:      obs.area,
+				Date:      obs.date,
+				Timestamp: obs.timestamp,
+				Value:     obs.value,
+				Threshold: r.Threshold,
+				Message:   message(r, obs),
+			})
+		}
+	}
+	return alerts
+}
+
+// observe extracts the worst value per kind and area from a document.
+func observe(doc any) []observation {
+	var out []observation
+
+	switch d := doc.(type) {
+	case *reserve.Response:
+		for _, a := range d.Areas {
+			obs := observation{kind: KindReserveMargin, area: a.Area, date: d.Date, value: a.ReserveMarginPct}
+			if a.Minimum != nil {
+				obs.timestamp, obs.value = a.Minimum.Timestamp, a.Minimum.ReserveMarginPct
+			}
+			out = append(out, obs)
+		}
+
+	case *jepx.Response:
+		if obs, ok := maxOf(KindSpotPrice, d.Area, d.Date, len(d.PriceYenPerKwh), func(i int) (string, float64, bool) {
+			return d.PriceYenPerKwh[i].Timestamp, d.PriceYenPerKwh[i].Price, true
+		}); ok {
+			out = append(out, obs)
+		}
+
+	case *jepx.MarketResponse:
+		if obs, ok := maxOf(KindSpotPrice, SystemArea, d.Date, len(d.Series), func(i int) (string, float64, bool) {
+			return d.Series[i].Timestamp, d.Series[i].SystemPrice, true
+		}); ok {
+			out = append(out, obs)
+		}
+		for _, area := range d.Areas {
+			if obs, ok := maxOf(KindSpotPrice, area, d.Date, len(d.Series), func(i int) (string, float64, bool) {
+				price, ok := d.Series[i].AreaPrices[area]
+				return d.Series[i].Timestamp, price, ok
+			}); ok {
+				out = append(out, obs)
+			}
+		}
+		if obs, ok := maxOf(KindSpread, SystemArea, d.Date, len(d.Series), func(i int) (string, float64, bool) {
+			p := spread.DetectPeriod(d.Series[i], d.Areas, spread.DefaultTolerance)
+			return p.Timestamp, p.MaxSpread, true
+		}); ok {
+			out = append(out, obs)
+		}
+
+	case *demand.Response:
+		if obs, ok := maxOf(KindDemandForecast, string(d.Area), d.Date, len(d.Series), func(i int) (string, float64, bool) {
+			p := d.Series[i]
+			if p.ForecastMW == nil || *p.ForecastMW <= 0 {
+				return "", 0, false
+			}
+			return timeutil.FormatISO8601(p.Timestamp), math.Abs(p.DemandMW-*p.ForecastMW) / *p.ForecastMW * 100, true
+		}); ok {
+			out = append(out, obs)
+		}
+	}
+
+	return out
+}
+
+// maxOf returns the highest of n values (the first on ties), skipping those
+// at(i) marks as missing.
+func maxOf(kind Kind, area, date string, n int, at func(i int) (string, float64, bool)) (observation, bool) {
+	obs := observation{kind: kind, area: area, date: date}
+	found := false
+	for i := 0; i < n; i++ {
+		ts, v, ok := at(i)
+		if !ok || (found && v <= obs.value) {
+			continue
+		}
+		obs.timestamp, obs.value, found = ts, v, true
+	}
+	return obs, found
+}
+
+// message describes a fired rule for humans.
+func message(r Rule, obs observation) string {
+	at := ""
+	if t, err := time.Parse(time.RFC3339, obs.timestamp); err == nil {
+		at = " at " + t.In(timeutil.TokyoLocation).Format("15:04")
+	}
+
+	switch r.Kind {
+	case KindReserveMargin:
+		return fmt.Sprintf("%s reserve margin %.2f%%%s on %s is below %.2f%%", obs.area, obs.value, at, obs.date, r.Threshold)
+	case KindSpotPrice:
+		return fmt.Sprintf("%s spot price %.2f JPY/kWh%s on %s is above %.2f JPY/kWh", obs.area, obs.value, at, obs.date, r.Threshold)
+	case KindSpread:
+		return fmt.Sprintf("JEPX area price spread %.2f JPY/kWh%s on %s is above %.2f JPY/kWh", obs.value, at, obs.date, r.Threshold)
+	default:
+		return fmt.Sprintf("%s demand is %.1f%% off forecast%s on %s (above %.1f%%)", obs.area, obs.value, at, obs.date, r.Threshold)
+	}
+}
@@ -0,0 +1,120 @@
+package alert
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/teo/aversome/backend/internal/demand"
+	"github.com/teo/aversome/backend/internal/jepx"
+	"github.com/teo/aversome/backend/internal/reserve"
+	"github.com/teo/aversome/backend/pkg/timeutil"
+)
+
+func TestEvaluate_Reserve(t *testing.T) {
+	doc := &reserve.Response{
+		Date: "2025-10-23",
+		Areas: []reserve.AreaReserve{
+			{Area: "tokyo", ReserveMarginPct: 6.1, Minimum: &reserve.Minimum{Timestamp: "2025-10-23T17:30:00+09:00", ReserveMarginPct: 2.5}},
+			{Area: "kansai", ReserveMarginPct: 4.2},
+			{Area: "kyushu", ReserveMarginPct: 12.0, Minimum: &reserve.Minimum{Timestamp: "2025-10-23T18:00:00+09:00", ReserveMarginPct: 9.0}},
+		},
+	}
+
+	got := Evaluate(DefaultRules(), doc)
+
+	want := []string{
+		"reserve-tight/tokyo/2025-10-23",
+		"reserve-critical/tokyo/2025-10-23",
+		"reserve-tight/kansai/2025-10-23",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Expected %d alerts, got %d: %+v", len(want), len(got), got)
+	}
+	for i, key := range want {
+		if got[i].Key != key {
+			t.Errorf("alert %d: key = %s, want %s", i, got[i].Key, key)
+		}
+	}
+
+	// Tokyo fires on its tightest interval, not the daily average
+	if got[1].Value != 2.5 || got[1].Severity != SeverityCritical || got[1].Timestamp != "2025-10-23T17:30:00+09:00" {
+		t.Errorf("critical alert = %+v", got[1])
+	}
+	if !strings.Contains(got[1].Message, "at 17:30") {
+		t.Errorf("message %q should name the interval", got[1].Message)
+	}
+}
+
+func TestEvaluate_Prices(t *testing.T) {
+	rules := []Rule{
+		{ID: "spike", Kind: KindSpotPrice, Threshold: 30, Severity: SeverityWarning},
+		{ID: "spike-tokyo", Kind: KindSpotPrice, Areas: []string{"tokyo"}, Threshold: 20, Severity: SeverityInfo},
+		{ID: "spread", Kind: KindSpread, Threshold: 5, Severity: SeverityWarning},
+	}
+
+	t.Run("area prices", func(t *testing.T) {
+		doc := jepx.NewResponse("2025-10-23", "kansai")
+		doc.PriceYenPerKwh = []jepx.PricePoint{
+			{Timestamp: "2025-10-23T17:00:00+09:00", Price: 25},
+			{Timestamp: "2025-10-23T17:30:00+09:00", Price: 31.5},
+		}
+
+		got := Evaluate(rules, doc)
+		if len(got) != 1 || got[0].Key != "spike/kansai/2025-10-23" || got[0].Value != 31.5 {
+			t.Errorf("Evaluate() = %+v, want one spike alert for kansai at 31.5", got)
+		}
+	})
+
+	t.Run("market", func(t *testing.T) {
+		doc := &jepx.MarketResponse{
+			Date:  "2025-10-23",
+			Areas: []string{"tokyo", "kansai"},
+			Series: []jepx.MarketPoint{
+				{Timestamp: "2025-10-23T17:00:00+09:00", SystemPrice: 18, AreaPrices: map[string]float64{"tokyo": 22, "kansai": 15}},
+				{Timestamp: "2025-10-23T17:30:00+09:00", SystemPrice: 19, AreaPrices: map[string]float64{"tokyo": 21, "kansai": 18}},
+			},
+		}
+
+		got := Evaluate(rules, doc)
+		keys := make([]string, len(got))
+		for i, a := range got {
+			keys[i] = a.Key
+		}
+		want := "spike-tokyo/tokyo/2025-10-23,spread/system/2025-10-23"
+		if strings.Join(keys, ",") != want {
+			t.Fatalf("keys = %v, want %s", keys, want)
+		}
+		if got[1].Value != 7 || got[1].Timestamp != "2025-10-23T17:00:00+09:00" {
+			t.Errorf("spread alert = %+v, want 7 at 17:00", got[1])
+		}
+	})
+}
+
+func TestEvaluate_Demand(t *testing.T) {
+	forecast := func(v float64) *float64 { return &v }
+	ts := func(hh int) time.Time {
+		return time.Date(2025, 10, 23, hh, 0, 0, 0, timeutil.TokyoLocation)
+	}
+
+	doc := demand.NewResponse(demand.AreaTokyo, "2025-10-23")
+	doc.Series = []demand.SeriesPoint{
+		{Timestamp: ts(9), DemandMW: 30000, ForecastMW: forecast(29500)},
+		{Timestamp: ts(10), DemandMW: 33000, ForecastMW: forecast(30000)},
+		{Timestamp: ts(11), DemandMW: 40000},
+	}
+
+	got := Evaluate(DefaultRules(), doc)
+	if len(got) != 1 {
+		t.Fatalf("Expected 1 alert, got %+v", got)
+	}
+	if got[0].RuleID != "demand-miss" || got[0].Value != 10 || got[0].Timestamp != "2025-10-23T10:00:00+09:00" {
+		t.Errorf("alert = %+v, want demand-miss 10%% at 10:00", got[0])
+	}
+}
+
+func TestEvaluate_UnknownDocument(t *testing.T) {
+	if got := Evaluate(DefaultRules(), &reserve.Forecast{}); got != nil {
+		t.Errorf("Evaluate(forecast) = %+v, want none", got)
+	}
+}
@@ -0,0 +1,42 @@
+package alert
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/teo/aversome/backend/internal/pipeline"
+	"github.com/teo/aversome/backend/internal/storage"
+)
+
+// OpenEngine builds the Engine of the alert configuration (LoadConfig) for
+// a server or CLI, recording alerts in store. A FileStore pinned to one
+// output file (CLI -output) records them under its Dir instead, so alerts
+// never overwrite the fetched document.
+func OpenEngine(store storage.Store) (*Engine, error) {
+	cfg, err := LoadConfig()
+	if err != nil {
+		return nil, err
+	}
+	if fs, ok := store.(*storage.FileStore); ok && fs.Path != "" {
+		store = storage.NewFileStore(fs.Dir)
+	}
+	return NewEngine(cfg.Rules, store, cfg.Notifiers()), nil
+}
+
+// AfterSave is a pipeline.Config.AfterSave hook evaluating every saved
+// document. Alert failures never fail the ingest; they are logged and
+// retried on the next one.
+func (e *Engine) AfterSave(res *pipeline.Result, doc any) {
+	fired, err := e.Observe(doc)
+	for _, a := range fired {
+		log.Printf("🚨 [%s] %s", a.Severity, a.Message)
+	}
+	if err != nil {
+		log.Printf("⚠️  Alert delivery for %s %s failed: %v", res.Dataset, res.Date, err)
+	}
+}
+
+// Summary describes the engine for startup logs.
+func (e *Engine) Summary() string {
+	return fmt.Sprintf("%d rules, %d notifiers", len(e.rules), len(e.notifiers))
+}
@@ -0,0 +1,73 @@
+package alert
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/teo/aversome/backend/internal/areas"
+	"github.com/teo/aversome/backend/internal/pipeline"
+	"github.com/teo/aversome/backend/internal/storage"
+)
+
+// TestEngine_AfterSave runs a fetch the way the CLIs do (testdata, -output
+// file) and expects the alert to reach the webhook.
+func TestEngine_AfterSave(t *testing.T) {
+	var received []WebhookPayload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var p WebhookPayload
+		if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+			t.Errorf("failed to decode payload: %v", err)
+		}
+		received = append(received, p)
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	config := filepath.Join(dir, "alerts.json")
+	rules := `{"rules":[{"id":"tokyo-priced","kind":"spot_price","areas":["tokyo"],"threshold":0,"severity":"info"}]}`
+	if err := os.WriteFile(config, []byte(rules), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("ALERT_CONFIG", config)
+	t.Setenv("ALERT_WEBHOOK_URLS", server.URL)
+	t.Setenv("ALERT_EMAIL_TO", "")
+
+	output := filepath.Join(dir, "spot-tokyo.json")
+	store := &storage.FileStore{Dir: dir, Path: output}
+	engine, err := OpenEngine(store)
+	if err != nil {
+		t.Fatalf("OpenEngine() error = %v", err)
+	}
+
+	p := pipeline.New(pipeline.Config{
+		TestdataDir: filepath.Join("..", "adapters", "testdata"),
+		Store:       store,
+		AfterSave:   engine.AfterSave,
+	})
+	tokyo, _ := areas.Parse("tokyo")
+	if _, err := p.FetchJEPX(tokyo, "2025-10-23"); err != nil {
+		t.Fatalf("FetchJEPX() error = %v", err)
+	}
+
+	if len(received) != 1 || len(received[0].Alerts) != 1 || received[0].Alerts[0].RuleID != "tokyo-priced" {
+		t.Fatalf("webhook received %+v, want tokyo-priced", received)
+	}
+
+	// The output file keeps the prices; alerts are recorded beside it
+	data, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc map[string]any
+	if err := json.Unmarshal(data, &doc); err != nil || doc["price_yen_per_kwh"] == nil {
+		t.Errorf("output = %.80s, want the JEPX prices", data)
+	}
+	day, err := engine.Load("2025-10-23")
+	if err != nil || len(day.Alerts) != 1 || day.Alerts[0].Pending {
+		t.Errorf("recorded alerts = %+v, %v; want one delivered alert", day, err)
+	}
+}
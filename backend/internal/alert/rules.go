@@ -0,0 +1,71 @@
+package alert
+
+import (
+	"errors"
+	"fmt"
+)
+
+// DefaultRules returns the rules used when no rule file is configured:
+// reserve margin below 5% (warning) and 3% (critical, the 需給ひっ迫警報 level),
+// spot prices above 30 JPY/kWh, area spreads above 5 JPY/kWh and demand more
+// than 5% off its forecast.
+func DefaultRules() []Rule {
+	return []Rule{
+		{ID: "reserve-tight", Kind: KindReserveMargin, Threshold: 5, Severity: SeverityWarning},
+		{ID: "reserve-critical", Kind: KindReserveMargin, Threshold: 3, Severity: SeverityCritical},
+		{ID: "spot-spike", Kind: KindSpotPrice, Threshold: 30, Severity: SeverityWarning},
+		{ID: "spread-wide", Kind: KindSpread, Threshold: 5, Severity: SeverityWarning},
+		{ID: "demand-miss", Kind: KindDemandForecast, Threshold: 5, Severity: SeverityInfo},
+	}
+}
+
+// ValidateRules checks rule IDs, kinds and severities.
+func ValidateRules(rules []Rule) error {
+	if len(rules) == 0 {
+		return errors.New("no alert rules")
+	}
+
+	ids := make(map[string]bool, len(rules))
+	for i, r := range rules {
+		if r.ID == "" {
+			return fmt.Errorf("rule %d: id is required", i)
+		}
+		if ids[r.ID] {
+			return fmt.Errorf("rule %q: duplicate id", r.ID)
+		}
+		ids[r.ID] = true
+
+		switch r.Kind {
+		case KindReserveMargin, KindSpotPrice, KindSpread, KindDemandForecast:
+		default:
+			return fmt.Errorf("rule %q: unknown kind %q", r.ID, r.Kind)
+		}
+		switch r.Severity {
+		case SeverityInfo, SeverityWarning, SeverityCritical:
+		default:
+			return fmt.Errorf("rule %q: severity must be info, warning or critical, got %q", r.ID, r.Severity)
+		}
+	}
+	return nil
+}
+
+// appliesTo reports whether the rule covers area.
+func (r Rule) appliesTo(area string) bool {
+	if len(r.Areas) == 0 {
+		return true
+	}
+	for _, a := range r.Areas {
+		if a == area {
+			return true
+		}
+	}
+	return false
+}
+
+// fires reports whether value crosses the rule's threshold.
+func (r Rule) fires(value float64) bool {
+	if r.Kind == KindReserveMargin {
+		return value < r.Threshold
+	}
+	return value > r.Threshold
+}
@@ -0,0 +1,60 @@
+// Package alert evaluates threshold rules against freshly ingested documents
+// (reserve margin, JEPX prices, price spreads, demand vs forecast), records
+// the alerts fired per date for de-duplication and delivers new ones to
+// notifiers such as JSON webhooks.
+package alert
+
+// Kind names what a rule watches.
+type Kind string
+
+const (
+	KindReserveMargin  Kind = "reserve_margin"  // Area reserve margin (%) at its tightest interval, fires below the threshold
+	KindSpotPrice      Kind = "spot_price"      // JEPX spot price (JPY/kWh), fires above the threshold
+	KindSpread         Kind = "spread"          // Highest minus lowest JEPX area price (JPY/kWh), fires above the threshold
+	KindDemandForecast Kind = "demand_forecast" // |actual - forecast| / forecast demand (%), fires above the threshold
+)
+
+// Severity ranks an alert for the receiving channel.
+type Severity string
+
+const (
+	SeverityInfo     Severity = "info"
+	SeverityWarning  Severity = "warning"
+	SeverityCritical Severity = "critical"
+)
+
+// SystemArea is the area of alerts on the JEPX system price and on spreads.
+const SystemArea = "system"
+
+// Rule is one threshold on one kind of value.
+type Rule struct {
+	ID        string   `json:"id"`              // Unique, part of the de-duplication key
+	Kind      Kind     `json:"kind"`            // What the threshold applies to
+	Areas     []string `json:"areas,omitempty"` // Limit to these areas ("system" for the system price); all if empty
+	Threshold float64  `json:"threshold"`       // Below for reserve_margin, above for the other kinds
+	Severity  Severity `json:"severity"`
+}
+
+// Alert is a rule that fired for an area and date. One alert is kept per
+// rule, area and date; a re-ingest of the same day does not fire it again.
+type Alert struct {
+	Key       string   `json:"key"` // De-duplication key: rule/area/date
+	RuleID    string   `json:"rule_id"`
+	Kind      Kind     `json:"kind"`
+	Severity  Severity `json:"severity"`
+	Area      string   `json:"area"` // Area code or "system"
+	Date      string   `json:"date"` // YYYY-MM-DD
+	Timestamp string   `json:"ts"`   // Interval of the worst value, ISO8601
+	Value     float64  `json:"value"`
+	Threshold float64  `json:"threshold"`
+	Message   string   `json:"message"`
+	FiredAt   string   `json:"fired_at"`          // When the alert was first recorded, ISO8601
+	Pending   bool     `json:"pending,omitempty"` // Delivery failed; retried after the next ingest
+}
+
+// Day is the stored record of the alerts fired for one date.
+// GET /api/alerts/:date
+type Day struct {
+	Date   string  `json:"date"`
+	Alerts []Alert `json:"alerts"`
+}
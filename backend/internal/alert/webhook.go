@@ -0,0 +1,81 @@
+package alert
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// Notifier delivers newly fired alerts.
+type Notifier interface {
+	Name() string
+	Notify(alerts []Alert) error
+}
+
+// WebhookTimeout bounds one webhook delivery.
+const WebhookTimeout = 10 * time.Second
+
+// WebhookPayload is the JSON body posted to webhooks.
+type WebhookPayload struct {
+	Source string  `json:"source"`  // Always "japan-energy-dashboard"
+	SentAt string  `json:"sent_at"` // ISO8601
+	Alerts []Alert `json:"alerts"`
+}
+
+// Webhook posts alerts as a WebhookPayload to a URL. Any 2xx response
+// counts as delivered.
+type Webhook struct {
+	URL     string
+	Headers map[string]string // Extra request headers (e.g., Authorization)
+	client  *http.Client
+}
+
+// NewWebhook creates a webhook notifier.
+func NewWebhook(cfg WebhookConfig) *Webhook {
+	return &Webhook{
+		URL:     cfg.URL,
+		Headers: cfg.Headers,
+		client:  &http.Client{Timeout: WebhookTimeout},
+	}
+}
+
+// Name identifies the webhook in delivery errors.
+func (w *Webhook) Name() string {
+	return "webhook " + w.URL
+}
+
+// Notify posts the alerts in one request.
+func (w *Webhook) Notify(alerts []Alert) error {
+	body, err := json.Marshal(WebhookPayload{
+		Source: "japan-energy-dashboard",
+		SentAt: time.Now().Format(time.RFC3339),
+		Alerts: alerts,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal alerts: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range w.Headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,148 @@
+// Package areas provides the registry of the ten OCCTO supply areas.
+// Every adapter, CLI and API route validates and normalizes area names against it.
+package areas
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Code is the canonical lowercase identifier of a supply area (e.g., "tokyo").
+type Code string
+
+const (
+	Hokkaido Code = "hokkaido"
+	Tohoku   Code = "tohoku"
+	Tokyo    Code = "tokyo"
+	Chubu    Code = "chubu"
+	Hokuriku Code = "hokuriku"
+	Kansai   Code = "kansai"
+	Chugoku  Code = "chugoku"
+	Shikoku  Code = "shikoku"
+	Kyushu   Code = "kyushu"
+	Okinawa  Code = "okinawa"
+)
+
+// Area describes a single supply area.
+type Area struct {
+	Code        Code    `json:"code"`         // e.g., "tokyo"
+	NameEN      string  `json:"name_en"`      // e.g., "Tokyo"
+	NameJA      string  `json:"name_ja"`      // e.g., "東京"
+	OCCTOCode   string  `json:"occto_code"`   // OCCTO area number, "01" (Hokkaido) to "10" (Okinawa)
+	JEPXColumn  string  `json:"jepx_column"`  // japanesepower.org price column; empty if not a JEPX area
+	FrequencyHz int     `json:"frequency_hz"` // 50 (east) or 60 (west)
+	Latitude    float64 `json:"lat"`          // Representative city (for weather lookups)
+	Longitude   float64 `json:"lon"`
+}
+
+// HasJEPXPrice reports whether JEPX publishes an area price for this area.
+// Okinawa is not connected to the mainland grid and has no spot price.
+func (a Area) HasJEPXPrice() bool {
+	return a.JEPXColumn != ""
+}
+
+// registry lists all areas in OCCTO order (north to south).
+var registry = []Area{
+	{Hokkaido, "Hokkaido", "北海道", "01", "Hokkaido Yen/kWh", 50, 43.0621, 141.3544},
+	{Tohoku, "Tohoku", "東北", "02", "Tohoku Yen/kWh", 50, 38.2682, 140.8694},
+	{Tokyo, "Tokyo", "東京", "03", "Tokyo Yen/kWh", 50, 35.6895, 139.6917},
+	{Chubu, "Chubu", "中部", "04", "Chubu Yen/kWh", 60, 35.1815, 136.9066},
+	{Hokuriku, "Hokuriku", "北陸", "05", "Hokuriku Yen/kWh", 60, 36.6953, 137.2113},
+	{Kansai, "Kansai", "関西", "06", "Kansai Yen/kWh", 60, 34.6937, 135.5023},
+	{Chugoku, "Chugoku", "中国", "07", "Chugoku Yen/kWh", 60, 34.3853, 132.4553},
+	{Shikoku, "Shikoku", "四国", "08", "Shikoku Yen/kWh", 60, 34.3428, 134.0466},
+	{Kyushu, "Kyushu", "九州", "09", "Kyushu Yen/kWh", 60, 33.5904, 130.4017},
+	{Okinawa, "Okinawa", "沖縄", "10", "", 60, 26.2124, 127.6809},
+}
+
+// All returns every area in OCCTO order.
+func All() []Area {
+	out := make([]Area, len(registry))
+	copy(out, registry)
+	return out
+}
+
+// Codes returns the canonical codes of every area in OCCTO order.
+func Codes() []Code {
+	codes := make([]Code, len(registry))
+	for i, a := range registry {
+		codes[i] = a.Code
+	}
+	return codes
+}
+
+// Lookup returns the area for a canonical code.
+func Lookup(code Code) (Area, bool) {
+	for _, a := range registry {
+		if a.Code == code {
+			return a, true
+		}
+	}
+	return Area{}, false
+}
+
+// Normalize maps any known representation of an area to its canonical code.
+// Accepts canonical codes, English names, Japanese names (with or without the
+// "エリア" suffix), OCCTO area numbers and japanesepower.org column names.
+// Only exact matches count; adapters map decorated source names such as
+// "Tokyo Area" themselves. Returns false for unknown areas.
+func Normalize(s string) (Code, bool) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return "", false
+	}
+	lower := strings.ToLower(s)
+	ja := strings.TrimSuffix(s, "エリア")
+
+	for _, a := range registry {
+		switch {
+		case lower == string(a.Code),
+			ja == a.NameJA,
+			s == a.OCCTOCode,
+			a.JEPXColumn != "" && strings.EqualFold(s, a.JEPXColumn):
+			return a.Code, true
+		}
+	}
+
+	return "", false
+}
+
+// Parse validates user input (flag, URL parameter, request body) and returns the area.
+func Parse(s string) (Area, error) {
+	code, ok := Normalize(s)
+	if !ok {
+		return Area{}, fmt.Errorf("invalid area %q (must be one of %s)", s, strings.Join(codeStrings(), ", "))
+	}
+	a, _ := Lookup(code)
+	return a, nil
+}
+
+// ParseList parses a comma-separated list of areas (flag input). "all"
+// selects every area; blank entries are skipped.
+func ParseList(list string) ([]Area, error) {
+	if strings.EqualFold(strings.TrimSpace(list), "all") {
+		return All(), nil
+	}
+
+	var out []Area
+	for _, name := range strings.Split(list, ",") {
+		if strings.TrimSpace(name) == "" {
+			continue
+		}
+		a, err := Parse(strings.TrimSpace(name))
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, a)
+	}
+	return out, nil
+}
+
+// codeStrings returns the canonical codes as plain strings for error messages.
+func codeStrings() []string {
+	out := make([]string, len(registry))
+	for i, a := range registry {
+		out[i] = string(a.Code)
+	}
+	return out
+}
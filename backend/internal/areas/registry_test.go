@@ -0,0 +1,92 @@
+package areas
+
+import "testing"
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		input  string
+		want   Code
+		wantOK bool
+	}{
+		{"tokyo", Tokyo, true},
+		{"Tokyo", Tokyo, true},
+		{"東京", Tokyo, true},
+		{"東京エリア", Tokyo, true},
+		{"03", Tokyo, true},
+		{"Kyushu Yen/kWh", Kyushu, true},
+		{"九州", Kyushu, true},
+		{"中国", Chugoku, true},
+		{"中部", Chubu, true},
+		{"10", Okinawa, true},
+		{" HOKKAIDO ", Hokkaido, true},
+		{"", "", false},
+		{"osaka", "", false},
+		{"tokyoXYZ", "", false},
+		{"kansai-foo", "", false},
+		{"東京都", "", false},
+		{"Tokyo Area", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, ok := Normalize(tt.input)
+			if ok != tt.wantOK || got != tt.want {
+				t.Errorf("Normalize(%q) = %q, %v; want %q, %v", tt.input, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	a, err := Parse("関西")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if a.Code != Kansai || a.FrequencyHz != 60 || a.OCCTOCode != "06" {
+		t.Errorf("Parse(関西) = %+v", a)
+	}
+
+	// Exact matches only: decorated names are rejected
+	for _, input := range []string{"narnia", "tokyoXYZ", "kansai-foo", "東京都"} {
+		if _, err := Parse(input); err == nil {
+			t.Errorf("Parse(%q) expected error, got nil", input)
+		}
+	}
+}
+
+func TestParseList(t *testing.T) {
+	got, err := ParseList(" 東京, kansai,,")
+	if err != nil || len(got) != 2 || got[0].Code != Tokyo || got[1].Code != Kansai {
+		t.Errorf("ParseList() = %+v, %v; want tokyo, kansai", got, err)
+	}
+
+	if got, err := ParseList(" ALL "); err != nil || len(got) != 10 {
+		t.Errorf("ParseList(all) = %d areas, %v; want 10", len(got), err)
+	}
+	if got, err := ParseList(""); err != nil || len(got) != 0 {
+		t.Errorf("ParseList(\"\") = %+v, %v; want none", got, err)
+	}
+	if _, err := ParseList("tokyo,narnia"); err == nil {
+		t.Error("ParseList(tokyo,narnia) expected error, got nil")
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	all := All()
+	if len(all) != 10 {
+		t.Fatalf("registry has %d areas, want 10", len(all))
+	}
+
+	east := 0
+	for _, a := range all {
+		if a.FrequencyHz == 50 {
+			east++
+		}
+		if !a.HasJEPXPrice() && a.Code != Okinawa {
+			t.Errorf("%s has no JEPX column", a.Code)
+		}
+	}
+	if east != 3 {
+		t.Errorf("got %d 50 Hz areas, want 3", east)
+	}
+}
@@ -0,0 +1,42 @@
+package curtailment
+
+import (
+	"fmt"
+
+	"github.com/teo/aversome/backend/internal/generation"
+	"github.com/teo/aversome/backend/pkg/timeutil"
+)
+
+// Join sets the curtailed solar and wind output of every generation point
+// (averaged over hourly points) and recalculates its meta, so the energy
+// lost to curtailment is shown next to the estimated solar and wind output.
+// gen must be the same area and date.
+func (r *Response) Join(gen *generation.Response) error {
+	if gen.Area != r.Area || gen.Date != r.Date {
+		return fmt.Errorf("cannot join %s %s curtailment with %s %s generation", r.Area, r.Date, gen.Area, gen.Date)
+	}
+	if len(r.Series) != timeutil.SlotsPerDay {
+		return fmt.Errorf("curtailment series has %d intervals, want %d", len(r.Series), timeutil.SlotsPerDay)
+	}
+
+	slots := 1
+	if gen.Timescale == generation.TimescaleHourly {
+		slots = 2
+	}
+
+	for i := range gen.Series {
+		p := &gen.Series[i]
+		first := timeutil.SlotIndex(p.Timestamp)
+
+		var solar, wind float64
+		for slot := first; slot < first+slots && slot < len(r.Series); slot++ {
+			solar += r.Series[slot].SolarMW
+			wind += r.Series[slot].WindMW
+		}
+		p.CurtailedSolarMW = solar / float64(slots)
+		p.CurtailedWindMW = wind / float64(slots)
+	}
+
+	gen.CalculateMeta()
+	return nil
+}
@@ -0,0 +1,93 @@
+package curtailment
+
+import (
+	"testing"
+
+	"github.com/teo/aversome/backend/internal/generation"
+	"github.com/teo/aversome/backend/pkg/timeutil"
+)
+
+func newTestCurtailment(t *testing.T) *Response {
+	t.Helper()
+	r := NewResponse("2025-10-24", "kyushu")
+	r.Events = []Event{
+		{Start: "2025-10-24T11:00:00+09:00", End: "2025-10-24T13:00:00+09:00", Fuel: FuelSolar, CurtailedMW: 1000},
+		{Start: "2025-10-24T11:30:00+09:00", End: "2025-10-24T12:00:00+09:00", Fuel: FuelWind, CurtailedMW: 100},
+	}
+	if err := r.Calculate(); err != nil {
+		t.Fatalf("Calculate() error = %v", err)
+	}
+	return r
+}
+
+func newTestGeneration(timescale string) *generation.Response {
+	day, _ := timeutil.ParseDate("2025-10-24")
+	gen := generation.NewResponseWithTimescale("kyushu", "2025-10-24", timescale)
+	for i := 0; i < timeutil.PointsPerDay(timescale); i++ {
+		ts := timeutil.SlotTime(day, i)
+		if timescale == generation.TimescaleHourly {
+			ts = timeutil.SlotTime(day, i*2)
+		}
+		gen.Series = append(gen.Series, generation.GenerationPoint{Timestamp: ts, SolarMW: 500, LNGMW: 500, TotalMW: 1000})
+	}
+	gen.CalculateMeta()
+	return gen
+}
+
+func TestResponse_Join(t *testing.T) {
+	c := newTestCurtailment(t)
+	if c.Summary.CurtailedMWh != 2050 || c.Summary.CurtailedIntervals != 4 {
+		t.Fatalf("summary = %+v, want 2050 MWh over 4 intervals", c.Summary)
+	}
+
+	gen := newTestGeneration(generation.Timescale30Min)
+	if err := c.Join(gen); err != nil {
+		t.Fatalf("Join() error = %v", err)
+	}
+	if p := gen.Series[23]; p.CurtailedSolarMW != 1000 || p.CurtailedWindMW != 100 || p.TotalMW != 1000 {
+		t.Errorf("11:30 = %+v, want 1000 MW solar and 100 MW wind curtailed", p)
+	}
+	if gen.Series[21].CurtailedSolarMW != 0 || gen.Meta.CurtailedMWh != 2050 {
+		t.Errorf("meta = %+v, want 2050 MWh curtailed", gen.Meta)
+	}
+
+	// Hourly points average their two intervals
+	hourly := newTestGeneration(generation.TimescaleHourly)
+	if err := c.Join(hourly); err != nil {
+		t.Fatalf("Join(hourly) error = %v", err)
+	}
+	if p := hourly.Series[11]; p.CurtailedSolarMW != 1000 || p.CurtailedWindMW != 50 {
+		t.Errorf("hourly 11:00 = %+v, want 1000/50 MW", p)
+	}
+	if hourly.Meta.CurtailedMWh != 2050 {
+		t.Errorf("hourly curtailed = %v MWh, want 2050", hourly.Meta.CurtailedMWh)
+	}
+
+	// Resampling keeps the curtailed output
+	resampled, err := gen.Resample(generation.TimescaleHourly)
+	if err != nil || resampled.Series[11].CurtailedWindMW != 50 || resampled.Meta.CurtailedMWh != 2050 {
+		t.Errorf("Resample() = %+v, %v", resampled.Meta, err)
+	}
+
+	other := newTestGeneration(generation.Timescale30Min)
+	other.Area = "chugoku"
+	if err := c.Join(other); err == nil {
+		t.Error("Join(other area) expected error")
+	}
+}
+
+func TestParseReason(t *testing.T) {
+	tests := map[string]Reason{
+		"下げ調整力不足":         ReasonOversupply,
+		"需給バランス制約":        ReasonOversupply,
+		"送電容量制約":          ReasonGridConstraint,
+		"系統制約（局所）":        ReasonGridConstraint,
+		"grid_constraint": ReasonGridConstraint,
+		"点検":              ReasonOther,
+	}
+	for input, want := range tests {
+		if got := ParseReason(input); got != want {
+			t.Errorf("ParseReason(%q) = %s, want %s", input, got, want)
+		}
+	}
+}
@@ -0,0 +1,189 @@
+// Package curtailment provides types for renewable output curtailment
+// (再エネ出力制御) events: periods in which a transmission operator ordered
+// solar or wind plants to reduce output, with the curtailed MW and the
+// reason. Curtailment for oversupply is what pins spot prices near zero in
+// Kyushu and other solar-heavy areas.
+package curtailment
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/teo/aversome/backend/pkg/timeutil"
+)
+
+// Timescale30Min is the resolution of the per-interval series.
+const Timescale30Min = timeutil.Timescale30Min
+
+// Fuel is the curtailed generation type.
+type Fuel string
+
+const (
+	FuelSolar Fuel = "solar" // 太陽光
+	FuelWind  Fuel = "wind"  // 風力
+)
+
+// ParseFuel accepts "solar"/"wind" or the Japanese OCCTO names.
+func ParseFuel(s string) (Fuel, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "solar", "太陽光":
+		return FuelSolar, nil
+	case "wind", "風力":
+		return FuelWind, nil
+	}
+	return "", fmt.Errorf("unknown curtailed fuel %q", s)
+}
+
+// Reason is why output was curtailed.
+type Reason string
+
+const (
+	ReasonOversupply     Reason = "oversupply"      // 需給バランス制約 (下げ調整力不足): supply exceeds demand plus exports
+	ReasonGridConstraint Reason = "grid_constraint" // 送電容量制約 (系統制約): local transmission limit
+	ReasonOther          Reason = "other"
+)
+
+// ParseReason classifies a published reason text. Unrecognized text is ReasonOther.
+func ParseReason(s string) Reason {
+	s = strings.TrimSpace(s)
+	switch {
+	case s == string(ReasonOversupply) || strings.Contains(s, "需給") || strings.Contains(s, "下げ"):
+		return ReasonOversupply
+	case s == string(ReasonGridConstraint) || strings.Contains(s, "送電") || strings.Contains(s, "系統"):
+		return ReasonGridConstraint
+	}
+	return ReasonOther
+}
+
+// Event is one curtailment order: Fuel output reduced by CurtailedMW from
+// Start to End.
+type Event struct {
+	Start       string  `json:"start"` // ISO8601 with Asia/Tokyo offset
+	End         string  `json:"end"`   // ISO8601 with Asia/Tokyo offset, exclusive
+	Fuel        Fuel    `json:"fuel"`
+	CurtailedMW float64 `json:"curtailed_mw"` // 出力制御量
+	Reason      Reason  `json:"reason"`
+	ReasonText  string  `json:"reason_text,omitempty"` // As published
+}
+
+// Point is the curtailed output of one 30-minute interval, summed over the
+// events that cover it.
+type Point struct {
+	Timestamp string  `json:"ts"` // Interval start, ISO8601 with Asia/Tokyo offset
+	SolarMW   float64 `json:"solar_mw"`
+	WindMW    float64 `json:"wind_mw"`
+}
+
+// Source contains attribution for the data source.
+type Source struct {
+	Name string `json:"name"` // e.g., "OCCTO"
+	URL  string `json:"url"`  // Original data source URL
+}
+
+// Meta contains optional metadata and warnings.
+type Meta struct {
+	Warning string `json:"warning,omitempty"` // Non-blocking warning message
+}
+
+// Summary aggregates the day's events.
+type Summary struct {
+	Events             int     `json:"events"`
+	CurtailedMWh       float64 `json:"curtailed_mwh"` // Solar and wind energy not generated
+	SolarMWh           float64 `json:"solar_mwh"`
+	WindMWh            float64 `json:"wind_mwh"`
+	PeakCurtailedMW    float64 `json:"peak_curtailed_mw"`
+	PeakAt             string  `json:"peak_ts,omitempty"` // ISO8601
+	CurtailedIntervals int     `json:"curtailed_intervals"`
+}
+
+// Response holds the curtailment events of one area and day. A day without
+// curtailment has no events and an all-zero series.
+// GET /api/curtailment/{area}/{date}
+type Response struct {
+	Date      string  `json:"date"`           // YYYY-MM-DD format
+	Area      string  `json:"area"`           // e.g., "kyushu"
+	Timescale string  `json:"timescale"`      // Always "30min"
+	Events    []Event `json:"events"`         // In start order
+	Series    []Point `json:"series"`         // 48 intervals
+	Summary   Summary `json:"summary"`        // Totals over Series
+	Source    Source  `json:"source"`         // Data attribution
+	Meta      *Meta   `json:"meta,omitempty"` // Optional metadata/warnings
+}
+
+// NewResponse creates a properly initialized Response with defaults.
+func NewResponse(date, area string) *Response {
+	return &Response{
+		Date:      date,
+		Area:      area,
+		Timescale: Timescale30Min,
+		Events:    make([]Event, 0),
+		Series:    make([]Point, 0, timeutil.SlotsPerDay),
+	}
+}
+
+// Calculate builds Series and Summary from Events. Events are spread over
+// the intervals they overlap, pro rata for partial intervals.
+func (r *Response) Calculate() error {
+	day, err := timeutil.ParseDate(r.Date)
+	if err != nil {
+		return err
+	}
+
+	r.Series = make([]Point, timeutil.SlotsPerDay)
+	for i := range r.Series {
+		r.Series[i].Timestamp = timeutil.FormatISO8601(timeutil.SlotTime(day, i))
+	}
+
+	for _, e := range r.Events {
+		start, err := time.Parse(time.RFC3339, e.Start)
+		if err != nil {
+			return fmt.Errorf("invalid event start %q: %w", e.Start, err)
+		}
+		end, err := time.Parse(time.RFC3339, e.End)
+		if err != nil {
+			return fmt.Errorf("invalid event end %q: %w", e.End, err)
+		}
+
+		for i := range r.Series {
+			slotStart := timeutil.SlotTime(day, i)
+			mw := e.CurtailedMW * overlap(start, end, slotStart, slotStart.Add(timeutil.SlotDuration))
+			switch e.Fuel {
+			case FuelSolar:
+				r.Series[i].SolarMW += mw
+			case FuelWind:
+				r.Series[i].WindMW += mw
+			}
+		}
+	}
+
+	r.Summary = Summary{Events: len(r.Events)}
+	for _, p := range r.Series {
+		total := p.SolarMW + p.WindMW
+		r.Summary.SolarMWh += p.SolarMW / 2
+		r.Summary.WindMWh += p.WindMW / 2
+		if total > 0 {
+			r.Summary.CurtailedIntervals++
+		}
+		if total > r.Summary.PeakCurtailedMW {
+			r.Summary.PeakCurtailedMW, r.Summary.PeakAt = total, p.Timestamp
+		}
+	}
+	r.Summary.CurtailedMWh = r.Summary.SolarMWh + r.Summary.WindMWh
+	return nil
+}
+
+// overlap returns the fraction of [from, to) covered by [start, end).
+func overlap(start, end, from, to time.Time) float64 {
+	length := to.Sub(from)
+	if start.After(from) {
+		from = start
+	}
+	if end.Before(to) {
+		to = end
+	}
+	if !to.After(from) {
+		return 0
+	}
+	return float64(to.Sub(from)) / float64(length)
+}
@@ -0,0 +1,69 @@
+package demand
+
+import (
+	"fmt"
+
+	"github.com/teo/aversome/backend/internal/series"
+)
+
+// RangeResponse is a multi-day demand series.
+// GET /api/demand/{area}?from=YYYY-MM-DD&to=YYYY-MM-DD
+type RangeResponse struct {
+	Area      Area          `json:"area"`
+	From      string        `json:"from"`               // First requested date
+	To        string        `json:"to"`                 // Last requested date
+	Timezone  string        `json:"timezone"`           // Always "Asia/Tokyo"
+	Timescale Timescale     `json:"timescale"`          // Common resolution of the series
+	Series    []SeriesPoint `json:"series"`             // Concatenated points of every stored day
+	Gaps      []series.Gap  `json:"gaps"`               // Days with no stored data
+	Sources   []Source      `json:"sources"`            // Distinct sources in the range
+	Warnings  []string      `json:"warnings,omitempty"` // Per-day warnings, prefixed with the date
+}
+
+// NewRangeResponse concatenates daily responses (ascending, one per stored
+// day) into a range series. Days are resampled to a common timescale: the
+// requested one, or hourly if the days disagree.
+func NewRangeResponse(area Area, from, to string, days []*Response, gaps []series.Gap, timescale Timescale) (*RangeResponse, error) {
+	timescales := make([]string, len(days))
+	for i, d := range days {
+		timescales[i] = string(d.Timescale)
+	}
+	target := Timescale(series.CommonTimescale(string(timescale), timescales))
+
+	out := &RangeResponse{
+		Area:      area,
+		From:      from,
+		To:        to,
+		Timezone:  "Asia/Tokyo",
+		Timescale: target,
+		Series:    make([]SeriesPoint, 0, len(days)*48),
+		Gaps:      gaps,
+		Sources:   make([]Source, 0, 1),
+	}
+
+	for _, d := range days {
+		day, err := d.Resample(target)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", d.Date, err)
+		}
+		out.Series = append(out.Series, day.Series...)
+
+		if !containsSource(out.Sources, day.Source) {
+			out.Sources = append(out.Sources, day.Source)
+		}
+		if day.Meta != nil && day.Meta.Warning != "" {
+			out.Warnings = append(out.Warnings, fmt.Sprintf("%s: %s", day.Date, day.Meta.Warning))
+		}
+	}
+
+	return out, nil
+}
+
+func containsSource(sources []Source, s Source) bool {
+	for _, existing := range sources {
+		if existing == s {
+			return true
+		}
+	}
+	return false
+}
@@ -2,21 +2,36 @@
 // Follows AGENT_TECH_SPEC.md §3.1 API contract.
 package demand
 
-import "time"
+import (
+	"fmt"
+	"time"
+
+	"github.com/teo/aversome/backend/pkg/timeutil"
+)
 
 // Area represents a geographic region for demand data.
+// Values match the canonical codes in the areas registry.
 type Area string
 
 const (
-	AreaTokyo  Area = "tokyo"
-	AreaKansai Area = "kansai"
+	AreaHokkaido Area = "hokkaido"
+	AreaTohoku   Area = "tohoku"
+	AreaTokyo    Area = "tokyo"
+	AreaChubu    Area = "chubu"
+	AreaHokuriku Area = "hokuriku"
+	AreaKansai   Area = "kansai"
+	AreaChugoku  Area = "chugoku"
+	AreaShikoku  Area = "shikoku"
+	AreaKyushu   Area = "kyushu"
+	AreaOkinawa  Area = "okinawa"
 )
 
 // Timescale represents the granularity of data points.
 type Timescale string
 
 const (
-	TimescaleHourly Timescale = "hourly"
+	TimescaleHourly Timescale = timeutil.TimescaleHourly
+	Timescale30Min  Timescale = timeutil.Timescale30Min // Native OCCTO resolution (48 コマ)
 )
 
 // Source contains attribution for the data source.
@@ -44,18 +59,67 @@ type Response struct {
 	Date      string        `json:"date"`      // YYYY-MM-DD format
 	Timezone  string        `json:"timezone"`  // Always "Asia/Tokyo" for Japan
 	Timescale Timescale     `json:"timescale"` // Data granularity
-	Series    []SeriesPoint `json:"series"`    // Hourly data points (up to 24)
+	Series    []SeriesPoint `json:"series"`    // Up to 24 (hourly) or 48 (30min) points
 	Source    Source        `json:"source"`    // Data attribution
 	Meta      *Meta         `json:"meta,omitempty"`
 }
 
 // NewResponse creates a properly initialized Response with defaults.
 func NewResponse(area Area, date string) *Response {
+	return NewResponseWithTimescale(area, date, TimescaleHourly)
+}
+
+// NewResponseWithTimescale creates a Response at the given resolution.
+func NewResponseWithTimescale(area Area, date string, timescale Timescale) *Response {
 	return &Response{
 		Area:      area,
 		Date:      date,
 		Timezone:  "Asia/Tokyo",
-		Timescale: TimescaleHourly,
-		Series:    make([]SeriesPoint, 0, 24),
+		Timescale: timescale,
+		Series:    make([]SeriesPoint, 0, timeutil.PointsPerDay(string(timescale))),
+	}
+}
+
+// Resample returns the response at the requested timescale.
+// 30min data is averaged into hourly buckets; hourly data cannot be
+// upsampled and returns an error. The receiver is not modified.
+func (r *Response) Resample(timescale Timescale) (*Response, error) {
+	if r.Timescale == timescale {
+		return r, nil
 	}
+	if r.Timescale != Timescale30Min || timescale != TimescaleHourly {
+		return nil, fmt.Errorf("cannot resample demand from %s to %s", r.Timescale, timescale)
+	}
+
+	out := *r
+	out.Timescale = TimescaleHourly
+	out.Series = make([]SeriesPoint, 0, 24)
+
+	for i := 0; i < len(r.Series); {
+		ts := r.Series[i].Timestamp
+		hourStart := ts.Truncate(time.Hour)
+
+		var demandSum, forecastSum float64
+		var count, forecastCount int
+		for ; i < len(r.Series) && r.Series[i].Timestamp.Truncate(time.Hour).Equal(hourStart); i++ {
+			demandSum += r.Series[i].DemandMW
+			count++
+			if r.Series[i].ForecastMW != nil {
+				forecastSum += *r.Series[i].ForecastMW
+				forecastCount++
+			}
+		}
+
+		point := SeriesPoint{
+			Timestamp: hourStart,
+			DemandMW:  demandSum / float64(count),
+		}
+		if forecastCount > 0 {
+			avg := forecastSum / float64(forecastCount)
+			point.ForecastMW = &avg
+		}
+		out.Series = append(out.Series, point)
+	}
+
+	return &out, nil
 }
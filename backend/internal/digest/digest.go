@@ -0,0 +1,195 @@
+// Package digest summarizes one day of stored data per area (demand peak,
+// JEPX average and maximum price, tightest reserve margin) with the data
+// quality warnings and fired alerts of the day, for the morning email.
+package digest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/teo/aversome/backend/internal/alert"
+	"github.com/teo/aversome/backend/internal/areas"
+	"github.com/teo/aversome/backend/internal/demand"
+	"github.com/teo/aversome/backend/internal/jepx"
+	"github.com/teo/aversome/backend/internal/reserve"
+	"github.com/teo/aversome/backend/internal/storage"
+	"github.com/teo/aversome/backend/pkg/timeutil"
+)
+
+// Digest is the summary of one day.
+type Digest struct {
+	Date     string        `json:"date"`
+	Areas    []AreaSummary `json:"areas"`
+	Warnings []Warning     `json:"warnings,omitempty"` // Meta.Warning of the summarized documents
+	Alerts   []alert.Alert `json:"alerts,omitempty"`   // Alerts fired for the date
+}
+
+// AreaSummary is one area's day. Sections are nil when nothing is stored.
+type AreaSummary struct {
+	Area    string          `json:"area"`
+	Demand  *DemandSummary  `json:"demand,omitempty"`
+	Price   *PriceSummary   `json:"price,omitempty"`
+	Reserve *ReserveSummary `json:"reserve,omitempty"`
+}
+
+// DemandSummary is the daily demand peak.
+type DemandSummary struct {
+	PeakMW float64 `json:"peak_mw"`
+	PeakAt string  `json:"peak_ts"` // ISO8601
+}
+
+// PriceSummary is the daily JEPX spot price average and maximum.
+type PriceSummary struct {
+	AvgYenPerKwh float64 `json:"avg_yen_per_kwh"`
+	MaxYenPerKwh float64 `json:"max_yen_per_kwh"`
+	MaxAt        string  `json:"max_ts"` // ISO8601
+}
+
+// ReserveSummary is the tightest reserve interval (the daily average when
+// the document has no series) and its classification.
+type ReserveSummary struct {
+	MinPct float64        `json:"min_pct"`
+	MinAt  string         `json:"min_ts,omitempty"` // ISO8601
+	Status reserve.Status `json:"status"`
+	Tier   string         `json:"tier,omitempty"`
+}
+
+// Warning is a data quality warning of one stored document.
+type Warning struct {
+	Dataset storage.Dataset `json:"dataset"`
+	Area    string          `json:"area,omitempty"`
+	Message string          `json:"message"`
+}
+
+// Build summarizes the stored demand, JEPX and reserve documents and the
+// alerts of date for the selected areas. Reserve margins are classified
+// under policy (reserve.DefaultPolicy if nil). Missing documents leave their
+// section empty; other storage errors are returned.
+func Build(store storage.Store, date string, selected []areas.Area, policy *reserve.Policy) (*Digest, error) {
+	if _, err := timeutil.ParseDate(date); err != nil {
+		return nil, err
+	}
+	if policy == nil {
+		policy = reserve.DefaultPolicy()
+	}
+
+	d := &Digest{Date: date, Areas: make([]AreaSummary, 0, len(selected))}
+
+	// Reserve covers every area in one document
+	var res reserve.Response
+	hasReserve, err := load(store, storage.DatasetReserve, "", date, &res)
+	if err != nil {
+		return nil, err
+	}
+	if hasReserve {
+		policy.Apply(&res)
+		if res.Meta != nil && res.Meta.Warning != "" {
+			d.Warnings = append(d.Warnings, Warning{Dataset: storage.DatasetReserve, Message: res.Meta.Warning})
+		}
+	}
+
+	for _, a := range selected {
+		area := string(a.Code)
+		sum := AreaSummary{Area: area}
+
+		var dem demand.Response
+		ok, err := load(store, storage.DatasetDemand, area, date, &dem)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			sum.Demand = summarizeDemand(&dem)
+			if dem.Meta != nil && dem.Meta.Warning != "" {
+				d.Warnings = append(d.Warnings, Warning{Dataset: storage.DatasetDemand, Area: area, Message: dem.Meta.Warning})
+			}
+		}
+
+		if a.HasJEPXPrice() {
+			var prices jepx.Response
+			ok, err := load(store, storage.DatasetJEPX, area, date, &prices)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				sum.Price = summarizePrices(&prices)
+				if prices.Meta != nil && prices.Meta.Warning != "" {
+					d.Warnings = append(d.Warnings, Warning{Dataset: storage.DatasetJEPX, Area: area, Message: prices.Meta.Warning})
+				}
+			}
+		}
+
+		if hasReserve {
+			sum.Reserve = summarizeReserve(&res, area)
+		}
+
+		d.Areas = append(d.Areas, sum)
+	}
+
+	var day alert.Day
+	if _, err := load(store, storage.DatasetAlerts, "", date, &day); err != nil {
+		return nil, err
+	}
+	d.Alerts = day.Alerts
+
+	return d, nil
+}
+
+// load decodes a stored document into v, reporting false if none is stored.
+func load(store storage.Store, dataset storage.Dataset, area, date string, v any) (bool, error) {
+	data, err := store.Load(dataset, area, date)
+	if errors.Is(err, storage.ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to load %s %s %s: %w", dataset, area, date, err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return false, fmt.Errorf("failed to decode %s %s %s: %w", dataset, area, date, err)
+	}
+	return true, nil
+}
+
+// summarizeDemand returns the demand peak (the first on ties), or nil without data.
+func summarizeDemand(resp *demand.Response) *DemandSummary {
+	var out *DemandSummary
+	for _, p := range resp.Series {
+		if out == nil || p.DemandMW > out.PeakMW {
+			out = &DemandSummary{PeakMW: p.DemandMW, PeakAt: timeutil.FormatISO8601(p.Timestamp)}
+		}
+	}
+	return out
+}
+
+// summarizePrices returns the average and maximum price, or nil without data.
+func summarizePrices(resp *jepx.Response) *PriceSummary {
+	if len(resp.PriceYenPerKwh) == 0 {
+		return nil
+	}
+
+	out := &PriceSummary{MaxYenPerKwh: resp.PriceYenPerKwh[0].Price, MaxAt: resp.PriceYenPerKwh[0].Timestamp}
+	sum := 0.0
+	for _, p := range resp.PriceYenPerKwh {
+		sum += p.Price
+		if p.Price > out.MaxYenPerKwh {
+			out.MaxYenPerKwh, out.MaxAt = p.Price, p.Timestamp
+		}
+	}
+	out.AvgYenPerKwh = sum / float64(len(resp.PriceYenPerKwh))
+	return out
+}
+
+// summarizeReserve returns the area's tightest interval, or nil if the area
+// is not in the document.
+func summarizeReserve(resp *reserve.Response, area string) *ReserveSummary {
+	for _, a := range resp.Areas {
+		if a.Area != area {
+			continue
+		}
+		if m := a.Minimum; m != nil {
+			return &ReserveSummary{MinPct: m.ReserveMarginPct, MinAt: m.Timestamp, Status: m.Status, Tier: m.Tier}
+		}
+		return &ReserveSummary{MinPct: a.ReserveMarginPct, Status: a.Status, Tier: a.Tier}
+	}
+	return nil
+}
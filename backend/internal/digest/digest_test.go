@@ -0,0 +1,203 @@
+package digest
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/teo/aversome/backend/internal/alert"
+	"github.com/teo/aversome/backend/internal/areas"
+	"github.com/teo/aversome/backend/internal/demand"
+	"github.com/teo/aversome/backend/internal/jepx"
+	"github.com/teo/aversome/backend/internal/reserve"
+	"github.com/teo/aversome/backend/internal/storage"
+	"github.com/teo/aversome/backend/pkg/mail"
+	"github.com/teo/aversome/backend/pkg/timeutil"
+)
+
+const date = "2025-10-23"
+
+// newTestStore stores a day of Tokyo demand and prices, reserve for Tokyo and
+// Kansai, and one alert.
+func newTestStore(t *testing.T) storage.Store {
+	t.Helper()
+	store := storage.NewFileStore(t.TempDir())
+	save := func(dataset storage.Dataset, area string, doc any) {
+		if _, err := store.Save(dataset, area, date, doc); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	dem := demand.NewResponse(demand.AreaTokyo, date)
+	for hh, mw := range map[int]float64{9: 38000, 17: 41234.4, 18: 40100} {
+		dem.Series = append(dem.Series, demand.SeriesPoint{
+			Timestamp: time.Date(2025, 10, 23, hh, 0, 0, 0, timeutil.TokyoLocation),
+			DemandMW:  mw,
+		})
+	}
+	dem.Meta = &demand.Meta{Warning: "2 hours interpolated"}
+	save(storage.DatasetDemand, "tokyo", dem)
+
+	prices := jepx.NewResponse(date, "tokyo")
+	prices.PriceYenPerKwh = []jepx.PricePoint{
+		{Timestamp: "2025-10-23T17:00:00+09:00", Price: 20},
+		{Timestamp: "2025-10-23T17:30:00+09:00", Price: 31},
+		{Timestamp: "2025-10-23T18:00:00+09:00", Price: 15},
+	}
+	save(storage.DatasetJEPX, "tokyo", prices)
+
+	save(storage.DatasetReserve, "", &reserve.Response{
+		Date: date,
+		Areas: []reserve.AreaReserve{
+			{Area: "tokyo", ReserveMarginPct: 9, Minimum: &reserve.Minimum{Timestamp: "2025-10-23T17:30:00+09:00", ReserveMarginPct: 4.2}},
+			{Area: "kansai", ReserveMarginPct: 12.5},
+		},
+	})
+
+	save(storage.DatasetAlerts, "", &alert.Day{Date: date, Alerts: []alert.Alert{{
+		RuleID: "reserve-tight", Kind: alert.KindReserveMargin, Severity: alert.SeverityWarning, Area: "tokyo", Date: date,
+		Timestamp: "2025-10-23T17:30:00+09:00", Value: 4.2, Threshold: 5,
+		Message: "tokyo reserve margin 4.20% at 17:30 on 2025-10-23 is below 5.00%",
+	}}})
+
+	return store
+}
+
+func mustAreas(t *testing.T, codes ...string) []areas.Area {
+	t.Helper()
+	out := make([]areas.Area, len(codes))
+	for i, c := range codes {
+		a, err := areas.Parse(c)
+		if err != nil {
+			t.Fatal(err)
+		}
+		out[i] = a
+	}
+	return out
+}
+
+func TestBuild(t *testing.T) {
+	d, err := Build(newTestStore(t), date, mustAreas(t, "tokyo", "kansai"), nil)
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	tokyo := d.Areas[0]
+	if tokyo.Demand == nil || tokyo.Demand.PeakMW != 41234.4 || tokyo.Demand.PeakAt != "2025-10-23T17:00:00+09:00" {
+		t.Errorf("tokyo demand = %+v", tokyo.Demand)
+	}
+	if p := tokyo.Price; p == nil || p.AvgYenPerKwh != 22 || p.MaxYenPerKwh != 31 || p.MaxAt != "2025-10-23T17:30:00+09:00" {
+		t.Errorf("tokyo price = %+v", tokyo.Price)
+	}
+	if r := tokyo.Reserve; r == nil || r.MinPct != 4.2 || r.Status != reserve.StatusTight || r.Tier != "tight" {
+		t.Errorf("tokyo reserve = %+v", tokyo.Reserve)
+	}
+
+	// Kansai has only reserve (its daily average without a series)
+	kansai := d.Areas[1]
+	if kansai.Demand != nil || kansai.Price != nil || kansai.Reserve == nil || kansai.Reserve.MinPct != 12.5 {
+		t.Errorf("kansai = %+v", kansai)
+	}
+
+	if len(d.Warnings) != 1 || d.Warnings[0].Dataset != storage.DatasetDemand || d.Warnings[0].Area != "tokyo" {
+		t.Errorf("warnings = %+v", d.Warnings)
+	}
+	if len(d.Alerts) != 1 {
+		t.Errorf("alerts = %+v", d.Alerts)
+	}
+
+	if _, err := Build(newTestStore(t), "2025/10/23", nil, nil); err == nil {
+		t.Error("Build() accepted an invalid date")
+	}
+}
+
+// TestBuild_Overwrite rewrites the system-wide reserve and alert documents
+// in a store that upserts by key, as Postgres does, and expects the digest to
+// read the latest of each.
+func TestBuild_Overwrite(t *testing.T) {
+	store := storage.NewMemoryStore()
+	for _, pct := range []float64{4.2, 8.4} {
+		if _, err := store.Save(storage.DatasetReserve, "", date, &reserve.Response{
+			Date:  date,
+			Areas: []reserve.AreaReserve{{Area: "tokyo", ReserveMarginPct: pct}},
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for n := 1; n <= 2; n++ {
+		day := &alert.Day{Date: date}
+		for i := 0; i < n; i++ {
+			day.Alerts = append(day.Alerts, alert.Alert{RuleID: "reserve-tight", Area: "tokyo", Date: date})
+		}
+		if _, err := store.Save(storage.DatasetAlerts, "", date, day); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	d, err := Build(store, date, mustAreas(t, "tokyo"), nil)
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	if r := d.Areas[0].Reserve; r == nil || r.MinPct != 8.4 || r.Status != reserve.StatusStable {
+		t.Errorf("tokyo reserve = %+v, want the overwritten 8.4%%", r)
+	}
+	if len(d.Alerts) != 2 {
+		t.Errorf("alerts = %+v, want the overwritten day of 2", d.Alerts)
+	}
+}
+
+func TestRender(t *testing.T) {
+	d, err := Build(newTestStore(t), date, mustAreas(t, "tokyo", "kansai"), nil)
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	tests := []struct {
+		lang        mail.Lang
+		wantSubject string
+		wantText    []string
+		wantHTML    []string
+	}{
+		{mail.LangJA, "【需給日報】2025-10-23（アラート 1件）",
+			[]string{
+				"ピーク需要: 41,234 MW（17:00）",
+				"スポット価格: 平均 22.00 円/kWh、最高 31.00 円/kWh（17:30）",
+				"予備率: 最低 4.20%（17:30） ひっ迫",
+				"■ 関西\n  ピーク需要: —",
+				"- 2025-10-23 東京 の予備率 4.20%（17:30）が閾値 5.00% を下回りました",
+				"- [需要 東京] 2 hours interpolated",
+			},
+			[]string{"<td>東京</td>", "ひっ迫</span>"}},
+		{mail.LangEN, "[Daily energy digest] 2025-10-23 (1 alert)",
+			[]string{
+				"Demand peak: 41,234 MW at 17:00",
+				"Spot price: avg 22.00 JPY/kWh, max 31.00 JPY/kWh at 17:30",
+				"Reserve margin: min 4.20% at 17:30 (tight)",
+				"Reserve margin: min 12.50% (stable)",
+				"- [demand Tokyo] 2 hours interpolated",
+			},
+			[]string{"<td>Kansai</td>", "Data quality warnings"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.lang), func(t *testing.T) {
+			msg, err := Render(d, tt.lang)
+			if err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+			if msg.Subject != tt.wantSubject {
+				t.Errorf("Subject = %q, want %q", msg.Subject, tt.wantSubject)
+			}
+			for _, s := range tt.wantText {
+				if !strings.Contains(msg.Text, s) {
+					t.Errorf("text body missing %q:\n%s", s, msg.Text)
+				}
+			}
+			for _, s := range tt.wantHTML {
+				if !strings.Contains(msg.HTML, s) {
+					t.Errorf("HTML body missing %q", s)
+				}
+			}
+		})
+	}
+}
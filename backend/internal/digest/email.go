@@ -0,0 +1,93 @@
+package digest
+
+import (
+	"embed"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/teo/aversome/backend/internal/alert"
+	"github.com/teo/aversome/backend/internal/areas"
+	"github.com/teo/aversome/backend/internal/reserve"
+	"github.com/teo/aversome/backend/internal/storage"
+	"github.com/teo/aversome/backend/pkg/mail"
+	"github.com/teo/aversome/backend/pkg/timeutil"
+)
+
+//go:embed templates/*
+var templateFS embed.FS
+
+// emailTemplates renders digest.{ja,en}.{txt,html}.
+var emailTemplates = mail.MustParseTemplates(templateFS, "templates/digest", templateFuncs)
+
+// Render renders the digest email in lang, without recipients.
+func Render(d *Digest, lang mail.Lang) (mail.Message, error) {
+	return emailTemplates.Render(lang, d)
+}
+
+// templateFuncs returns the localized helpers of the digest templates.
+func templateFuncs(lang mail.Lang) map[string]any {
+	ja := lang == mail.LangJA
+	return map[string]any{
+		"area": func(code string) string {
+			a, ok := areas.Lookup(areas.Code(code))
+			switch {
+			case !ok:
+				return code
+			case ja:
+				return a.NameJA
+			default:
+				return a.NameEN
+			}
+		},
+		"at": func(ts string) string {
+			t, err := time.Parse(time.RFC3339, ts)
+			if err != nil {
+				return ""
+			}
+			return t.In(timeutil.TokyoLocation).Format("15:04")
+		},
+		"describe": func(a alert.Alert) string { return alert.Describe(a, lang) },
+		"mw":       func(v float64) string { return thousands(int64(v + 0.5)) },
+		"num":      func(v float64) string { return fmt.Sprintf("%.2f", v) },
+		"status": func(s reserve.Status) string {
+			if !ja {
+				return string(s)
+			}
+			return map[reserve.Status]string{
+				reserve.StatusStable: "安定",
+				reserve.StatusWatch:  "注意",
+				reserve.StatusTight:  "ひっ迫",
+			}[s]
+		},
+		"dataset": func(ds storage.Dataset) string {
+			if !ja {
+				return string(ds)
+			}
+			return map[storage.Dataset]string{
+				storage.DatasetDemand:  "需要",
+				storage.DatasetJEPX:    "JEPX",
+				storage.DatasetReserve: "予備率",
+			}[ds]
+		},
+	}
+}
+
+// thousands formats n with comma separators (e.g., 41,234).
+func thousands(n int64) string {
+	s := fmt.Sprintf("%d", n)
+	neg := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(s, "-")
+
+	var b strings.Builder
+	for i, c := range s {
+		if i > 0 && (len(s)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(c)
+	}
+	if neg {
+		return "-" + b.String()
+	}
+	return b.String()
+}
@@ -0,0 +1,222 @@
+// Package emission provides CO2 emission factor sets (gCO2/kWh per fuel,
+// optionally per area) for carbon intensity, loaded from a versioned JSON
+// data file. Sets are either combustion-only (direct emissions at the plant)
+// or lifecycle (including fuel supply, construction and decommissioning).
+package emission
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/teo/aversome/backend/internal/areas"
+)
+
+// DefaultSetID is the built-in set with the dashboard's original factors.
+const DefaultSetID = "default"
+
+// Fuel is a generation category with its own emission factor. The names
+// match the generation JSON keys without the _mw suffix.
+type Fuel string
+
+const (
+	FuelSolar      Fuel = "solar"
+	FuelWind       Fuel = "wind"
+	FuelHydro      Fuel = "hydro"
+	FuelGeothermal Fuel = "geothermal"
+	FuelBiomass    Fuel = "biomass"
+	FuelNuclear    Fuel = "nuclear"
+	FuelLNG        Fuel = "lng"
+	FuelCoal       Fuel = "coal"
+	FuelOil        Fuel = "oil"
+	FuelOther      Fuel = "other"
+)
+
+// Fuels lists every fuel with an emission factor.
+var Fuels = []Fuel{FuelSolar, FuelWind, FuelHydro, FuelGeothermal, FuelBiomass, FuelNuclear, FuelLNG, FuelCoal, FuelOil, FuelOther}
+
+// Basis tells which emissions a set counts.
+type Basis string
+
+const (
+	BasisCombustion Basis = "combustion" // Fuel burnt at the plant
+	BasisLifecycle  Basis = "lifecycle"  // Whole lifecycle, non-zero for every fuel
+)
+
+// Factors maps fuels to gCO2/kWh. Missing fuels are zero.
+type Factors map[Fuel]float64
+
+// FactorSet is one set of emission factors.
+type FactorSet struct {
+	ID      string                 `json:"id"`
+	Name    string                 `json:"name,omitempty"`
+	Basis   Basis                  `json:"basis"`
+	Source  string                 `json:"source,omitempty"` // Publication the factors come from
+	Note    string                 `json:"note,omitempty"`
+	Version string                 `json:"version"`         // Version of the data file the set was loaded from
+	Factors Factors                `json:"factors"`         // National factors
+	Areas   map[areas.Code]Factors `json:"areas,omitempty"` // Per-area overrides of single fuels
+}
+
+// Factor returns the gCO2/kWh of a fuel in an area: the area override if
+// the set has one, else the national factor.
+func (s *FactorSet) Factor(area string, fuel Fuel) float64 {
+	if f, ok := s.Areas[areas.Code(area)][fuel]; ok {
+		return f
+	}
+	return s.Factors[fuel]
+}
+
+// Registry is the content of an emission factor data file:
+//
+//	{"version": "2025.10", "default": "criepi-2016-lifecycle", "sets": [
+//	  {"id": "criepi-2016-lifecycle", "basis": "lifecycle",
+//	   "factors": {"coal": 943, "lng": 474, "solar": 38},
+//	   "areas": {"okinawa": {"oil": 760}}}]}
+type Registry struct {
+	Version string       `json:"version"`
+	Default string       `json:"default"` // ID of the set used when none is requested
+	Sets    []*FactorSet `json:"sets"`
+}
+
+//go:embed factors.json
+var builtinData []byte
+
+// DefaultRegistry returns the bundled factor sets. They are national, without
+// per-area overrides; those come from a file loaded with OpenRegistry.
+func DefaultRegistry() *Registry {
+	r, err := parseRegistry(builtinData)
+	if err != nil {
+		panic(fmt.Sprintf("invalid bundled emission factors: %v", err))
+	}
+	return r
+}
+
+// DefaultSet returns the bundled default set.
+func DefaultSet() *FactorSet {
+	r := DefaultRegistry()
+	s, _ := r.Lookup(r.Default)
+	return s
+}
+
+// LoadRegistry reads and validates an emission factor data file.
+func LoadRegistry(path string) (*Registry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read emission factors: %w", err)
+	}
+	r, err := parseRegistry(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid emission factors %s: %w", path, err)
+	}
+	return r, nil
+}
+
+// OpenRegistry loads the data file at path, or the bundled sets if path is empty.
+func OpenRegistry(path string) (*Registry, error) {
+	if path == "" {
+		return DefaultRegistry(), nil
+	}
+	return LoadRegistry(path)
+}
+
+// parseRegistry decodes and validates a data file and stamps its version on every set.
+func parseRegistry(data []byte) (*Registry, error) {
+	var r Registry
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, err
+	}
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
+	for _, s := range r.Sets {
+		s.Version = r.Version
+	}
+	return &r, nil
+}
+
+// Validate checks the version, set IDs, bases, fuels, areas and factor values.
+func (r *Registry) Validate() error {
+	if r.Version == "" {
+		return errors.New("version is required")
+	}
+	if len(r.Sets) == 0 {
+		return errors.New("at least one factor set is required")
+	}
+
+	ids := make(map[string]bool, len(r.Sets))
+	for i, s := range r.Sets {
+		if s == nil || s.ID == "" {
+			return fmt.Errorf("set %d: id is required", i)
+		}
+		if ids[s.ID] {
+			return fmt.Errorf("set %q: duplicate id", s.ID)
+		}
+		ids[s.ID] = true
+
+		if s.Basis != BasisCombustion && s.Basis != BasisLifecycle {
+			return fmt.Errorf("set %q: basis must be combustion or lifecycle, got %q", s.ID, s.Basis)
+		}
+		if err := s.Factors.validate(); err != nil {
+			return fmt.Errorf("set %q: %w", s.ID, err)
+		}
+		for code, f := range s.Areas {
+			if _, ok := areas.Lookup(code); !ok {
+				return fmt.Errorf("set %q: unknown area %q", s.ID, code)
+			}
+			if err := f.validate(); err != nil {
+				return fmt.Errorf("set %q area %s: %w", s.ID, code, err)
+			}
+		}
+	}
+
+	if r.Default == "" {
+		return errors.New("default set is required")
+	}
+	if !ids[r.Default] {
+		return fmt.Errorf("default set %q is not defined", r.Default)
+	}
+	return nil
+}
+
+func (f Factors) validate() error {
+	for fuel, v := range f {
+		if !knownFuel(fuel) {
+			return fmt.Errorf("unknown fuel %q", fuel)
+		}
+		if v < 0 {
+			return fmt.Errorf("fuel %s: factor must not be negative", fuel)
+		}
+	}
+	return nil
+}
+
+func knownFuel(fuel Fuel) bool {
+	for _, f := range Fuels {
+		if f == fuel {
+			return true
+		}
+	}
+	return false
+}
+
+// Lookup returns a set by ID.
+func (r *Registry) Lookup(id string) (*FactorSet, bool) {
+	for _, s := range r.Sets {
+		if s.ID == id {
+			return s, true
+		}
+	}
+	return nil, false
+}
+
+// IDs lists the set IDs in file order.
+func (r *Registry) IDs() []string {
+	ids := make([]string, len(r.Sets))
+	for i, s := range r.Sets {
+		ids[i] = s.ID
+	}
+	return ids
+}
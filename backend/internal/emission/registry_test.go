@@ -0,0 +1,134 @@
+package emission
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/teo/aversome/backend/internal/areas"
+)
+
+func TestDefaultRegistry(t *testing.T) {
+	r := DefaultRegistry()
+	if r.Default != DefaultSetID {
+		t.Errorf("default = %s, want %s", r.Default, DefaultSetID)
+	}
+
+	// The default set keeps the dashboard's original factors
+	set, ok := r.Lookup(DefaultSetID)
+	if !ok {
+		t.Fatalf("Lookup(%s) not found", DefaultSetID)
+	}
+	want := Factors{FuelLNG: 350, FuelCoal: 850, FuelOil: 700, FuelOther: 500}
+	for _, fuel := range Fuels {
+		if got := set.Factor("tokyo", fuel); got != want[fuel] {
+			t.Errorf("default %s factor = %v, want %v", fuel, got, want[fuel])
+		}
+	}
+
+	for _, id := range r.IDs() {
+		set, _ := r.Lookup(id)
+		if set.Version != r.Version {
+			t.Errorf("set %s version = %q, want %q", id, set.Version, r.Version)
+		}
+	}
+	lifecycle, ok := r.Lookup("criepi-2016-lifecycle")
+	if !ok || lifecycle.Basis != BasisLifecycle || lifecycle.Factors[FuelSolar] == 0 {
+		t.Errorf("criepi-2016-lifecycle = %+v, want lifecycle factors for solar", lifecycle)
+	}
+}
+
+func TestFactorSet_Factor(t *testing.T) {
+	set := &FactorSet{
+		ID:      "x",
+		Factors: Factors{FuelOil: 700, FuelLNG: 350},
+		Areas:   map[areas.Code]Factors{"okinawa": {FuelOil: 760}},
+	}
+
+	if got := set.Factor("okinawa", FuelOil); got != 760 {
+		t.Errorf("okinawa oil = %v, want area override 760", got)
+	}
+	if got := set.Factor("okinawa", FuelLNG); got != 350 {
+		t.Errorf("okinawa lng = %v, want national 350", got)
+	}
+	if got := set.Factor("tokyo", FuelOil); got != 700 {
+		t.Errorf("tokyo oil = %v, want national 700", got)
+	}
+	if got := set.Factor("tokyo", FuelCoal); got != 0 {
+		t.Errorf("tokyo coal = %v, want 0 for a missing fuel", got)
+	}
+}
+
+func TestRegistry_Validate(t *testing.T) {
+	set := func(id string) *FactorSet {
+		return &FactorSet{ID: id, Basis: BasisCombustion, Factors: Factors{FuelCoal: 850}}
+	}
+	tests := []struct {
+		name     string
+		registry Registry
+		wantErr  string
+	}{
+		{"valid", Registry{Version: "1", Default: "a", Sets: []*FactorSet{set("a"), set("b")}}, ""},
+		{"missing version", Registry{Default: "a", Sets: []*FactorSet{set("a")}}, "version is required"},
+		{"no sets", Registry{Version: "1", Default: "a"}, "at least one factor set"},
+		{"missing id", Registry{Version: "1", Default: "a", Sets: []*FactorSet{set("")}}, "id is required"},
+		{"duplicate id", Registry{Version: "1", Default: "a", Sets: []*FactorSet{set("a"), set("a")}}, "duplicate id"},
+		{"bad basis", Registry{Version: "1", Default: "a", Sets: []*FactorSet{{ID: "a", Basis: "scope3"}}}, "basis must be"},
+		{"unknown fuel", Registry{Version: "1", Default: "a", Sets: []*FactorSet{
+			{ID: "a", Basis: BasisLifecycle, Factors: Factors{"peat": 1000}},
+		}}, "unknown fuel"},
+		{"negative factor", Registry{Version: "1", Default: "a", Sets: []*FactorSet{
+			{ID: "a", Basis: BasisLifecycle, Factors: Factors{FuelCoal: -1}},
+		}}, "must not be negative"},
+		{"unknown area", Registry{Version: "1", Default: "a", Sets: []*FactorSet{
+			{ID: "a", Basis: BasisLifecycle, Areas: map[areas.Code]Factors{"osaka": {FuelCoal: 900}}},
+		}}, "unknown area"},
+		{"undefined default", Registry{Version: "1", Default: "c", Sets: []*FactorSet{set("a")}}, "not defined"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.registry.Validate()
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("Validate() error = %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("Validate() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestOpenRegistry(t *testing.T) {
+	r, err := OpenRegistry("")
+	if err != nil || r.Default != DefaultSetID {
+		t.Fatalf("OpenRegistry(\"\") = %v, %v, want bundled sets", r, err)
+	}
+
+	path := filepath.Join(t.TempDir(), "factors.json")
+	data := `{"version": "2026.1", "default": "scope2", "sets": [
+		{"id": "scope2", "basis": "lifecycle", "factors": {"coal": 943, "lng": 474},
+		 "areas": {"okinawa": {"oil": 760}}}]}`
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	r, err = OpenRegistry(path)
+	if err != nil {
+		t.Fatalf("OpenRegistry(file) error = %v", err)
+	}
+	set, ok := r.Lookup("scope2")
+	if !ok || set.Version != "2026.1" || set.Factor("okinawa", FuelOil) != 760 {
+		t.Errorf("loaded set = %+v, want scope2 version 2026.1 with okinawa oil 760", set)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"version": "1", "default": "a", "sets": []}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := OpenRegistry(path); err == nil || !strings.Contains(err.Error(), "at least one factor set") {
+		t.Errorf("OpenRegistry(invalid file) error = %v", err)
+	}
+	if _, err := OpenRegistry(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("OpenRegistry(missing file) error = nil")
+	}
+}
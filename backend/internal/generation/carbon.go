@@ -0,0 +1,123 @@
+package generation
+
+import (
+	"github.com/teo/aversome/backend/internal/emission"
+	"github.com/teo/aversome/backend/pkg/timeutil"
+)
+
+// meritOrder lists the dispatchable fuels from the most to the least
+// expensive to run. The first one generating is assumed to set the price and
+// follow demand, so its emission factor approximates the marginal intensity
+// of the interval. Other thermal is left out: it mixes waste, by-product gas
+// and in-house plants that run regardless of demand. This is a heuristic;
+// package marginal measures the marginal factor from the generation history.
+var meritOrder = []emission.Fuel{emission.FuelOil, emission.FuelLNG, emission.FuelCoal}
+
+// meritOrderFallback is assumed marginal when no thermal plant runs: reservoir hydro
+// and pumped storage absorb demand changes.
+const meritOrderFallback = emission.FuelHydro
+
+// CarbonPoint is the carbon intensity of one interval.
+type CarbonPoint struct {
+	Timestamp         string        `json:"ts"`                   // Interval start, ISO8601 with Asia/Tokyo offset
+	AvgGCO2KWh        float64       `json:"avg_gco2_kwh"`         // Emissions / generated energy
+	MeritOrderGCO2KWh float64       `json:"merit_order_gco2_kwh"` // Factor of MeritOrderFuel, a marginal intensity heuristic
+	MeritOrderFuel    emission.Fuel `json:"merit_order_fuel"`     // Most expensive fuel generating, assumed marginal
+	GeneratedMW       float64       `json:"generated_mw"`         // Area generation, without storage and imports
+	EmissionsTCO2H    float64       `json:"emissions_tco2_h"`     // CO2 emission rate
+}
+
+// CarbonSummary aggregates the intensity series.
+type CarbonSummary struct {
+	AvgGCO2KWh           float64 `json:"avg_gco2_kwh"` // Energy-weighted, equals meta.avg_carbon_gco2_kwh
+	MinGCO2KWh           float64 `json:"min_gco2_kwh"`
+	MinAt                string  `json:"min_ts,omitempty"` // First interval with the lowest intensity
+	MaxGCO2KWh           float64 `json:"max_gco2_kwh"`
+	MaxAt                string  `json:"max_ts,omitempty"`
+	AvgMeritOrderGCO2KWh float64 `json:"avg_merit_order_gco2_kwh"` // Unweighted mean over the intervals
+}
+
+// CarbonResponse is the per-interval carbon intensity of an area and day.
+// GET /api/carbon/{area}/{date}
+type CarbonResponse struct {
+	Date              string         `json:"date"`                // YYYY-MM-DD
+	Area              string         `json:"area"`                // tokyo, kansai, etc.
+	Timezone          string         `json:"timezone"`            // Asia/Tokyo
+	Timescale         string         `json:"timescale"`           // hourly or 30min, as the generation mix
+	EmissionFactorSet string         `json:"emission_factor_set"` // Factor set of every value
+	Basis             emission.Basis `json:"emission_factor_basis"`
+	Series            []CarbonPoint  `json:"series"`
+	Summary           CarbonSummary  `json:"summary"`
+	Source            Source         `json:"source"` // Source of the generation mix
+}
+
+// CarbonGCO2KWh is the average carbon intensity of GeneratedMW in gCO2/kWh,
+// zero when nothing is generated.
+func (p GenerationPoint) CarbonGCO2KWh(set *emission.FactorSet, area string) float64 {
+	generated := p.GeneratedMW()
+	if generated <= 0 {
+		return 0
+	}
+	return p.EmissionsTCO2PerHour(set, area) / generated * 1000 // t/MWh = kg/kWh
+}
+
+// MeritOrderFuel returns the fuel following demand: the most expensive
+// dispatchable fuel generating, or hydro when no thermal plant runs.
+func (p GenerationPoint) MeritOrderFuel() emission.Fuel {
+	for _, fuel := range meritOrder {
+		if p.FuelMW(fuel) > 0 {
+			return fuel
+		}
+	}
+	return meritOrderFallback
+}
+
+// CarbonIntensity returns the average and merit-order carbon intensity of
+// every interval under the response's emission factors.
+func (r *Response) CarbonIntensity() *CarbonResponse {
+	factors := r.EmissionFactors()
+	out := &CarbonResponse{
+		Date:              r.Date,
+		Area:              r.Area,
+		Timezone:          r.Timezone,
+		Timescale:         r.Timescale,
+		EmissionFactorSet: factors.ID,
+		Basis:             factors.Basis,
+		Series:            make([]CarbonPoint, len(r.Series)),
+		Source:            r.Source,
+	}
+
+	var generatedMW, emissions, meritOrderSum float64
+	for i, p := range r.Series {
+		fuel := p.MeritOrderFuel()
+		cp := CarbonPoint{
+			Timestamp:         timeutil.FormatISO8601(p.Timestamp),
+			AvgGCO2KWh:        p.CarbonGCO2KWh(factors, r.Area),
+			MeritOrderGCO2KWh: factors.Factor(r.Area, fuel),
+			MeritOrderFuel:    fuel,
+			GeneratedMW:       p.GeneratedMW(),
+			EmissionsTCO2H:    p.EmissionsTCO2PerHour(factors, r.Area),
+		}
+		out.Series[i] = cp
+
+		generatedMW += cp.GeneratedMW
+		emissions += cp.EmissionsTCO2H
+		meritOrderSum += cp.MeritOrderGCO2KWh
+
+		s := &out.Summary
+		if i == 0 || cp.AvgGCO2KWh < s.MinGCO2KWh {
+			s.MinGCO2KWh, s.MinAt = cp.AvgGCO2KWh, cp.Timestamp
+		}
+		if i == 0 || cp.AvgGCO2KWh > s.MaxGCO2KWh {
+			s.MaxGCO2KWh, s.MaxAt = cp.AvgGCO2KWh, cp.Timestamp
+		}
+	}
+
+	if generatedMW > 0 {
+		out.Summary.AvgGCO2KWh = emissions / generatedMW * 1000
+	}
+	if len(r.Series) > 0 {
+		out.Summary.AvgMeritOrderGCO2KWh = meritOrderSum / float64(len(r.Series))
+	}
+	return out
+}
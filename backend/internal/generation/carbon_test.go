@@ -0,0 +1,76 @@
+package generation
+
+import (
+	"math"
+	"testing"
+
+	"github.com/teo/aversome/backend/internal/emission"
+	"github.com/teo/aversome/backend/pkg/timeutil"
+)
+
+func TestGenerationPoint_MeritOrderFuel(t *testing.T) {
+	tests := []struct {
+		point GenerationPoint
+		want  emission.Fuel
+	}{
+		{GenerationPoint{NuclearMW: 3000, CoalMW: 2000, LNGMW: 1000, OilMW: 200}, emission.FuelOil},
+		{GenerationPoint{NuclearMW: 3000, CoalMW: 2000, LNGMW: 1000, OtherMW: 100}, emission.FuelLNG},
+		{GenerationPoint{NuclearMW: 3000, CoalMW: 2000, LNGMW: 1000}, emission.FuelLNG},
+		{GenerationPoint{NuclearMW: 3000, CoalMW: 2000}, emission.FuelCoal},
+		{GenerationPoint{SolarMW: 5000, NuclearMW: 3000, PumpedStorageMW: -2000}, emission.FuelHydro},
+	}
+
+	for _, tt := range tests {
+		if got := tt.point.MeritOrderFuel(); got != tt.want {
+			t.Errorf("MeritOrderFuel(%+v) = %s, want %s", tt.point, got, tt.want)
+		}
+	}
+}
+
+func TestResponse_CarbonIntensity(t *testing.T) {
+	day, _ := timeutil.ParseDate("2025-10-24")
+	resp := NewResponseWithTimescale("kyushu", "2025-10-24", Timescale30Min)
+	resp.Series = append(resp.Series,
+		// Night: coal and LNG, LNG marginal
+		GenerationPoint{Timestamp: timeutil.SlotTime(day, 4), NuclearMW: 3000, CoalMW: 2000, LNGMW: 1000},
+		// Noon: solar surplus, no thermal running
+		GenerationPoint{Timestamp: timeutil.SlotTime(day, 24), SolarMW: 6000, NuclearMW: 3000, PumpedStorageMW: -1000},
+		// Evening: oil peakers marginal
+		GenerationPoint{Timestamp: timeutil.SlotTime(day, 36), NuclearMW: 3000, CoalMW: 2000, LNGMW: 3000, OilMW: 1000},
+	)
+	resp.CalculateMeta()
+
+	got := resp.CarbonIntensity()
+	if len(got.Series) != 3 || got.EmissionFactorSet != emission.DefaultSetID || got.Timescale != Timescale30Min {
+		t.Fatalf("CarbonIntensity() = %+v, want 3 30min points under the default set", got)
+	}
+
+	want := []struct {
+		avg, meritOrder float64
+		fuel            emission.Fuel
+	}{
+		{(2000*850 + 1000*350) / 6000.0, 350, emission.FuelLNG},
+		{0, 0, emission.FuelHydro},
+		{(2000*850 + 3000*350 + 1000*700) / 9000.0, 700, emission.FuelOil},
+	}
+	for i, w := range want {
+		p := got.Series[i]
+		if math.Abs(p.AvgGCO2KWh-w.avg) > 1e-9 || p.MeritOrderGCO2KWh != w.meritOrder || p.MeritOrderFuel != w.fuel {
+			t.Errorf("point %d = %+v, want avg %v, merit order %v (%s)", i, p, w.avg, w.meritOrder, w.fuel)
+		}
+	}
+	if got.Series[0].Timestamp != "2025-10-24T02:00:00+09:00" {
+		t.Errorf("ts = %s, want 2025-10-24T02:00:00+09:00", got.Series[0].Timestamp)
+	}
+
+	s := got.Summary
+	if math.Abs(s.AvgGCO2KWh-resp.Meta.AvgCarbonGCO2KWh) > 1e-9 {
+		t.Errorf("summary avg = %v, want meta avg %v", s.AvgGCO2KWh, resp.Meta.AvgCarbonGCO2KWh)
+	}
+	if s.MinGCO2KWh != 0 || s.MinAt != got.Series[1].Timestamp || s.MaxAt != got.Series[2].Timestamp {
+		t.Errorf("summary = %+v, want min at noon and max in the evening", s)
+	}
+	if math.Abs(s.AvgMeritOrderGCO2KWh-350) > 1e-9 {
+		t.Errorf("AvgMeritOrderGCO2KWh = %v, want 350", s.AvgMeritOrderGCO2KWh)
+	}
+}
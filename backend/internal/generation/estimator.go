@@ -8,8 +8,12 @@ import (
 
 	"github.com/teo/aversome/backend/internal/demand"
 	"github.com/teo/aversome/backend/internal/jepx"
+	"github.com/teo/aversome/backend/pkg/timeutil"
 )
 
+// EstimatedSourceName is the Source.Name of estimated generation mixes.
+const EstimatedSourceName = "Estimated (demand + price correlation)"
+
 // Estimator estimates generation mix from demand and price data.
 type Estimator struct{}
 
@@ -29,22 +33,34 @@ func (e *Estimator) EstimateFromDemandAndPrice(demandResp *demand.Response, jepx
 		return nil, fmt.Errorf("empty demand or price data")
 	}
 
+	// Bring demand and prices to a common resolution
+	demandResp, jepxResp, err := alignTimescales(demandResp, jepxResp)
+	if err != nil {
+		return nil, err
+	}
+
 	// Calculate price statistics for solar correlation
 	_, minPrice, maxPrice := e.calculatePriceStats(jepxResp)
 
-	resp := NewResponse(string(demandResp.Area), demandResp.Date)
+	// Index prices by timestamp so demand and price points pair up by period
+	priceByTS := make(map[string]float64, len(jepxResp.PriceYenPerKwh))
+	for _, p := range jepxResp.PriceYenPerKwh {
+		priceByTS[p.Timestamp] = p.Price
+	}
+
+	resp := NewResponseWithTimescale(string(demandResp.Area), demandResp.Date, string(demandResp.Timescale))
 	resp.Source = Source{
-		Name: "Estimated (demand + price correlation)",
+		Name: EstimatedSourceName,
 		URL:  "Internal calculation",
 	}
 
-	// Generate hourly generation points
-	for i, demandPoint := range demandResp.Series {
-		if i >= len(jepxResp.PriceYenPerKwh) {
-			break
+	// Generate one generation point per demand period
+	for _, demandPoint := range demandResp.Series {
+		price, ok := priceByTS[timeutil.FormatISO8601(demandPoint.Timestamp)]
+		if !ok {
+			continue // No price for this period
 		}
 
-		price := jepxResp.PriceYenPerKwh[i].Price
 		totalDemand := demandPoint.DemandMW
 		hour := demandPoint.Timestamp.Hour()
 
@@ -86,12 +102,34 @@ func (e *Estimator) EstimateFromDemandAndPrice(demandResp *demand.Response, jepx
 		resp.Series = append(resp.Series, point)
 	}
 
+	if len(resp.Series) == 0 {
+		return nil, fmt.Errorf("no overlapping demand and price periods")
+	}
+
 	// Calculate metadata
 	resp.CalculateMeta()
 
 	return resp, nil
 }
 
+// alignTimescales resamples demand or prices so both share a timescale.
+// 30min is kept only when both inputs are 30min; otherwise both become hourly.
+func alignTimescales(demandResp *demand.Response, jepxResp *jepx.Response) (*demand.Response, *jepx.Response, error) {
+	if string(demandResp.Timescale) == jepxResp.Timescale {
+		return demandResp, jepxResp, nil
+	}
+
+	d, err := demandResp.Resample(demand.TimescaleHourly)
+	if err != nil {
+		return nil, nil, err
+	}
+	p, err := jepxResp.Resample(jepx.TimescaleHourly)
+	if err != nil {
+		return nil, nil, err
+	}
+	return d, p, nil
+}
+
 // estimateSolar estimates solar generation based on hour and price.
 // Solar peaks at midday (11:00-14:00) and correlates with low prices.
 func (e *Estimator) estimateSolar(hour int, price, minPrice, maxPrice, totalDemand float64) float64 {
@@ -0,0 +1,73 @@
+package generation
+
+import (
+	"fmt"
+
+	"github.com/teo/aversome/backend/internal/emission"
+	"github.com/teo/aversome/backend/internal/series"
+)
+
+// RangeResponse is a multi-day generation mix series.
+// GET /api/generation/{area}?from=YYYY-MM-DD&to=YYYY-MM-DD
+type RangeResponse struct {
+	Area      string            `json:"area"`
+	From      string            `json:"from"`           // First requested date
+	To        string            `json:"to"`             // Last requested date
+	Timezone  string            `json:"timezone"`       // Always "Asia/Tokyo"
+	Timescale string            `json:"timescale"`      // Common resolution of the series
+	Series    []GenerationPoint `json:"series"`         // Concatenated points of every stored day
+	Gaps      []series.Gap      `json:"gaps"`           // Days with no stored data
+	Sources   []Source          `json:"sources"`        // Distinct sources in the range
+	Meta      *Meta             `json:"meta,omitempty"` // Aggregated over the whole range
+}
+
+// NewRangeResponse concatenates daily responses (ascending, one per stored
+// day) into a range series and recalculates Meta over the range. Days are
+// resampled to a common timescale: the requested one, or hourly if the days
+// disagree. Carbon intensity uses factors (the bundled default if nil).
+func NewRangeResponse(area, from, to string, days []*Response, gaps []series.Gap, timescale string, factors *emission.FactorSet) (*RangeResponse, error) {
+	timescales := make([]string, len(days))
+	for i, d := range days {
+		timescales[i] = d.Timescale
+	}
+	target := series.CommonTimescale(timescale, timescales)
+
+	// Reuse Response.CalculateMeta on the concatenated series
+	all := NewResponseWithTimescale(area, from, target)
+	all.factors = factors
+	sources := make([]Source, 0, 1)
+
+	for _, d := range days {
+		day, err := d.Resample(target)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", d.Date, err)
+		}
+		all.Series = append(all.Series, day.Series...)
+
+		if !containsSource(sources, day.Source) {
+			sources = append(sources, day.Source)
+		}
+	}
+	all.CalculateMeta()
+
+	return &RangeResponse{
+		Area:      area,
+		From:      from,
+		To:        to,
+		Timezone:  all.Timezone,
+		Timescale: target,
+		Series:    all.Series,
+		Gaps:      gaps,
+		Sources:   sources,
+		Meta:      all.Meta,
+	}, nil
+}
+
+func containsSource(sources []Source, s Source) bool {
+	for _, existing := range sources {
+		if existing == s {
+			return true
+		}
+	}
+	return false
+}
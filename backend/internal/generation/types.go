@@ -3,7 +3,17 @@ package generation
 
 import (
 	"encoding/json"
+	"fmt"
 	"time"
+
+	"github.com/teo/aversome/backend/internal/emission"
+	"github.com/teo/aversome/backend/pkg/timeutil"
+)
+
+// Timescale values for Response.Timescale.
+const (
+	TimescaleHourly = timeutil.TimescaleHourly
+	Timescale30Min  = timeutil.Timescale30Min // Native OCCTO resolution (48 コマ)
 )
 
 // Source represents the data source metadata.
@@ -13,70 +23,234 @@ type Source struct {
 }
 
 // GenerationPoint represents generation capacity by fuel type at a specific time.
+//
+// The original seven categories are always present. The finer OCCTO
+// categories are omitted when zero (estimated data has none of them).
+// Storage and interconnector values are signed: pumped-storage pumping and
+// battery charging are negative (load), net imports are positive. TotalMW is
+// the sum of every category, i.e. the supply that met the area demand.
 type GenerationPoint struct {
-	Timestamp  time.Time `json:"ts"`
-	SolarMW    float64   `json:"solar_mw"`
-	WindMW     float64   `json:"wind_mw"`
-	HydroMW    float64   `json:"hydro_mw"`
-	NuclearMW  float64   `json:"nuclear_mw"`
-	LNGMW      float64   `json:"lng_mw"`
-	CoalMW     float64   `json:"coal_mw"`
-	OtherMW    float64   `json:"other_mw"`
-	TotalMW    float64   `json:"total_mw"`
+	Timestamp time.Time `json:"ts"`
+	SolarMW   float64   `json:"solar_mw"`
+	WindMW    float64   `json:"wind_mw"`
+	HydroMW   float64   `json:"hydro_mw"` // Conventional hydro, without pumped storage
+	NuclearMW float64   `json:"nuclear_mw"`
+	LNGMW     float64   `json:"lng_mw"`
+	CoalMW    float64   `json:"coal_mw"`
+	OtherMW   float64   `json:"other_mw"` // Other thermal (火力(その他)) and uncategorized (その他)
+	TotalMW   float64   `json:"total_mw"`
+
+	OilMW            float64 `json:"oil_mw,omitempty"`            // 火力(石油)
+	BiomassMW        float64 `json:"biomass_mw,omitempty"`        // バイオマス
+	GeothermalMW     float64 `json:"geothermal_mw,omitempty"`     // 地熱
+	PumpedStorageMW  float64 `json:"pumped_storage_mw,omitempty"` // 揚水: generation minus pumping
+	BatteryMW        float64 `json:"battery_mw,omitempty"`        // 蓄電池: discharge minus charge
+	InterconnectorMW float64 `json:"interconnector_mw,omitempty"` // 連系線: net imports
+
+	// Renewable output curtailed (出力制御) in the interval, set by
+	// curtailment.Response.Join; not part of TotalMW
+	CurtailedSolarMW float64 `json:"curtailed_solar_mw,omitempty"`
+	CurtailedWindMW  float64 `json:"curtailed_wind_mw,omitempty"`
+}
+
+// defaultFactors is used by responses without SetEmissionFactors, e.g. after decoding.
+var defaultFactors = emission.DefaultSet()
+
+// RenewableMW is solar, wind, conventional hydro, geothermal and biomass output.
+func (p GenerationPoint) RenewableMW() float64 {
+	return p.SolarMW + p.WindMW + p.HydroMW + p.GeothermalMW + p.BiomassMW
+}
+
+// GeneratedMW is the output of the area's power plants: every category
+// except storage and interconnector imports, which move energy generated
+// elsewhere or earlier.
+func (p GenerationPoint) GeneratedMW() float64 {
+	return p.RenewableMW() + p.NuclearMW + p.LNGMW + p.CoalMW + p.OilMW + p.OtherMW
+}
+
+// FuelMW returns the output of a fuel with an emission factor.
+func (p GenerationPoint) FuelMW(fuel emission.Fuel) float64 {
+	switch fuel {
+	case emission.FuelSolar:
+		return p.SolarMW
+	case emission.FuelWind:
+		return p.WindMW
+	case emission.FuelHydro:
+		return p.HydroMW
+	case emission.FuelGeothermal:
+		return p.GeothermalMW
+	case emission.FuelBiomass:
+		return p.BiomassMW
+	case emission.FuelNuclear:
+		return p.NuclearMW
+	case emission.FuelLNG:
+		return p.LNGMW
+	case emission.FuelCoal:
+		return p.CoalMW
+	case emission.FuelOil:
+		return p.OilMW
+	case emission.FuelOther:
+		return p.OtherMW
+	}
+	return 0
+}
+
+// EmissionsTCO2PerHour is the CO2 emission rate of GeneratedMW in tonnes per
+// hour under the factors of set for area. Storage and imports are zero;
+// storage emits when its charging energy is generated.
+func (p GenerationPoint) EmissionsTCO2PerHour(set *emission.FactorSet, area string) float64 {
+	var kgPerHour float64
+	for _, fuel := range emission.Fuels { // Fixed order keeps the sum reproducible
+		kgPerHour += p.FuelMW(fuel) * set.Factor(area, fuel) // MW x g/kWh = kg/h
+	}
+	return kgPerHour / 1000
+}
+
+// SumMW sets TotalMW to the sum of every category.
+func (p *GenerationPoint) SumMW() {
+	p.TotalMW = p.GeneratedMW() + p.PumpedStorageMW + p.BatteryMW + p.InterconnectorMW
+}
+
+// add adds every MW value of q to p.
+func (p *GenerationPoint) add(q GenerationPoint) {
+	p.SolarMW += q.SolarMW
+	p.WindMW += q.WindMW
+	p.HydroMW += q.HydroMW
+	p.NuclearMW += q.NuclearMW
+	p.LNGMW += q.LNGMW
+	p.CoalMW += q.CoalMW
+	p.OtherMW += q.OtherMW
+	p.TotalMW += q.TotalMW
+	p.OilMW += q.OilMW
+	p.BiomassMW += q.BiomassMW
+	p.GeothermalMW += q.GeothermalMW
+	p.PumpedStorageMW += q.PumpedStorageMW
+	p.BatteryMW += q.BatteryMW
+	p.InterconnectorMW += q.InterconnectorMW
+	p.CurtailedSolarMW += q.CurtailedSolarMW
+	p.CurtailedWindMW += q.CurtailedWindMW
+}
+
+// scale multiplies every MW value of p by f.
+func (p *GenerationPoint) scale(f float64) {
+	for _, v := range []*float64{
+		&p.SolarMW, &p.WindMW, &p.HydroMW, &p.NuclearMW, &p.LNGMW, &p.CoalMW, &p.OtherMW, &p.TotalMW,
+		&p.OilMW, &p.BiomassMW, &p.GeothermalMW, &p.PumpedStorageMW, &p.BatteryMW, &p.InterconnectorMW,
+		&p.CurtailedSolarMW, &p.CurtailedWindMW,
+	} {
+		*v *= f
+	}
 }
 
 // Response represents the complete generation mix response.
 type Response struct {
-	Date       string            `json:"date"`        // YYYY-MM-DD
-	Area       string            `json:"area"`        // tokyo, kansai, etc.
-	Timezone   string            `json:"timezone"`    // Asia/Tokyo
-	Timescale  string            `json:"timescale"`   // hourly
-	Series     []GenerationPoint `json:"series"`
-	Source     Source            `json:"source"`
-	Meta       *Meta             `json:"meta,omitempty"`
+	Date      string            `json:"date"`      // YYYY-MM-DD
+	Area      string            `json:"area"`      // tokyo, kansai, etc.
+	Timezone  string            `json:"timezone"`  // Asia/Tokyo
+	Timescale string            `json:"timescale"` // hourly or 30min
+	Series    []GenerationPoint `json:"series"`
+	Source    Source            `json:"source"`
+	Meta      *Meta             `json:"meta,omitempty"`
+
+	factors *emission.FactorSet // Emission factors for AvgCarbonGCO2KWh (defaultFactors if nil)
 }
 
 // Meta contains aggregated metrics.
 type Meta struct {
-	AvgRenewablePct  float64 `json:"avg_renewable_pct"`  // (Solar + Wind + Hydro) / Total
-	AvgCarbonGCO2KWh float64 `json:"avg_carbon_gco2_kwh"` // Average carbon intensity
+	AvgRenewablePct  float64 `json:"avg_renewable_pct"`   // Renewable / generated energy over the period
+	AvgCarbonGCO2KWh float64 `json:"avg_carbon_gco2_kwh"` // Emissions / generated energy over the period
 	PeakSolarMW      float64 `json:"peak_solar_mw"`
 	PeakWindMW       float64 `json:"peak_wind_mw"`
+	PumpingMWh       float64 `json:"pumping_mwh,omitempty"`   // Energy used for pumped-storage pumping
+	CurtailedMWh     float64 `json:"curtailed_mwh,omitempty"` // Curtailed solar + wind energy
+
+	EmissionFactorSet     string         `json:"emission_factor_set,omitempty"`     // ID of the factor set behind AvgCarbonGCO2KWh
+	EmissionFactorBasis   emission.Basis `json:"emission_factor_basis,omitempty"`   // combustion or lifecycle
+	EmissionFactorVersion string         `json:"emission_factor_version,omitempty"` // Version of the factor data file
 }
 
 // NewResponse creates a new generation mix response.
 func NewResponse(area, date string) *Response {
+	return NewResponseWithTimescale(area, date, TimescaleHourly)
+}
+
+// NewResponseWithTimescale creates a generation mix response at the given resolution.
+func NewResponseWithTimescale(area, date, timescale string) *Response {
 	return &Response{
 		Date:      date,
 		Area:      area,
 		Timezone:  "Asia/Tokyo",
-		Timescale: "hourly",
-		Series:    []GenerationPoint{},
+		Timescale: timescale,
+		Series:    make([]GenerationPoint, 0, timeutil.PointsPerDay(timescale)),
+	}
+}
+
+// SetEmissionFactors selects the emission factors for carbon intensity and
+// recalculates metadata. A nil set selects the bundled default.
+func (r *Response) SetEmissionFactors(set *emission.FactorSet) {
+	r.factors = set
+	r.CalculateMeta()
+}
+
+// EmissionFactors returns the factor set carbon intensity is calculated with.
+func (r *Response) EmissionFactors() *emission.FactorSet {
+	if r.factors == nil {
+		return defaultFactors
+	}
+	return r.factors
+}
+
+// Resample returns the response at the requested timescale.
+// 30min points are averaged into hourly points and metadata is recalculated;
+// hourly data cannot be upsampled and returns an error. The receiver is not modified.
+func (r *Response) Resample(timescale string) (*Response, error) {
+	if r.Timescale == timescale {
+		return r, nil
+	}
+	if r.Timescale != Timescale30Min || timescale != TimescaleHourly {
+		return nil, fmt.Errorf("cannot resample generation mix from %s to %s", r.Timescale, timescale)
 	}
+
+	out := *r
+	out.Timescale = TimescaleHourly
+	out.Series = make([]GenerationPoint, 0, 24)
+
+	for i := 0; i < len(r.Series); {
+		hourStart := r.Series[i].Timestamp.Truncate(time.Hour)
+		sum := GenerationPoint{Timestamp: hourStart}
+		count := 0
+		for ; i < len(r.Series) && r.Series[i].Timestamp.Truncate(time.Hour).Equal(hourStart); i++ {
+			sum.add(r.Series[i])
+			count++
+		}
+
+		sum.scale(1 / float64(count))
+		out.Series = append(out.Series, sum)
+	}
+
+	out.CalculateMeta()
+	return &out, nil
 }
 
 // CalculateMeta computes aggregated metrics from series data.
+// Carbon intensity uses the response's emission factors for its area.
+// Renewable share and carbon intensity are energy-weighted over the area's
+// own generation (GeneratedMW), so pumping, charging and imports do not
+// distort them.
 func (r *Response) CalculateMeta() {
 	if len(r.Series) == 0 {
 		return
 	}
 
-	var renewablePctSum float64
-	var carbonSum float64
+	factors := r.EmissionFactors()
+	var generatedMW, renewableMW, emissions float64
 	var peakSolar, peakWind float64
+	var pumpingMW, curtailedMW float64
 
 	for _, point := range r.Series {
-		if point.TotalMW > 0 {
-			renewableMW := point.SolarMW + point.WindMW + point.HydroMW
-			renewablePct := (renewableMW / point.TotalMW) * 100
-			renewablePctSum += renewablePct
-
-			// Calculate carbon intensity (simplified emission factors in gCO2/kWh)
-			// Nuclear: 0, Solar: 0, Wind: 0, Hydro: 0
-			// LNG: 350 gCO2/kWh, Coal: 850 gCO2/kWh, Other: 500 gCO2/kWh (avg)
-			carbonIntensity := (point.LNGMW*350 + point.CoalMW*850 + point.OtherMW*500) / point.TotalMW
-			carbonSum += carbonIntensity
-		}
+		generatedMW += point.GeneratedMW()
+		renewableMW += point.RenewableMW()
+		emissions += point.EmissionsTCO2PerHour(factors, r.Area)
 
 		if point.SolarMW > peakSolar {
 			peakSolar = point.SolarMW
@@ -84,14 +258,31 @@ func (r *Response) CalculateMeta() {
 		if point.WindMW > peakWind {
 			peakWind = point.WindMW
 		}
+		if point.PumpedStorageMW < 0 {
+			pumpingMW -= point.PumpedStorageMW
+		}
+		curtailedMW += point.CurtailedSolarMW + point.CurtailedWindMW
+	}
+
+	// Points are averages over their interval, so MWh = MW x interval hours
+	hours := 1.0
+	if r.Timescale == Timescale30Min {
+		hours = 0.5
 	}
 
-	count := float64(len(r.Series))
 	r.Meta = &Meta{
-		AvgRenewablePct:  renewablePctSum / count,
-		AvgCarbonGCO2KWh: carbonSum / count,
-		PeakSolarMW:      peakSolar,
-		PeakWindMW:       peakWind,
+		PeakSolarMW:  peakSolar,
+		PeakWindMW:   peakWind,
+		PumpingMWh:   pumpingMW * hours,
+		CurtailedMWh: curtailedMW * hours,
+
+		EmissionFactorSet:     factors.ID,
+		EmissionFactorBasis:   factors.Basis,
+		EmissionFactorVersion: factors.Version,
+	}
+	if generatedMW > 0 {
+		r.Meta.AvgRenewablePct = renewableMW / generatedMW * 100
+		r.Meta.AvgCarbonGCO2KWh = emissions / generatedMW * 1000 // t/MWh = kg/kWh
 	}
 }
 
@@ -99,31 +290,18 @@ func (r *Response) CalculateMeta() {
 func (r *Response) MarshalJSON() ([]byte, error) {
 	type Alias Response
 
-	// Format timestamps as ISO8601
+	// Format timestamps as ISO8601; the outer ts field shadows the embedded one
+	type point GenerationPoint
 	type SeriesAlias struct {
-		Timestamp  string  `json:"ts"`
-		SolarMW    float64 `json:"solar_mw"`
-		WindMW     float64 `json:"wind_mw"`
-		HydroMW    float64 `json:"hydro_mw"`
-		NuclearMW  float64 `json:"nuclear_mw"`
-		LNGMW      float64 `json:"lng_mw"`
-		CoalMW     float64 `json:"coal_mw"`
-		OtherMW    float64 `json:"other_mw"`
-		TotalMW    float64 `json:"total_mw"`
+		Timestamp string `json:"ts"`
+		*point
 	}
 
 	series := make([]SeriesAlias, len(r.Series))
-	for i, point := range r.Series {
+	for i := range r.Series {
 		series[i] = SeriesAlias{
-			Timestamp: point.Timestamp.Format(time.RFC3339),
-			SolarMW:   point.SolarMW,
-			WindMW:    point.WindMW,
-			HydroMW:   point.HydroMW,
-			NuclearMW: point.NuclearMW,
-			LNGMW:     point.LNGMW,
-			CoalMW:    point.CoalMW,
-			OtherMW:   point.OtherMW,
-			TotalMW:   point.TotalMW,
+			Timestamp: r.Series[i].Timestamp.Format(time.RFC3339),
+			point:     (*point)(&r.Series[i]),
 		}
 	}
 
@@ -0,0 +1,137 @@
+package generation
+
+import (
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/teo/aversome/backend/internal/areas"
+	"github.com/teo/aversome/backend/internal/emission"
+	"github.com/teo/aversome/backend/pkg/timeutil"
+)
+
+func TestResponse_CalculateMeta(t *testing.T) {
+	day, _ := timeutil.ParseDate("2025-10-24")
+	resp := NewResponseWithTimescale("kyushu", "2025-10-24", Timescale30Min)
+
+	// Midday: solar surplus pumped up and exported
+	noon := GenerationPoint{
+		Timestamp: timeutil.SlotTime(day, 24), SolarMW: 6000, HydroMW: 500, BiomassMW: 300, GeothermalMW: 200,
+		NuclearMW: 3000, LNGMW: 1000, PumpedStorageMW: -2000, InterconnectorMW: -1000,
+	}
+	// Evening: pumped storage generates, coal and oil run
+	evening := GenerationPoint{
+		Timestamp: timeutil.SlotTime(day, 36), HydroMW: 500, NuclearMW: 3000, LNGMW: 3000, CoalMW: 2000, OilMW: 500,
+		PumpedStorageMW: 1000, BatteryMW: 200,
+	}
+	noon.SumMW()
+	evening.SumMW()
+	resp.Series = append(resp.Series, noon, evening)
+	resp.CalculateMeta()
+
+	if noon.TotalMW != 8000 || evening.TotalMW != 10200 {
+		t.Fatalf("totals = %v/%v, want 8000/10200", noon.TotalMW, evening.TotalMW)
+	}
+
+	// Renewable 7500 of 20000 MW generated; pumping and exports are not generation
+	if got := resp.Meta.AvgRenewablePct; math.Abs(got-37.5) > 1e-9 {
+		t.Errorf("AvgRenewablePct = %v, want 37.5", got)
+	}
+	// (4000*350 + 2000*850 + 500*700) / 20000
+	if got := resp.Meta.AvgCarbonGCO2KWh; math.Abs(got-172.5) > 1e-9 {
+		t.Errorf("AvgCarbonGCO2KWh = %v, want 172.5", got)
+	}
+	if resp.Meta.PumpingMWh != 1000 || resp.Meta.PeakSolarMW != 6000 {
+		t.Errorf("meta = %+v, want 1000 MWh pumping and 6000 MW peak solar", resp.Meta)
+	}
+}
+
+func TestResponse_SetEmissionFactors(t *testing.T) {
+	day, _ := timeutil.ParseDate("2025-10-24")
+	resp := NewResponse("okinawa", "2025-10-24")
+	point := GenerationPoint{Timestamp: day, SolarMW: 200, LNGMW: 400, OilMW: 400}
+	point.SumMW()
+	resp.Series = append(resp.Series, point)
+
+	resp.CalculateMeta()
+	if resp.Meta.EmissionFactorSet != emission.DefaultSetID {
+		t.Errorf("EmissionFactorSet = %q, want %q", resp.Meta.EmissionFactorSet, emission.DefaultSetID)
+	}
+	// (400*350 + 400*700) / 1000
+	if got := resp.Meta.AvgCarbonGCO2KWh; math.Abs(got-420) > 1e-9 {
+		t.Errorf("default AvgCarbonGCO2KWh = %v, want 420", got)
+	}
+
+	// Lifecycle factors count solar; the area override replaces the national oil factor
+	resp.SetEmissionFactors(&emission.FactorSet{
+		ID: "scope2", Basis: emission.BasisLifecycle, Version: "2026.1",
+		Factors: emission.Factors{emission.FuelSolar: 40, emission.FuelLNG: 470, emission.FuelOil: 740},
+		Areas:   map[areas.Code]emission.Factors{"okinawa": {emission.FuelOil: 760}},
+	})
+	// (200*40 + 400*470 + 400*760) / 1000
+	if got := resp.Meta.AvgCarbonGCO2KWh; math.Abs(got-500) > 1e-9 {
+		t.Errorf("scope2 AvgCarbonGCO2KWh = %v, want 500", got)
+	}
+	if m := resp.Meta; m.EmissionFactorSet != "scope2" || m.EmissionFactorBasis != emission.BasisLifecycle || m.EmissionFactorVersion != "2026.1" {
+		t.Errorf("meta = %+v, want scope2 lifecycle 2026.1", m)
+	}
+
+	// Resampling keeps the selected factors
+	resp.Timescale = Timescale30Min
+	hourly, err := resp.Resample(TimescaleHourly)
+	if err != nil || hourly.Meta.EmissionFactorSet != "scope2" {
+		t.Errorf("Resample() meta = %+v, %v, want scope2", hourly.Meta, err)
+	}
+}
+
+func TestResponse_MarshalJSON(t *testing.T) {
+	resp := NewResponseWithTimescale("tokyo", "2025-10-24", Timescale30Min)
+	ts := time.Date(2025, 10, 24, 12, 0, 0, 0, timeutil.TokyoLocation)
+	resp.Series = append(resp.Series,
+		GenerationPoint{Timestamp: ts, SolarMW: 100, LNGMW: 50, TotalMW: 150},
+		GenerationPoint{Timestamp: ts.Add(30 * time.Minute), SolarMW: 100, PumpedStorageMW: -40, TotalMW: 60},
+	)
+
+	data, err := json.Marshal(resp)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	// Estimated points keep the original shape
+	var raw struct {
+		Series []map[string]any `json:"series"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	first := raw.Series[0]
+	for _, key := range []string{"ts", "solar_mw", "wind_mw", "hydro_mw", "nuclear_mw", "lng_mw", "coal_mw", "other_mw", "total_mw"} {
+		if _, ok := first[key]; !ok {
+			t.Errorf("series[0] has no %s", key)
+		}
+	}
+	if len(first) != 9 || first["ts"] != "2025-10-24T12:00:00+09:00" {
+		t.Errorf("series[0] = %v, want the 9 original keys", first)
+	}
+	if raw.Series[1]["pumped_storage_mw"] != -40.0 {
+		t.Errorf("series[1] = %v, want pumped_storage_mw -40", raw.Series[1])
+	}
+
+	var back Response
+	if err := json.Unmarshal(data, &back); err != nil {
+		t.Fatalf("Unmarshal(Response) error = %v", err)
+	}
+	if !back.Series[1].Timestamp.Equal(resp.Series[1].Timestamp) || back.Series[1].PumpedStorageMW != -40 {
+		t.Errorf("round trip = %+v", back.Series[1])
+	}
+
+	hourly, err := resp.Resample(TimescaleHourly)
+	if err != nil || len(hourly.Series) != 1 || hourly.Series[0].PumpedStorageMW != -20 || hourly.Series[0].TotalMW != 105 {
+		t.Errorf("Resample() = %+v, %v", hourly.Series, err)
+	}
+	if !strings.Contains(string(data), `"timescale":"30min"`) {
+		t.Errorf("Marshal() = %s", data)
+	}
+}
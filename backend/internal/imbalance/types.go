@@ -0,0 +1,51 @@
+// Package imbalance provides types for imbalance prices (インバランス料金),
+// the per-koma unit prices at which balancing groups settle the difference
+// between their nominated plan and actual demand or supply.
+package imbalance
+
+import "github.com/teo/aversome/backend/pkg/timeutil"
+
+// Timescale30Min is the only resolution imbalance prices are published at.
+const Timescale30Min = timeutil.Timescale30Min
+
+// PricePoint is the imbalance unit price of one koma. Since FY2022 both
+// prices are normally equal; they differ only when the price is corrected
+// (e.g., scarcity pricing applied to one side).
+type PricePoint struct {
+	Timestamp     string  `json:"ts"`             // ISO8601 with Asia/Tokyo offset
+	Period        int     `json:"period"`         // コマ number (1-48)
+	SurplusPrice  float64 `json:"surplus_price"`  // 余剰インバランス料金単価, JPY/kWh (paid to the BG)
+	ShortagePrice float64 `json:"shortage_price"` // 不足インバランス料金単価, JPY/kWh (charged to the BG)
+}
+
+// Source contains attribution for the data source.
+type Source struct {
+	Name string `json:"name"` // e.g., "Imbalance Price Portal"
+	URL  string `json:"url"`  // Original data source URL
+}
+
+// Meta contains optional metadata and warnings.
+type Meta struct {
+	Warning string `json:"warning,omitempty"` // Non-blocking warning message
+}
+
+// Response holds the imbalance prices of one area and day.
+// GET /api/imbalance/{area}/{date}
+type Response struct {
+	Date      string       `json:"date"`           // YYYY-MM-DD format
+	Area      string       `json:"area"`           // e.g., "tokyo", "kansai"
+	Timescale string       `json:"timescale"`      // Always "30min"
+	Prices    []PricePoint `json:"prices"`         // 48 koma
+	Source    Source       `json:"source"`         // Data attribution
+	Meta      *Meta        `json:"meta,omitempty"` // Optional metadata/warnings
+}
+
+// NewResponse creates a properly initialized Response with defaults.
+func NewResponse(date, area string) *Response {
+	return &Response{
+		Date:      date,
+		Area:      area,
+		Timescale: Timescale30Min,
+		Prices:    make([]PricePoint, 0, timeutil.SlotsPerDay),
+	}
+}
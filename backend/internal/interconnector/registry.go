@@ -0,0 +1,64 @@
+package interconnector
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/teo/aversome/backend/internal/areas"
+)
+
+// Line describes one interconnector. The forward direction (順方向) is
+// From → To, as OCCTO publishes it.
+type Line struct {
+	ID     string     `json:"id"`      // e.g., "tohoku-tokyo"
+	NameJA string     `json:"name_ja"` // e.g., "東北東京間"
+	NameEN string     `json:"name_en"` // e.g., "Tohoku-Tokyo"
+	From   areas.Code `json:"from"`
+	To     areas.Code `json:"to"`
+	HVDC   bool       `json:"hvdc"` // DC link or frequency converter (flow fully controllable)
+
+	aliases []string // Other names in OCCTO files
+}
+
+// registry lists the interconnectors in OCCTO order.
+var registry = []Line{
+	{ID: "hokkaido-honshu", NameJA: "北海道本州間", NameEN: "Hokkaido-Honshu", From: areas.Hokkaido, To: areas.Tohoku, HVDC: true,
+		aliases: []string{"北海道本州間連系設備", "北本連系"}},
+	{ID: "tohoku-tokyo", NameJA: "東北東京間", NameEN: "Tohoku-Tokyo", From: areas.Tohoku, To: areas.Tokyo},
+	{ID: "tokyo-chubu", NameJA: "東京中部間", NameEN: "Tokyo-Chubu (FC)", From: areas.Tokyo, To: areas.Chubu, HVDC: true,
+		aliases: []string{"周波数変換設備", "東京中部間連系設備"}},
+	{ID: "chubu-kansai", NameJA: "中部関西間", NameEN: "Chubu-Kansai", From: areas.Chubu, To: areas.Kansai},
+	{ID: "chubu-hokuriku", NameJA: "中部北陸間", NameEN: "Chubu-Hokuriku", From: areas.Chubu, To: areas.Hokuriku, HVDC: true,
+		aliases: []string{"中部北陸間連系設備"}},
+	{ID: "hokuriku-kansai", NameJA: "北陸関西間", NameEN: "Hokuriku-Kansai", From: areas.Hokuriku, To: areas.Kansai},
+	{ID: "kansai-chugoku", NameJA: "関西中国間", NameEN: "Kansai-Chugoku", From: areas.Kansai, To: areas.Chugoku},
+	{ID: "kansai-shikoku", NameJA: "関西四国間", NameEN: "Kansai-Shikoku", From: areas.Kansai, To: areas.Shikoku, HVDC: true,
+		aliases: []string{"関西四国間連系設備"}},
+	{ID: "chugoku-shikoku", NameJA: "中国四国間", NameEN: "Chugoku-Shikoku", From: areas.Chugoku, To: areas.Shikoku},
+	{ID: "chugoku-kyushu", NameJA: "中国九州間", NameEN: "Chugoku-Kyushu", From: areas.Chugoku, To: areas.Kyushu},
+}
+
+// Lines returns every interconnector in OCCTO order.
+func Lines() []Line {
+	out := make([]Line, len(registry))
+	copy(out, registry)
+	return out
+}
+
+// ParseLine resolves a line ID, OCCTO name (with or without the 連系線
+// suffix) or alias.
+func ParseLine(s string) (Line, error) {
+	name := strings.TrimSpace(s)
+	name = strings.TrimSuffix(name, "連系線")
+	for _, l := range registry {
+		if strings.EqualFold(name, l.ID) || name == l.NameJA {
+			return l, nil
+		}
+		for _, alias := range l.aliases {
+			if name == alias {
+				return l, nil
+			}
+		}
+	}
+	return Line{}, fmt.Errorf("unknown interconnector %q", s)
+}
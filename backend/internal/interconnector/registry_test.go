@@ -0,0 +1,58 @@
+package interconnector
+
+import "testing"
+
+func TestParseLine(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"tohoku-tokyo", "tohoku-tokyo"},
+		{"東北東京間", "tohoku-tokyo"},
+		{"東北東京間連系線", "tohoku-tokyo"},
+		{"周波数変換設備", "tokyo-chubu"},
+		{"北海道本州間連系設備", "hokkaido-honshu"},
+		{" Chugoku-Kyushu ", "chugoku-kyushu"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseLine(tt.input)
+			if err != nil || got.ID != tt.want {
+				t.Errorf("ParseLine(%q) = %q, %v; want %q", tt.input, got.ID, err, tt.want)
+			}
+		})
+	}
+
+	if _, err := ParseLine("北海道東北間"); err == nil {
+		t.Error("ParseLine(北海道東北間) expected error, got nil")
+	}
+}
+
+func TestNewFlowPoint(t *testing.T) {
+	f := func(v float64) *float64 { return &v }
+
+	tests := []struct {
+		name      string
+		planned   *float64
+		actual    *float64
+		direction Direction
+		margin    float64
+		congested bool
+	}{
+		{"forward", f(1000), f(1200), DirectionForward, 800, false},
+		{"planned only", f(1970), nil, DirectionForward, 30, true},
+		{"reverse", f(-1000), f(-1480), DirectionReverse, 20, true},
+		{"just above threshold", nil, f(-1469), DirectionReverse, 31, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewFlowPoint("2025-10-24T00:00:00+09:00", tt.planned, tt.actual, 2000, 1500)
+			if p.Direction != tt.direction || p.MarginMW != tt.margin || p.Congested != tt.congested {
+				t.Errorf("NewFlowPoint() = %s %v MW congested=%v; want %s %v MW congested=%v",
+					p.Direction, p.MarginMW, p.Congested, tt.direction, tt.margin, tt.congested)
+			}
+		})
+	}
+}
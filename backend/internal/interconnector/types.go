@@ -0,0 +1,135 @@
+// Package interconnector provides types for OCCTO interconnector (連系線)
+// flows: planned and actual flow, operating capacity and remaining margin of
+// each inter-area line per 30-minute interval, with congested intervals
+// flagged. Congestion splits the JEPX market, so these flows explain most
+// inter-area price spreads.
+package interconnector
+
+import "github.com/teo/aversome/backend/pkg/timeutil"
+
+// Timescale30Min is the only resolution interconnector flows are published at.
+const Timescale30Min = timeutil.Timescale30Min
+
+// CongestionMarginPct is the remaining margin, as a percentage of the
+// operating capacity in the flow direction, at or below which an interval
+// counts as congested.
+const CongestionMarginPct = 2.0
+
+// Direction of a flow relative to the line's From → To orientation.
+type Direction string
+
+const (
+	DirectionForward Direction = "forward" // From → To (順方向)
+	DirectionReverse Direction = "reverse" // To → From (逆方向)
+)
+
+// FlowPoint is one line's flow in one 30-minute interval. Flows are signed:
+// positive From → To, negative To → From. Capacities are positive in both
+// directions.
+type FlowPoint struct {
+	Timestamp         string    `json:"ts"`                   // Interval start, ISO8601 with Asia/Tokyo offset
+	PlannedMW         *float64  `json:"planned_mw,omitempty"` // 計画潮流
+	ActualMW          *float64  `json:"actual_mw,omitempty"`  // 潮流実績 (absent until published)
+	CapacityForwardMW float64   `json:"capacity_forward_mw"`  // 運用容量 (順方向)
+	CapacityReverseMW float64   `json:"capacity_reverse_mw"`  // 運用容量 (逆方向)
+	Direction         Direction `json:"direction"`            // Of the actual flow, else the planned flow
+	MarginMW          float64   `json:"margin_mw"`            // Capacity in Direction minus |flow|
+	UtilizationPct    float64   `json:"utilization_pct"`      // |flow| / capacity in Direction * 100
+	Congested         bool      `json:"congested"`            // Margin <= CongestionMarginPct of the capacity
+}
+
+// NewFlowPoint derives direction, margin, utilization and the congestion
+// flag from the flows and capacities. The actual flow is used when present.
+func NewFlowPoint(ts string, planned, actual *float64, capForward, capReverse float64) FlowPoint {
+	p := FlowPoint{
+		Timestamp:         ts,
+		PlannedMW:         planned,
+		ActualMW:          actual,
+		CapacityForwardMW: capForward,
+		CapacityReverseMW: capReverse,
+		Direction:         DirectionForward,
+	}
+
+	flow := 0.0
+	switch {
+	case actual != nil:
+		flow = *actual
+	case planned != nil:
+		flow = *planned
+	}
+
+	capacity := capForward
+	if flow < 0 {
+		p.Direction, capacity, flow = DirectionReverse, capReverse, -flow
+	}
+	p.MarginMW = capacity - flow
+	if capacity > 0 {
+		p.UtilizationPct = flow / capacity * 100
+	}
+	p.Congested = p.MarginMW <= capacity*CongestionMarginPct/100
+	return p
+}
+
+// LineFlows is the day of one interconnector.
+type LineFlows struct {
+	Line               string      `json:"line"`                // Line ID (e.g., "tohoku-tokyo")
+	Name               string      `json:"name"`                // OCCTO name (e.g., "東北東京間")
+	From               string      `json:"from"`                // Area code at the forward end
+	To                 string      `json:"to"`                  // Area code at the reverse end
+	Series             []FlowPoint `json:"series"`              // 48 intervals
+	CongestedIntervals int         `json:"congested_intervals"` // Number of congested intervals
+	MaxUtilizationPct  float64     `json:"max_utilization_pct"`
+}
+
+// Summarize sets CongestedIntervals and MaxUtilizationPct from the series.
+func (l *LineFlows) Summarize() {
+	l.CongestedIntervals, l.MaxUtilizationPct = 0, 0
+	for _, p := range l.Series {
+		if p.Congested {
+			l.CongestedIntervals++
+		}
+		if p.UtilizationPct > l.MaxUtilizationPct {
+			l.MaxUtilizationPct = p.UtilizationPct
+		}
+	}
+}
+
+// Source contains attribution for the data source.
+type Source struct {
+	Name string `json:"name"` // e.g., "OCCTO"
+	URL  string `json:"url"`  // Original data source URL
+}
+
+// Meta contains optional metadata and warnings.
+type Meta struct {
+	Warning string `json:"warning,omitempty"` // Non-blocking warning message
+}
+
+// Response holds every interconnector's flows of one day.
+// GET /api/interconnectors/{date}
+type Response struct {
+	Date      string      `json:"date"`           // YYYY-MM-DD format
+	Timescale string      `json:"timescale"`      // Always "30min"
+	Lines     []LineFlows `json:"lines"`          // Registry order
+	Source    Source      `json:"source"`         // Data attribution
+	Meta      *Meta       `json:"meta,omitempty"` // Optional metadata/warnings
+}
+
+// NewResponse creates a properly initialized Response with defaults.
+func NewResponse(date string) *Response {
+	return &Response{
+		Date:      date,
+		Timescale: Timescale30Min,
+		Lines:     make([]LineFlows, 0, len(registry)),
+	}
+}
+
+// Line returns the flows of one line, or nil.
+func (r *Response) Line(id string) *LineFlows {
+	for i := range r.Lines {
+		if r.Lines[i].Line == id {
+			return &r.Lines[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+package jepx
+
+import (
+	"fmt"
+
+	"github.com/teo/aversome/backend/pkg/timeutil"
+)
+
+// IntradayPoint is the result of one 30-minute product in the JEPX intraday
+// market (時間前市場). Prices are nil for products without trades.
+type IntradayPoint struct {
+	Timestamp   string   `json:"ts"`           // Product start, ISO8601 with Asia/Tokyo offset
+	Period      int      `json:"period"`       // JEPX コマ number (1-48)
+	Open        *float64 `json:"open"`         // JPY/kWh (始値)
+	High        *float64 `json:"high"`         // JPY/kWh (高値)
+	Low         *float64 `json:"low"`          // JPY/kWh (安値)
+	Close       *float64 `json:"close"`        // JPY/kWh (終値)
+	WeightedAvg *float64 `json:"weighted_avg"` // Volume-weighted average JPY/kWh (平均)
+	VolumeMWh   float64  `json:"volume_mwh"`   // Contracted volume (約定量合計, MWh/h as published)
+	Trades      int      `json:"trades"`       // Number of contracts (約定件数)
+}
+
+// IntradayResponse is one delivery day of JEPX intraday results.
+// The intraday market is continuous and nationwide, so there is no area.
+// GET /api/intraday/{date}
+type IntradayResponse struct {
+	Date      string          `json:"date"`           // Delivery date, YYYY-MM-DD
+	Timescale string          `json:"timescale"`      // Always "30min"
+	Products  []IntradayPoint `json:"products"`       // 48 products
+	Source    Source          `json:"source"`         // Data attribution
+	Meta      *Meta           `json:"meta,omitempty"` // Optional metadata/warnings
+}
+
+// NewIntradayResponse creates an empty IntradayResponse.
+func NewIntradayResponse(date string) *IntradayResponse {
+	return &IntradayResponse{
+		Date:      date,
+		Timescale: Timescale30Min,
+		Products:  make([]IntradayPoint, 0, timeutil.SlotsPerDay),
+	}
+}
+
+// ComparisonPoint compares one intraday product with the day-ahead price of
+// the same period.
+type ComparisonPoint struct {
+	Timestamp     string   `json:"ts"`
+	Period        int      `json:"period"`
+	DayAheadPrice float64  `json:"day_ahead_price"`   // JPY/kWh
+	IntradayAvg   *float64 `json:"intraday_avg"`      // Weighted average, nil if untraded
+	IntradayClose *float64 `json:"intraday_close"`    // Last trade, nil if untraded
+	Premium       *float64 `json:"premium,omitempty"` // IntradayAvg − DayAheadPrice
+	VolumeMWh     float64  `json:"volume_mwh"`
+}
+
+// ComparisonMeta aggregates a comparison over the traded products.
+type ComparisonMeta struct {
+	TradedProducts    int     `json:"traded_products"`
+	TotalVolumeMWh    float64 `json:"total_volume_mwh"`
+	AvgPremium        float64 `json:"avg_premium"`         // Simple mean over traded products
+	VolumeWeightedAvg float64 `json:"volume_weighted_avg"` // Intraday price weighted by volume
+	VolumeWeightedDA  float64 `json:"volume_weighted_da"`  // Day-ahead price weighted by intraday volume
+}
+
+// Comparison is intraday against day-ahead for one area (or the system price).
+// GET /api/intraday/{date}/compare?area=tokyo
+type Comparison struct {
+	Date    string            `json:"date"`
+	Area    string            `json:"area"` // Day-ahead price area, or "system"
+	Periods []ComparisonPoint `json:"periods"`
+	Meta    ComparisonMeta    `json:"meta"`
+}
+
+// Compare joins intraday products with day-ahead prices by period. dayAhead
+// must be a 30min response of the same date (see MarketResponse.AreaResponse).
+func Compare(intraday *IntradayResponse, dayAhead *Response) (*Comparison, error) {
+	if intraday.Date != dayAhead.Date {
+		return nil, fmt.Errorf("intraday date %s differs from day-ahead date %s", intraday.Date, dayAhead.Date)
+	}
+	if dayAhead.Timescale != Timescale30Min {
+		return nil, fmt.Errorf("day-ahead prices must be %s, got %s", Timescale30Min, dayAhead.Timescale)
+	}
+
+	daByPeriod := make(map[int]float64, len(dayAhead.PriceYenPerKwh))
+	for _, p := range dayAhead.PriceYenPerKwh {
+		daByPeriod[p.Period] = p.Price
+	}
+
+	out := &Comparison{
+		Date:    intraday.Date,
+		Area:    dayAhead.Area,
+		Periods: make([]ComparisonPoint, 0, len(intraday.Products)),
+	}
+
+	var premiumSum, weightedID, weightedDA float64
+	for _, p := range intraday.Products {
+		da, ok := daByPeriod[p.Period]
+		if !ok {
+			return nil, fmt.Errorf("no day-ahead price for period %d", p.Period)
+		}
+
+		point := ComparisonPoint{
+			Timestamp:     p.Timestamp,
+			Period:        p.Period,
+			DayAheadPrice: da,
+			IntradayAvg:   p.WeightedAvg,
+			IntradayClose: p.Close,
+			VolumeMWh:     p.VolumeMWh,
+		}
+		if p.WeightedAvg != nil {
+			premium := *p.WeightedAvg - da
+			point.Premium = &premium
+
+			out.Meta.TradedProducts++
+			out.Meta.TotalVolumeMWh += p.VolumeMWh
+			premiumSum += premium
+			weightedID += *p.WeightedAvg * p.VolumeMWh
+			weightedDA += da * p.VolumeMWh
+		}
+		out.Periods = append(out.Periods, point)
+	}
+
+	if out.Meta.TradedProducts > 0 {
+		out.Meta.AvgPremium = premiumSum / float64(out.Meta.TradedProducts)
+	}
+	if out.Meta.TotalVolumeMWh > 0 {
+		out.Meta.VolumeWeightedAvg = weightedID / out.Meta.TotalVolumeMWh
+		out.Meta.VolumeWeightedDA = weightedDA / out.Meta.TotalVolumeMWh
+	}
+
+	return out, nil
+}
@@ -0,0 +1,196 @@
+package jepx
+
+import (
+	"fmt"
+
+	"github.com/teo/aversome/backend/internal/series"
+)
+
+// Volume is the auction volume of one period. JEPX publishes bid and
+// contracted volumes for the whole market, not per area, so every area
+// response of a period carries the same Volume.
+type Volume struct {
+	SellBidMWh    float64 `json:"sell_bid_mwh"`   // 売り入札量
+	BuyBidMWh     float64 `json:"buy_bid_mwh"`    // 買い入札量
+	ContractedMWh float64 `json:"contracted_mwh"` // 約定総量
+}
+
+// add returns the sum of two optional volumes (nil if both are nil).
+func (v *Volume) add(o *Volume) *Volume {
+	if v == nil && o == nil {
+		return nil
+	}
+	sum := Volume{}
+	for _, x := range []*Volume{v, o} {
+		if x != nil {
+			sum.SellBidMWh += x.SellBidMWh
+			sum.BuyBidMWh += x.BuyBidMWh
+			sum.ContractedMWh += x.ContractedMWh
+		}
+	}
+	return &sum
+}
+
+// BidImbalance is (buy − sell) / (buy + sell): positive when buyers bid more
+// volume than sellers offer, in [-1, 1]. Zero when nothing was bid.
+func (v Volume) BidImbalance() float64 {
+	total := v.BuyBidMWh + v.SellBidMWh
+	if total == 0 {
+		return 0
+	}
+	return (v.BuyBidMWh - v.SellBidMWh) / total
+}
+
+// LiquidityPoint is the price and volume of one period with derived liquidity metrics.
+type LiquidityPoint struct {
+	Timestamp     string  `json:"ts"`
+	Period        int     `json:"period,omitempty"`
+	Price         float64 `json:"price"` // JPY/kWh
+	Volume                // Sell/buy bid and contracted MWh
+	BidImbalance  float64 `json:"bid_imbalance"`   // (buy − sell) / (buy + sell)
+	SellFilledPct float64 `json:"sell_filled_pct"` // Contracted share of sell bids, %
+	BuyFilledPct  float64 `json:"buy_filled_pct"`  // Contracted share of buy bids, %
+}
+
+// LiquidityStats aggregates the liquidity of a day (or range of days).
+type LiquidityStats struct {
+	Date          string          `json:"date,omitempty"` // Set for daily stats
+	Periods       int             `json:"periods"`        // Periods with volume data
+	ContractedMWh float64         `json:"contracted_mwh"`
+	SellBidMWh    float64         `json:"sell_bid_mwh"`
+	BuyBidMWh     float64         `json:"buy_bid_mwh"`
+	BidImbalance  float64         `json:"bid_imbalance"`            // Of the summed bids
+	AvgPrice      float64         `json:"avg_price"`                // Simple mean, JPY/kWh
+	VWAP          float64         `json:"vwap"`                     // Price weighted by contracted volume, JPY/kWh
+	MinContracted *LiquidityPoint `json:"min_contracted,omitempty"` // Thinnest period
+	MaxContracted *LiquidityPoint `json:"max_contracted,omitempty"` // Deepest period
+}
+
+// Liquidity is the per-period and daily liquidity of one area's spot prices.
+// GET /api/liquidity/{area}/{date}
+type Liquidity struct {
+	Date      string           `json:"date"`
+	Area      string           `json:"area"` // Price area of the VWAP, or "system"
+	Timescale string           `json:"timescale"`
+	Periods   []LiquidityPoint `json:"periods"`
+	Day       LiquidityStats   `json:"day"`
+	Meta      *Meta            `json:"meta,omitempty"`
+}
+
+// Liquidity derives liquidity metrics from the volumes of r. Periods without
+// volume are left out with a warning; a response without any volume is an error.
+func (r *Response) Liquidity() (*Liquidity, error) {
+	out := &Liquidity{
+		Date:      r.Date,
+		Area:      r.Area,
+		Timescale: r.Timescale,
+		Periods:   make([]LiquidityPoint, 0, len(r.PriceYenPerKwh)),
+	}
+
+	for _, p := range r.PriceYenPerKwh {
+		if p.Volume == nil {
+			continue
+		}
+		point := LiquidityPoint{
+			Timestamp:    p.Timestamp,
+			Period:       p.Period,
+			Price:        p.Price,
+			Volume:       *p.Volume,
+			BidImbalance: p.Volume.BidImbalance(),
+		}
+		if p.Volume.SellBidMWh > 0 {
+			point.SellFilledPct = p.Volume.ContractedMWh / p.Volume.SellBidMWh * 100
+		}
+		if p.Volume.BuyBidMWh > 0 {
+			point.BuyFilledPct = p.Volume.ContractedMWh / p.Volume.BuyBidMWh * 100
+		}
+		out.Periods = append(out.Periods, point)
+	}
+
+	if len(out.Periods) == 0 {
+		return nil, fmt.Errorf("no volume data in JEPX %s prices for %s", r.Area, r.Date)
+	}
+	if missing := len(r.PriceYenPerKwh) - len(out.Periods); missing > 0 {
+		out.Meta = &Meta{Warning: fmt.Sprintf("%d periods without volume data", missing)}
+	}
+
+	out.Day = SummarizeLiquidity(out.Periods)
+	out.Day.Date = r.Date
+
+	return out, nil
+}
+
+// SummarizeLiquidity aggregates liquidity points (of one day or several).
+func SummarizeLiquidity(points []LiquidityPoint) LiquidityStats {
+	var stats LiquidityStats
+	var priceSum, weighted float64
+
+	for i := range points {
+		p := &points[i]
+		stats.Periods++
+		stats.ContractedMWh += p.ContractedMWh
+		stats.SellBidMWh += p.SellBidMWh
+		stats.BuyBidMWh += p.BuyBidMWh
+		priceSum += p.Price
+		weighted += p.Price * p.ContractedMWh
+
+		if stats.MinContracted == nil || p.ContractedMWh < stats.MinContracted.ContractedMWh {
+			stats.MinContracted = p
+		}
+		if stats.MaxContracted == nil || p.ContractedMWh > stats.MaxContracted.ContractedMWh {
+			stats.MaxContracted = p
+		}
+	}
+	if stats.Periods == 0 {
+		return stats
+	}
+
+	stats.AvgPrice = priceSum / float64(stats.Periods)
+	if stats.ContractedMWh > 0 {
+		stats.VWAP = weighted / stats.ContractedMWh
+	}
+	stats.BidImbalance = Volume{SellBidMWh: stats.SellBidMWh, BuyBidMWh: stats.BuyBidMWh}.BidImbalance()
+
+	return stats
+}
+
+// LiquidityRange is the daily liquidity of one area over a date range.
+// GET /api/liquidity/{area}?from=YYYY-MM-DD&to=YYYY-MM-DD
+type LiquidityRange struct {
+	Area     string           `json:"area"`
+	From     string           `json:"from"`
+	To       string           `json:"to"`
+	Days     []LiquidityStats `json:"days"`               // One entry per stored day with volume data
+	Total    LiquidityStats   `json:"total"`              // Over every period of Days
+	Gaps     []series.Gap     `json:"gaps"`               // Days with no stored data
+	Warnings []string         `json:"warnings,omitempty"` // Days without volume data, partial days
+}
+
+// NewLiquidityRange summarizes the liquidity of daily responses (ascending,
+// one per stored day). Days without volume data are reported as warnings.
+func NewLiquidityRange(area, from, to string, days []*Response, gaps []series.Gap) *LiquidityRange {
+	out := &LiquidityRange{
+		Area: area,
+		From: from,
+		To:   to,
+		Days: make([]LiquidityStats, 0, len(days)),
+		Gaps: gaps,
+	}
+
+	var all []LiquidityPoint
+	for _, d := range days {
+		liq, err := d.Liquidity()
+		if err != nil {
+			out.Warnings = append(out.Warnings, fmt.Sprintf("%s: %v", d.Date, err))
+			continue
+		}
+		if liq.Meta != nil && liq.Meta.Warning != "" {
+			out.Warnings = append(out.Warnings, fmt.Sprintf("%s: %s", d.Date, liq.Meta.Warning))
+		}
+		out.Days = append(out.Days, liq.Day)
+		all = append(all, liq.Periods...)
+	}
+	out.Total = SummarizeLiquidity(all)
+
+	return out
+}
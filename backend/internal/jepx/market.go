@@ -0,0 +1,139 @@
+package jepx
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/teo/aversome/backend/pkg/timeutil"
+)
+
+// SystemArea is the Area of a Response holding the system price.
+const SystemArea = "system"
+
+// MarketPoint is one period of the day-ahead auction: the system price and
+// the price of every area.
+type MarketPoint struct {
+	Timestamp   string             `json:"ts"`               // ISO8601 with Asia/Tokyo offset
+	Period      int                `json:"period,omitempty"` // JEPX コマ number (1-48), set for 30min data
+	SystemPrice float64            `json:"system_price"`     // JPY/kWh
+	AreaPrices  map[string]float64 `json:"area_prices"`      // JPY/kWh by area code
+	Volume      *Volume            `json:"volume,omitempty"` // Market-wide auction volume, when the source publishes it
+}
+
+// MarketResponse carries the system price and every area price of one day,
+// so area-to-system spreads and market splitting can be computed from one artifact.
+// GET /api/jepx/all/{date}
+type MarketResponse struct {
+	Date      string        `json:"date"`           // YYYY-MM-DD format
+	Timescale string        `json:"timescale"`      // "30min" (native) or "hourly"
+	Areas     []string      `json:"areas"`          // Area codes in AreaPrices, in registry order
+	Series    []MarketPoint `json:"series"`         // 48 (30min) or 24 (hourly) periods
+	Source    Source        `json:"source"`         // Data attribution
+	Meta      *Meta         `json:"meta,omitempty"` // Optional metadata/warnings
+}
+
+// NewMarketResponse creates an empty MarketResponse for the given areas.
+func NewMarketResponse(date, timescale string, areas []string) *MarketResponse {
+	return &MarketResponse{
+		Date:      date,
+		Timescale: timescale,
+		Areas:     areas,
+		Series:    make([]MarketPoint, 0, timeutil.PointsPerDay(timescale)),
+	}
+}
+
+// AreaResponse extracts the spot price response of one area
+// (SystemArea for the system price).
+func (r *MarketResponse) AreaResponse(area string) (*Response, error) {
+	if area != SystemArea && !r.hasArea(area) {
+		return nil, fmt.Errorf("no %s prices in JEPX market data for %s", area, r.Date)
+	}
+
+	out := NewResponseWithTimescale(r.Date, area, r.Timescale)
+	out.Source = r.Source
+	if r.Meta != nil {
+		meta := *r.Meta
+		out.Meta = &meta
+	}
+
+	for _, p := range r.Series {
+		price := p.SystemPrice
+		if area != SystemArea {
+			price = p.AreaPrices[area]
+		}
+		out.PriceYenPerKwh = append(out.PriceYenPerKwh, PricePoint{
+			Timestamp: p.Timestamp,
+			Period:    p.Period,
+			Price:     price,
+			Volume:    p.Volume,
+		})
+	}
+
+	return out, nil
+}
+
+// Resample returns the response at the requested timescale. Like
+// Response.Resample, 30min prices are averaged into hourly prices, volumes
+// are summed and hourly data cannot be upsampled. The receiver is not modified.
+func (r *MarketResponse) Resample(timescale string) (*MarketResponse, error) {
+	if r.Timescale == timescale {
+		return r, nil
+	}
+	if r.Timescale != Timescale30Min || timescale != TimescaleHourly {
+		return nil, fmt.Errorf("cannot resample JEPX market data from %s to %s", r.Timescale, timescale)
+	}
+
+	out := *r
+	out.Timescale = TimescaleHourly
+	out.Series = make([]MarketPoint, 0, 24)
+
+	var bucket time.Time
+	var sum MarketPoint
+	var count int
+	flush := func() {
+		if count == 0 {
+			return
+		}
+		point := MarketPoint{
+			Timestamp:   bucket.Format(time.RFC3339),
+			SystemPrice: sum.SystemPrice / float64(count),
+			AreaPrices:  make(map[string]float64, len(sum.AreaPrices)),
+			Volume:      sum.Volume,
+		}
+		for area, total := range sum.AreaPrices {
+			point.AreaPrices[area] = total / float64(count)
+		}
+		out.Series = append(out.Series, point)
+	}
+
+	for _, p := range r.Series {
+		ts, err := time.Parse(time.RFC3339, p.Timestamp)
+		if err != nil {
+			return nil, fmt.Errorf("invalid timestamp %q: %w", p.Timestamp, err)
+		}
+		hourStart := ts.In(timeutil.TokyoLocation).Truncate(time.Hour)
+		if !hourStart.Equal(bucket) {
+			flush()
+			bucket, count = hourStart, 0
+			sum = MarketPoint{AreaPrices: make(map[string]float64, len(p.AreaPrices))}
+		}
+		sum.SystemPrice += p.SystemPrice
+		sum.Volume = sum.Volume.add(p.Volume)
+		for area, price := range p.AreaPrices {
+			sum.AreaPrices[area] += price
+		}
+		count++
+	}
+	flush()
+
+	return &out, nil
+}
+
+func (r *MarketResponse) hasArea(area string) bool {
+	for _, a := range r.Areas {
+		if a == area {
+			return true
+		}
+	}
+	return false
+}
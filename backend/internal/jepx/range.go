@@ -0,0 +1,67 @@
+package jepx
+
+import (
+	"fmt"
+
+	"github.com/teo/aversome/backend/internal/series"
+)
+
+// RangeResponse is a multi-day spot price series.
+// GET /api/jepx/{area}?from=YYYY-MM-DD&to=YYYY-MM-DD
+type RangeResponse struct {
+	Area           string       `json:"area"`
+	From           string       `json:"from"`               // First requested date
+	To             string       `json:"to"`                 // Last requested date
+	Timescale      string       `json:"timescale"`          // Common resolution of the series
+	PriceYenPerKwh []PricePoint `json:"price_yen_per_kwh"`  // Concatenated prices of every stored day
+	Gaps           []series.Gap `json:"gaps"`               // Days with no stored data
+	Sources        []Source     `json:"sources"`            // Distinct sources in the range
+	Warnings       []string     `json:"warnings,omitempty"` // Per-day warnings, prefixed with the date
+}
+
+// NewRangeResponse concatenates daily responses (ascending, one per stored
+// day) into a range series. Days are resampled to a common timescale: the
+// requested one, or hourly if the days disagree.
+func NewRangeResponse(area, from, to string, days []*Response, gaps []series.Gap, timescale string) (*RangeResponse, error) {
+	timescales := make([]string, len(days))
+	for i, d := range days {
+		timescales[i] = d.Timescale
+	}
+	target := series.CommonTimescale(timescale, timescales)
+
+	out := &RangeResponse{
+		Area:           area,
+		From:           from,
+		To:             to,
+		Timescale:      target,
+		PriceYenPerKwh: make([]PricePoint, 0, len(days)*48),
+		Gaps:           gaps,
+		Sources:        make([]Source, 0, 1),
+	}
+
+	for _, d := range days {
+		day, err := d.Resample(target)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", d.Date, err)
+		}
+		out.PriceYenPerKwh = append(out.PriceYenPerKwh, day.PriceYenPerKwh...)
+
+		if !containsSource(out.Sources, day.Source) {
+			out.Sources = append(out.Sources, day.Source)
+		}
+		if day.Meta != nil && day.Meta.Warning != "" {
+			out.Warnings = append(out.Warnings, fmt.Sprintf("%s: %s", day.Date, day.Meta.Warning))
+		}
+	}
+
+	return out, nil
+}
+
+func containsSource(sources []Source, s Source) bool {
+	for _, existing := range sources {
+		if existing == s {
+			return true
+		}
+	}
+	return false
+}
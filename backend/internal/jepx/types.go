@@ -2,10 +2,25 @@
 // Follows AGENT_TECH_SPEC.md §3.3 API contract.
 package jepx
 
-// PricePoint represents a single hourly spot price.
+import (
+	"fmt"
+	"time"
+
+	"github.com/teo/aversome/backend/pkg/timeutil"
+)
+
+// Timescale values for Response.Timescale.
+const (
+	TimescaleHourly = timeutil.TimescaleHourly
+	Timescale30Min  = timeutil.Timescale30Min // Native JEPX resolution (48 コマ)
+)
+
+// PricePoint represents a single spot price for one period.
 type PricePoint struct {
-	Timestamp string  `json:"ts"`    // ISO8601 with Asia/Tokyo offset (e.g., "2025-10-23T00:00:00+09:00")
-	Price     float64 `json:"price"` // JPY/kWh
+	Timestamp string  `json:"ts"`               // ISO8601 with Asia/Tokyo offset (e.g., "2025-10-23T00:00:00+09:00")
+	Period    int     `json:"period,omitempty"` // JEPX コマ number (1-48), set for 30min data
+	Price     float64 `json:"price"`            // JPY/kWh
+	Volume    *Volume `json:"volume,omitempty"` // Market-wide auction volume, when the source publishes it
 }
 
 // Source contains attribution for the data source.
@@ -24,18 +39,72 @@ type Meta struct {
 type Response struct {
 	Date             string       `json:"date"`               // YYYY-MM-DD format
 	Area             string       `json:"area"`               // e.g., "tokyo", "kansai"
-	Timescale        string       `json:"timescale"`          // Always "hourly"
-	PriceYenPerKwh   []PricePoint `json:"price_yen_per_kwh"`  // 24 hourly price points
+	Timescale        string       `json:"timescale"`          // "30min" (native) or "hourly"
+	PriceYenPerKwh   []PricePoint `json:"price_yen_per_kwh"`  // 48 (30min) or 24 (hourly) price points
 	Source           Source       `json:"source"`             // Data attribution
 	Meta             *Meta        `json:"meta,omitempty"`     // Optional metadata/warnings
 }
 
 // NewResponse creates a properly initialized Response with defaults.
 func NewResponse(date, area string) *Response {
+	return NewResponseWithTimescale(date, area, TimescaleHourly)
+}
+
+// NewResponseWithTimescale creates a Response at the given resolution.
+func NewResponseWithTimescale(date, area, timescale string) *Response {
 	return &Response{
 		Date:           date,
 		Area:           area,
-		Timescale:      "hourly",
-		PriceYenPerKwh: make([]PricePoint, 0, 24), // Pre-allocate for 24 hours
+		Timescale:      timescale,
+		PriceYenPerKwh: make([]PricePoint, 0, timeutil.PointsPerDay(timescale)),
+	}
+}
+
+// Resample returns the response at the requested timescale.
+// 30min prices are averaged into hourly prices (simple mean of the two コマ)
+// and volumes are summed;
+// hourly data cannot be upsampled and returns an error. The receiver is not modified.
+func (r *Response) Resample(timescale string) (*Response, error) {
+	if r.Timescale == timescale {
+		return r, nil
 	}
+	if r.Timescale != Timescale30Min || timescale != TimescaleHourly {
+		return nil, fmt.Errorf("cannot resample JEPX prices from %s to %s", r.Timescale, timescale)
+	}
+
+	out := *r
+	out.Timescale = TimescaleHourly
+	out.PriceYenPerKwh = make([]PricePoint, 0, 24)
+
+	var bucket time.Time
+	var sum float64
+	var volume *Volume
+	var count int
+	flush := func() {
+		if count > 0 {
+			out.PriceYenPerKwh = append(out.PriceYenPerKwh, PricePoint{
+				Timestamp: bucket.Format(time.RFC3339),
+				Price:     sum / float64(count),
+				Volume:    volume,
+			})
+		}
+	}
+
+	for _, p := range r.PriceYenPerKwh {
+		ts, err := time.Parse(time.RFC3339, p.Timestamp)
+		if err != nil {
+			return nil, fmt.Errorf("invalid timestamp %q: %w", p.Timestamp, err)
+		}
+		hourStart := ts.In(timeutil.TokyoLocation).Truncate(time.Hour)
+		if !hourStart.Equal(bucket) {
+			flush()
+			bucket, sum, volume, count = hourStart, 0, nil, 0
+		}
+		sum += p.Price
+		volume = volume.add(p.Volume)
+		count++
+	}
+	flush()
+
+	return &out, nil
 }
@@ -0,0 +1,145 @@
+package marginal
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/teo/aversome/backend/internal/emission"
+	"github.com/teo/aversome/backend/internal/generation"
+	"github.com/teo/aversome/backend/pkg/timeutil"
+)
+
+// hourSums accumulates the regression sums of one hour of day.
+type hourSums struct {
+	n      int
+	sxx    float64                   // Σ Δsupply²
+	sxy    float64                   // Σ Δsupply × Δemissions
+	syy    float64                   // Σ Δemissions²
+	shares map[emission.Fuel]float64 // Σ Δsupply × Δfuel
+}
+
+// Estimate derives the marginal emission factor of every hour of day from
+// the generation mixes of the window ending on date (ascending, one per
+// stored day, all of area). Emissions use factors for the area. Pairs never
+// span two days; hours with fewer than MinSamples pairs are not estimated.
+// Days with an estimated mix are counted in EstimatedDays and warned about:
+// their fuels scale with demand, so the factors only restate the estimator.
+func Estimate(area, date string, windowDays int, days []*generation.Response, factors *emission.FactorSet) (*Response, error) {
+	end, err := timeutil.ParseDate(date)
+	if err != nil {
+		return nil, err
+	}
+	if windowDays < 1 {
+		return nil, fmt.Errorf("window must be at least 1 day, got %d", windowDays)
+	}
+	if len(days) == 0 {
+		return nil, errors.New("no generation mix in the window")
+	}
+
+	estimated := 0
+	sums := make([]hourSums, 24)
+	for h := range sums {
+		sums[h].shares = make(map[emission.Fuel]float64, len(emission.Fuels))
+	}
+
+	for _, d := range days {
+		if d.Area != area {
+			return nil, fmt.Errorf("%s: generation mix of %s, want %s", d.Date, d.Area, area)
+		}
+		if d.Source.Name == generation.EstimatedSourceName {
+			estimated++
+		}
+		for i := 1; i < len(d.Series); i++ {
+			prev, cur := d.Series[i-1], d.Series[i]
+			dx := cur.TotalMW - prev.TotalMW
+			dy := cur.EmissionsTCO2PerHour(factors, area) - prev.EmissionsTCO2PerHour(factors, area)
+
+			s := &sums[cur.Timestamp.In(timeutil.TokyoLocation).Hour()]
+			s.n++
+			s.sxx += dx * dx
+			s.sxy += dx * dy
+			s.syy += dy * dy
+			for _, fuel := range emission.Fuels {
+				s.shares[fuel] += dx * (cur.FuelMW(fuel) - prev.FuelMW(fuel))
+			}
+		}
+	}
+
+	resp := &Response{
+		Date:              date,
+		Area:              area,
+		Timezone:          "Asia/Tokyo",
+		WindowFrom:        timeutil.FormatDate(end.AddDate(0, 0, -(windowDays - 1))),
+		WindowDays:        windowDays,
+		DaysUsed:          len(days),
+		EstimatedDays:     estimated,
+		Confidence:        Confidence,
+		EmissionFactorSet: factors.ID,
+		Basis:             factors.Basis,
+		Hours:             make([]HourEstimate, 24),
+	}
+
+	var missing, warnings []string
+	for h, s := range sums {
+		resp.Hours[h] = s.estimate(h)
+		if resp.Hours[h].FactorGCO2KWh == nil {
+			missing = append(missing, fmt.Sprintf("%02d", h))
+		}
+	}
+	if estimated > 0 {
+		warnings = append(warnings, fmt.Sprintf("%d of %d days use an estimated generation mix, not OCCTO actuals; their factors follow the estimator", estimated, len(days)))
+	}
+	if len(missing) > 0 {
+		warnings = append(warnings, "Too few supply changes to estimate hours: "+strings.Join(missing, ", "))
+	}
+	if len(warnings) > 0 {
+		resp.Meta = &Meta{Warning: strings.Join(warnings, "; ")}
+	}
+
+	return resp, nil
+}
+
+// estimate fits Δemissions = b × Δsupply through the origin. b (t/MWh) is
+// the marginal factor; its bounds use the slope's standard error.
+func (s hourSums) estimate(hour int) HourEstimate {
+	out := HourEstimate{Hour: hour, Samples: s.n}
+	if s.n < MinSamples || s.sxx == 0 {
+		return out
+	}
+
+	b := s.sxy / s.sxx
+	rss := math.Max(s.syy-b*s.sxy, 0) // Σ(Δy - bΔx)²
+	se := math.Sqrt(rss / float64(s.n-1) / s.sxx)
+	margin := tQuantile(s.n-1) * se
+
+	// t/MWh = kg/kWh, x1000 for g/kWh
+	factor, lower, upper := b*1000, (b-margin)*1000, (b+margin)*1000
+	out.FactorGCO2KWh, out.LowerGCO2KWh, out.UpperGCO2KWh = &factor, &lower, &upper
+
+	out.FuelShares = make(map[emission.Fuel]float64)
+	for fuel, sum := range s.shares {
+		if share := sum / s.sxx; share != 0 {
+			out.FuelShares[fuel] = share
+		}
+	}
+	return out
+}
+
+// tQuantile returns the two-sided 95% quantile of Student's t distribution
+// with df degrees of freedom: tabulated up to 30, the Cornish-Fisher
+// expansion of the normal quantile above (error below 0.05%).
+func tQuantile(df int) float64 {
+	table := []float64{
+		12.706, 4.303, 3.182, 2.776, 2.571, 2.447, 2.365, 2.306, 2.262, 2.228,
+		2.201, 2.179, 2.160, 2.145, 2.131, 2.120, 2.110, 2.101, 2.093, 2.086,
+		2.080, 2.074, 2.069, 2.064, 2.060, 2.056, 2.052, 2.048, 2.045, 2.042,
+	}
+	if df <= len(table) {
+		return table[df-1]
+	}
+	const z = 1.959964
+	n := float64(df)
+	return z + (z*z*z+z)/(4*n) + (5*math.Pow(z, 5)+16*z*z*z+3*z)/(96*n*n)
+}
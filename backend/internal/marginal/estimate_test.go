@@ -0,0 +1,182 @@
+package marginal
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/teo/aversome/backend/internal/emission"
+	"github.com/teo/aversome/backend/internal/generation"
+	"github.com/teo/aversome/backend/pkg/timeutil"
+)
+
+// testDay builds a 30min generation mix whose supply changes every interval
+// by the LNG and coal ramps returned for the slot.
+func testDay(t *testing.T, date string, ramp func(slot int) (lng, coal float64)) *generation.Response {
+	t.Helper()
+	day, err := timeutil.ParseDate(date)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp := generation.NewResponseWithTimescale("kyushu", date, generation.Timescale30Min)
+	lng, coal := 2000.0, 4000.0
+	for slot := 0; slot < timeutil.SlotsPerDay; slot++ {
+		dLNG, dCoal := ramp(slot)
+		lng, coal = lng+dLNG, coal+dCoal
+		p := generation.GenerationPoint{
+			Timestamp: timeutil.SlotTime(day, slot),
+			NuclearMW: 3000, SolarMW: 1000, LNGMW: lng, CoalMW: coal,
+		}
+		p.SumMW()
+		resp.Series = append(resp.Series, p)
+	}
+	return resp
+}
+
+// rampMW is a supply change of 100-600 MW, up in the morning and down in the evening.
+func rampMW(slot int) float64 {
+	mw := 100 + float64((slot*7)%11)*50
+	if slot >= timeutil.SlotsPerDay/2 {
+		return -mw
+	}
+	return mw
+}
+
+func TestEstimate_SingleFuel(t *testing.T) {
+	// Every supply change is met by LNG
+	lngOnly := func(slot int) (float64, float64) { return rampMW(slot), 0 }
+	days := []*generation.Response{
+		testDay(t, "2025-10-22", lngOnly),
+		testDay(t, "2025-10-23", lngOnly),
+		testDay(t, "2025-10-24", lngOnly),
+	}
+
+	resp, err := Estimate("kyushu", "2025-10-24", DefaultWindowDays, days, emission.DefaultSet())
+	if err != nil {
+		t.Fatalf("Estimate() error = %v", err)
+	}
+	if resp.WindowFrom != "2025-09-27" || resp.DaysUsed != 3 || resp.EmissionFactorSet != emission.DefaultSetID {
+		t.Errorf("window = %s, %d days, set %s, want 2025-09-27, 3 days, default", resp.WindowFrom, resp.DaysUsed, resp.EmissionFactorSet)
+	}
+	if len(resp.Hours) != 24 {
+		t.Fatalf("hours = %d, want 24", len(resp.Hours))
+	}
+
+	// Hour 0 only has the 00:00 → 00:30 pair of each day
+	if h := resp.Hours[0]; h.Samples != 3 || h.FactorGCO2KWh != nil {
+		t.Errorf("hour 0 = %+v, want 3 samples and no estimate", h)
+	}
+	if resp.Meta == nil || !strings.Contains(resp.Meta.Warning, "00") {
+		t.Errorf("Meta = %+v, want warning for hour 00", resp.Meta)
+	}
+
+	h := resp.Hours[12]
+	if h.Samples != 6 || h.FactorGCO2KWh == nil {
+		t.Fatalf("hour 12 = %+v, want 6 samples with an estimate", h)
+	}
+	if math.Abs(*h.FactorGCO2KWh-350) > 1e-6 || math.Abs(*h.LowerGCO2KWh-350) > 1e-3 || math.Abs(*h.UpperGCO2KWh-350) > 1e-3 {
+		t.Errorf("hour 12 factor = %v [%v, %v], want the LNG factor 350 with no spread", *h.FactorGCO2KWh, *h.LowerGCO2KWh, *h.UpperGCO2KWh)
+	}
+	if len(h.FuelShares) != 1 || math.Abs(h.FuelShares[emission.FuelLNG]-1) > 1e-9 {
+		t.Errorf("hour 12 shares = %v, want lng 1", h.FuelShares)
+	}
+}
+
+func TestEstimate_MixedFuels(t *testing.T) {
+	// Odd slots ramp LNG, even slots ramp coal
+	mixed := func(slot int) (float64, float64) {
+		if slot%2 == 1 {
+			return rampMW(slot), 0
+		}
+		return 0, rampMW(slot)
+	}
+	days := make([]*generation.Response, 0, 5)
+	for _, date := range []string{"2025-10-20", "2025-10-21", "2025-10-22", "2025-10-23", "2025-10-24"} {
+		days = append(days, testDay(t, date, mixed))
+	}
+
+	resp, err := Estimate("kyushu", "2025-10-24", 7, days, emission.DefaultSet())
+	if err != nil {
+		t.Fatalf("Estimate() error = %v", err)
+	}
+	if resp.WindowFrom != "2025-10-18" || resp.EstimatedDays != 0 || resp.Meta != nil {
+		t.Errorf("WindowFrom = %s, %d estimated days, Meta %+v; want 2025-10-18 and no warning", resp.WindowFrom, resp.EstimatedDays, resp.Meta)
+	}
+
+	for _, h := range resp.Hours[1:] {
+		if h.FactorGCO2KWh == nil {
+			t.Fatalf("hour %d not estimated (%d samples)", h.Hour, h.Samples)
+		}
+		f, lo, hi := *h.FactorGCO2KWh, *h.LowerGCO2KWh, *h.UpperGCO2KWh
+		if f <= 350 || f >= 850 || lo >= f || hi <= f {
+			t.Errorf("hour %d factor = %v [%v, %v], want between LNG and coal inside its bounds", h.Hour, f, lo, hi)
+		}
+
+		// The shares weight the fuel factors into the marginal factor
+		weighted := h.FuelShares[emission.FuelLNG]*350 + h.FuelShares[emission.FuelCoal]*850
+		if math.Abs(weighted-f) > 1e-6 {
+			t.Errorf("hour %d shares %v weigh to %v, want %v", h.Hour, h.FuelShares, weighted, f)
+		}
+	}
+}
+
+func TestEstimate_EstimatedDays(t *testing.T) {
+	lngOnly := func(slot int) (float64, float64) { return rampMW(slot), 0 }
+	days := []*generation.Response{
+		testDay(t, "2025-10-22", lngOnly),
+		testDay(t, "2025-10-23", lngOnly),
+		testDay(t, "2025-10-24", lngOnly),
+	}
+	days[0].Source.Name = "OCCTO"
+	days[1].Source.Name = generation.EstimatedSourceName
+	days[2].Source.Name = generation.EstimatedSourceName
+
+	resp, err := Estimate("kyushu", "2025-10-24", DefaultWindowDays, days, emission.DefaultSet())
+	if err != nil {
+		t.Fatalf("Estimate() error = %v", err)
+	}
+	if resp.DaysUsed != 3 || resp.EstimatedDays != 2 {
+		t.Errorf("days = %d used, %d estimated; want 3 and 2", resp.DaysUsed, resp.EstimatedDays)
+	}
+	if resp.Meta == nil || !strings.Contains(resp.Meta.Warning, "2 of 3 days use an estimated generation mix") ||
+		!strings.Contains(resp.Meta.Warning, "Too few supply changes") {
+		t.Errorf("Meta = %+v, want estimated days and missing hours warnings", resp.Meta)
+	}
+}
+
+func TestEstimate_Errors(t *testing.T) {
+	day := testDay(t, "2025-10-24", func(int) (float64, float64) { return 0, 0 })
+
+	if _, err := Estimate("kyushu", "2025-10-24", DefaultWindowDays, nil, emission.DefaultSet()); err == nil {
+		t.Error("Estimate(no days) error = nil")
+	}
+	if _, err := Estimate("tokyo", "2025-10-24", DefaultWindowDays, []*generation.Response{day}, emission.DefaultSet()); err == nil {
+		t.Error("Estimate(other area) error = nil")
+	}
+	if _, err := Estimate("kyushu", "2025-10-24", 0, []*generation.Response{day}, emission.DefaultSet()); err == nil {
+		t.Error("Estimate(0-day window) error = nil")
+	}
+
+	// Flat supply: no change to estimate from
+	resp, err := Estimate("kyushu", "2025-10-24", DefaultWindowDays, []*generation.Response{day}, emission.DefaultSet())
+	if err != nil {
+		t.Fatalf("Estimate(flat) error = %v", err)
+	}
+	if resp.Hours[12].FactorGCO2KWh != nil {
+		t.Errorf("flat hour 12 = %+v, want no estimate", resp.Hours[12])
+	}
+}
+
+func TestTQuantile(t *testing.T) {
+	tests := []struct {
+		df   int
+		want float64
+	}{{1, 12.706}, {5, 2.571}, {30, 2.042}, {40, 2.021}, {120, 1.980}}
+
+	for _, tt := range tests {
+		if got := tQuantile(tt.df); math.Abs(got-tt.want) > 0.001 {
+			t.Errorf("tQuantile(%d) = %v, want %v", tt.df, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+// Package marginal estimates marginal emission factors: the CO2 emitted per
+// extra MWh of demand, per area and hour of day. Unlike the average
+// intensity of the generation mix, it tells how much load shifting into or
+// out of an hour changes emissions.
+//
+// Factors are estimated from the generation mix history: for every pair of
+// consecutive intervals, the change in emissions is regressed on the change
+// in supply (demand met, including storage and imports) of the same hour of
+// day. The slope is the marginal factor; which fuels ramp with supply gives
+// the fuel shares.
+package marginal
+
+import "github.com/teo/aversome/backend/internal/emission"
+
+// DefaultWindowDays is the number of days of generation history an estimate uses.
+const DefaultWindowDays = 28
+
+// MinSamples is the number of interval pairs below which an hour is not estimated.
+const MinSamples = 4
+
+// Confidence is the level of the confidence bounds.
+const Confidence = 0.95
+
+// HourEstimate is the marginal emission factor of one hour of day. The
+// factor and bounds are absent when the hour has fewer than MinSamples pairs
+// or no supply change.
+type HourEstimate struct {
+	Hour          int      `json:"hour"`                      // 0-23, hour of the later interval of each pair
+	FactorGCO2KWh *float64 `json:"factor_gco2_kwh,omitempty"` // Emissions change / supply change
+	LowerGCO2KWh  *float64 `json:"lower_gco2_kwh,omitempty"`  // Confidence bounds (t-distribution)
+	UpperGCO2KWh  *float64 `json:"upper_gco2_kwh,omitempty"`
+	Samples       int      `json:"samples"` // Interval pairs used
+
+	// Share of the supply change met by each fuel (regression slope of the
+	// fuel's change on the supply change); the remainder is storage and imports
+	FuelShares map[emission.Fuel]float64 `json:"fuel_shares,omitempty"`
+}
+
+// Response holds the marginal emission factors of an area for a date,
+// estimated from the generation mix of the window ending on that date.
+// GET /api/marginal/{area}/{date}
+type Response struct {
+	Date              string         `json:"date"`                // YYYY-MM-DD, last day of the window
+	Area              string         `json:"area"`                // e.g., "kyushu"
+	Timezone          string         `json:"timezone"`            // Asia/Tokyo
+	WindowFrom        string         `json:"window_from"`         // First day of the window
+	WindowDays        int            `json:"window_days"`         // Days in the window
+	DaysUsed          int            `json:"days_used"`           // Days with a stored generation mix
+	EstimatedDays     int            `json:"estimated_days"`      // Of those, estimated mixes (no OCCTO actuals)
+	Confidence        float64        `json:"confidence"`          // Level of the bounds, e.g. 0.95
+	EmissionFactorSet string         `json:"emission_factor_set"` // Factors the emissions are calculated with
+	Basis             emission.Basis `json:"emission_factor_basis"`
+	Hours             []HourEstimate `json:"hours"` // 24 hours of day
+	Meta              *Meta          `json:"meta,omitempty"`
+}
+
+// Meta contains optional metadata and warnings.
+type Meta struct {
+	Warning string `json:"warning,omitempty"` // Non-blocking warning message
+}
@@ -0,0 +1,400 @@
+package pipeline
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/teo/aversome/backend/internal/areas"
+	"github.com/teo/aversome/backend/internal/series"
+	"github.com/teo/aversome/backend/internal/storage"
+)
+
+// BackfillStatus is the outcome of one backfill item.
+type BackfillStatus string
+
+const (
+	BackfillFilled  BackfillStatus = "filled"  // Fetched and saved
+	BackfillSkipped BackfillStatus = "skipped" // Already stored without warning
+	BackfillFailed  BackfillStatus = "failed"  // Inspect or fetch failed
+	BackfillPlanned BackfillStatus = "planned" // Would be fetched (dry run)
+)
+
+// Reasons a backfill item was selected (or skipped).
+const (
+	ReasonMissing = "missing" // Nothing stored for the day
+	ReasonWarning = "warning" // Stored document carries a data quality warning
+	ReasonRefetch = "refetch" // Stored, refetched on request
+	ReasonStored  = "stored"  // Stored, left alone
+)
+
+// DefaultBackfillConcurrency bounds the number of concurrent fetch jobs.
+const DefaultBackfillConcurrency = 4
+
+// BackfillDatasets lists the datasets Backfill can fill, in run order.
+// Generation is estimated from stored demand and JEPX, so it runs last.
+var BackfillDatasets = []storage.Dataset{
+	storage.DatasetDemand,
+	storage.DatasetJEPX,
+	storage.DatasetJEPXMarket,
+	storage.DatasetReserve,
+	storage.DatasetGeneration,
+}
+
+// BackfillConfig selects what Backfill inspects and fetches.
+type BackfillConfig struct {
+	From        string            // First date (YYYY-MM-DD)
+	To          string            // Last date (YYYY-MM-DD), inclusive
+	Datasets    []storage.Dataset // Subset of BackfillDatasets
+	Areas       []areas.Area      // Areas for per-area datasets (JEPX and generation skip areas without a spot price)
+	Concurrency int               // Max concurrent jobs (DefaultBackfillConcurrency if zero)
+	Refetch     bool              // Also fetch days that are stored without warning
+	DryRun      bool              // Inspect storage only
+}
+
+// BackfillItem is one dataset/area/date considered by Backfill.
+type BackfillItem struct {
+	Dataset  storage.Dataset `json:"dataset"`
+	Area     string          `json:"area,omitempty"`
+	Date     string          `json:"date"`
+	Reason   string          `json:"reason"` // missing, warning, refetch or stored
+	Status   BackfillStatus  `json:"status"`
+	Mode     Mode            `json:"mode,omitempty"`
+	Warning  string          `json:"warning,omitempty"` // Warning on the newly fetched document
+	Stage    Stage           `json:"stage,omitempty"`   // Failed stage
+	Err      error           `json:"-"`
+	Duration time.Duration   `json:"duration"`
+}
+
+// BackfillReport summarizes a Backfill run.
+type BackfillReport struct {
+	Items    []BackfillItem `json:"items"`
+	Filled   int            `json:"filled"`
+	Skipped  int            `json:"skipped"`
+	Failed   int            `json:"failed"`
+	Planned  int            `json:"planned"`
+	Duration time.Duration  `json:"duration"`
+}
+
+// Backfill inspects storage for every dataset/area/date in the range and
+// fetches the days that are missing or carry a warning, running at most
+// Concurrency jobs at once. Item failures are recorded in the report; the
+// returned error is only for an invalid config.
+func (p *Pipeline) Backfill(cfg BackfillConfig) (*BackfillReport, error) {
+	start := time.Now()
+
+	dates, err := series.Dates(cfg.From, cfg.To)
+	if err != nil {
+		return nil, err
+	}
+	datasets, err := orderDatasets(cfg.Datasets)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Concurrency <= 0 {
+		cfg.Concurrency = DefaultBackfillConcurrency
+	}
+
+	report := &BackfillReport{}
+
+	// One phase per dataset so estimates see the demand and JEPX filled before them
+	for _, dataset := range datasets {
+		items := p.planBackfill(dataset, dates, cfg)
+		switch {
+		case cfg.DryRun:
+		case dataset == storage.DatasetJEPX:
+			// One download of the all-history CSV covers every date and area
+			p.fillJEPX(items)
+		case dataset == storage.DatasetJEPXMarket:
+			p.fillJEPXMarket(items)
+		default:
+			p.runBackfill(items, cfg.Concurrency)
+		}
+		report.Items = append(report.Items, items...)
+	}
+
+	for _, item := range report.Items {
+		switch item.Status {
+		case BackfillFilled:
+			report.Filled++
+		case BackfillSkipped:
+			report.Skipped++
+		case BackfillFailed:
+			report.Failed++
+		case BackfillPlanned:
+			report.Planned++
+		}
+	}
+	report.Duration = time.Since(start)
+
+	return report, nil
+}
+
+// orderDatasets validates datasets and sorts them into BackfillDatasets order.
+func orderDatasets(datasets []storage.Dataset) ([]storage.Dataset, error) {
+	if len(datasets) == 0 {
+		return nil, errors.New("no datasets selected")
+	}
+
+	want := make(map[storage.Dataset]bool, len(datasets))
+	for _, d := range datasets {
+		supported := false
+		for _, b := range BackfillDatasets {
+			supported = supported || d == b
+		}
+		if !supported {
+			return nil, fmt.Errorf("dataset %q cannot be backfilled", d)
+		}
+		want[d] = true
+	}
+
+	out := make([]storage.Dataset, 0, len(want))
+	for _, d := range BackfillDatasets {
+		if want[d] {
+			out = append(out, d)
+		}
+	}
+	return out, nil
+}
+
+// planBackfill lists the items of one dataset and marks which need fetching.
+func (p *Pipeline) planBackfill(dataset storage.Dataset, dates []string, cfg BackfillConfig) []BackfillItem {
+	var targets []string
+	switch dataset {
+	case storage.DatasetJEPXMarket, storage.DatasetReserve:
+		targets = []string{""} // System-wide
+	default:
+		for _, a := range cfg.Areas {
+			// Generation is estimated from JEPX prices too
+			needsPrice := dataset == storage.DatasetJEPX || dataset == storage.DatasetGeneration
+			if needsPrice && !a.HasJEPXPrice() {
+				continue
+			}
+			targets = append(targets, string(a.Code))
+		}
+	}
+
+	items := make([]BackfillItem, 0, len(dates)*len(targets))
+	for _, date := range dates {
+		for _, area := range targets {
+			item := BackfillItem{Dataset: dataset, Area: area, Date: date}
+			item.Reason, item.Err = p.inspect(dataset, area, date)
+
+			switch {
+			case item.Err != nil:
+				item.Status, item.Stage = BackfillFailed, StageLoad
+			case item.Reason == ReasonStored && !cfg.Refetch:
+				item.Status = BackfillSkipped
+			case item.Reason == ReasonStored:
+				item.Reason, item.Status = ReasonRefetch, BackfillPlanned
+			default:
+				item.Status = BackfillPlanned
+			}
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// inspect reports whether a stored document is missing, warning-flagged or fine.
+func (p *Pipeline) inspect(dataset storage.Dataset, area, date string) (string, error) {
+	data, err := p.cfg.Store.Load(dataset, area, date)
+	if errors.Is(err, storage.ErrNotFound) {
+		return ReasonMissing, nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	// Demand, JEPX, JEPX market and reserve documents share meta.warning
+	var doc struct {
+		Meta *struct {
+			Warning string `json:"warning"`
+		} `json:"meta"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return "", fmt.Errorf("failed to decode stored %s: %w", dataset, err)
+	}
+	if doc.Meta != nil && doc.Meta.Warning != "" {
+		return ReasonWarning, nil
+	}
+	return ReasonStored, nil
+}
+
+// runBackfill fetches the planned items with at most concurrency jobs at once.
+func (p *Pipeline) runBackfill(items []BackfillItem, concurrency int) {
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+
+	for i := range items {
+		if items[i].Status != BackfillPlanned {
+			continue
+		}
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(item *BackfillItem) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			p.fill(item)
+		}(&items[i])
+	}
+
+	wg.Wait()
+}
+
+// fill runs the job for one item and records its outcome.
+func (p *Pipeline) fill(item *BackfillItem) {
+	start := time.Now()
+	defer func() { item.Duration = time.Since(start) }()
+
+	var res *Result
+	var err error
+
+	var a areas.Area
+	if item.Area != "" {
+		a, err = areas.Parse(item.Area)
+	}
+	if err == nil {
+		switch item.Dataset {
+		case storage.DatasetDemand:
+			var r *DemandResult
+			if r, err = p.FetchDemand(a, item.Date); err == nil {
+				res = &r.Result
+			}
+		case storage.DatasetJEPX:
+			var r *JEPXResult
+			if r, err = p.FetchJEPX(a, item.Date); err == nil {
+				res = &r.Result
+			}
+		case storage.DatasetReserve:
+			var r *ReserveResult
+			if r, err = p.FetchReserve(item.Date); err == nil {
+				res = &r.Result
+			}
+		case storage.DatasetGeneration:
+			var r *GenerationResult
+			if r, err = p.EstimateGeneration(a, item.Date); err == nil {
+				res = &r.Result
+			}
+		}
+	}
+
+	if err != nil {
+		item.Status, item.Err = BackfillFailed, err
+		var pipeErr *Error
+		if errors.As(err, &pipeErr) {
+			item.Stage = pipeErr.Stage
+		}
+		return
+	}
+
+	item.Status = BackfillFilled
+	item.Mode = res.Mode
+	item.Warning = res.Warning
+}
+
+// fillJEPX fills the planned JEPX items from a single FetchJEPXRange pass.
+func (p *Pipeline) fillJEPX(items []BackfillItem) {
+	start := time.Now()
+
+	planned := make(map[string]*BackfillItem)
+	var from, to string
+	var selected []areas.Area
+	seenArea := make(map[string]bool)
+
+	for i := range items {
+		item := &items[i]
+		if item.Status != BackfillPlanned {
+			continue
+		}
+		planned[item.Date+"/"+item.Area] = item
+		if from == "" || item.Date < from {
+			from = item.Date
+		}
+		if item.Date > to {
+			to = item.Date
+		}
+		if !seenArea[item.Area] {
+			seenArea[item.Area] = true
+			if a, err := areas.Parse(item.Area); err == nil {
+				selected = append(selected, a)
+			}
+		}
+	}
+	if len(planned) == 0 {
+		return
+	}
+
+	results, err := p.FetchJEPXRange(from, to, selected, func(date, area string) bool {
+		return planned[date+"/"+area] != nil
+	})
+
+	for _, res := range results {
+		item := planned[res.Date+"/"+res.Area]
+		item.Status, item.Mode, item.Warning = BackfillFilled, res.Mode, res.Warning
+	}
+	for _, item := range planned {
+		item.Duration = time.Since(start)
+		if item.Status == BackfillPlanned {
+			// Whole-run failure, or the date is not in the CSV
+			failBackfillItem(item, err, fmt.Errorf("no %s price for %s in JEPX CSV", item.Area, item.Date))
+		}
+	}
+}
+
+// fillJEPXMarket fills the planned all-area JEPX items from a single FetchJEPXMarket pass.
+func (p *Pipeline) fillJEPXMarket(items []BackfillItem) {
+	start := time.Now()
+
+	planned := make(map[string]*BackfillItem)
+	var from, to string
+	for i := range items {
+		item := &items[i]
+		if item.Status != BackfillPlanned {
+			continue
+		}
+		planned[item.Date] = item
+		if from == "" || item.Date < from {
+			from = item.Date
+		}
+		if item.Date > to {
+			to = item.Date
+		}
+	}
+	if len(planned) == 0 {
+		return
+	}
+
+	results, err := p.FetchJEPXMarket(from, to, func(date string) bool {
+		return planned[date] != nil
+	})
+
+	for _, res := range results {
+		item := planned[res.Date]
+		item.Status, item.Mode, item.Warning = BackfillFilled, res.Mode, res.Warning
+	}
+	for _, item := range planned {
+		item.Duration = time.Since(start)
+		if item.Status == BackfillPlanned {
+			failBackfillItem(item, err, fmt.Errorf("no prices for %s in JEPX CSV", item.Date))
+		}
+	}
+}
+
+// failBackfillItem marks an item of a batch fetch as failed, with the batch
+// error if there was one and notInCSV otherwise.
+func failBackfillItem(item *BackfillItem, err, notInCSV error) {
+	item.Status = BackfillFailed
+	item.Err = err
+	if item.Err == nil {
+		item.Err = notInCSV
+	}
+	item.Stage = StageParse
+	var pipeErr *Error
+	if errors.As(item.Err, &pipeErr) {
+		item.Stage = pipeErr.Stage
+	}
+}
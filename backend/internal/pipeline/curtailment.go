@@ -0,0 +1,87 @@
+package pipeline
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/teo/aversome/backend/internal/adapters"
+	"github.com/teo/aversome/backend/internal/areas"
+	"github.com/teo/aversome/backend/internal/curtailment"
+	"github.com/teo/aversome/backend/internal/storage"
+)
+
+// occtoKindCurtailment is the OCCTO download kind (jhSybt) of the renewable
+// output curtailment results (再エネ出力制御実績, every area).
+const occtoKindCurtailment = "08"
+
+// CurtailmentResult is the outcome of a curtailment job.
+type CurtailmentResult struct {
+	Result
+	Response *curtailment.Response `json:"-"`
+}
+
+// FetchCurtailment fetches, normalizes and saves the renewable output
+// curtailment events (出力制御) of an area for a date. A day without events
+// is saved too. HTTP failures fall back to the bundled testdata; a date the
+// sample does not cover is a StageFetch error wrapping storage.ErrNotFound
+// rather than a saved day without curtailment.
+func (p *Pipeline) FetchCurtailment(a areas.Area, date string) (*CurtailmentResult, error) {
+	start := time.Now()
+	area := string(a.Code)
+
+	if err := validateArea(storage.DatasetCurtailment, a, date); err != nil {
+		return nil, err
+	}
+	parsedDate, err := validateDate(storage.DatasetCurtailment, area, date)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &CurtailmentResult{Result: Result{Dataset: storage.DatasetCurtailment, Area: area, Date: date}}
+	fail := func(stage Stage, err error) (*CurtailmentResult, error) {
+		return nil, &Error{Stage: stage, Dataset: storage.DatasetCurtailment, Area: area, Date: date, Err: err}
+	}
+
+	reader, err := p.openOCCTOOutlook(&res.Result, occtoURL(occtoKindCurtailment, parsedDate), "occto-curtailment-sample.csv", start)
+	if err != nil {
+		return fail(StageFetch, err)
+	}
+	defer reader.Close()
+
+	var csvReader io.Reader = reader
+	if res.Mode == ModeTestdata {
+		data, err := io.ReadAll(reader)
+		if err != nil {
+			return fail(StageFetch, fmt.Errorf("failed to read testdata CSV: %w", err))
+		}
+		covered, err := adapters.NewOCCTOAdapter().CurtailmentCoversDate(bytes.NewReader(data), date)
+		if err != nil {
+			return fail(StageParse, err)
+		}
+		if !covered {
+			return fail(StageFetch, fmt.Errorf("no curtailment testdata for %s: %w", date, storage.ErrNotFound))
+		}
+		csvReader = bytes.NewReader(data)
+	}
+
+	// Parse CSV using OCCTO adapter
+	resp, err := adapters.NewOCCTOAdapter().ParseCurtailmentCSV(csvReader, date, a)
+	if err != nil {
+		p.cfg.Logger.LogFetch(res.Source, "failure", "", "CSV parsing failed", time.Since(start), err)
+		return fail(StageParse, err)
+	}
+	resp.Source.Name = res.Source
+
+	res.Response = resp
+	res.Points = len(resp.Events)
+	if resp.Meta != nil {
+		res.Warning = resp.Meta.Warning
+	}
+
+	if err := p.save(&res.Result, resp, start); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
@@ -0,0 +1,136 @@
+package pipeline
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/teo/aversome/backend/internal/adapters"
+	"github.com/teo/aversome/backend/internal/areas"
+	"github.com/teo/aversome/backend/internal/demand"
+	"github.com/teo/aversome/backend/internal/storage"
+	pkghttp "github.com/teo/aversome/backend/pkg/http"
+)
+
+// DemandResult is the outcome of a demand job.
+type DemandResult struct {
+	Result
+	Response *demand.Response `json:"-"`
+}
+
+// FetchDemand fetches, normalizes and saves area demand for a date.
+// Tokyo comes from the TEPCO monthly ZIP, every other area from OCCTO
+// (jhSybt=02). HTTP failures fall back to the bundled testdata.
+func (p *Pipeline) FetchDemand(a areas.Area, date string) (*DemandResult, error) {
+	start := time.Now()
+	area := string(a.Code)
+
+	if err := validateArea(storage.DatasetDemand, a, date); err != nil {
+		return nil, err
+	}
+	parsedDate, err := validateDate(storage.DatasetDemand, area, date)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &DemandResult{Result: Result{Dataset: storage.DatasetDemand, Area: area, Date: date}}
+	fail := func(stage Stage, err error) (*DemandResult, error) {
+		return nil, &Error{Stage: stage, Dataset: storage.DatasetDemand, Area: area, Date: date, Err: err}
+	}
+
+	var reader io.ReadCloser
+
+	if p.cfg.UseHTTP {
+		fetcher := pkghttp.NewFetcher(pkghttp.DefaultConfig())
+		var url string
+
+		if a.Code == areas.Tokyo {
+			// TEPCO provides data in monthly ZIP archives
+			// Format: YYYYMM_power_usage.zip containing YYYYMMDD_power_usage.csv files
+			yearMonth := parsedDate.Format("200601")                      // YYYYMM
+			dayFile := parsedDate.Format("20060102") + "_power_usage.csv" // YYYYMMDD_power_usage.csv
+
+			url = fmt.Sprintf("https://www.tepco.co.jp/forecast/html/images/%s_power_usage.zip", yearMonth)
+			res.Source = "TEPCO"
+
+			p.cfg.Logger.Info(fmt.Sprintf("Attempting to fetch TEPCO ZIP from %s (looking for %s)", url, dayFile))
+			reader, err = fetcher.FetchFromZip(url, dayFile)
+		} else {
+			// Other areas: OCCTO jhSybt=02 provides 30-minute demand for all 10 areas
+			url = occtoURL("02", parsedDate)
+			res.Source = "OCCTO"
+
+			p.cfg.Logger.Info(fmt.Sprintf("Attempting to fetch %s demand from OCCTO: %s", a.NameEN, url))
+			reader, err = fetcher.Fetch(url)
+		}
+
+		if err != nil && p.cfg.NoFallback {
+			p.cfg.Logger.LogFetch(res.Source, "failure", "", "HTTP fetch failed", time.Since(start), err)
+			return fail(StageFetch, err)
+		} else if err != nil {
+			p.cfg.Logger.LogFetch(res.Source, "failure", "", "HTTP fetch failed, falling back to testdata", time.Since(start), err)
+			res.FallbackErr = err
+		} else {
+			p.cfg.Logger.LogFetch(res.Source, "success", "", "HTTP fetch successful", time.Since(start), nil)
+			res.Mode = ModeHTTP
+		}
+	}
+
+	// Fallback to testdata if HTTP failed or not requested
+	if res.Mode != ModeHTTP {
+		res.Mode = ModeTestdata
+
+		var name string
+		switch a.Code {
+		case areas.Tokyo:
+			name, res.Source = "tepco-sample.csv", "TEPCO (testdata)"
+		case areas.Kansai:
+			name, res.Source = "kansai-sample.csv", "Kansai (testdata)"
+		default:
+			name, res.Source = "occto-sample.csv", "OCCTO (testdata)"
+		}
+
+		reader, err = p.openTestdata(name)
+		if err != nil {
+			return fail(StageFetch, fmt.Errorf("failed to open testdata CSV: %w", err))
+		}
+	}
+	defer reader.Close()
+
+	// Parse CSV using appropriate adapter
+	var resp *demand.Response
+
+	switch {
+	case a.Code == areas.Tokyo:
+		resp, err = adapters.NewTEPCOAdapter().ParseCSV(reader, date)
+	case a.Code == areas.Kansai && res.Mode == ModeTestdata:
+		resp, err = adapters.NewKansaiAdapter().ParseCSV(reader, date)
+	default:
+		// OCCTO covers every other area (and Kansai over HTTP)
+		resp, err = adapters.NewOCCTOAdapter().ParseDemandCSV(reader, date, demand.Area(area))
+	}
+	if err != nil {
+		p.cfg.Logger.LogFetch(res.Source, "failure", "", "CSV parsing failed", time.Since(start), err)
+		return fail(StageParse, err)
+	}
+
+	res.Response = resp
+	res.Points = len(resp.Series)
+	if resp.Meta != nil {
+		res.Warning = resp.Meta.Warning
+	}
+
+	if err := p.save(&res.Result, resp, start); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// occtoURL builds the OCCTO public CSV download URL for a data kind (jhSybt).
+func occtoURL(kind string, date time.Time) string {
+	dateFormatted := date.Format("2006/01/02") // YYYY/MM/DD
+	return fmt.Sprintf(
+		"https://web-kohyo.occto.or.jp/kks-web-public/download/downloadCsv?jhSybt=%s&tgtYmdFrom=%s&tgtYmdTo=%s",
+		kind, dateFormatted, dateFormatted,
+	)
+}
@@ -0,0 +1,156 @@
+package pipeline
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/teo/aversome/backend/internal/adapters"
+	"github.com/teo/aversome/backend/internal/areas"
+	"github.com/teo/aversome/backend/internal/demand"
+	"github.com/teo/aversome/backend/internal/generation"
+	"github.com/teo/aversome/backend/internal/jepx"
+	"github.com/teo/aversome/backend/internal/storage"
+	pkghttp "github.com/teo/aversome/backend/pkg/http"
+)
+
+// ErrNoTestdata is returned when a dataset has no bundled sample to fall back to.
+var ErrNoTestdata = errors.New("testdata mode not implemented (use HTTP)")
+
+// GenerationResult is the outcome of a generation mix job.
+type GenerationResult struct {
+	Result
+	Response *generation.Response `json:"-"`
+}
+
+// FetchGeneration fetches, normalizes and saves the OCCTO generation mix
+// (jhSybt=03, 電源種別供給力) for an area and date. HTTP only.
+func (p *Pipeline) FetchGeneration(a areas.Area, date string) (*GenerationResult, error) {
+	start := time.Now()
+	area := string(a.Code)
+
+	if err := validateArea(storage.DatasetGeneration, a, date); err != nil {
+		return nil, err
+	}
+	parsedDate, err := validateDate(storage.DatasetGeneration, area, date)
+	if err != nil {
+		return nil, err
+	}
+
+	fail := func(stage Stage, err error) (*GenerationResult, error) {
+		return nil, &Error{Stage: stage, Dataset: storage.DatasetGeneration, Area: area, Date: date, Err: err}
+	}
+
+	if !p.cfg.UseHTTP {
+		return fail(StageValidate, ErrNoTestdata)
+	}
+
+	res := &GenerationResult{Result: Result{Dataset: storage.DatasetGeneration, Area: area, Date: date, Source: "OCCTO", Mode: ModeHTTP}}
+
+	// OCCTO blocks non-browser clients
+	fetcher := pkghttp.NewFetcher(pkghttp.BrowserConfig())
+	url := occtoURL("03", parsedDate)
+
+	p.cfg.Logger.Info(fmt.Sprintf("Attempting HTTP fetch from %s", url))
+	reader, err := fetcher.Fetch(url)
+	if err != nil {
+		p.cfg.Logger.LogFetch(res.Source, "failure", "", "HTTP fetch failed", time.Since(start), err)
+		return fail(StageFetch, err)
+	}
+	defer reader.Close()
+	p.cfg.Logger.LogFetch(res.Source, "success", "", "HTTP fetch successful", time.Since(start), nil)
+
+	// Parse CSV using OCCTO adapter
+	resp, err := adapters.NewOCCTOAdapter().ParseGenerationMixCSV(reader, date, area)
+	if err != nil {
+		p.cfg.Logger.LogFetch(res.Source, "failure", "", "CSV parsing failed", time.Since(start), err)
+		return fail(StageParse, err)
+	}
+	resp.Source.Name = res.Source
+	resp.SetEmissionFactors(p.cfg.EmissionFactors)
+
+	res.Response = resp
+	res.Points = len(resp.Series)
+
+	if err := p.save(&res.Result, resp, start); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// EstimateGeneration estimates the generation mix for an area and date from
+// the stored demand and JEPX documents and saves it. Missing inputs are
+// reported as a StageLoad error wrapping storage.ErrNotFound.
+func (p *Pipeline) EstimateGeneration(a areas.Area, date string) (*GenerationResult, error) {
+	start := time.Now()
+	area := string(a.Code)
+
+	if err := validateArea(storage.DatasetGeneration, a, date); err != nil {
+		return nil, err
+	}
+	parsedDate, err := validateDate(storage.DatasetGeneration, area, date)
+	if err != nil {
+		return nil, err
+	}
+
+	fail := func(stage Stage, err error) (*GenerationResult, error) {
+		return nil, &Error{Stage: stage, Dataset: storage.DatasetGeneration, Area: area, Date: date, Err: err}
+	}
+
+	if !a.HasJEPXPrice() {
+		return fail(StageValidate, fmt.Errorf("area %s has no JEPX spot price to estimate from", a.Code))
+	}
+
+	// Load demand data
+	var demandResp demand.Response
+	if err := p.loadJSON(storage.DatasetDemand, area, date, &demandResp); err != nil {
+		return fail(StageLoad, err)
+	}
+
+	// Load JEPX price data
+	var jepxResp jepx.Response
+	if err := p.loadJSON(storage.DatasetJEPX, area, date, &jepxResp); err != nil {
+		return fail(StageLoad, err)
+	}
+
+	// Estimate generation mix
+	estimator := generation.NewEstimator()
+	resp, err := estimator.EstimateFromDemandAndPrice(&demandResp, &jepxResp)
+	if err != nil {
+		return fail(StageEstimate, err)
+	}
+
+	// Apply seasonal adjustment
+	resp = estimator.EstimateWithSeasonalAdjustment(resp, parsedDate)
+	resp.SetEmissionFactors(p.cfg.EmissionFactors)
+
+	res := &GenerationResult{
+		Result: Result{
+			Dataset: storage.DatasetGeneration,
+			Area:    area,
+			Date:    date,
+			Source:  resp.Source.Name,
+			Mode:    ModeEstimated,
+			Points:  len(resp.Series),
+		},
+		Response: resp,
+	}
+
+	if err := p.save(&res.Result, resp, start); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// loadJSON loads a stored document from the sink and decodes it into v.
+func (p *Pipeline) loadJSON(dataset storage.Dataset, area, date string, v any) error {
+	data, err := p.cfg.Store.Load(dataset, area, date)
+	if err != nil {
+		return fmt.Errorf("load %s: %w", dataset, err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("parse %s JSON: %w", dataset, err)
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package pipeline
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/teo/aversome/backend/internal/adapters"
+	"github.com/teo/aversome/backend/internal/areas"
+	"github.com/teo/aversome/backend/internal/imbalance"
+	"github.com/teo/aversome/backend/internal/storage"
+	pkghttp "github.com/teo/aversome/backend/pkg/http"
+)
+
+// imbalanceURL is the monthly imbalance price CSV (every area and koma of a month).
+const imbalanceURL = "https://www.imbalanceprices-cs.jp/public/price/download?year=%d&month=%02d"
+
+// ImbalanceResult is the outcome of an imbalance price job.
+type ImbalanceResult struct {
+	Result
+	Response *imbalance.Response `json:"-"`
+}
+
+// FetchImbalance fetches, normalizes and saves the imbalance prices
+// (インバランス料金) of an area for a date. HTTP failures fall back to the
+// bundled testdata.
+func (p *Pipeline) FetchImbalance(a areas.Area, date string) (*ImbalanceResult, error) {
+	start := time.Now()
+	area := string(a.Code)
+
+	if err := validateArea(storage.DatasetImbalance, a, date); err != nil {
+		return nil, err
+	}
+	parsedDate, err := validateDate(storage.DatasetImbalance, area, date)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &ImbalanceResult{Result: Result{Dataset: storage.DatasetImbalance, Area: area, Date: date}}
+	fail := func(stage Stage, err error) (*ImbalanceResult, error) {
+		return nil, &Error{Stage: stage, Dataset: storage.DatasetImbalance, Area: area, Date: date, Err: err}
+	}
+
+	var reader io.ReadCloser
+
+	if p.cfg.UseHTTP {
+		fetcher := pkghttp.NewFetcher(pkghttp.DefaultConfig())
+		url := fmt.Sprintf(imbalanceURL, parsedDate.Year(), int(parsedDate.Month()))
+		res.Source = "Imbalance Price Portal"
+
+		p.cfg.Logger.Info(fmt.Sprintf("Attempting HTTP fetch from %s", url))
+		reader, err = fetcher.Fetch(url)
+
+		if err != nil && p.cfg.NoFallback {
+			p.cfg.Logger.LogFetch(res.Source, "failure", "", "HTTP fetch failed", time.Since(start), err)
+			return fail(StageFetch, err)
+		} else if err != nil {
+			p.cfg.Logger.LogFetch(res.Source, "failure", "", "HTTP fetch failed, falling back to testdata", time.Since(start), err)
+			res.FallbackErr = err
+		} else {
+			p.cfg.Logger.LogFetch(res.Source, "success", "", "HTTP fetch successful", time.Since(start), nil)
+			res.Mode = ModeHTTP
+		}
+	}
+
+	// Fallback to testdata if HTTP failed or not requested
+	if res.Mode != ModeHTTP {
+		res.Mode = ModeTestdata
+		res.Source = "Imbalance Price Portal (testdata)"
+
+		reader, err = p.openTestdata("imbalance-sample.csv")
+		if err != nil {
+			return fail(StageFetch, fmt.Errorf("failed to open testdata CSV: %w", err))
+		}
+	}
+	defer reader.Close()
+
+	// Parse CSV using imbalance adapter
+	resp, err := adapters.NewImbalanceAdapter().ParseCSV(reader, date, a)
+	if err != nil {
+		p.cfg.Logger.LogFetch(res.Source, "failure", "", "CSV parsing failed", time.Since(start), err)
+		return fail(StageParse, err)
+	}
+	resp.Source.Name = res.Source
+
+	res.Response = resp
+	res.Points = len(resp.Prices)
+	if resp.Meta != nil {
+		res.Warning = resp.Meta.Warning
+	}
+
+	if err := p.save(&res.Result, resp, start); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
@@ -0,0 +1,62 @@
+package pipeline
+
+import (
+	"time"
+
+	"github.com/teo/aversome/backend/internal/adapters"
+	"github.com/teo/aversome/backend/internal/interconnector"
+	"github.com/teo/aversome/backend/internal/storage"
+)
+
+// occtoKindInterconnector is the OCCTO download kind (jhSybt) of the
+// interconnector flows (連系線潮流: planned, actual and operating capacity).
+const occtoKindInterconnector = "07"
+
+// InterconnectorResult is the outcome of an interconnector flow job for one date.
+type InterconnectorResult struct {
+	Result
+	Response *interconnector.Response `json:"-"`
+}
+
+// FetchInterconnector fetches, normalizes and saves the OCCTO flows of every
+// interconnector for a date. HTTP failures fall back to the bundled testdata.
+func (p *Pipeline) FetchInterconnector(date string) (*InterconnectorResult, error) {
+	start := time.Now()
+
+	parsedDate, err := validateDate(storage.DatasetInterconnector, "", date)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &InterconnectorResult{Result: Result{Dataset: storage.DatasetInterconnector, Date: date}}
+	fail := func(stage Stage, err error) (*InterconnectorResult, error) {
+		return nil, &Error{Stage: stage, Dataset: storage.DatasetInterconnector, Date: date, Err: err}
+	}
+
+	reader, err := p.openOCCTOOutlook(&res.Result, occtoURL(occtoKindInterconnector, parsedDate), "occto-interconnector-sample.csv", start)
+	if err != nil {
+		return fail(StageFetch, err)
+	}
+	defer reader.Close()
+
+	// Parse CSV using OCCTO adapter
+	resp, err := adapters.NewOCCTOAdapter().ParseInterconnectorCSV(reader, date)
+	if err != nil {
+		p.cfg.Logger.LogFetch(res.Source, "failure", "", "CSV parsing failed", time.Since(start), err)
+		return fail(StageParse, err)
+	}
+	resp.Source.Name = res.Source
+
+	res.Response = resp
+	for _, l := range resp.Lines {
+		res.Points += len(l.Series)
+	}
+	if resp.Meta != nil {
+		res.Warning = resp.Meta.Warning
+	}
+
+	if err := p.save(&res.Result, resp, start); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
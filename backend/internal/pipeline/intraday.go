@@ -0,0 +1,92 @@
+package pipeline
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/teo/aversome/backend/internal/adapters"
+	"github.com/teo/aversome/backend/internal/jepx"
+	"github.com/teo/aversome/backend/internal/storage"
+	pkghttp "github.com/teo/aversome/backend/pkg/http"
+)
+
+// jepxIntradayURL is the yearly JEPX intraday trade summary CSV (all delivery dates of a year).
+const jepxIntradayURL = "https://www.jepx.jp/market/excel/im_trade_summary_%d.csv"
+
+// IntradayResult is the outcome of a JEPX intraday job.
+type IntradayResult struct {
+	Result
+	Response *jepx.IntradayResponse `json:"-"`
+}
+
+// FetchJEPXIntraday fetches, normalizes and saves the JEPX intraday market
+// (時間前市場) results of a delivery date. HTTP failures fall back to the
+// bundled testdata.
+func (p *Pipeline) FetchJEPXIntraday(date string) (*IntradayResult, error) {
+	start := time.Now()
+
+	parsedDate, err := validateDate(storage.DatasetIntraday, "", date)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &IntradayResult{Result: Result{Dataset: storage.DatasetIntraday, Date: date}}
+	fail := func(stage Stage, err error) (*IntradayResult, error) {
+		return nil, &Error{Stage: stage, Dataset: storage.DatasetIntraday, Date: date, Err: err}
+	}
+
+	var reader io.ReadCloser
+
+	if p.cfg.UseHTTP {
+		// JEPX blocks non-browser clients
+		fetcher := pkghttp.NewFetcher(pkghttp.BrowserConfig())
+		url := fmt.Sprintf(jepxIntradayURL, parsedDate.Year())
+		res.Source = "JEPX Intraday"
+
+		p.cfg.Logger.Info(fmt.Sprintf("Attempting HTTP fetch from %s", url))
+		reader, err = fetcher.Fetch(url)
+
+		if err != nil && p.cfg.NoFallback {
+			p.cfg.Logger.LogFetch(res.Source, "failure", "", "HTTP fetch failed", time.Since(start), err)
+			return fail(StageFetch, err)
+		} else if err != nil {
+			p.cfg.Logger.LogFetch(res.Source, "failure", "", "HTTP fetch failed, falling back to testdata", time.Since(start), err)
+			res.FallbackErr = err
+		} else {
+			p.cfg.Logger.LogFetch(res.Source, "success", "", "HTTP fetch successful", time.Since(start), nil)
+			res.Mode = ModeHTTP
+		}
+	}
+
+	// Fallback to testdata if HTTP failed or not requested
+	if res.Mode != ModeHTTP {
+		res.Mode = ModeTestdata
+		res.Source = "JEPX Intraday (testdata)"
+
+		reader, err = p.openTestdata("jepx-intraday-sample.csv")
+		if err != nil {
+			return fail(StageFetch, fmt.Errorf("failed to open testdata CSV: %w", err))
+		}
+	}
+	defer reader.Close()
+
+	// Parse CSV using JEPX intraday adapter
+	resp, err := adapters.NewJEPXIntradayAdapter().ParseCSV(reader, date)
+	if err != nil {
+		p.cfg.Logger.LogFetch(res.Source, "failure", "", "CSV parsing failed", time.Since(start), err)
+		return fail(StageParse, err)
+	}
+	resp.Source.Name = res.Source
+
+	res.Response = resp
+	res.Points = len(resp.Products)
+	if resp.Meta != nil {
+		res.Warning = resp.Meta.Warning
+	}
+
+	if err := p.save(&res.Result, resp, start); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
@@ -0,0 +1,241 @@
+package pipeline
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/teo/aversome/backend/internal/adapters"
+	"github.com/teo/aversome/backend/internal/areas"
+	"github.com/teo/aversome/backend/internal/jepx"
+	"github.com/teo/aversome/backend/internal/series"
+	"github.com/teo/aversome/backend/internal/storage"
+	pkghttp "github.com/teo/aversome/backend/pkg/http"
+)
+
+// jepxCSVURL is the japanesepower.org mirror of JEPX spot results (all dates,
+// all areas). JEPX itself offers no direct CSV download.
+const jepxCSVURL = "https://japanesepower.org/jepxSpot.csv"
+
+// JEPXResult is the outcome of a JEPX spot price job.
+type JEPXResult struct {
+	Result
+	Response *jepx.Response `json:"-"`
+}
+
+// FetchJEPX fetches, normalizes and saves JEPX spot prices for an area and date.
+// HTTP failures fall back to the bundled testdata.
+func (p *Pipeline) FetchJEPX(a areas.Area, date string) (*JEPXResult, error) {
+	start := time.Now()
+	area := string(a.Code)
+
+	if err := validateArea(storage.DatasetJEPX, a, date); err != nil {
+		return nil, err
+	}
+	if !a.HasJEPXPrice() {
+		return nil, &Error{Stage: StageValidate, Dataset: storage.DatasetJEPX, Area: area, Date: date,
+			Err: fmt.Errorf("area %s has no JEPX spot price", a.Code)}
+	}
+	if _, err := validateDate(storage.DatasetJEPX, area, date); err != nil {
+		return nil, err
+	}
+
+	res := &JEPXResult{Result: Result{Dataset: storage.DatasetJEPX, Area: area, Date: date}}
+	fail := func(stage Stage, err error) (*JEPXResult, error) {
+		return nil, &Error{Stage: stage, Dataset: storage.DatasetJEPX, Area: area, Date: date, Err: err}
+	}
+
+	reader, err := p.openJEPXCSV(&res.Result, start)
+	if err != nil {
+		return fail(StageFetch, err)
+	}
+	defer reader.Close()
+
+	// Parse CSV using JEPX adapter
+	resp, err := adapters.NewJEPXAdapter().ParseCSV(reader, date, area)
+	if err != nil {
+		p.cfg.Logger.LogFetch(res.Source, "failure", "", "CSV parsing failed", time.Since(start), err)
+		return fail(StageParse, err)
+	}
+
+	res.Response = resp
+	res.Points = len(resp.PriceYenPerKwh)
+	if resp.Meta != nil {
+		res.Warning = resp.Meta.Warning
+	}
+
+	if err := p.save(&res.Result, resp, start); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// FetchJEPXRange downloads the JEPX CSV once and saves spot prices for every
+// selected area and date between from and to (inclusive) in a single pass.
+// want, if non-nil, limits which (date, area) pairs are saved. Dates missing
+// from the CSV produce no result.
+func (p *Pipeline) FetchJEPXRange(from, to string, selected []areas.Area, want func(date, area string) bool) ([]*JEPXResult, error) {
+	start := time.Now()
+	span := from + ".." + to
+
+	fail := func(stage Stage, err error) ([]*JEPXResult, error) {
+		return nil, &Error{Stage: stage, Dataset: storage.DatasetJEPX, Date: span, Err: err}
+	}
+
+	if _, err := series.Dates(from, to); err != nil {
+		return fail(StageValidate, err)
+	}
+	for _, a := range selected {
+		if err := validateArea(storage.DatasetJEPX, a, span); err != nil {
+			return nil, err
+		}
+		if !a.HasJEPXPrice() {
+			return fail(StageValidate, fmt.Errorf("area %s has no JEPX spot price", a.Code))
+		}
+	}
+	if len(selected) == 0 {
+		return fail(StageValidate, fmt.Errorf("no areas selected"))
+	}
+
+	var fetched Result
+	reader, err := p.openJEPXCSV(&fetched, start)
+	if err != nil {
+		return fail(StageFetch, err)
+	}
+	defer reader.Close()
+
+	// Parse every selected (date, area) in one pass, saving as we go
+	var results []*JEPXResult
+	var saveErr error
+	err = adapters.NewJEPXAdapter().ParseCSVBatch(reader, from, to, selected, func(resp *jepx.Response) error {
+		if want != nil && !want(resp.Date, resp.Area) {
+			return nil
+		}
+
+		res := &JEPXResult{Result: fetched, Response: resp}
+		res.Dataset, res.Area, res.Date = storage.DatasetJEPX, resp.Area, resp.Date
+		res.Points = len(resp.PriceYenPerKwh)
+		if resp.Meta != nil {
+			res.Warning = resp.Meta.Warning
+		}
+
+		if saveErr = p.save(&res.Result, resp, start); saveErr != nil {
+			return saveErr
+		}
+		results = append(results, res)
+		return nil
+	})
+	if saveErr != nil {
+		return nil, saveErr
+	}
+	if err != nil {
+		p.cfg.Logger.LogFetch(fetched.Source, "failure", "", "CSV parsing failed", time.Since(start), err)
+		return fail(StageParse, err)
+	}
+
+	return results, nil
+}
+
+// JEPXMarketResult is the outcome of an all-area JEPX job for one date.
+type JEPXMarketResult struct {
+	Result
+	Response *jepx.MarketResponse `json:"-"`
+}
+
+// FetchJEPXMarket downloads the JEPX CSV once and saves the all-area market
+// document (system price and every area price) of each date between from and
+// to. want, if non-nil, limits which dates are saved.
+func (p *Pipeline) FetchJEPXMarket(from, to string, want func(date string) bool) ([]*JEPXMarketResult, error) {
+	start := time.Now()
+	span := from
+	if to != from {
+		span = from + ".." + to
+	}
+
+	fail := func(stage Stage, err error) ([]*JEPXMarketResult, error) {
+		return nil, &Error{Stage: stage, Dataset: storage.DatasetJEPXMarket, Date: span, Err: err}
+	}
+
+	if _, err := series.Dates(from, to); err != nil {
+		return fail(StageValidate, err)
+	}
+
+	var fetched Result
+	reader, err := p.openJEPXCSV(&fetched, start)
+	if err != nil {
+		return fail(StageFetch, err)
+	}
+	defer reader.Close()
+
+	var results []*JEPXMarketResult
+	var saveErr error
+	err = adapters.NewJEPXAdapter().ParseMarketCSVBatch(reader, from, to, func(resp *jepx.MarketResponse) error {
+		if want != nil && !want(resp.Date) {
+			return nil
+		}
+
+		res := &JEPXMarketResult{Result: fetched, Response: resp}
+		res.Dataset, res.Date = storage.DatasetJEPXMarket, resp.Date
+		res.Points = len(resp.Series)
+		if resp.Meta != nil {
+			res.Warning = resp.Meta.Warning
+		}
+
+		if saveErr = p.save(&res.Result, resp, start); saveErr != nil {
+			return saveErr
+		}
+		results = append(results, res)
+		return nil
+	})
+	if saveErr != nil {
+		return nil, saveErr
+	}
+	if err != nil {
+		p.cfg.Logger.LogFetch(fetched.Source, "failure", "", "CSV parsing failed", time.Since(start), err)
+		return fail(StageParse, err)
+	}
+	if len(results) == 0 && from == to && want == nil {
+		return fail(StageParse, fmt.Errorf("no data found for date %s", from))
+	}
+
+	return results, nil
+}
+
+// openJEPXCSV opens the all-history JEPX CSV over HTTP, or the bundled sample
+// when HTTP is off or failed (unless NoFallback), recording source and mode on res.
+func (p *Pipeline) openJEPXCSV(res *Result, start time.Time) (io.ReadCloser, error) {
+	var reader io.ReadCloser
+	var err error
+
+	if p.cfg.UseHTTP {
+		fetcher := pkghttp.NewFetcher(pkghttp.DefaultConfig())
+		res.Source = "JEPX (japanesepower.org)"
+
+		p.cfg.Logger.Info(fmt.Sprintf("Attempting HTTP fetch from %s", jepxCSVURL))
+		reader, err = fetcher.Fetch(jepxCSVURL)
+
+		if err != nil && p.cfg.NoFallback {
+			p.cfg.Logger.LogFetch(res.Source, "failure", "", "HTTP fetch failed", time.Since(start), err)
+			return nil, err
+		} else if err != nil {
+			p.cfg.Logger.LogFetch(res.Source, "failure", "", "HTTP fetch failed, falling back to testdata", time.Since(start), err)
+			res.FallbackErr = err
+		} else {
+			p.cfg.Logger.LogFetch(res.Source, "success", "", "HTTP fetch successful", time.Since(start), nil)
+			res.Mode = ModeHTTP
+		}
+	}
+
+	// Fallback to testdata if HTTP failed or not requested
+	if res.Mode != ModeHTTP {
+		res.Mode = ModeTestdata
+		res.Source = "JEPX (testdata)"
+
+		reader, err = p.openTestdata("jepx-sample.csv")
+		if err != nil {
+			return nil, fmt.Errorf("failed to open testdata CSV: %w", err)
+		}
+	}
+
+	return reader, nil
+}
@@ -0,0 +1,111 @@
+package pipeline
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/teo/aversome/backend/internal/areas"
+	"github.com/teo/aversome/backend/internal/emission"
+	"github.com/teo/aversome/backend/internal/generation"
+	"github.com/teo/aversome/backend/internal/marginal"
+	"github.com/teo/aversome/backend/internal/storage"
+	"github.com/teo/aversome/backend/pkg/timeutil"
+)
+
+// MarginalResult is the outcome of a marginal emission factor job.
+type MarginalResult struct {
+	Result
+	Response *marginal.Response `json:"-"`
+}
+
+// EstimateMarginal estimates the marginal emission factors of an area for a
+// date from the stored generation mixes of the marginal.DefaultWindowDays
+// ending on it, under factors (Config.EmissionFactors if nil). Estimates
+// under Config.EmissionFactors are saved; the store keeps one per area and
+// date, so those under other sets are only returned. A window without any
+// stored generation mix is reported as a StageLoad error wrapping
+// storage.ErrNotFound.
+func (p *Pipeline) EstimateMarginal(a areas.Area, date string, factors *emission.FactorSet) (*MarginalResult, error) {
+	start := time.Now()
+	area := string(a.Code)
+
+	if err := validateArea(storage.DatasetMarginal, a, date); err != nil {
+		return nil, err
+	}
+	parsedDate, err := validateDate(storage.DatasetMarginal, area, date)
+	if err != nil {
+		return nil, err
+	}
+
+	fail := func(stage Stage, err error) (*MarginalResult, error) {
+		return nil, &Error{Stage: stage, Dataset: storage.DatasetMarginal, Area: area, Date: date, Err: err}
+	}
+
+	from := timeutil.FormatDate(parsedDate.AddDate(0, 0, -(marginal.DefaultWindowDays - 1)))
+	docs, err := p.cfg.Store.LoadRange(storage.DatasetGeneration, area, from, date)
+	if err != nil {
+		return fail(StageLoad, err)
+	}
+	if len(docs) == 0 {
+		return fail(StageLoad, fmt.Errorf("load %s %s to %s: %w", storage.DatasetGeneration, from, date, storage.ErrNotFound))
+	}
+
+	days := make([]*generation.Response, len(docs))
+	for i, doc := range docs {
+		days[i] = &generation.Response{}
+		if err := json.Unmarshal(doc.Data, days[i]); err != nil {
+			return fail(StageLoad, fmt.Errorf("parse %s %s JSON: %w", storage.DatasetGeneration, doc.Date, err))
+		}
+	}
+
+	if factors == nil {
+		factors = p.cfg.EmissionFactors
+	}
+	resp, err := marginal.Estimate(area, date, marginal.DefaultWindowDays, days, factors)
+	if err != nil {
+		return fail(StageEstimate, err)
+	}
+
+	res := &MarginalResult{
+		Result: Result{
+			Dataset: storage.DatasetMarginal,
+			Area:    area,
+			Date:    date,
+			Source:  "generation",
+			Mode:    ModeEstimated,
+			Points:  len(resp.Hours),
+		},
+		Response: resp,
+	}
+	if resp.Meta != nil {
+		res.Warning = resp.Meta.Warning
+	}
+
+	if factors.ID != p.cfg.EmissionFactors.ID {
+		res.Duration = time.Since(start)
+		return res, nil
+	}
+	if err := p.save(&res.Result, resp, start); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// MarginalStale reports whether a stored estimate left out generation mixes
+// stored in its window since, e.g. one made from the requested day alone
+// before the history was backfilled.
+func (p *Pipeline) MarginalStale(resp *marginal.Response) (bool, error) {
+	dates, err := p.cfg.Store.ListDates(storage.DatasetGeneration, resp.Area)
+	if err != nil {
+		return false, err
+	}
+	stored := 0
+	for _, d := range dates {
+		// YYYY-MM-DD compares lexically
+		if d >= resp.WindowFrom && d <= resp.Date {
+			stored++
+		}
+	}
+	return stored != resp.DaysUsed, nil
+}
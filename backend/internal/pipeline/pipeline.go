@@ -0,0 +1,180 @@
+// Package pipeline implements the fetch → adapter → persist jobs shared by the
+// cmd/fetch-* CLIs and the API server. Every job returns a typed result and a
+// *pipeline.Error describing the stage that failed.
+package pipeline
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/teo/aversome/backend/internal/areas"
+	"github.com/teo/aversome/backend/internal/emission"
+	"github.com/teo/aversome/backend/internal/reserve"
+	"github.com/teo/aversome/backend/internal/storage"
+	"github.com/teo/aversome/backend/pkg/logger"
+	"github.com/teo/aversome/backend/pkg/timeutil"
+)
+
+// Mode records where the data of a job came from.
+type Mode string
+
+const (
+	ModeHTTP      Mode = "http"      // Live source
+	ModeTestdata  Mode = "testdata"  // Bundled sample CSV
+	ModeEstimated Mode = "estimated" // Derived from other stored datasets
+)
+
+// Stage names the step of a job that failed.
+type Stage string
+
+const (
+	StageValidate Stage = "validate" // Invalid input (area, date, unsupported mode)
+	StageFetch    Stage = "fetch"    // HTTP download or testdata open
+	StageParse    Stage = "parse"    // Adapter rejected the CSV
+	StageLoad     Stage = "load"     // Reading a stored dataset
+	StageEstimate Stage = "estimate" // Estimation from stored datasets
+	StageSave     Stage = "save"     // Persisting the result
+)
+
+// Error describes a failed job.
+type Error struct {
+	Stage   Stage
+	Dataset storage.Dataset
+	Area    string // Empty for system-wide datasets
+	Date    string
+	Err     error
+}
+
+func (e *Error) Error() string {
+	target := e.Date
+	if e.Area != "" {
+		target = e.Area + "/" + e.Date
+	}
+	return fmt.Sprintf("%s %s: %s failed: %v", e.Dataset, target, e.Stage, e.Err)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Result summarizes a completed job.
+type Result struct {
+	Dataset     storage.Dataset `json:"dataset"`
+	Area        string          `json:"area,omitempty"`
+	Date        string          `json:"date"`
+	Source      string          `json:"source"`             // e.g., "OCCTO", "JEPX (testdata)"
+	Mode        Mode            `json:"mode"`               // http, testdata or estimated
+	FallbackErr error           `json:"-"`                  // HTTP error that triggered the testdata fallback
+	Location    string          `json:"location,omitempty"` // Where the document was saved
+	Points      int             `json:"points"`             // Series points (areas for reserve)
+	Warning     string          `json:"warning,omitempty"`  // Data quality warning from the adapter
+	Duration    time.Duration   `json:"duration"`
+}
+
+// Config configures a Pipeline.
+type Config struct {
+	UseHTTP     bool           // Fetch live data (falls back to testdata on failure where supported)
+	NoFallback  bool           // With UseHTTP, fail instead of falling back to testdata
+	TestdataDir string         // Directory with bundled sample CSVs
+	Store       storage.Store  // Where documents are loaded from and saved to
+	Logger      *logger.Logger // Structured logger for fetch events
+
+	ReservePolicy   *reserve.Policy     // Reserve status tiers (reserve.DefaultPolicy if nil)
+	EmissionFactors *emission.FactorSet // Carbon intensity of generation mixes (emission.DefaultSet if nil)
+
+	// AfterSave, if set, runs after every saved document (e.g., alert evaluation)
+	AfterSave func(res *Result, doc any)
+}
+
+// DefaultConfig returns a testdata-mode config writing to public/data/jp,
+// with paths relative to the backend directory.
+func DefaultConfig() Config {
+	return Config{
+		TestdataDir: filepath.Join("internal", "adapters", "testdata"),
+		Store:       storage.NewFileStore(storage.DefaultDataDir),
+		Logger:      logger.New(false),
+	}
+}
+
+// Pipeline runs fetch jobs.
+type Pipeline struct {
+	cfg Config
+}
+
+// New creates a Pipeline, filling unset fields from DefaultConfig.
+func New(cfg Config) *Pipeline {
+	def := DefaultConfig()
+	if cfg.TestdataDir == "" {
+		cfg.TestdataDir = def.TestdataDir
+	}
+	if cfg.Store == nil {
+		cfg.Store = def.Store
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = def.Logger
+	}
+	if cfg.ReservePolicy == nil {
+		cfg.ReservePolicy = reserve.DefaultPolicy()
+	}
+	if cfg.EmissionFactors == nil {
+		cfg.EmissionFactors = emission.DefaultSet()
+	}
+	return &Pipeline{cfg: cfg}
+}
+
+// Store returns the store the pipeline loads from and persists to.
+func (p *Pipeline) Store() storage.Store {
+	return p.cfg.Store
+}
+
+// validateDate checks a YYYY-MM-DD date and returns it parsed in Asia/Tokyo.
+func validateDate(dataset storage.Dataset, area, date string) (time.Time, error) {
+	t, err := timeutil.ParseDate(date)
+	if err != nil {
+		return time.Time{}, &Error{Stage: StageValidate, Dataset: dataset, Area: area, Date: date, Err: err}
+	}
+	return t, nil
+}
+
+// validateArea checks that a is a registered area.
+func validateArea(dataset storage.Dataset, a areas.Area, date string) error {
+	if _, ok := areas.Lookup(a.Code); !ok {
+		return &Error{Stage: StageValidate, Dataset: dataset, Area: string(a.Code), Date: date,
+			Err: fmt.Errorf("unknown area %q", a.Code)}
+	}
+	return nil
+}
+
+// openTestdata opens a bundled sample CSV.
+func (p *Pipeline) openTestdata(name string) (io.ReadCloser, error) {
+	path := filepath.Join(p.cfg.TestdataDir, name)
+	p.cfg.Logger.Info(fmt.Sprintf("Using testdata: %s", path))
+	return os.Open(path)
+}
+
+// save persists doc and logs the outcome.
+func (p *Pipeline) save(res *Result, doc any, start time.Time) error {
+	location, err := p.cfg.Store.Save(res.Dataset, res.Area, res.Date, doc)
+	if err != nil {
+		return &Error{Stage: StageSave, Dataset: res.Dataset, Area: res.Area, Date: res.Date, Err: err}
+	}
+	res.Location = location
+	res.Duration = time.Since(start)
+
+	p.cfg.Logger.LogFetch(
+		res.Source,
+		"success",
+		location,
+		fmt.Sprintf("Successfully wrote %s data (%s mode)", res.Dataset, res.Mode),
+		res.Duration,
+		nil,
+	)
+
+	if p.cfg.AfterSave != nil {
+		p.cfg.AfterSave(res, doc)
+	}
+	return nil
+}
@@ -0,0 +1,522 @@
+package pipeline
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/teo/aversome/backend/internal/areas"
+	"github.com/teo/aversome/backend/internal/emission"
+	"github.com/teo/aversome/backend/internal/storage"
+)
+
+// newTestPipeline returns a testdata-mode pipeline writing to a temp directory.
+func newTestPipeline(t *testing.T) (*Pipeline, string) {
+	t.Helper()
+	dir := t.TempDir()
+	p := New(Config{
+		TestdataDir: filepath.Join("..", "adapters", "testdata"),
+		Store:       storage.NewFileStore(dir),
+	})
+	return p, dir
+}
+
+func mustArea(t *testing.T, code string) areas.Area {
+	t.Helper()
+	a, err := areas.Parse(code)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return a
+}
+
+func TestPipeline_FetchDemand(t *testing.T) {
+	tests := []struct {
+		area       string
+		wantSource string
+		wantPoints int
+	}{
+		{"tokyo", "TEPCO (testdata)", 48},
+		{"kansai", "Kansai (testdata)", 24},
+		{"kyushu", "OCCTO (testdata)", 48},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.area, func(t *testing.T) {
+			p, dir := newTestPipeline(t)
+
+			res, err := p.FetchDemand(mustArea(t, tt.area), "2025-10-24")
+			if err != nil {
+				t.Fatalf("FetchDemand() error = %v", err)
+			}
+
+			if res.Mode != ModeTestdata {
+				t.Errorf("Mode = %q, want %q", res.Mode, ModeTestdata)
+			}
+			if res.Source != tt.wantSource {
+				t.Errorf("Source = %q, want %q", res.Source, tt.wantSource)
+			}
+			if res.Points != tt.wantPoints {
+				t.Errorf("Points = %d, want %d", res.Points, tt.wantPoints)
+			}
+
+			wantPath := filepath.Join(dir, tt.area, "demand-2025-10-24.json")
+			if res.Location != wantPath {
+				t.Errorf("Location = %q, want %q", res.Location, wantPath)
+			}
+			if _, err := os.Stat(wantPath); err != nil {
+				t.Errorf("output file not written: %v", err)
+			}
+		})
+	}
+}
+
+func TestPipeline_FetchJEPXAndReserve(t *testing.T) {
+	p, dir := newTestPipeline(t)
+
+	jepxRes, err := p.FetchJEPX(mustArea(t, "tokyo"), "2025-10-24")
+	if err != nil {
+		t.Fatalf("FetchJEPX() error = %v", err)
+	}
+	if jepxRes.Points != 48 {
+		t.Errorf("JEPX Points = %d, want 48", jepxRes.Points)
+	}
+	if want := filepath.Join(dir, "jepx", "spot-tokyo-2025-10-24.json"); jepxRes.Location != want {
+		t.Errorf("JEPX Location = %q, want %q", jepxRes.Location, want)
+	}
+
+	reserveRes, err := p.FetchReserve("2025-10-24")
+	if err != nil {
+		t.Fatalf("FetchReserve() error = %v", err)
+	}
+	if reserveRes.Points != 10 {
+		t.Errorf("reserve Points = %d, want 10", reserveRes.Points)
+	}
+	if reserveRes.Response.Source.Name != "OCCTO (testdata)" {
+		t.Errorf("reserve Source.Name = %q", reserveRes.Response.Source.Name)
+	}
+	if meta := reserveRes.Response.Meta; meta == nil || meta.Policy == nil || meta.Policy.ID != "default" {
+		t.Errorf("reserve meta.policy = %+v, want default", meta)
+	}
+}
+
+func TestPipeline_EstimateGeneration(t *testing.T) {
+	p, _ := newTestPipeline(t)
+	kyushu := mustArea(t, "kyushu")
+
+	// Inputs not stored yet
+	_, err := p.EstimateGeneration(kyushu, "2025-10-24")
+	if !errors.Is(err, storage.ErrNotFound) {
+		t.Fatalf("EstimateGeneration() error = %v, want storage.ErrNotFound", err)
+	}
+
+	if _, err := p.FetchDemand(kyushu, "2025-10-24"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := p.FetchJEPX(kyushu, "2025-10-24"); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := p.EstimateGeneration(kyushu, "2025-10-24")
+	if err != nil {
+		t.Fatalf("EstimateGeneration() error = %v", err)
+	}
+	if res.Mode != ModeEstimated {
+		t.Errorf("Mode = %q, want %q", res.Mode, ModeEstimated)
+	}
+	if res.Points != 48 {
+		t.Errorf("Points = %d, want 48 (30-min demand and prices)", res.Points)
+	}
+	if meta := res.Response.Meta; meta == nil || meta.EmissionFactorSet != "default" {
+		t.Errorf("Meta = %+v, want emission factor set default", meta)
+	}
+}
+
+func TestPipeline_Errors(t *testing.T) {
+	p, _ := newTestPipeline(t)
+
+	tests := []struct {
+		name      string
+		run       func() error
+		wantStage Stage
+		wantErr   error
+	}{
+		{
+			name: "invalid date",
+			run: func() error {
+				_, err := p.FetchDemand(mustArea(t, "tokyo"), "2025/10/24")
+				return err
+			},
+			wantStage: StageValidate,
+		},
+		{
+			name: "okinawa has no JEPX price",
+			run: func() error {
+				_, err := p.FetchJEPX(mustArea(t, "okinawa"), "2025-10-24")
+				return err
+			},
+			wantStage: StageValidate,
+		},
+		{
+			name: "generation mix has no testdata",
+			run: func() error {
+				_, err := p.FetchGeneration(mustArea(t, "tokyo"), "2025-10-24")
+				return err
+			},
+			wantStage: StageValidate,
+			wantErr:   ErrNoTestdata,
+		},
+		{
+			name: "date missing from JEPX sample",
+			run: func() error {
+				_, err := p.FetchJEPX(mustArea(t, "tokyo"), "2020-01-01")
+				return err
+			},
+			wantStage: StageParse,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.run()
+
+			var pipeErr *Error
+			if !errors.As(err, &pipeErr) {
+				t.Fatalf("error = %v, want *pipeline.Error", err)
+			}
+			if pipeErr.Stage != tt.wantStage {
+				t.Errorf("Stage = %q, want %q", pipeErr.Stage, tt.wantStage)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPipeline_Backfill(t *testing.T) {
+	p, _ := newTestPipeline(t)
+	tokyo := mustArea(t, "tokyo")
+
+	// 10-23 already stored; 10-24 stored with a warning
+	if _, err := p.FetchJEPX(tokyo, "2025-10-23"); err != nil {
+		t.Fatal(err)
+	}
+	flagged := map[string]any{"meta": map[string]string{"warning": "partial data"}}
+	if _, err := p.Store().Save(storage.DatasetJEPX, "tokyo", "2025-10-24", flagged); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := BackfillConfig{
+		From:     "2025-10-22",
+		To:       "2025-10-24",
+		Datasets: []storage.Dataset{storage.DatasetJEPX},
+		Areas:    []areas.Area{tokyo, mustArea(t, "okinawa")}, // Okinawa has no JEPX price
+	}
+
+	dry := cfg
+	dry.DryRun = true
+	report, err := p.Backfill(dry)
+	if err != nil {
+		t.Fatalf("Backfill(dry run) error = %v", err)
+	}
+	if report.Planned != 2 || report.Skipped != 1 || len(report.Items) != 3 {
+		t.Fatalf("dry run planned=%d skipped=%d items=%d, want 2/1/3", report.Planned, report.Skipped, len(report.Items))
+	}
+
+	report, err = p.Backfill(cfg)
+	if err != nil {
+		t.Fatalf("Backfill() error = %v", err)
+	}
+
+	want := map[string]struct {
+		reason string
+		status BackfillStatus
+	}{
+		"2025-10-22": {ReasonMissing, BackfillFilled},
+		"2025-10-23": {ReasonStored, BackfillSkipped},
+		"2025-10-24": {ReasonWarning, BackfillFilled},
+	}
+	for _, item := range report.Items {
+		w := want[item.Date]
+		if item.Reason != w.reason || item.Status != w.status {
+			t.Errorf("%s: reason=%q status=%q (err %v), want %q/%q", item.Date, item.Reason, item.Status, item.Err, w.reason, w.status)
+		}
+	}
+	if report.Filled != 2 || report.Skipped != 1 || report.Failed != 0 {
+		t.Errorf("filled=%d skipped=%d failed=%d, want 2/1/0", report.Filled, report.Skipped, report.Failed)
+	}
+
+	if _, err := p.Backfill(BackfillConfig{From: "2025-10-22", To: "2025-10-24", Datasets: []storage.Dataset{storage.DatasetWeather}}); err == nil {
+		t.Error("Backfill(weather) expected error")
+	}
+}
+
+func TestPipeline_FetchJEPXRange(t *testing.T) {
+	p, _ := newTestPipeline(t)
+
+	var all []areas.Area
+	for _, a := range areas.All() {
+		if a.HasJEPXPrice() {
+			all = append(all, a)
+		}
+	}
+
+	results, err := p.FetchJEPXRange("2025-10-22", "2025-10-25", all, nil)
+	if err != nil {
+		t.Fatalf("FetchJEPXRange() error = %v", err)
+	}
+	if len(results) != 27 {
+		t.Errorf("got %d results, want 27 (3 dates × 9 areas)", len(results))
+	}
+
+	dates, err := p.Store().ListDates(storage.DatasetJEPX, "kyushu")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dates) != 3 {
+		t.Errorf("stored kyushu dates = %v, want 3", dates)
+	}
+
+	// want limits what is saved
+	p, _ = newTestPipeline(t)
+	results, err = p.FetchJEPXRange("2025-10-22", "2025-10-24", all, func(date, area string) bool {
+		return area == "tokyo" && date == "2025-10-23"
+	})
+	if err != nil {
+		t.Fatalf("FetchJEPXRange(want) error = %v", err)
+	}
+	if len(results) != 1 || results[0].Points != 48 || results[0].Mode != ModeTestdata {
+		t.Fatalf("FetchJEPXRange(want) = %+v, want one 48-point testdata result", results)
+	}
+
+	_, err = p.FetchJEPXRange("2025-10-22", "2025-10-24", []areas.Area{mustArea(t, "okinawa")}, nil)
+	var pipeErr *Error
+	if !errors.As(err, &pipeErr) || pipeErr.Stage != StageValidate {
+		t.Errorf("FetchJEPXRange(okinawa) error = %v, want validate stage", err)
+	}
+}
+
+func TestPipeline_FetchJEPXMarket(t *testing.T) {
+	p, _ := newTestPipeline(t)
+
+	results, err := p.FetchJEPXMarket("2025-10-22", "2025-10-24", nil)
+	if err != nil {
+		t.Fatalf("FetchJEPXMarket() error = %v", err)
+	}
+	if len(results) != 3 || results[0].Points != 48 || len(results[0].Response.Areas) != 9 {
+		t.Fatalf("FetchJEPXMarket() = %d results, want 3 × 48 periods × 9 areas", len(results))
+	}
+
+	if _, err := p.Store().Load(storage.DatasetJEPXMarket, "", "2025-10-24"); err != nil {
+		t.Errorf("Load(jepx_market) error = %v", err)
+	}
+
+	_, err = p.FetchJEPXMarket("2025-12-01", "2025-12-01", nil)
+	var pipeErr *Error
+	if !errors.As(err, &pipeErr) || pipeErr.Stage != StageParse {
+		t.Errorf("FetchJEPXMarket(no data) error = %v, want parse stage", err)
+	}
+}
+
+func TestPipeline_FetchJEPXIntraday(t *testing.T) {
+	p, _ := newTestPipeline(t)
+
+	res, err := p.FetchJEPXIntraday("2025-10-24")
+	if err != nil {
+		t.Fatalf("FetchJEPXIntraday() error = %v", err)
+	}
+	if res.Points != 48 || res.Mode != ModeTestdata || res.Warning == "" {
+		t.Errorf("FetchJEPXIntraday() = %+v, want 48 testdata products with an untraded warning", res.Result)
+	}
+	if _, err := p.Store().Load(storage.DatasetIntraday, "", "2025-10-24"); err != nil {
+		t.Errorf("Load(jepx_intraday) error = %v", err)
+	}
+
+	_, err = p.FetchJEPXIntraday("2025-12-01")
+	var pipeErr *Error
+	if !errors.As(err, &pipeErr) || pipeErr.Stage != StageParse {
+		t.Errorf("FetchJEPXIntraday(no data) error = %v, want parse stage", err)
+	}
+}
+
+func TestPipeline_FetchImbalance(t *testing.T) {
+	p, dir := newTestPipeline(t)
+
+	res, err := p.FetchImbalance(mustArea(t, "kansai"), "2025-10-23")
+	if err != nil {
+		t.Fatalf("FetchImbalance() error = %v", err)
+	}
+	if res.Points != 48 || res.Mode != ModeTestdata {
+		t.Errorf("FetchImbalance() = %+v, want 48 testdata prices", res.Result)
+	}
+	if want := filepath.Join(dir, "kansai", "imbalance-2025-10-23.json"); res.Location != want {
+		t.Errorf("Location = %s, want %s", res.Location, want)
+	}
+}
+
+func TestPipeline_FetchReserveForecast(t *testing.T) {
+	p, dir := newTestPipeline(t)
+
+	res, err := p.FetchReserveNextDay("2025-10-24")
+	if err != nil {
+		t.Fatalf("FetchReserveNextDay() error = %v", err)
+	}
+	if res.Points != 10 || res.Mode != ModeTestdata {
+		t.Errorf("FetchReserveNextDay() = %+v, want 10 testdata areas", res.Result)
+	}
+	if f := res.Response; f.IssuedAt != "2025-10-23T17:00:00+09:00" || f.Meta == nil || f.Meta.Policy == nil {
+		t.Errorf("next-day forecast issued_at = %q meta = %+v, want 2025-10-23 17:00 with a policy", f.IssuedAt, f.Meta)
+	}
+	if want := filepath.Join(dir, "system", "reserve-nextday-2025-10-24.json"); res.Location != want {
+		t.Errorf("Location = %s, want %s", res.Location, want)
+	}
+
+	// One weekly outlook covers the seven days after publication
+	results, err := p.FetchReserveWeekly("2025-10-20")
+	if err != nil {
+		t.Fatalf("FetchReserveWeekly() error = %v", err)
+	}
+	if len(results) != 7 || results[0].Date != "2025-10-21" || results[6].Date != "2025-10-27" {
+		t.Fatalf("FetchReserveWeekly() = %d results, want 2025-10-21..2025-10-27", len(results))
+	}
+	if _, err := p.Store().Load(storage.DatasetReserveWeekly, "", "2025-10-24"); err != nil {
+		t.Errorf("Load(reserve_weekly) error = %v", err)
+	}
+}
+
+func TestPipeline_AfterSave(t *testing.T) {
+	var saved []storage.Dataset
+	var doc any
+	p := New(Config{
+		TestdataDir: filepath.Join("..", "adapters", "testdata"),
+		Store:       storage.NewFileStore(t.TempDir()),
+		AfterSave: func(res *Result, d any) {
+			saved = append(saved, res.Dataset)
+			doc = d
+		},
+	})
+
+	res, err := p.FetchReserve("2025-10-24")
+	if err != nil {
+		t.Fatalf("FetchReserve() error = %v", err)
+	}
+	if len(saved) != 1 || saved[0] != storage.DatasetReserve {
+		t.Errorf("AfterSave datasets = %v, want [reserve]", saved)
+	}
+	if doc != res.Response {
+		t.Errorf("AfterSave doc = %T, want the saved response", doc)
+	}
+}
+
+func TestPipeline_FetchInterconnector(t *testing.T) {
+	p, dir := newTestPipeline(t)
+
+	res, err := p.FetchInterconnector("2025-10-24")
+	if err != nil {
+		t.Fatalf("FetchInterconnector() error = %v", err)
+	}
+	if res.Points != 480 || res.Mode != ModeTestdata {
+		t.Errorf("FetchInterconnector() = %+v, want 10 lines x 48 testdata intervals", res.Result)
+	}
+	if l := res.Response.Line("tohoku-tokyo"); l == nil || l.CongestedIntervals == 0 {
+		t.Errorf("tohoku-tokyo = %+v, want congested intervals", l)
+	}
+	if want := filepath.Join(dir, "system", "interconnector-2025-10-24.json"); res.Location != want {
+		t.Errorf("Location = %s, want %s", res.Location, want)
+	}
+}
+
+func TestPipeline_FetchCurtailment(t *testing.T) {
+	p, dir := newTestPipeline(t)
+
+	res, err := p.FetchCurtailment(mustArea(t, "kyushu"), "2025-10-24")
+	if err != nil {
+		t.Fatalf("FetchCurtailment() error = %v", err)
+	}
+	if res.Points != 3 || res.Mode != ModeTestdata || res.Response.Summary.CurtailedMWh == 0 {
+		t.Errorf("FetchCurtailment() = %+v, want 3 testdata events", res.Result)
+	}
+	if want := filepath.Join(dir, "kyushu", "curtailment-2025-10-24.json"); res.Location != want {
+		t.Errorf("Location = %s, want %s", res.Location, want)
+	}
+
+	// Days without curtailment are stored as such
+	res, err = p.FetchCurtailment(mustArea(t, "tokyo"), "2025-10-24")
+	if err != nil || res.Points != 0 {
+		t.Errorf("FetchCurtailment(tokyo) = %+v, %v; want no events", res, err)
+	}
+
+	// Dates the sample does not cover are not saved as days without curtailment
+	_, err = p.FetchCurtailment(mustArea(t, "kyushu"), "2025-10-22")
+	if !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("FetchCurtailment(2025-10-22) error = %v, want ErrNotFound", err)
+	}
+	if _, err := p.Store().Load(storage.DatasetCurtailment, "kyushu", "2025-10-22"); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("Load(2025-10-22) error = %v, want nothing saved", err)
+	}
+}
+
+func TestPipeline_EstimateMarginal(t *testing.T) {
+	p, dir := newTestPipeline(t)
+	kyushu := mustArea(t, "kyushu")
+
+	// No generation mix stored in the window
+	_, err := p.EstimateMarginal(kyushu, "2025-10-24", nil)
+	if !errors.Is(err, storage.ErrNotFound) {
+		t.Fatalf("EstimateMarginal() error = %v, want storage.ErrNotFound", err)
+	}
+
+	if _, err := p.FetchDemand(kyushu, "2025-10-24"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := p.FetchJEPX(kyushu, "2025-10-24"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := p.EstimateGeneration(kyushu, "2025-10-24"); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := p.EstimateMarginal(kyushu, "2025-10-24", nil)
+	if err != nil {
+		t.Fatalf("EstimateMarginal() error = %v", err)
+	}
+	if res.Points != 24 || res.Response.DaysUsed != 1 || res.Response.EmissionFactorSet != "default" {
+		t.Errorf("EstimateMarginal() = %+v, want 24 hours from 1 day under the default set", res.Result)
+	}
+	if res.Response.EstimatedDays != 1 || !strings.Contains(res.Warning, "estimated generation mix") {
+		t.Errorf("EstimateMarginal() = %d estimated days, warning %q; want the estimated mix flagged", res.Response.EstimatedDays, res.Warning)
+	}
+	if want := filepath.Join(dir, "kyushu", "marginal-2025-10-24.json"); res.Location != want {
+		t.Errorf("Location = %s, want %s", res.Location, want)
+	}
+	if stale, err := p.MarginalStale(res.Response); err != nil || stale {
+		t.Errorf("MarginalStale() = %v, %v; want fresh", stale, err)
+	}
+
+	// Estimates under another factor set are not saved over the default one
+	lifecycle, _ := emission.DefaultRegistry().Lookup("criepi-2016-lifecycle")
+	res, err = p.EstimateMarginal(kyushu, "2025-10-24", lifecycle)
+	if err != nil || res.Response.EmissionFactorSet != "criepi-2016-lifecycle" || res.Location != "" {
+		t.Fatalf("EstimateMarginal(other) = %+v, %v; want an unsaved estimate", res, err)
+	}
+	data, err := p.Store().Load(storage.DatasetMarginal, "kyushu", "2025-10-24")
+	if err != nil || !strings.Contains(string(data), `"emission_factor_set": "default"`) {
+		t.Errorf("stored estimate = %.120s, %v; want the default set", data, err)
+	}
+
+	// A generation mix stored later in the window makes the estimate stale
+	data, err = p.Store().Load(storage.DatasetGeneration, "kyushu", "2025-10-24")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := p.Store().Save(storage.DatasetGeneration, "kyushu", "2025-10-23", json.RawMessage(data)); err != nil {
+		t.Fatal(err)
+	}
+	if stale, err := p.MarginalStale(res.Response); err != nil || !stale {
+		t.Errorf("MarginalStale() = %v, %v; want stale", stale, err)
+	}
+}
@@ -0,0 +1,92 @@
+package pipeline
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/teo/aversome/backend/internal/adapters"
+	"github.com/teo/aversome/backend/internal/reserve"
+	"github.com/teo/aversome/backend/internal/storage"
+	pkghttp "github.com/teo/aversome/backend/pkg/http"
+)
+
+// ReserveResult is the outcome of a reserve margin job.
+type ReserveResult struct {
+	Result
+	Response *reserve.Response `json:"-"`
+}
+
+// FetchReserve fetches, normalizes and saves the system-wide OCCTO reserve
+// margin for a date, classified under Config.ReservePolicy. HTTP failures
+// fall back to the bundled testdata.
+func (p *Pipeline) FetchReserve(date string) (*ReserveResult, error) {
+	start := time.Now()
+
+	parsedDate, err := validateDate(storage.DatasetReserve, "", date)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &ReserveResult{Result: Result{Dataset: storage.DatasetReserve, Date: date}}
+	fail := func(stage Stage, err error) (*ReserveResult, error) {
+		return nil, &Error{Stage: stage, Dataset: storage.DatasetReserve, Date: date, Err: err}
+	}
+
+	var reader io.ReadCloser
+
+	if p.cfg.UseHTTP {
+		// OCCTO blocks non-browser clients
+		fetcher := pkghttp.NewFetcher(pkghttp.BrowserConfig())
+		url := occtoURL("02", parsedDate)
+		res.Source = "OCCTO"
+
+		p.cfg.Logger.Info(fmt.Sprintf("Attempting HTTP fetch from %s", url))
+		reader, err = fetcher.Fetch(url)
+
+		if err != nil && p.cfg.NoFallback {
+			p.cfg.Logger.LogFetch(res.Source, "failure", "", "HTTP fetch failed", time.Since(start), err)
+			return fail(StageFetch, err)
+		} else if err != nil {
+			p.cfg.Logger.LogFetch(res.Source, "failure", "", "HTTP fetch failed, falling back to testdata", time.Since(start), err)
+			res.FallbackErr = err
+		} else {
+			p.cfg.Logger.LogFetch(res.Source, "success", "", "HTTP fetch successful", time.Since(start), nil)
+			res.Mode = ModeHTTP
+		}
+	}
+
+	// Fallback to testdata if HTTP failed or not requested
+	if res.Mode != ModeHTTP {
+		res.Mode = ModeTestdata
+		res.Source = "OCCTO (testdata)"
+
+		reader, err = p.openTestdata("occto-sample.csv")
+		if err != nil {
+			return fail(StageFetch, fmt.Errorf("failed to open testdata CSV: %w", err))
+		}
+	}
+	defer reader.Close()
+
+	// Parse CSV using OCCTO adapter
+	resp, err := adapters.NewOCCTOAdapter().ParseCSV(reader, date)
+	if err != nil {
+		p.cfg.Logger.LogFetch(res.Source, "failure", "", "CSV parsing failed", time.Since(start), err)
+		return fail(StageParse, err)
+	}
+	resp.Source.Name = res.Source
+
+	// Status and tier under the configured policy (recorded in meta.policy)
+	p.cfg.ReservePolicy.Apply(resp)
+
+	res.Response = resp
+	res.Points = len(resp.Areas)
+	if resp.Meta != nil {
+		res.Warning = resp.Meta.Warning
+	}
+
+	if err := p.save(&res.Result, resp, start); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
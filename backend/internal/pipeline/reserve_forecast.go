@@ -0,0 +1,154 @@
+package pipeline
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/teo/aversome/backend/internal/adapters"
+	"github.com/teo/aversome/backend/internal/reserve"
+	"github.com/teo/aversome/backend/internal/storage"
+	pkghttp "github.com/teo/aversome/backend/pkg/http"
+)
+
+// OCCTO download kinds (jhSybt) of the supply-demand outlooks.
+const (
+	occtoKindNextDay = "05" // 翌日の需給見通し (30-minute, by target date)
+	occtoKindWeekly  = "06" // 週間需給見通し (tightest interval per day, by publication date)
+)
+
+// ReserveForecastResult is the outcome of a reserve forecast job for one target date.
+type ReserveForecastResult struct {
+	Result
+	Response *reserve.Forecast `json:"-"`
+}
+
+// FetchReserveNextDay fetches, normalizes and saves the OCCTO next-day
+// reserve outlook for a target date, classified under Config.ReservePolicy.
+// HTTP failures fall back to the bundled testdata.
+func (p *Pipeline) FetchReserveNextDay(date string) (*ReserveForecastResult, error) {
+	start := time.Now()
+
+	parsedDate, err := validateDate(storage.DatasetReserveNextDay, "", date)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &ReserveForecastResult{Result: Result{Dataset: storage.DatasetReserveNextDay, Date: date}}
+	fail := func(stage Stage, err error) (*ReserveForecastResult, error) {
+		return nil, &Error{Stage: stage, Dataset: storage.DatasetReserveNextDay, Date: date, Err: err}
+	}
+
+	reader, err := p.openOCCTOOutlook(&res.Result, occtoURL(occtoKindNextDay, parsedDate), "occto-nextday-sample.csv", start)
+	if err != nil {
+		return fail(StageFetch, err)
+	}
+	defer reader.Close()
+
+	// Parse CSV using OCCTO adapter
+	f, err := adapters.NewOCCTOAdapter().ParseNextDayCSV(reader, date)
+	if err != nil {
+		p.cfg.Logger.LogFetch(res.Source, "failure", "", "CSV parsing failed", time.Since(start), err)
+		return fail(StageParse, err)
+	}
+	f.Source.Name = res.Source
+	p.cfg.ReservePolicy.Apply(&f.Response)
+
+	res.Response = f
+	res.Points = len(f.Areas)
+	if f.Meta != nil {
+		res.Warning = f.Meta.Warning
+	}
+
+	if err := p.save(&res.Result, f, start); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// FetchReserveWeekly fetches the OCCTO weekly reserve outlook published on
+// issued and saves one document per target date it covers, replacing older
+// outlooks for those dates. HTTP failures fall back to the bundled testdata.
+func (p *Pipeline) FetchReserveWeekly(issued string) ([]*ReserveForecastResult, error) {
+	start := time.Now()
+
+	parsedDate, err := validateDate(storage.DatasetReserveWeekly, "", issued)
+	if err != nil {
+		return nil, err
+	}
+
+	fail := func(stage Stage, err error) ([]*ReserveForecastResult, error) {
+		return nil, &Error{Stage: stage, Dataset: storage.DatasetReserveWeekly, Date: issued, Err: err}
+	}
+
+	var fetched Result
+	reader, err := p.openOCCTOOutlook(&fetched, occtoURL(occtoKindWeekly, parsedDate), "occto-weekly-sample.csv", start)
+	if err != nil {
+		return fail(StageFetch, err)
+	}
+	defer reader.Close()
+
+	forecasts, err := adapters.NewOCCTOAdapter().ParseWeeklyCSV(reader)
+	if err != nil {
+		p.cfg.Logger.LogFetch(fetched.Source, "failure", "", "CSV parsing failed", time.Since(start), err)
+		return fail(StageParse, err)
+	}
+
+	results := make([]*ReserveForecastResult, 0, len(forecasts))
+	for _, f := range forecasts {
+		f.Source.Name = fetched.Source
+		p.cfg.ReservePolicy.Apply(&f.Response)
+
+		res := &ReserveForecastResult{Result: fetched, Response: f}
+		res.Dataset, res.Date = storage.DatasetReserveWeekly, f.Date
+		res.Points = len(f.Areas)
+		if f.Meta != nil {
+			res.Warning = f.Meta.Warning
+		}
+
+		if err := p.save(&res.Result, f, start); err != nil {
+			return nil, err
+		}
+		results = append(results, res)
+	}
+	return results, nil
+}
+
+// openOCCTOOutlook opens an OCCTO outlook CSV over HTTP, or the bundled sample
+// when HTTP is off or failed (unless NoFallback), recording source and mode on res.
+func (p *Pipeline) openOCCTOOutlook(res *Result, url, testdata string, start time.Time) (io.ReadCloser, error) {
+	var reader io.ReadCloser
+	var err error
+
+	if p.cfg.UseHTTP {
+		// OCCTO blocks non-browser clients
+		fetcher := pkghttp.NewFetcher(pkghttp.BrowserConfig())
+		res.Source = "OCCTO"
+
+		p.cfg.Logger.Info(fmt.Sprintf("Attempting HTTP fetch from %s", url))
+		reader, err = fetcher.Fetch(url)
+
+		if err != nil && p.cfg.NoFallback {
+			p.cfg.Logger.LogFetch(res.Source, "failure", "", "HTTP fetch failed", time.Since(start), err)
+			return nil, err
+		} else if err != nil {
+			p.cfg.Logger.LogFetch(res.Source, "failure", "", "HTTP fetch failed, falling back to testdata", time.Since(start), err)
+			res.FallbackErr = err
+		} else {
+			p.cfg.Logger.LogFetch(res.Source, "success", "", "HTTP fetch successful", time.Since(start), nil)
+			res.Mode = ModeHTTP
+		}
+	}
+
+	// Fallback to testdata if HTTP failed or not requested
+	if res.Mode != ModeHTTP {
+		res.Mode = ModeTestdata
+		res.Source = "OCCTO (testdata)"
+
+		reader, err = p.openTestdata(testdata)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open testdata CSV: %w", err)
+		}
+	}
+	return reader, nil
+}
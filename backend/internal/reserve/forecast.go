@@ -0,0 +1,146 @@
+package reserve
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Horizon is how far ahead a reserve forecast was published.
+type Horizon string
+
+const (
+	HorizonNextDay Horizon = "next_day" // 翌日の需給見通し: 30-minute intervals
+	HorizonWeekly  Horizon = "weekly"   // 週間需給見通し: tightest interval per day
+)
+
+// ParseHorizon validates a horizon name.
+func ParseHorizon(s string) (Horizon, error) {
+	switch h := Horizon(strings.ToLower(s)); h {
+	case HorizonNextDay, HorizonWeekly:
+		return h, nil
+	default:
+		return "", fmt.Errorf("invalid horizon %q (must be %s or %s)", s, HorizonNextDay, HorizonWeekly)
+	}
+}
+
+// Forecast is a reserve margin outlook for one target date, shaped like the
+// actuals. Next-day forecasts carry the 30-minute series and blocks; weekly
+// forecasts only the tightest interval, whose margin is also the area margin.
+// GET /api/reserve/:date/forecast?horizon=next_day|weekly
+type Forecast struct {
+	Response
+	Horizon  Horizon `json:"horizon"`
+	IssuedAt string  `json:"issued_at,omitempty"` // Publication time, ISO8601
+}
+
+// NewForecast creates a Forecast for a target date.
+func NewForecast(date string, horizon Horizon) *Forecast {
+	return &Forecast{Response: *NewResponse(date), Horizon: horizon}
+}
+
+// ComparisonPoint is the forecast and actual margin of one interval.
+type ComparisonPoint struct {
+	Timestamp   string  `json:"ts"`
+	ForecastPct float64 `json:"forecast_pct"`
+	ActualPct   float64 `json:"actual_pct"`
+	ErrorPct    float64 `json:"error_pct"` // Actual - forecast (percentage points)
+}
+
+// AreaComparison compares an area's forecast tightest interval with the
+// actual one, and for next-day forecasts every interval.
+type AreaComparison struct {
+	Area     string            `json:"area"`
+	Forecast *Minimum          `json:"forecast"`
+	Actual   *Minimum          `json:"actual"`
+	ErrorPct float64           `json:"error_pct"`         // Actual - forecast minimum margin (percentage points)
+	MAEPct   *float64          `json:"mae_pct,omitempty"` // Mean absolute interval error (next-day)
+	Series   []ComparisonPoint `json:"series,omitempty"`  // Intervals present in both
+}
+
+// ForecastComparison is a forecast set against the actual margins of its date.
+// GET /api/reserve/:date/compare?horizon=next_day|weekly
+type ForecastComparison struct {
+	Date     string           `json:"date"`
+	Horizon  Horizon          `json:"horizon"`
+	IssuedAt string           `json:"issued_at,omitempty"`
+	Areas    []AreaComparison `json:"areas"`
+	Sources  []Source         `json:"sources"` // Forecast, then actual
+	Meta     *Meta            `json:"meta,omitempty"`
+}
+
+// Compare sets a forecast against the actuals of the same date. Areas
+// without an actual daily minimum are skipped and listed in the warning.
+func Compare(f *Forecast, actual *Response) (*ForecastComparison, error) {
+	if f.Date != actual.Date {
+		return nil, fmt.Errorf("forecast date %s does not match actual date %s", f.Date, actual.Date)
+	}
+
+	out := &ForecastComparison{
+		Date:     f.Date,
+		Horizon:  f.Horizon,
+		IssuedAt: f.IssuedAt,
+		Areas:    make([]AreaComparison, 0, len(f.Areas)),
+		Sources:  []Source{f.Source},
+	}
+	if actual.Source != f.Source {
+		out.Sources = append(out.Sources, actual.Source)
+	}
+
+	actualByArea := make(map[string]AreaReserve, len(actual.Areas))
+	for _, a := range actual.Areas {
+		actualByArea[a.Area] = a
+	}
+
+	var missing []string
+	for _, fa := range f.Areas {
+		aa, ok := actualByArea[fa.Area]
+		if !ok || aa.Minimum == nil || fa.Minimum == nil {
+			missing = append(missing, fa.Area)
+			continue
+		}
+
+		cmp := AreaComparison{
+			Area:     fa.Area,
+			Forecast: fa.Minimum,
+			Actual:   aa.Minimum,
+			ErrorPct: aa.Minimum.ReserveMarginPct - fa.Minimum.ReserveMarginPct,
+		}
+
+		// Interval errors where both sides have the interval
+		actualPct := make(map[string]float64, len(aa.Series))
+		for _, p := range aa.Series {
+			actualPct[p.Timestamp] = p.ReserveMarginPct
+		}
+		var absSum float64
+		for _, p := range fa.Series {
+			pct, ok := actualPct[p.Timestamp]
+			if !ok {
+				continue
+			}
+			e := pct - p.ReserveMarginPct
+			absSum += math.Abs(e)
+			cmp.Series = append(cmp.Series, ComparisonPoint{
+				Timestamp:   p.Timestamp,
+				ForecastPct: p.ReserveMarginPct,
+				ActualPct:   pct,
+				ErrorPct:    e,
+			})
+		}
+		if n := len(cmp.Series); n > 0 {
+			mae := absSum / float64(n)
+			cmp.MAEPct = &mae
+		}
+
+		out.Areas = append(out.Areas, cmp)
+	}
+
+	if len(out.Areas) == 0 {
+		return nil, fmt.Errorf("no area has both a forecast and an actual minimum for %s", f.Date)
+	}
+	if len(missing) > 0 {
+		out.Meta = &Meta{Warning: fmt.Sprintf("No actuals for: %s", strings.Join(missing, ", "))}
+	}
+
+	return out, nil
+}
@@ -0,0 +1,70 @@
+package reserve
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/teo/aversome/backend/pkg/timeutil"
+)
+
+// areaDay builds an area with a series of (demand, capacity) intervals from 00:00.
+func areaDay(area string, intervals ...[2]float64) AreaReserve {
+	base, _ := timeutil.ParseDate("2025-10-24")
+	ar := AreaReserve{Area: area}
+	for i, mw := range intervals {
+		ar.Series = append(ar.Series, NewPoint(base.Add(time.Duration(i)*30*time.Minute), mw[0], mw[1]))
+	}
+	ar.Minimum = FindMinimum(ar.Series)
+	return ar
+}
+
+func TestCompare(t *testing.T) {
+	f := NewForecast("2025-10-24", HorizonNextDay)
+	f.IssuedAt = "2025-10-23T17:00:00+09:00"
+	f.Source = Source{Name: "OCCTO"}
+	f.Areas = []AreaReserve{
+		areaDay("tokyo", [2]float64{90, 100}, [2]float64{95, 100}),
+		areaDay("okinawa", [2]float64{80, 100}),
+	}
+
+	actual := NewResponse("2025-10-24")
+	actual.Source = Source{Name: "OCCTO"}
+	actual.Areas = []AreaReserve{areaDay("tokyo", [2]float64{92, 100}, [2]float64{98, 100})}
+
+	cmp, err := Compare(f, actual)
+	if err != nil {
+		t.Fatalf("Compare() error = %v", err)
+	}
+	if len(cmp.Areas) != 1 || len(cmp.Sources) != 1 || cmp.IssuedAt != f.IssuedAt {
+		t.Fatalf("Compare() = %+v, want tokyo only from one source", cmp)
+	}
+
+	tokyo := cmp.Areas[0]
+	// Tightest: forecast 5% at 00:30, actual 2% at 00:30
+	if math.Abs(tokyo.ErrorPct-(-3)) > 1e-9 {
+		t.Errorf("ErrorPct = %v, want -3", tokyo.ErrorPct)
+	}
+	if len(tokyo.Series) != 2 || tokyo.MAEPct == nil || math.Abs(*tokyo.MAEPct-2.5) > 1e-9 {
+		t.Errorf("series = %d points, MAE = %v, want 2 points, 2.5", len(tokyo.Series), tokyo.MAEPct)
+	}
+	if cmp.Meta == nil || !strings.Contains(cmp.Meta.Warning, "okinawa") {
+		t.Errorf("Meta = %+v, want a warning naming okinawa", cmp.Meta)
+	}
+
+	// Wrong date
+	actual.Date = "2025-10-25"
+	if _, err := Compare(f, actual); err == nil {
+		t.Error("Compare(mismatched dates) error = nil")
+	}
+}
+
+func TestParseHorizon(t *testing.T) {
+	if h, err := ParseHorizon("Weekly"); err != nil || h != HorizonWeekly {
+		t.Errorf("ParseHorizon(Weekly) = %v, %v", h, err)
+	}
+	if _, err := ParseHorizon("monthly"); err == nil {
+		t.Error("ParseHorizon(monthly) error = nil")
+	}
+}
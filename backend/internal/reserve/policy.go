@@ -0,0 +1,204 @@
+package reserve
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+)
+
+// Built-in policy IDs.
+const (
+	PolicyDefault = "default"  // AGENT_TECH_SPEC.md §3.2 cut-offs (8% / 5%)
+	PolicyJPAlert = "jp-alert" // Government supply-demand tightness alerts (5% 注意報, 3% 警報)
+)
+
+// Tier is a named reserve margin band of a Policy.
+type Tier struct {
+	Name   string   `json:"name"`              // e.g., "warning"
+	Label  string   `json:"label,omitempty"`   // e.g., "需給ひっ迫警報"
+	MinPct *float64 `json:"min_pct,omitempty"` // Inclusive lower bound; nil (last tier only) for no bound
+	Status Status   `json:"status"`            // Dashboard status of the tier (stable/watch/tight)
+}
+
+// Policy maps reserve margins to named tiers. Tiers are ordered loosest
+// first with strictly decreasing MinPct; a margin belongs to the first tier
+// whose MinPct it reaches, and the last (unbounded) tier takes the rest.
+type Policy struct {
+	ID    string `json:"id"`
+	Name  string `json:"name,omitempty"`
+	Tiers []Tier `json:"tiers"`
+}
+
+func minPct(v float64) *float64 {
+	return &v
+}
+
+// DefaultPolicy returns the policy behind DeriveStatus: >= 8% stable,
+// 5-8% watch, < 5% tight.
+func DefaultPolicy() *Policy {
+	return &Policy{
+		ID:   PolicyDefault,
+		Name: "Dashboard default",
+		Tiers: []Tier{
+			{Name: "stable", MinPct: minPct(8), Status: StatusStable},
+			{Name: "watch", MinPct: minPct(5), Status: StatusWatch},
+			{Name: "tight", Status: StatusTight},
+		},
+	}
+}
+
+// JPAlertPolicy returns the tiers of the government's supply-demand
+// tightness alerts: below 5% 需給ひっ迫注意報, below 3% 需給ひっ迫警報.
+// Margins of 5-8% keep the dashboard's watch tier.
+func JPAlertPolicy() *Policy {
+	return &Policy{
+		ID:   PolicyJPAlert,
+		Name: "需給ひっ迫警報・注意報",
+		Tiers: []Tier{
+			{Name: "stable", MinPct: minPct(8), Status: StatusStable},
+			{Name: "watch", MinPct: minPct(5), Status: StatusWatch},
+			{Name: "advisory", Label: "需給ひっ迫注意報", MinPct: minPct(3), Status: StatusTight},
+			{Name: "warning", Label: "需給ひっ迫警報", Status: StatusTight},
+		},
+	}
+}
+
+// builtinPolicies returns fresh copies of the built-in policies by ID.
+func builtinPolicies() map[string]*Policy {
+	return map[string]*Policy{
+		PolicyDefault: DefaultPolicy(),
+		PolicyJPAlert: JPAlertPolicy(),
+	}
+}
+
+// PolicyIDs lists the built-in policy IDs in sorted order.
+func PolicyIDs() []string {
+	ids := make([]string, 0, 2)
+	for id := range builtinPolicies() {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// LookupPolicy returns a built-in policy by ID.
+func LookupPolicy(id string) (*Policy, bool) {
+	p, ok := builtinPolicies()[id]
+	return p, ok
+}
+
+// LoadPolicy reads and validates a policy from a JSON file:
+//
+//	{"id": "internal", "tiers": [
+//	  {"name": "ok", "min_pct": 10, "status": "stable"},
+//	  {"name": "watch", "min_pct": 6, "status": "watch"},
+//	  {"name": "page", "status": "tight"}]}
+func LoadPolicy(path string) (*Policy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read policy: %w", err)
+	}
+
+	var p Policy
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("failed to parse policy %s: %w", path, err)
+	}
+	if err := p.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid policy %s: %w", path, err)
+	}
+	return &p, nil
+}
+
+// OpenPolicy resolves ref as a built-in policy ID, or else as a policy file.
+// An empty ref yields DefaultPolicy.
+func OpenPolicy(ref string) (*Policy, error) {
+	if ref == "" {
+		return DefaultPolicy(), nil
+	}
+	if p, ok := LookupPolicy(ref); ok {
+		return p, nil
+	}
+	return LoadPolicy(ref)
+}
+
+// Validate checks that the policy has an ID and well-ordered tiers.
+func (p *Policy) Validate() error {
+	if p.ID == "" {
+		return errors.New("policy id is required")
+	}
+	if len(p.Tiers) == 0 {
+		return errors.New("policy needs at least one tier")
+	}
+
+	names := make(map[string]bool, len(p.Tiers))
+	last := len(p.Tiers) - 1
+	for i, t := range p.Tiers {
+		if t.Name == "" {
+			return fmt.Errorf("tier %d: name is required", i)
+		}
+		if names[t.Name] {
+			return fmt.Errorf("tier %q: duplicate name", t.Name)
+		}
+		names[t.Name] = true
+
+		switch t.Status {
+		case StatusStable, StatusWatch, StatusTight:
+		default:
+			return fmt.Errorf("tier %q: status must be stable, watch or tight, got %q", t.Name, t.Status)
+		}
+
+		switch {
+		case i == last && t.MinPct != nil:
+			return fmt.Errorf("tier %q: the last tier takes every lower margin and must not set min_pct", t.Name)
+		case i < last && t.MinPct == nil:
+			return fmt.Errorf("tier %q: min_pct is required", t.Name)
+		case i > 0 && i < last && *t.MinPct >= *p.Tiers[i-1].MinPct:
+			return fmt.Errorf("tier %q: min_pct %.2f must be below the previous tier's %.2f", t.Name, *t.MinPct, *p.Tiers[i-1].MinPct)
+		}
+	}
+	return nil
+}
+
+// Classify returns the tier a reserve margin falls into.
+func (p *Policy) Classify(reserveMarginPct float64) Tier {
+	for _, t := range p.Tiers {
+		if t.MinPct == nil || reserveMarginPct >= *t.MinPct {
+			return t
+		}
+	}
+	return p.Tiers[len(p.Tiers)-1]
+}
+
+// Apply re-derives the status and tier of every area, interval, daily
+// minimum and wide-area block of resp, and records the policy in resp.Meta.
+func (p *Policy) Apply(resp *Response) {
+	for i := range resp.Areas {
+		a := &resp.Areas[i]
+		t := p.Classify(a.ReserveMarginPct)
+		a.Status, a.Tier = t.Status, t.Name
+
+		for j := range a.Series {
+			t := p.Classify(a.Series[j].ReserveMarginPct)
+			a.Series[j].Status, a.Series[j].Tier = t.Status, t.Name
+		}
+		if a.Minimum != nil {
+			t := p.Classify(a.Minimum.ReserveMarginPct)
+			a.Minimum.Status, a.Minimum.Tier = t.Status, t.Name
+		}
+	}
+
+	for i := range resp.Blocks {
+		for j := range resp.Blocks[i].Blocks {
+			b := &resp.Blocks[i].Blocks[j]
+			t := p.Classify(b.ReserveMarginPct)
+			b.Status, b.Tier = t.Status, t.Name
+		}
+	}
+
+	if resp.Meta == nil {
+		resp.Meta = &Meta{}
+	}
+	resp.Meta.Policy = p
+}
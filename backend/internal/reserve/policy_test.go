@@ -0,0 +1,136 @@
+package reserve
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPolicy_Classify(t *testing.T) {
+	tests := []struct {
+		policy     *Policy
+		pct        float64
+		wantTier   string
+		wantStatus Status
+	}{
+		{DefaultPolicy(), 8.0, "stable", StatusStable},
+		{DefaultPolicy(), 5.0, "watch", StatusWatch},
+		{DefaultPolicy(), 2.0, "tight", StatusTight},
+		{JPAlertPolicy(), 7.9, "watch", StatusWatch},
+		{JPAlertPolicy(), 4.9, "advisory", StatusTight},
+		{JPAlertPolicy(), 3.0, "advisory", StatusTight},
+		{JPAlertPolicy(), 2.9, "warning", StatusTight},
+		{JPAlertPolicy(), -1.0, "warning", StatusTight},
+	}
+
+	for _, tt := range tests {
+		got := tt.policy.Classify(tt.pct)
+		if got.Name != tt.wantTier || got.Status != tt.wantStatus {
+			t.Errorf("%s.Classify(%v) = %s/%s, want %s/%s", tt.policy.ID, tt.pct, got.Name, got.Status, tt.wantTier, tt.wantStatus)
+		}
+	}
+}
+
+func TestPolicy_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  Policy
+		wantErr string
+	}{
+		{"builtin", *JPAlertPolicy(), ""},
+		{"missing id", Policy{Tiers: []Tier{{Name: "all", Status: StatusStable}}}, "id is required"},
+		{"no tiers", Policy{ID: "x"}, "at least one tier"},
+		{"bad status", Policy{ID: "x", Tiers: []Tier{{Name: "all", Status: "red"}}}, "status must be"},
+		{"bounded last tier", Policy{ID: "x", Tiers: []Tier{{Name: "all", MinPct: minPct(0), Status: StatusTight}}}, "must not set min_pct"},
+		{"unbounded middle tier", Policy{ID: "x", Tiers: []Tier{
+			{Name: "a", Status: StatusStable},
+			{Name: "b", Status: StatusTight},
+		}}, "min_pct is required"},
+		{"not decreasing", Policy{ID: "x", Tiers: []Tier{
+			{Name: "a", MinPct: minPct(5), Status: StatusStable},
+			{Name: "b", MinPct: minPct(6), Status: StatusWatch},
+			{Name: "c", Status: StatusTight},
+		}}, "must be below"},
+		{"duplicate name", Policy{ID: "x", Tiers: []Tier{
+			{Name: "a", MinPct: minPct(5), Status: StatusStable},
+			{Name: "a", Status: StatusTight},
+		}}, "duplicate"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.policy.Validate()
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("Validate() error = %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("Validate() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestOpenPolicy(t *testing.T) {
+	p, err := OpenPolicy("")
+	if err != nil || p.ID != PolicyDefault {
+		t.Fatalf("OpenPolicy(\"\") = %v, %v, want default", p, err)
+	}
+	if p, err = OpenPolicy(PolicyJPAlert); err != nil || len(p.Tiers) != 4 {
+		t.Fatalf("OpenPolicy(jp-alert) = %v, %v", p, err)
+	}
+
+	path := filepath.Join(t.TempDir(), "policy.json")
+	data := `{"id": "internal", "tiers": [
+		{"name": "ok", "min_pct": 10, "status": "stable"},
+		{"name": "page", "status": "tight"}]}`
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	p, err = OpenPolicy(path)
+	if err != nil {
+		t.Fatalf("OpenPolicy(file) error = %v", err)
+	}
+	if got := p.Classify(9.9).Name; p.ID != "internal" || got != "page" {
+		t.Errorf("loaded policy %s classifies 9.9%% as %q, want page", p.ID, got)
+	}
+
+	if _, err := OpenPolicy(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("OpenPolicy(missing file) error = nil")
+	}
+}
+
+func TestPolicy_Apply(t *testing.T) {
+	resp := NewResponse("2025-10-24")
+	resp.Areas = append(resp.Areas, AreaReserve{
+		Area:             "tokyo",
+		ReserveMarginPct: 5.1,
+		Status:           StatusWatch,
+		Minimum:          &Minimum{ReserveMarginPct: 2.05, Status: StatusTight},
+		Series: []ReservePoint{
+			{ReserveMarginPct: 9.0, Status: StatusStable},
+			{ReserveMarginPct: 4.0, Status: StatusTight},
+		},
+	})
+
+	resp.Blocks = []BlockInterval{{Blocks: []Block{NewBlock(4, 39180, 40000)}}}
+
+	JPAlertPolicy().Apply(resp)
+
+	tokyo := resp.Areas[0]
+	if tokyo.Tier != "watch" || tokyo.Status != StatusWatch {
+		t.Errorf("area tier = %s/%s, want watch/watch", tokyo.Tier, tokyo.Status)
+	}
+	if tokyo.Minimum.Tier != "warning" {
+		t.Errorf("minimum tier = %s, want warning", tokyo.Minimum.Tier)
+	}
+	if tokyo.Series[0].Tier != "stable" || tokyo.Series[1].Tier != "advisory" {
+		t.Errorf("series tiers = %s, %s, want stable, advisory", tokyo.Series[0].Tier, tokyo.Series[1].Tier)
+	}
+	if block := resp.Blocks[0].Blocks[0]; block.Tier != "warning" || block.Status != StatusTight {
+		t.Errorf("block tier = %s/%s, want warning/tight", block.Tier, block.Status)
+	}
+	if resp.Meta == nil || resp.Meta.Policy == nil || resp.Meta.Policy.ID != PolicyJPAlert {
+		t.Errorf("meta.policy = %+v, want %s", resp.Meta, PolicyJPAlert)
+	}
+}
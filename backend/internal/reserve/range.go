@@ -0,0 +1,58 @@
+package reserve
+
+import (
+	"fmt"
+
+	"github.com/teo/aversome/backend/internal/series"
+)
+
+// DayReserve holds the reserve margins of one day in a range.
+type DayReserve struct {
+	Date  string        `json:"date"`
+	Areas []AreaReserve `json:"areas"`
+}
+
+// RangeResponse is a multi-day reserve margin series.
+// GET /api/reserve?from=YYYY-MM-DD&to=YYYY-MM-DD
+type RangeResponse struct {
+	From     string       `json:"from"`               // First requested date
+	To       string       `json:"to"`                 // Last requested date
+	Days     []DayReserve `json:"days"`               // One entry per stored day, ascending
+	Gaps     []series.Gap `json:"gaps"`               // Days with no stored data
+	Sources  []Source     `json:"sources"`            // Distinct sources in the range
+	Warnings []string     `json:"warnings,omitempty"` // Per-day warnings, prefixed with the date
+	Policy   *Policy      `json:"policy,omitempty"`   // Thresholds behind status and tier
+}
+
+// NewRangeResponse concatenates daily responses (ascending, one per stored day).
+func NewRangeResponse(from, to string, days []*Response, gaps []series.Gap) *RangeResponse {
+	out := &RangeResponse{
+		From:    from,
+		To:      to,
+		Days:    make([]DayReserve, 0, len(days)),
+		Gaps:    gaps,
+		Sources: make([]Source, 0, 1),
+	}
+
+	for _, d := range days {
+		out.Days = append(out.Days, DayReserve{Date: d.Date, Areas: d.Areas})
+
+		if !containsSource(out.Sources, d.Source) {
+			out.Sources = append(out.Sources, d.Source)
+		}
+		if d.Meta != nil && d.Meta.Warning != "" {
+			out.Warnings = append(out.Warnings, fmt.Sprintf("%s: %s", d.Date, d.Meta.Warning))
+		}
+	}
+
+	return out
+}
+
+func containsSource(sources []Source, s Source) bool {
+	for _, existing := range sources {
+		if existing == s {
+			return true
+		}
+	}
+	return false
+}
@@ -2,6 +2,12 @@
 // Follows AGENT_TECH_SPEC.md §3.2 API contract.
 package reserve
 
+import (
+	"time"
+
+	"github.com/teo/aversome/backend/pkg/timeutil"
+)
+
 // Status represents the reserve margin status based on thresholds.
 type Status string
 
@@ -13,9 +19,55 @@ const (
 
 // AreaReserve represents reserve margin data for a specific area.
 type AreaReserve struct {
-	Area              string  `json:"area"`                 // e.g., "tokyo", "kansai"
-	ReserveMarginPct  float64 `json:"reserve_margin_pct"`   // Reserve margin percentage
-	Status            Status  `json:"status"`               // Derived status (stable/watch/tight)
+	Area             string         `json:"area"`               // e.g., "tokyo", "kansai"
+	ReserveMarginPct float64        `json:"reserve_margin_pct"` // Reserve margin percentage (daily average)
+	Status           Status         `json:"status"`             // Derived status (stable/watch/tight)
+	Tier             string         `json:"tier,omitempty"`     // Policy tier (e.g., "advisory")
+	Minimum          *Minimum       `json:"minimum,omitempty"`  // Tightest interval of the day
+	Series           []ReservePoint `json:"series,omitempty"`   // Per-interval margins (30min)
+}
+
+// ReservePoint is the reserve margin of one area in one 30-minute interval.
+type ReservePoint struct {
+	Timestamp        string   `json:"ts"`                 // Interval start, ISO8601 with Asia/Tokyo offset
+	DemandMW         float64  `json:"demand_mw"`          // エリア需要
+	CapacityMW       float64  `json:"capacity_mw"`        // エリア供給力
+	ReserveMarginPct float64  `json:"reserve_margin_pct"` // (capacity - demand) / capacity * 100
+	Status           Status   `json:"status"`
+	Tier             string   `json:"tier,omitempty"`
+	Block            int      `json:"block,omitempty"`            // Wide-area block of the interval (ブロックNo)
+	BlockMarginPct   *float64 `json:"block_margin_pct,omitempty"` // Reserve margin of that block
+}
+
+// Minimum is the interval with the lowest reserve margin of the day.
+type Minimum struct {
+	Timestamp        string   `json:"ts"`   // Interval start
+	Hour             int      `json:"hour"` // Hour of day (0-23, Asia/Tokyo)
+	ReserveMarginPct float64  `json:"reserve_margin_pct"`
+	Status           Status   `json:"status"`
+	Tier             string   `json:"tier,omitempty"`
+	Block            int      `json:"block,omitempty"`
+	BlockMarginPct   *float64 `json:"block_margin_pct,omitempty"`
+}
+
+// BlockInterval lists the wide-area blocks (広域ブロック) of one 30-minute interval.
+type BlockInterval struct {
+	Timestamp string  `json:"ts"`     // Interval start
+	Blocks    []Block `json:"blocks"` // Ascending block number
+}
+
+// Block is a group of areas OCCTO coupled through the interconnectors for
+// one interval, sharing reserve. OCCTO's 広域ブロック予備率 divides by demand;
+// ReserveMarginPct uses MarginPct so block and area margins compare directly.
+type Block struct {
+	No               int      `json:"block"`              // ブロックNo
+	Areas            []string `json:"areas"`              // Coupled areas, registry order
+	DemandMW         float64  `json:"demand_mw"`          // 広域ブロック需要
+	CapacityMW       float64  `json:"capacity_mw"`        // 広域ブロック供給力
+	ReserveMW        float64  `json:"reserve_mw"`         // capacity - demand
+	ReserveMarginPct float64  `json:"reserve_margin_pct"` // (capacity - demand) / capacity * 100
+	Status           Status   `json:"status"`
+	Tier             string   `json:"tier,omitempty"`
 }
 
 // Source contains attribution for the data source.
@@ -26,16 +78,19 @@ type Source struct {
 
 // Meta contains optional metadata and warnings.
 type Meta struct {
-	Warning string `json:"warning,omitempty"` // Non-blocking warning message
+	Warning string  `json:"warning,omitempty"` // Non-blocking warning message
+	Policy  *Policy `json:"policy,omitempty"`  // Thresholds behind status and tier
 }
 
 // Response is the top-level response structure for reserve margin endpoint.
 // GET /api/jp/system/reserve?date=YYYY-MM-DD
 type Response struct {
-	Date   string         `json:"date"`            // YYYY-MM-DD format
-	Areas  []AreaReserve  `json:"areas"`           // Reserve data for each area
-	Source Source         `json:"source"`          // Data attribution
-	Meta   *Meta          `json:"meta,omitempty"`  // Optional metadata/warnings
+	Date      string          `json:"date"`                // YYYY-MM-DD format
+	Timescale string          `json:"timescale,omitempty"` // Resolution of the area series ("30min")
+	Areas     []AreaReserve   `json:"areas"`               // Reserve data for each area
+	Blocks    []BlockInterval `json:"blocks,omitempty"`    // Wide-area blocks per interval
+	Source    Source          `json:"source"`              // Data attribution
+	Meta      *Meta           `json:"meta,omitempty"`      // Optional metadata/warnings
 }
 
 // NewResponse creates a properly initialized Response with defaults.
@@ -46,18 +101,73 @@ func NewResponse(date string) *Response {
 	}
 }
 
-// DeriveStatus calculates the status from reserve margin percentage.
+// DeriveStatus calculates the status from reserve margin percentage using
+// DefaultPolicy (Policy.Apply re-derives it under another policy).
 // Thresholds per AGENT_TECH_SPEC.md §3.2:
 //   >= 8%   → stable
 //   5-8%    → watch
 //   < 5%    → tight
 func DeriveStatus(reserveMarginPct float64) Status {
-	switch {
-	case reserveMarginPct >= 8.0:
-		return StatusStable
-	case reserveMarginPct >= 5.0:
-		return StatusWatch
-	default:
-		return StatusTight
+	return defaultPolicy.Classify(reserveMarginPct).Status
+}
+
+var defaultPolicy = DefaultPolicy()
+
+// MarginPct calculates the reserve margin: (capacity - demand) / capacity * 100.
+// Zero capacity yields 0.
+func MarginPct(demandMW, capacityMW float64) float64 {
+	if capacityMW <= 0 {
+		return 0
+	}
+	return ((capacityMW - demandMW) / capacityMW) * 100
+}
+
+// NewPoint builds a series point with its margin and status.
+func NewPoint(ts time.Time, demandMW, capacityMW float64) ReservePoint {
+	pct := MarginPct(demandMW, capacityMW)
+	return ReservePoint{
+		Timestamp:        timeutil.FormatISO8601(ts),
+		DemandMW:         demandMW,
+		CapacityMW:       capacityMW,
+		ReserveMarginPct: pct,
+		Status:           DeriveStatus(pct),
+	}
+}
+
+// NewBlock builds a wide-area block with its reserve, margin and status.
+func NewBlock(no int, demandMW, capacityMW float64) Block {
+	pct := MarginPct(demandMW, capacityMW)
+	return Block{
+		No:               no,
+		DemandMW:         demandMW,
+		CapacityMW:       capacityMW,
+		ReserveMW:        capacityMW - demandMW,
+		ReserveMarginPct: pct,
+		Status:           DeriveStatus(pct),
+	}
+}
+
+// FindMinimum returns the tightest point of a series (the first one on
+// ties), or nil for an empty series.
+func FindMinimum(series []ReservePoint) *Minimum {
+	var min *Minimum
+	for _, p := range series {
+		if min != nil && p.ReserveMarginPct >= min.ReserveMarginPct {
+			continue
+		}
+		hour := 0
+		if ts, err := time.Parse(time.RFC3339, p.Timestamp); err == nil {
+			hour = ts.In(timeutil.TokyoLocation).Hour()
+		}
+		min = &Minimum{
+			Timestamp:        p.Timestamp,
+			Hour:             hour,
+			ReserveMarginPct: p.ReserveMarginPct,
+			Status:           p.Status,
+			Tier:             p.Tier,
+			Block:            p.Block,
+			BlockMarginPct:   p.BlockMarginPct,
+		}
 	}
+	return min
 }
@@ -0,0 +1,86 @@
+// Package series provides helpers shared by the multi-day (range) responses
+// of the demand, JEPX, reserve and generation packages.
+package series
+
+import (
+	"fmt"
+
+	"github.com/teo/aversome/backend/pkg/timeutil"
+)
+
+// MaxRangeDays limits a single range query.
+const MaxRangeDays = 366
+
+// Gap marks a run of consecutive days with no stored data in a range.
+type Gap struct {
+	From string `json:"from"` // First missing date (YYYY-MM-DD)
+	To   string `json:"to"`   // Last missing date (YYYY-MM-DD)
+	Days int    `json:"days"` // Number of missing days
+}
+
+// Dates returns every date from..to inclusive (YYYY-MM-DD, Asia/Tokyo).
+func Dates(from, to string) ([]string, error) {
+	start, err := timeutil.ParseDate(from)
+	if err != nil {
+		return nil, fmt.Errorf("invalid from: %w", err)
+	}
+	end, err := timeutil.ParseDate(to)
+	if err != nil {
+		return nil, fmt.Errorf("invalid to: %w", err)
+	}
+	if end.Before(start) {
+		return nil, fmt.Errorf("from %s is after to %s", from, to)
+	}
+
+	var dates []string
+	for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
+		if len(dates) == MaxRangeDays {
+			return nil, fmt.Errorf("range exceeds %d days", MaxRangeDays)
+		}
+		dates = append(dates, timeutil.FormatDate(d))
+	}
+	return dates, nil
+}
+
+// FindGaps returns the runs of dates not present in have, in order.
+func FindGaps(dates []string, have map[string]bool) []Gap {
+	gaps := make([]Gap, 0)
+	for _, date := range dates {
+		if have[date] {
+			continue
+		}
+		if n := len(gaps); n > 0 && gaps[n-1].To == previousDate(date) {
+			gaps[n-1].To = date
+			gaps[n-1].Days++
+			continue
+		}
+		gaps = append(gaps, Gap{From: date, To: date, Days: 1})
+	}
+	return gaps
+}
+
+// CommonTimescale picks the timescale for a concatenated series: the
+// requested one if set, the shared one if every day agrees, hourly otherwise.
+func CommonTimescale(requested string, timescales []string) string {
+	if requested != "" {
+		return requested
+	}
+	if len(timescales) == 0 {
+		return timeutil.TimescaleHourly
+	}
+	for _, ts := range timescales[1:] {
+		if ts != timescales[0] {
+			return timeutil.TimescaleHourly
+		}
+	}
+	return timescales[0]
+}
+
+// previousDate returns the day before date, or "" if date is invalid.
+func previousDate(date string) string {
+	t, err := timeutil.ParseDate(date)
+	if err != nil {
+		return ""
+	}
+	return timeutil.FormatDate(t.AddDate(0, 0, -1))
+}
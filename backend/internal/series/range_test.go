@@ -0,0 +1,74 @@
+package series
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDates(t *testing.T) {
+	tests := []struct {
+		name    string
+		from    string
+		to      string
+		want    []string
+		wantErr bool
+	}{
+		{name: "single day", from: "2025-10-24", to: "2025-10-24", want: []string{"2025-10-24"}},
+		{name: "month boundary", from: "2025-10-30", to: "2025-11-02",
+			want: []string{"2025-10-30", "2025-10-31", "2025-11-01", "2025-11-02"}},
+		{name: "reversed", from: "2025-10-24", to: "2025-10-23", wantErr: true},
+		{name: "invalid from", from: "2025/10/24", to: "2025-10-24", wantErr: true},
+		{name: "too long", from: "2024-01-01", to: "2025-12-31", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Dates(tt.from, tt.to)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Dates() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Dates() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFindGaps(t *testing.T) {
+	dates, err := Dates("2025-10-20", "2025-10-26")
+	if err != nil {
+		t.Fatal(err)
+	}
+	have := map[string]bool{"2025-10-22": true, "2025-10-23": true, "2025-10-25": true}
+
+	want := []Gap{
+		{From: "2025-10-20", To: "2025-10-21", Days: 2},
+		{From: "2025-10-24", To: "2025-10-24", Days: 1},
+		{From: "2025-10-26", To: "2025-10-26", Days: 1},
+	}
+	if got := FindGaps(dates, have); !reflect.DeepEqual(got, want) {
+		t.Errorf("FindGaps() = %+v, want %+v", got, want)
+	}
+}
+
+func TestCommonTimescale(t *testing.T) {
+	tests := []struct {
+		name       string
+		requested  string
+		timescales []string
+		want       string
+	}{
+		{name: "requested wins", requested: "hourly", timescales: []string{"30min"}, want: "hourly"},
+		{name: "shared", timescales: []string{"30min", "30min"}, want: "30min"},
+		{name: "mixed", timescales: []string{"30min", "hourly"}, want: "hourly"},
+		{name: "empty", want: "hourly"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CommonTimescale(tt.requested, tt.timescales); got != tt.want {
+				t.Errorf("CommonTimescale() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -4,6 +4,7 @@ package settlement
 import (
 	"fmt"
 	"math"
+	"time"
 
 	"github.com/teo/aversome/backend/internal/jepx"
 )
@@ -41,15 +42,18 @@ func Calculate(req *Request, prices []jepx.PricePoint, priceSource jepx.Source)
 	var totalKWh, totalCost float64
 	var firstTS, lastTS string
 
-	// Calculate per-hour costs
+	var missing []string
+
+	// Calculate per-period costs
 	for i, profilePoint := range req.Profile {
 		ts := profilePoint.Timestamp
 		kwh := profilePoint.KWh
 
-		// Find matching price
+		// Find matching price (collect every gap so callers can report them all)
 		price, ok := priceMap[ts]
 		if !ok {
-			return nil, fmt.Errorf("no price found for timestamp %s", ts)
+			missing = append(missing, ts)
+			continue
 		}
 
 		// Apply PV offset: effective consumption = kwh × (1 - pv_offset_pct)
@@ -82,6 +86,14 @@ func Calculate(req *Request, prices []jepx.PricePoint, priceSource jepx.Source)
 		lastTS = ts
 	}
 
+	if len(missing) > 0 {
+		return nil, &MissingPriceError{
+			Area:       req.Prices.Area,
+			Date:       req.Prices.Date,
+			Timestamps: missing,
+		}
+	}
+
 	// Set totals with rounding
 	resp.Totals = Totals{
 		KWh:     roundTo(totalKWh, 0.1),
@@ -97,6 +109,104 @@ func Calculate(req *Request, prices []jepx.PricePoint, priceSource jepx.Source)
 	return resp, nil
 }
 
+// Validate checks a request before prices are loaded: non-empty profile with
+// parseable, unique timestamps and non-negative kWh, a price area and date,
+// and a PV offset within 0.0-1.0. Imbalance mode also needs a planned
+// profile for the same 30-minute timestamps.
+func (r *Request) Validate() error {
+	if len(r.Profile) == 0 {
+		return fmt.Errorf("profile is empty")
+	}
+	if r.Prices.Area == "" {
+		return fmt.Errorf("prices.area is required")
+	}
+	if _, err := time.Parse("2006-01-02", r.Prices.Date); err != nil {
+		return fmt.Errorf("prices.date must be YYYY-MM-DD, got %q", r.Prices.Date)
+	}
+	if r.PVOffsetPct < 0 || r.PVOffsetPct > 1 {
+		return fmt.Errorf("pv_offset_pct must be between 0 and 1, got %v", r.PVOffsetPct)
+	}
+
+	if err := validateProfile("profile", r.Profile); err != nil {
+		return err
+	}
+
+	switch r.Mode {
+	case "", ModeSpot:
+		if len(r.Planned) > 0 {
+			return fmt.Errorf("planned is only used in %s mode", ModeImbalance)
+		}
+	case ModeImbalance:
+		return r.validateImbalance()
+	default:
+		return fmt.Errorf("mode must be %q or %q, got %q", ModeSpot, ModeImbalance, r.Mode)
+	}
+
+	return nil
+}
+
+// validateImbalance checks the imbalance mode inputs: a 30-minute actual
+// profile and a planned profile covering exactly the same koma.
+func (r *Request) validateImbalance() error {
+	if r.PVOffsetPct != 0 {
+		return fmt.Errorf("pv_offset_pct is not used in %s mode", ModeImbalance)
+	}
+	if ProfileTimescale(r.Profile) != jepx.Timescale30Min {
+		return fmt.Errorf("%s mode needs a 30-minute profile (imbalance is settled per koma)", ModeImbalance)
+	}
+	if len(r.Planned) == 0 {
+		return fmt.Errorf("planned is required in %s mode", ModeImbalance)
+	}
+	if err := validateProfile("planned", r.Planned); err != nil {
+		return err
+	}
+	if len(r.Planned) != len(r.Profile) {
+		return fmt.Errorf("planned has %d points, profile %d", len(r.Planned), len(r.Profile))
+	}
+
+	actual := make(map[string]bool, len(r.Profile))
+	for _, p := range r.Profile {
+		actual[p.Timestamp] = true
+	}
+	for i, p := range r.Planned {
+		if !actual[p.Timestamp] {
+			return fmt.Errorf("planned[%d].ts %s is not in the profile", i, p.Timestamp)
+		}
+	}
+
+	return nil
+}
+
+// validateProfile checks parseable, unique timestamps and non-negative kWh.
+func validateProfile(name string, profile []ProfilePoint) error {
+	seen := make(map[string]bool, len(profile))
+	for i, p := range profile {
+		if _, err := time.Parse(time.RFC3339, p.Timestamp); err != nil {
+			return fmt.Errorf("%s[%d].ts must be ISO8601 with offset, got %q", name, i, p.Timestamp)
+		}
+		if seen[p.Timestamp] {
+			return fmt.Errorf("%s[%d].ts duplicates %s", name, i, p.Timestamp)
+		}
+		seen[p.Timestamp] = true
+		if p.KWh < 0 {
+			return fmt.Errorf("%s[%d].kwh must be non-negative, got %v", name, i, p.KWh)
+		}
+	}
+	return nil
+}
+
+// ProfileTimescale reports the resolution of a consumption profile:
+// "30min" if any point falls on a half hour, "hourly" otherwise.
+func ProfileTimescale(profile []ProfilePoint) string {
+	for _, p := range profile {
+		ts, err := time.Parse(time.RFC3339, p.Timestamp)
+		if err == nil && ts.Minute() == 30 {
+			return jepx.Timescale30Min
+		}
+	}
+	return jepx.TimescaleHourly
+}
+
 // roundTo rounds a float64 to the nearest multiple of precision.
 // Examples:
 //   roundTo(12345.67, 0.1) = 12345.7
@@ -1,6 +1,7 @@
 package settlement
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/teo/aversome/backend/internal/jepx"
@@ -201,7 +202,15 @@ func TestCalculate_MissingPrice(t *testing.T) {
 
 	_, err := Calculate(req, prices, priceSource)
 	if err == nil {
-		t.Error("Expected error for missing price, got nil")
+		t.Fatal("Expected error for missing price, got nil")
+	}
+
+	var missingErr *MissingPriceError
+	if !errors.As(err, &missingErr) {
+		t.Fatalf("Expected *MissingPriceError, got %T", err)
+	}
+	if len(missingErr.Timestamps) != 1 || missingErr.Timestamps[0] != "2025-10-23T01:00:00+09:00" {
+		t.Errorf("Timestamps = %v, want [2025-10-23T01:00:00+09:00]", missingErr.Timestamps)
 	}
 }
 
@@ -229,6 +238,63 @@ func TestCalculate_InvalidPVOffset(t *testing.T) {
 	}
 }
 
+func TestRequest_Validate(t *testing.T) {
+	valid := func() *Request {
+		return &Request{
+			Profile: []ProfilePoint{
+				{Timestamp: "2025-10-23T00:00:00+09:00", KWh: 100.0},
+				{Timestamp: "2025-10-23T00:30:00+09:00", KWh: 100.0},
+			},
+			Prices: PricesRequest{Area: "tokyo", Date: "2025-10-23"},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		mutate  func(r *Request)
+		wantErr bool
+	}{
+		{"valid", func(r *Request) {}, false},
+		{"empty profile", func(r *Request) { r.Profile = nil }, true},
+		{"missing area", func(r *Request) { r.Prices.Area = "" }, true},
+		{"bad date", func(r *Request) { r.Prices.Date = "2025/10/23" }, true},
+		{"pv offset out of range", func(r *Request) { r.PVOffsetPct = -0.1 }, true},
+		{"bad timestamp", func(r *Request) { r.Profile[0].Timestamp = "2025-10-23 00:00" }, true},
+		{"duplicate timestamp", func(r *Request) { r.Profile[1].Timestamp = r.Profile[0].Timestamp }, true},
+		{"negative kwh", func(r *Request) { r.Profile[1].KWh = -1 }, true},
+		{"unknown mode", func(r *Request) { r.Mode = "forward" }, true},
+		{"planned in spot mode", func(r *Request) { r.Planned = r.Profile }, true},
+		{"imbalance", func(r *Request) { r.Mode, r.Planned = ModeImbalance, clone(r.Profile) }, false},
+		{"imbalance without plan", func(r *Request) { r.Mode = ModeImbalance }, true},
+		{"imbalance with pv offset", func(r *Request) {
+			r.Mode, r.Planned, r.PVOffsetPct = ModeImbalance, clone(r.Profile), 0.1
+		}, true},
+		{"imbalance plan mismatch", func(r *Request) {
+			r.Mode, r.Planned = ModeImbalance, clone(r.Profile)
+			r.Planned[1].Timestamp = "2025-10-23T01:00:00+09:00"
+		}, true},
+		{"imbalance hourly profile", func(r *Request) {
+			r.Profile[1].Timestamp = "2025-10-23T01:00:00+09:00"
+			r.Mode, r.Planned = ModeImbalance, clone(r.Profile)
+		}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := valid()
+			tt.mutate(req)
+			err := req.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func clone(profile []ProfilePoint) []ProfilePoint {
+	return append([]ProfilePoint(nil), profile...)
+}
+
 func TestRoundTo(t *testing.T) {
 	tests := []struct {
 		value     float64
@@ -0,0 +1,126 @@
+package settlement
+
+import (
+	"fmt"
+
+	"github.com/teo/aversome/backend/internal/imbalance"
+)
+
+// CalculateImbalance settles the difference between the planned (nominated)
+// and actual profile of a balancing group at the imbalance price.
+// Per koma: imbalance = actual − planned; a shortage (> 0) is charged at the
+// shortage price, a surplus (< 0) is credited at the surplus price.
+// Formula: cost = Σ(imbalance_kWh × price)
+// Rounding: 0.1 JPY for costs, 0.1 kWh for consumption
+func CalculateImbalance(req *Request, prices []imbalance.PricePoint, priceSource imbalance.Source) (*Response, error) {
+	if len(req.Profile) == 0 {
+		return nil, fmt.Errorf("profile is empty")
+	}
+	if len(req.Planned) != len(req.Profile) {
+		return nil, fmt.Errorf("planned has %d points, profile %d", len(req.Planned), len(req.Profile))
+	}
+	if len(prices) == 0 {
+		return nil, fmt.Errorf("prices are empty")
+	}
+
+	// Build price and plan lookup maps by timestamp
+	priceMap := make(map[string]imbalance.PricePoint, len(prices))
+	for _, pp := range prices {
+		priceMap[pp.Timestamp] = pp
+	}
+	planned := make(map[string]float64, len(req.Planned))
+	for _, p := range req.Planned {
+		planned[p.Timestamp] = p.KWh
+	}
+
+	resp := NewResponse()
+	resp.ByPeriod = make([]ImbalanceBreakdown, 0, len(req.Profile))
+	resp.Assumptions = Assumptions{
+		Area: req.Prices.Area,
+		Mode: ModeImbalance,
+	}
+	resp.SourcePrices = Source{
+		Name: priceSource.Name,
+		URL:  priceSource.URL,
+	}
+
+	var totals ImbalanceTotals
+	var totalCost float64
+	var missing []string
+
+	// Calculate per-koma imbalance charges
+	for _, actual := range req.Profile {
+		ts := actual.Timestamp
+
+		price, ok := priceMap[ts]
+		if !ok {
+			missing = append(missing, ts)
+			continue
+		}
+		plan, ok := planned[ts]
+		if !ok {
+			return nil, fmt.Errorf("no planned value for %s", ts)
+		}
+
+		// Shortage is bought at the shortage price, surplus sold at the surplus price
+		diff := actual.KWh - plan
+		unitPrice := price.ShortagePrice
+		if diff < 0 {
+			unitPrice = price.SurplusPrice
+		}
+		cost := diff * unitPrice
+
+		// Accumulate totals with unrounded values to avoid rounding errors
+		totals.PlannedKWh += plan
+		totals.ActualKWh += actual.KWh
+		if diff > 0 {
+			totals.ShortageKWh += diff
+			totals.ShortageYen += cost
+		} else {
+			totals.SurplusKWh -= diff
+			totals.SurplusYen -= cost
+		}
+		totalCost += cost
+
+		resp.ByPeriod = append(resp.ByPeriod, ImbalanceBreakdown{
+			Timestamp:    ts,
+			PlannedKWh:   roundTo(plan, 0.1),
+			ActualKWh:    roundTo(actual.KWh, 0.1),
+			ImbalanceKWh: roundTo(diff, 0.1),
+			Price:        unitPrice,
+			Cost:         roundTo(cost, 0.1),
+		})
+	}
+
+	if len(missing) > 0 {
+		return nil, &MissingPriceError{
+			Area:       req.Prices.Area,
+			Date:       req.Prices.Date,
+			Timestamps: missing,
+		}
+	}
+
+	// Set totals with rounding
+	totals.NetKWh = totals.ActualKWh - totals.PlannedKWh
+	resp.ImbalanceTotals = &ImbalanceTotals{
+		PlannedKWh:  roundTo(totals.PlannedKWh, 0.1),
+		ActualKWh:   roundTo(totals.ActualKWh, 0.1),
+		ShortageKWh: roundTo(totals.ShortageKWh, 0.1),
+		SurplusKWh:  roundTo(totals.SurplusKWh, 0.1),
+		NetKWh:      roundTo(totals.NetKWh, 0.1),
+		ShortageYen: roundTo(totals.ShortageYen, 0.1),
+		SurplusYen:  roundTo(totals.SurplusYen, 0.1),
+	}
+	resp.Totals = Totals{
+		KWh:     roundTo(totals.ActualKWh, 0.1),
+		CostYen: roundTo(totalCost, 0.1),
+	}
+
+	// Set period
+	resp.Period = Period{
+		From: req.Profile[0].Timestamp,
+		To:   req.Profile[len(req.Profile)-1].Timestamp,
+	}
+
+	return resp, nil
+}
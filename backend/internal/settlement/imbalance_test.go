@@ -0,0 +1,65 @@
+package settlement
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/teo/aversome/backend/internal/imbalance"
+)
+
+func TestCalculateImbalance(t *testing.T) {
+	// Koma 1: 10 kWh short at 40 JPY/kWh → +400
+	// Koma 2: 20 kWh surplus at 10 JPY/kWh → −200
+	// Koma 3: on plan → 0
+	req := &Request{
+		Mode: ModeImbalance,
+		Profile: []ProfilePoint{
+			{Timestamp: "2025-10-23T00:00:00+09:00", KWh: 110},
+			{Timestamp: "2025-10-23T00:30:00+09:00", KWh: 80},
+			{Timestamp: "2025-10-23T01:00:00+09:00", KWh: 100},
+		},
+		Planned: []ProfilePoint{
+			{Timestamp: "2025-10-23T00:00:00+09:00", KWh: 100},
+			{Timestamp: "2025-10-23T00:30:00+09:00", KWh: 100},
+			{Timestamp: "2025-10-23T01:00:00+09:00", KWh: 100},
+		},
+		Prices: PricesRequest{Area: "tokyo", Date: "2025-10-23"},
+	}
+	prices := []imbalance.PricePoint{
+		{Timestamp: "2025-10-23T00:00:00+09:00", Period: 1, SurplusPrice: 12, ShortagePrice: 40},
+		{Timestamp: "2025-10-23T00:30:00+09:00", Period: 2, SurplusPrice: 10, ShortagePrice: 10},
+		{Timestamp: "2025-10-23T01:00:00+09:00", Period: 3, SurplusPrice: 11, ShortagePrice: 11},
+	}
+
+	resp, err := CalculateImbalance(req, prices, imbalance.Source{Name: "test"})
+	if err != nil {
+		t.Fatalf("CalculateImbalance() error = %v", err)
+	}
+
+	if resp.Totals.CostYen != 200 || resp.Totals.KWh != 290 {
+		t.Errorf("Totals = %+v, want 290 kWh and 200 JPY", resp.Totals)
+	}
+	want := ImbalanceTotals{
+		PlannedKWh: 300, ActualKWh: 290, ShortageKWh: 10, SurplusKWh: 20,
+		NetKWh: -10, ShortageYen: 400, SurplusYen: 200,
+	}
+	if *resp.ImbalanceTotals != want {
+		t.Errorf("ImbalanceTotals = %+v, want %+v", *resp.ImbalanceTotals, want)
+	}
+	if got := resp.ByPeriod[0]; got.ImbalanceKWh != 10 || got.Price != 40 || got.Cost != 400 {
+		t.Errorf("ByPeriod[0] = %+v, want shortage charged at 40", got)
+	}
+	if got := resp.ByPeriod[1]; got.ImbalanceKWh != -20 || got.Price != 10 || got.Cost != -200 {
+		t.Errorf("ByPeriod[1] = %+v, want surplus credited at 10", got)
+	}
+	if resp.Assumptions.Mode != ModeImbalance || len(resp.ByHour) != 0 {
+		t.Errorf("Assumptions = %+v, by_hour %d; want imbalance mode without hourly breakdown", resp.Assumptions, len(resp.ByHour))
+	}
+
+	// Missing price
+	_, err = CalculateImbalance(req, prices[:2], imbalance.Source{})
+	var missingErr *MissingPriceError
+	if !errors.As(err, &missingErr) || len(missingErr.Timestamps) != 1 {
+		t.Errorf("CalculateImbalance(missing) error = %v, want one missing timestamp", err)
+	}
+}
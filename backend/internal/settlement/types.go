@@ -2,6 +2,16 @@
 // Follows AGENT_TECH_SPEC.md §3.5 API contract.
 package settlement
 
+import "fmt"
+
+// Mode selects what a settlement charges.
+type Mode string
+
+const (
+	ModeSpot      Mode = "spot"      // Consumption at the JEPX spot price (default)
+	ModeImbalance Mode = "imbalance" // Actual − planned at the imbalance price
+)
+
 // ProfilePoint represents a single hourly consumption point.
 type ProfilePoint struct {
 	Timestamp string  `json:"ts"`  // ISO8601 with Asia/Tokyo offset
@@ -17,9 +27,11 @@ type PricesRequest struct {
 // Request is the input for settlement calculation.
 // POST /api/settlements/run
 type Request struct {
-	Profile     []ProfilePoint `json:"profile"`              // Hourly consumption profile
-	Prices      PricesRequest  `json:"prices"`               // JEPX price reference
+	Profile     []ProfilePoint `json:"profile"`              // Hourly consumption profile (actual consumption in imbalance mode)
+	Prices      PricesRequest  `json:"prices"`               // JEPX (or imbalance) price reference
 	PVOffsetPct float64        `json:"pv_offset_pct"`        // PV offset percentage (0.0-1.0)
+	Mode        Mode           `json:"mode,omitempty"`       // "spot" (default) or "imbalance"
+	Planned     []ProfilePoint `json:"planned,omitempty"`    // Nominated plan (計画値), imbalance mode only
 }
 
 // Period represents the time range of the settlement.
@@ -42,10 +54,32 @@ type HourlyBreakdown struct {
 	Cost      float64 `json:"cost"`  // Cost in JPY (kwh × price × (1 - pv%))
 }
 
+// ImbalanceBreakdown represents per-koma imbalance settlement details.
+type ImbalanceBreakdown struct {
+	Timestamp    string  `json:"ts"`            // ISO8601 with Asia/Tokyo offset
+	PlannedKWh   float64 `json:"planned_kwh"`   // Nominated consumption
+	ActualKWh    float64 `json:"actual_kwh"`    // Metered consumption
+	ImbalanceKWh float64 `json:"imbalance_kwh"` // actual − planned: > 0 shortage (不足), < 0 surplus (余剰)
+	Price        float64 `json:"price"`         // Shortage or surplus unit price applied, JPY/kWh
+	Cost         float64 `json:"cost"`          // imbalance_kwh × price, JPY (negative is a credit)
+}
+
+// ImbalanceTotals contains aggregated imbalance settlement results.
+type ImbalanceTotals struct {
+	PlannedKWh  float64 `json:"planned_kwh"`
+	ActualKWh   float64 `json:"actual_kwh"`
+	ShortageKWh float64 `json:"shortage_kwh"` // Sum of positive imbalances
+	SurplusKWh  float64 `json:"surplus_kwh"`  // Sum of negative imbalances, as a positive number
+	NetKWh      float64 `json:"net_kwh"`      // actual − planned
+	ShortageYen float64 `json:"shortage_yen"` // Charged for shortages
+	SurplusYen  float64 `json:"surplus_yen"`  // Credited for surpluses
+}
+
 // Assumptions contains the parameters used in the calculation.
 type Assumptions struct {
-	PVOffsetPct float64 `json:"pv_offset_pct"` // PV offset percentage
-	Area        string  `json:"area"`          // Price area
+	PVOffsetPct float64 `json:"pv_offset_pct"`  // PV offset percentage
+	Area        string  `json:"area"`           // Price area
+	Mode        Mode    `json:"mode,omitempty"` // Settlement mode
 }
 
 // Source contains attribution for price data.
@@ -56,11 +90,25 @@ type Source struct {
 
 // Response is the settlement calculation result.
 type Response struct {
-	Period       Period            `json:"period"`        // Time range
-	Totals       Totals            `json:"totals"`        // Aggregated results
-	ByHour       []HourlyBreakdown `json:"by_hour"`       // Per-hour breakdown
-	Assumptions  Assumptions       `json:"assumptions"`   // Calculation parameters
-	SourcePrices Source            `json:"source_prices"` // Price data attribution
+	Period          Period               `json:"period"`                     // Time range
+	Totals          Totals               `json:"totals"`                     // Aggregated results
+	ByHour          []HourlyBreakdown    `json:"by_hour"`                    // Per-hour breakdown (spot mode)
+	ByPeriod        []ImbalanceBreakdown `json:"by_period,omitempty"`        // Per-koma breakdown (imbalance mode)
+	ImbalanceTotals *ImbalanceTotals     `json:"imbalance_totals,omitempty"` // Imbalance mode only
+	Assumptions     Assumptions          `json:"assumptions"`                // Calculation parameters
+	SourcePrices    Source               `json:"source_prices"`              // Price data attribution
+}
+
+// MissingPriceError reports profile timestamps with no matching JEPX (or imbalance) price.
+type MissingPriceError struct {
+	Area       string
+	Date       string
+	Timestamps []string // Profile timestamps without a price, in profile order
+}
+
+func (e *MissingPriceError) Error() string {
+	return fmt.Sprintf("no price found for %d timestamp(s) in %s/%s, first %s",
+		len(e.Timestamps), e.Area, e.Date, e.Timestamps[0])
 }
 
 // NewResponse creates a properly initialized Response with defaults.
@@ -0,0 +1,265 @@
+package spread
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/teo/aversome/backend/internal/jepx"
+	"github.com/teo/aversome/backend/internal/series"
+)
+
+// DetectPeriod groups the areas of one period into price zones and flags the
+// areas priced away from the system price.
+func DetectPeriod(p jepx.MarketPoint, areas []string, tolerance float64) Period {
+	out := Period{
+		Timestamp:   p.Timestamp,
+		Period:      p.Period,
+		SystemPrice: p.SystemPrice,
+		Zones:       make([]Zone, 0, 1),
+	}
+
+	// Sort by price (stable, so zones keep registry order)
+	ordered := make([]string, 0, len(areas))
+	for _, area := range areas {
+		if _, ok := p.AreaPrices[area]; ok {
+			ordered = append(ordered, area)
+		}
+	}
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return p.AreaPrices[ordered[i]] < p.AreaPrices[ordered[j]]
+	})
+
+	for _, area := range ordered {
+		price := p.AreaPrices[area]
+		if n := len(out.Zones); n > 0 && math.Abs(price-out.Zones[n-1].Price) <= tolerance {
+			out.Zones[n-1].Areas = append(out.Zones[n-1].Areas, area)
+		} else {
+			out.Zones = append(out.Zones, Zone{Price: price, Areas: []string{area}})
+		}
+	}
+	for _, z := range out.Zones {
+		sort.SliceStable(z.Areas, func(i, j int) bool { return indexOf(areas, z.Areas[i]) < indexOf(areas, z.Areas[j]) })
+	}
+
+	for _, area := range areas {
+		if price, ok := p.AreaPrices[area]; ok && math.Abs(price-p.SystemPrice) > tolerance {
+			out.SplitAreas = append(out.SplitAreas, area)
+		}
+	}
+
+	out.Split = len(out.Zones) > 1
+	if len(ordered) > 0 {
+		out.MaxSpread = p.AreaPrices[ordered[len(ordered)-1]] - p.AreaPrices[ordered[0]]
+	}
+
+	return out
+}
+
+// Analyze detects market splitting in every period of days (ascending, one
+// MarketResponse per day, all at the same timescale) and aggregates daily,
+// monthly and range statistics.
+func Analyze(from, to string, days []*jepx.MarketResponse, gaps []series.Gap, opts Options) (*Response, error) {
+	if opts.Tolerance <= 0 {
+		opts.Tolerance = DefaultTolerance
+	}
+
+	resp := &Response{
+		From:      from,
+		To:        to,
+		Tolerance: opts.Tolerance,
+		Areas:     []string{},
+		Daily:     make([]Stats, 0, len(days)),
+		Monthly:   make([]Stats, 0, 1),
+		Gaps:      gaps,
+		Source:    Source{Name: "JEPX", URL: "https://www.jepx.jp/"},
+	}
+	if len(days) > 0 {
+		resp.Timescale = days[0].Timescale
+		resp.Areas = days[0].Areas
+		resp.Source = Source{Name: days[0].Source.Name, URL: days[0].Source.URL}
+	}
+
+	total := newAccumulator(from+".."+to, resp.Areas)
+	var month *accumulator
+
+	for _, day := range days {
+		if day.Timescale != resp.Timescale {
+			return nil, fmt.Errorf("%s: timescale %s differs from %s", day.Date, day.Timescale, resp.Timescale)
+		}
+
+		daily := newAccumulator(day.Date, resp.Areas)
+		if key := day.Date[:7]; month == nil || month.key != key {
+			if month != nil {
+				resp.Monthly = append(resp.Monthly, month.stats())
+			}
+			month = newAccumulator(key, resp.Areas)
+		}
+
+		for _, p := range day.Series {
+			period := DetectPeriod(p, resp.Areas, opts.Tolerance)
+			for _, acc := range []*accumulator{daily, month, total} {
+				acc.add(p, period, opts.Tolerance)
+			}
+			if opts.IncludePeriods {
+				resp.Periods = append(resp.Periods, period)
+			}
+		}
+		resp.Daily = append(resp.Daily, daily.stats())
+	}
+	if month != nil {
+		resp.Monthly = append(resp.Monthly, month.stats())
+	}
+	resp.Total = total.stats()
+
+	if opts.Pair[0] != "" || opts.Pair[1] != "" {
+		pair, err := PairSpread(days, opts.Pair[0], opts.Pair[1])
+		if err != nil {
+			return nil, err
+		}
+		resp.Pair = pair
+	}
+
+	return resp, nil
+}
+
+// PairSpread returns the A − B price spread series of two areas
+// (jepx.SystemArea selects the system price).
+func PairSpread(days []*jepx.MarketResponse, a, b string) (*Pair, error) {
+	pair := &Pair{A: a, B: b, Series: make([]SpreadPoint, 0)}
+
+	for _, day := range days {
+		for _, p := range day.Series {
+			priceA, okA := marketPrice(p, a)
+			priceB, okB := marketPrice(p, b)
+			if !okA || !okB {
+				return nil, fmt.Errorf("no %s/%s prices in JEPX market data for %s", a, b, day.Date)
+			}
+			pair.Series = append(pair.Series, SpreadPoint{Timestamp: p.Timestamp, Spread: priceA - priceB})
+		}
+	}
+	if len(pair.Series) == 0 {
+		return pair, nil
+	}
+
+	var sum float64
+	pair.Min, pair.Max = pair.Series[0], pair.Series[0]
+	for _, s := range pair.Series {
+		sum += s.Spread
+		if s.Spread < pair.Min.Spread {
+			pair.Min = s
+		}
+		if s.Spread > pair.Max.Spread {
+			pair.Max = s
+		}
+	}
+	pair.Avg = sum / float64(len(pair.Series))
+
+	var variance float64
+	for _, s := range pair.Series {
+		variance += (s.Spread - pair.Avg) * (s.Spread - pair.Avg)
+	}
+	pair.StdDev = math.Sqrt(variance / float64(len(pair.Series)))
+
+	return pair, nil
+}
+
+// marketPrice returns the price of an area (or the system price) in one period.
+func marketPrice(p jepx.MarketPoint, area string) (float64, bool) {
+	if area == jepx.SystemArea {
+		return p.SystemPrice, true
+	}
+	price, ok := p.AreaPrices[area]
+	return price, ok
+}
+
+// accumulator sums per-period results into Stats.
+type accumulator struct {
+	key          string
+	areas        []string
+	periods      int
+	splitPeriods int
+	zones        int
+	maxSpread    float64
+	byArea       map[string]*areaAccumulator
+}
+
+type areaAccumulator struct {
+	count, split int
+	sum, absSum  float64
+	min, max     float64
+}
+
+func newAccumulator(key string, areas []string) *accumulator {
+	acc := &accumulator{key: key, areas: areas, byArea: make(map[string]*areaAccumulator, len(areas))}
+	for _, area := range areas {
+		acc.byArea[area] = &areaAccumulator{min: math.Inf(1), max: math.Inf(-1)}
+	}
+	return acc
+}
+
+func (acc *accumulator) add(p jepx.MarketPoint, period Period, tolerance float64) {
+	acc.periods++
+	acc.zones += len(period.Zones)
+	acc.maxSpread += period.MaxSpread
+	if period.Split {
+		acc.splitPeriods++
+	}
+
+	for area, a := range acc.byArea {
+		price, ok := p.AreaPrices[area]
+		if !ok {
+			continue
+		}
+		diff := price - p.SystemPrice
+		a.count++
+		a.sum += diff
+		a.absSum += math.Abs(diff)
+		a.min = math.Min(a.min, diff)
+		a.max = math.Max(a.max, diff)
+		if math.Abs(diff) > tolerance {
+			a.split++
+		}
+	}
+}
+
+func (acc *accumulator) stats() Stats {
+	out := Stats{
+		Key:          acc.key,
+		Periods:      acc.periods,
+		SplitPeriods: acc.splitPeriods,
+		Areas:        make([]AreaStats, 0, len(acc.areas)),
+	}
+	if acc.periods > 0 {
+		out.SplitPct = pct(acc.splitPeriods, acc.periods)
+		out.AvgZones = float64(acc.zones) / float64(acc.periods)
+		out.AvgMaxSpread = acc.maxSpread / float64(acc.periods)
+	}
+
+	for _, area := range acc.areas {
+		a := acc.byArea[area]
+		stats := AreaStats{Area: area, SplitPeriods: a.split}
+		if a.count > 0 {
+			stats.SplitPct = pct(a.split, a.count)
+			stats.AvgSpread = a.sum / float64(a.count)
+			stats.AvgAbsSpread = a.absSum / float64(a.count)
+			stats.MinSpread, stats.MaxSpread = a.min, a.max
+		}
+		out.Areas = append(out.Areas, stats)
+	}
+
+	return out
+}
+
+func pct(part, whole int) float64 {
+	return float64(part) / float64(whole) * 100
+}
+
+func indexOf(list []string, s string) int {
+	for i, v := range list {
+		if v == s {
+			return i
+		}
+	}
+	return len(list)
+}
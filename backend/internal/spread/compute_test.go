@@ -0,0 +1,130 @@
+package spread
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/teo/aversome/backend/internal/jepx"
+)
+
+var testAreas = []string{"hokkaido", "tohoku", "tokyo", "kansai", "kyushu"}
+
+func point(ts string, system float64, prices ...float64) jepx.MarketPoint {
+	p := jepx.MarketPoint{Timestamp: ts, SystemPrice: system, AreaPrices: map[string]float64{}}
+	for i, price := range prices {
+		p.AreaPrices[testAreas[i]] = price
+	}
+	return p
+}
+
+func day(date string, points ...jepx.MarketPoint) *jepx.MarketResponse {
+	resp := jepx.NewMarketResponse(date, jepx.Timescale30Min, testAreas)
+	resp.Series = points
+	return resp
+}
+
+func approx(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestDetectPeriod(t *testing.T) {
+	tests := []struct {
+		name       string
+		point      jepx.MarketPoint
+		wantSplit  bool
+		wantZones  []Zone
+		wantAreas  []string
+		wantSpread float64
+	}{
+		{
+			name:      "coupled",
+			point:     point("t", 10, 10, 10, 10, 10, 10),
+			wantZones: []Zone{{Price: 10, Areas: testAreas}},
+		},
+		{
+			name:       "east-west split",
+			point:      point("t", 23.56, 24.32, 24.32, 24.32, 23.15, 23.15),
+			wantSplit:  true,
+			wantZones:  []Zone{{Price: 23.15, Areas: []string{"kansai", "kyushu"}}, {Price: 24.32, Areas: []string{"hokkaido", "tohoku", "tokyo"}}},
+			wantAreas:  testAreas,
+			wantSpread: 24.32 - 23.15,
+		},
+		{
+			name:       "hokkaido alone",
+			point:      point("t", 10, 15, 10, 10, 10, 10),
+			wantSplit:  true,
+			wantZones:  []Zone{{Price: 10, Areas: []string{"tohoku", "tokyo", "kansai", "kyushu"}}, {Price: 15, Areas: []string{"hokkaido"}}},
+			wantAreas:  []string{"hokkaido"},
+			wantSpread: 5,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DetectPeriod(tt.point, testAreas, DefaultTolerance)
+			if got.Split != tt.wantSplit {
+				t.Errorf("Split = %v, want %v", got.Split, tt.wantSplit)
+			}
+			if !reflect.DeepEqual(got.Zones, tt.wantZones) {
+				t.Errorf("Zones = %+v, want %+v", got.Zones, tt.wantZones)
+			}
+			if !reflect.DeepEqual(got.SplitAreas, tt.wantAreas) {
+				t.Errorf("SplitAreas = %v, want %v", got.SplitAreas, tt.wantAreas)
+			}
+			if !approx(got.MaxSpread, tt.wantSpread) {
+				t.Errorf("MaxSpread = %v, want %v", got.MaxSpread, tt.wantSpread)
+			}
+		})
+	}
+}
+
+func TestAnalyze(t *testing.T) {
+	days := []*jepx.MarketResponse{
+		day("2025-10-31",
+			point("2025-10-31T00:00:00+09:00", 10, 10, 10, 10, 10, 10),
+			point("2025-10-31T00:30:00+09:00", 11, 14, 12, 12, 10, 10)),
+		day("2025-11-01",
+			point("2025-11-01T00:00:00+09:00", 10, 10, 10, 10, 10, 10),
+			point("2025-11-01T00:30:00+09:00", 10, 10, 10, 10, 10, 10)),
+	}
+
+	resp, err := Analyze("2025-10-31", "2025-11-01", days, nil, Options{Pair: [2]string{"tokyo", "kansai"}, IncludePeriods: true})
+	if err != nil {
+		t.Fatalf("Analyze() error = %v", err)
+	}
+
+	if len(resp.Daily) != 2 || len(resp.Monthly) != 2 || len(resp.Periods) != 4 {
+		t.Fatalf("daily=%d monthly=%d periods=%d, want 2/2/4", len(resp.Daily), len(resp.Monthly), len(resp.Periods))
+	}
+	if resp.Monthly[0].Key != "2025-10" || resp.Monthly[1].Key != "2025-11" {
+		t.Errorf("Monthly keys = %s, %s", resp.Monthly[0].Key, resp.Monthly[1].Key)
+	}
+
+	oct := resp.Daily[0]
+	if oct.SplitPeriods != 1 || oct.SplitPct != 50 || !approx(oct.AvgZones, 2) || !approx(oct.AvgMaxSpread, 2) {
+		t.Errorf("2025-10-31 stats = %+v", oct)
+	}
+	hokkaido := oct.Areas[0]
+	if hokkaido.Area != "hokkaido" || hokkaido.SplitPeriods != 1 || !approx(hokkaido.AvgSpread, 1.5) || !approx(hokkaido.MaxSpread, 3) {
+		t.Errorf("hokkaido stats = %+v", hokkaido)
+	}
+	if resp.Daily[1].SplitPeriods != 0 {
+		t.Errorf("2025-11-01 split periods = %d, want 0", resp.Daily[1].SplitPeriods)
+	}
+	if resp.Total.Periods != 4 || resp.Total.SplitPct != 25 {
+		t.Errorf("Total = %+v", resp.Total)
+	}
+
+	// Tokyo − Kansai: 0, 2, 0, 0
+	if resp.Pair == nil || len(resp.Pair.Series) != 4 || !approx(resp.Pair.Avg, 0.5) || !approx(resp.Pair.Max.Spread, 2) {
+		t.Fatalf("Pair = %+v", resp.Pair)
+	}
+	if resp.Pair.Max.Timestamp != "2025-10-31T00:30:00+09:00" || !approx(resp.Pair.StdDev, math.Sqrt(0.75)) {
+		t.Errorf("Pair max = %+v, std dev = %v", resp.Pair.Max, resp.Pair.StdDev)
+	}
+
+	if _, err := Analyze("2025-10-31", "2025-11-01", days, nil, Options{Pair: [2]string{"tokyo", "okinawa"}}); err == nil {
+		t.Error("expected error for pair area without prices")
+	}
+}
@@ -0,0 +1,95 @@
+// Package spread detects JEPX market splitting and computes inter-area price
+// spread statistics from all-area market data (jepx.MarketResponse).
+package spread
+
+import "github.com/teo/aversome/backend/internal/series"
+
+// DefaultTolerance is the price difference (JPY/kWh) below which two prices
+// count as equal. JEPX publishes prices to 0.01 JPY/kWh.
+const DefaultTolerance = 0.005
+
+// Zone is a group of areas that cleared at the same price in one period.
+type Zone struct {
+	Price float64  `json:"price"` // JPY/kWh
+	Areas []string `json:"areas"` // Area codes, in registry order
+}
+
+// Period is the market-splitting analysis of one period.
+type Period struct {
+	Timestamp   string   `json:"ts"`                    // ISO8601 with Asia/Tokyo offset
+	Period      int      `json:"period,omitempty"`      // JEPX コマ number (1-48), set for 30min data
+	SystemPrice float64  `json:"system_price"`          // JPY/kWh
+	Split       bool     `json:"split"`                 // Areas cleared at more than one price
+	SplitAreas  []string `json:"split_areas,omitempty"` // Areas whose price differs from the system price
+	Zones       []Zone   `json:"zones"`                 // Price zones, cheapest first
+	MaxSpread   float64  `json:"max_spread"`            // Highest minus lowest area price
+}
+
+// AreaStats summarizes one area against the system price.
+type AreaStats struct {
+	Area         string  `json:"area"`
+	SplitPeriods int     `json:"split_periods"`  // Periods priced away from the system price
+	SplitPct     float64 `json:"split_pct"`      // SplitPeriods / periods × 100
+	AvgSpread    float64 `json:"avg_spread"`     // Mean of area − system price
+	AvgAbsSpread float64 `json:"avg_abs_spread"` // Mean of |area − system price|
+	MinSpread    float64 `json:"min_spread"`
+	MaxSpread    float64 `json:"max_spread"`
+}
+
+// Stats summarizes market splitting over a day, a month or a whole range.
+type Stats struct {
+	Key          string      `json:"key"`            // YYYY-MM-DD (daily), YYYY-MM (monthly) or from..to (total)
+	Periods      int         `json:"periods"`        // Periods analyzed
+	SplitPeriods int         `json:"split_periods"`  // Periods with more than one price zone
+	SplitPct     float64     `json:"split_pct"`      // SplitPeriods / Periods × 100
+	AvgZones     float64     `json:"avg_zones"`      // Mean number of price zones
+	AvgMaxSpread float64     `json:"avg_max_spread"` // Mean of the per-period max spread
+	Areas        []AreaStats `json:"areas"`
+}
+
+// SpreadPoint is the price difference between two areas in one period.
+type SpreadPoint struct {
+	Timestamp string  `json:"ts"`
+	Spread    float64 `json:"spread"` // JPY/kWh, A − B
+}
+
+// Pair is the spread series between two areas (e.g. Tokyo − Kansai).
+type Pair struct {
+	A      string        `json:"a"`
+	B      string        `json:"b"`
+	Series []SpreadPoint `json:"series"`
+	Avg    float64       `json:"avg"`
+	StdDev float64       `json:"std_dev"`
+	Min    SpreadPoint   `json:"min"`
+	Max    SpreadPoint   `json:"max"`
+}
+
+// Options configures Analyze.
+type Options struct {
+	Tolerance      float64   // DefaultTolerance if zero
+	Pair           [2]string // Optional area pair for a spread series (A − B)
+	IncludePeriods bool      // Include the per-period analysis in the response
+}
+
+// Response is the market-splitting analysis of a date range.
+// GET /api/spreads?from=YYYY-MM-DD&to=YYYY-MM-DD
+type Response struct {
+	From      string       `json:"from"`
+	To        string       `json:"to"`
+	Timescale string       `json:"timescale"`
+	Tolerance float64      `json:"tolerance"`         // JPY/kWh
+	Areas     []string     `json:"areas"`             // Areas analyzed
+	Total     Stats        `json:"total"`             // Whole range
+	Daily     []Stats      `json:"daily"`             // One entry per analyzed day
+	Monthly   []Stats      `json:"monthly"`           // One entry per calendar month
+	Pair      *Pair        `json:"pair,omitempty"`    // Present if Options.Pair is set
+	Periods   []Period     `json:"periods,omitempty"` // Present if Options.IncludePeriods
+	Gaps      []series.Gap `json:"gaps"`              // Days without market data
+	Source    Source       `json:"source"`
+}
+
+// Source contains attribution for price data.
+type Source struct {
+	Name string `json:"name"` // e.g., "JEPX"
+	URL  string `json:"url"`  // Original data source URL
+}
@@ -0,0 +1,77 @@
+package storage
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/teo/aversome/backend/pkg/database"
+)
+
+// Backends accepted by Config.Backend.
+const (
+	BackendFilesystem = "filesystem"
+	BackendPostgres   = "postgres"
+)
+
+// Config selects and configures a Store.
+type Config struct {
+	Backend  string          // "filesystem" (default) or "postgres"
+	DataDir  string          // Filesystem root (defaults to DefaultDataDir)
+	Database database.Config // PostgreSQL connection (DATABASE_URL)
+}
+
+// LoadConfig reads the storage configuration from environment variables:
+//   - STORAGE_BACKEND: "filesystem" (default) or "postgres"
+//   - DATA_DIR: filesystem root (default public/data/jp)
+//   - DATABASE_URL: PostgreSQL connection string
+func LoadConfig() Config {
+	cfg := Config{
+		Backend:  os.Getenv("STORAGE_BACKEND"),
+		DataDir:  os.Getenv("DATA_DIR"),
+		Database: database.DefaultConfig(),
+	}
+	if cfg.Backend == "" {
+		cfg.Backend = BackendFilesystem
+	}
+	if cfg.DataDir == "" {
+		cfg.DataDir = DefaultDataDir
+	}
+	return cfg
+}
+
+// Open creates the Store selected by cfg. The Postgres backend connects and
+// runs migrations before returning.
+func Open(cfg Config) (Store, error) {
+	switch strings.ToLower(cfg.Backend) {
+	case "", BackendFilesystem, "file", "fs":
+		dir := cfg.DataDir
+		if dir == "" {
+			dir = DefaultDataDir
+		}
+		return NewFileStore(dir), nil
+
+	case BackendPostgres, "postgresql", "db":
+		db, err := database.Connect(cfg.Database)
+		if err != nil {
+			return nil, err
+		}
+		if err := db.RunMigrations(); err != nil {
+			db.Close()
+			return nil, fmt.Errorf("failed to run migrations: %w", err)
+		}
+		return NewPostgresStore(db), nil
+
+	default:
+		return nil, fmt.Errorf("unknown storage backend %q (must be %s or %s)", cfg.Backend, BackendFilesystem, BackendPostgres)
+	}
+}
+
+// OpenOutput is Open for pipeline CLIs: a non-empty outputPath (the -output
+// flag) overrides the configured backend with a FileStore saving to that file.
+func OpenOutput(cfg Config, outputPath string) (Store, error) {
+	if outputPath != "" {
+		return &FileStore{Dir: cfg.DataDir, Path: outputPath}, nil
+	}
+	return Open(cfg)
+}
@@ -23,15 +23,17 @@ func NewDataStorage(db *database.DB) *DataStorage {
 // DataRecord represents a stored data record
 type DataRecord struct {
 	ID        int
-	DataType  string    // 'demand', 'jepx', 'reserve'
-	Area      *string   // nullable for system-wide data
+	DataType  string    // Dataset: 'demand', 'jepx', 'reserve', 'generation', 'weather'
+	Area      *string   // "" for system-wide data (nil is stored as "")
 	Date      time.Time
 	Data      json.RawMessage
 	CreatedAt time.Time
 	UpdatedAt time.Time
 }
 
-// SaveData stores or updates energy data
+// SaveData stores or updates energy data. System-wide data (nil or "" area)
+// is stored with area '' so it conflicts on UNIQUE(data_type, area, date)
+// like per-area data; NULLs never conflict and would insert a row per save.
 func (s *DataStorage) SaveData(dataType string, area *string, date time.Time, data interface{}) error {
 	jsonData, err := json.Marshal(data)
 	if err != nil {
@@ -40,7 +42,7 @@ func (s *DataStorage) SaveData(dataType string, area *string, date time.Time, da
 
 	query := `
 		INSERT INTO energy_data (data_type, area, date, data, updated_at)
-		VALUES ($1, $2, $3, $4, NOW())
+		VALUES ($1, COALESCE($2, ''), $3, $4, NOW())
 		ON CONFLICT (data_type, area, date)
 		DO UPDATE SET
 			data = EXCLUDED.data,
@@ -55,20 +57,23 @@ func (s *DataStorage) SaveData(dataType string, area *string, date time.Time, da
 	return nil
 }
 
-// GetData retrieves energy data by type, area, and date
+// GetData retrieves energy data by type, area, and date (the latest row if
+// duplicates predating the '' migration remain)
 func (s *DataStorage) GetData(dataType string, area *string, date time.Time) (json.RawMessage, error) {
 	query := `
 		SELECT data
 		FROM energy_data
 		WHERE data_type = $1
-		  AND (area = $2 OR (area IS NULL AND $2 IS NULL))
+		  AND area = COALESCE($2, '')
 		  AND date = $3
+		ORDER BY updated_at DESC, id DESC
+		LIMIT 1
 	`
 
 	var data json.RawMessage
 	err := s.db.QueryRow(query, dataType, area, date).Scan(&data)
 	if err == sql.ErrNoRows {
-		return nil, fmt.Errorf("data not found for %s/%v/%s", dataType, area, date.Format("2006-01-02"))
+		return nil, fmt.Errorf("%w for %s/%v/%s", ErrNotFound, dataType, area, date.Format("2006-01-02"))
 	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to get data: %w", err)
@@ -77,13 +82,46 @@ func (s *DataStorage) GetData(dataType string, area *string, date time.Time) (js
 	return data, nil
 }
 
+// GetDataRange retrieves energy data between two dates (inclusive), oldest first
+func (s *DataStorage) GetDataRange(dataType string, area *string, from, to time.Time) ([]Document, error) {
+	query := `
+		SELECT date, data
+		FROM energy_data
+		WHERE data_type = $1
+		  AND area = COALESCE($2, '')
+		  AND date BETWEEN $3 AND $4
+		ORDER BY date ASC
+	`
+
+	rows, err := s.db.Query(query, dataType, area, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get data range: %w", err)
+	}
+	defer rows.Close()
+
+	docs := make([]Document, 0)
+	for rows.Next() {
+		var date time.Time
+		var data json.RawMessage
+		if err := rows.Scan(&date, &data); err != nil {
+			return nil, fmt.Errorf("failed to scan data: %w", err)
+		}
+		docs = append(docs, Document{Date: date.Format("2006-01-02"), Data: data})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read data range: %w", err)
+	}
+
+	return docs, nil
+}
+
 // GetLatestData retrieves the most recent data for a given type and area
 func (s *DataStorage) GetLatestData(dataType string, area *string) (json.RawMessage, time.Time, error) {
 	query := `
 		SELECT data, date
 		FROM energy_data
 		WHERE data_type = $1
-		  AND (area = $2 OR (area IS NULL AND $2 IS NULL))
+		  AND area = COALESCE($2, '')
 		ORDER BY date DESC
 		LIMIT 1
 	`
@@ -107,7 +145,7 @@ func (s *DataStorage) ListDates(dataType string, area *string, limit int) ([]tim
 		SELECT DISTINCT date
 		FROM energy_data
 		WHERE data_type = $1
-		  AND (area = $2 OR (area IS NULL AND $2 IS NULL))
+		  AND area = COALESCE($2, '')
 		ORDER BY date DESC
 		LIMIT $3
 	`
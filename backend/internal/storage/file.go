@@ -0,0 +1,177 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// DefaultDataDir is the root of the JSON artifacts served to the frontend,
+// relative to the backend directory.
+var DefaultDataDir = filepath.Join("public", "data", "jp")
+
+// FileStore stores documents as indented JSON files under Dir using the
+// public/data/jp layout served to the frontend:
+//
+//	{area}/{dataset}-{date}.json         demand, generation, weather, imbalance, curtailment, marginal
+//	jepx/spot-{area}-{date}.json
+//	jepx/market-{date}.json
+//	jepx/intraday-{date}.json
+//	system/reserve-{date}.json
+//	system/reserve-nextday-{date}.json
+//	system/reserve-weekly-{date}.json
+//	system/alerts-{date}.json
+//	system/interconnector-{date}.json
+//
+// New datasets are stored per area unless FilePath maps them elsewhere.
+type FileStore struct {
+	Dir  string
+	Path string // Optional fixed output path for Save (CLI -output flag)
+}
+
+// NewFileStore creates a FileStore rooted at dir.
+func NewFileStore(dir string) *FileStore {
+	return &FileStore{Dir: dir}
+}
+
+// FilePath returns the file a document is stored in.
+func (s *FileStore) FilePath(dataset Dataset, area, date string) string {
+	switch dataset {
+	case DatasetJEPX:
+		return filepath.Join(s.Dir, "jepx", fmt.Sprintf("spot-%s-%s.json", area, date))
+	case DatasetJEPXMarket:
+		return filepath.Join(s.Dir, "jepx", fmt.Sprintf("market-%s.json", date))
+	case DatasetIntraday:
+		return filepath.Join(s.Dir, "jepx", fmt.Sprintf("intraday-%s.json", date))
+	case DatasetReserve:
+		return filepath.Join(s.Dir, "system", fmt.Sprintf("reserve-%s.json", date))
+	case DatasetReserveNextDay:
+		return filepath.Join(s.Dir, "system", fmt.Sprintf("reserve-nextday-%s.json", date))
+	case DatasetReserveWeekly:
+		return filepath.Join(s.Dir, "system", fmt.Sprintf("reserve-weekly-%s.json", date))
+	case DatasetAlerts:
+		return filepath.Join(s.Dir, "system", fmt.Sprintf("alerts-%s.json", date))
+	case DatasetInterconnector:
+		return filepath.Join(s.Dir, "system", fmt.Sprintf("interconnector-%s.json", date))
+	default:
+		return filepath.Join(s.Dir, area, fmt.Sprintf("%s-%s.json", dataset, date))
+	}
+}
+
+// Save writes doc as indented JSON.
+func (s *FileStore) Save(dataset Dataset, area, date string, doc any) (string, error) {
+	path := s.Path
+	if path == "" {
+		path = s.FilePath(dataset, area, date)
+	}
+
+	jsonData, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+
+	// Create output directory
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	// Write JSON file
+	if err := os.WriteFile(path, jsonData, 0644); err != nil {
+		return "", fmt.Errorf("failed to write JSON file: %w", err)
+	}
+
+	return path, nil
+}
+
+// Load reads a stored document.
+func (s *FileStore) Load(dataset Dataset, area, date string) ([]byte, error) {
+	path := s.FilePath(dataset, area, date)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return data, nil
+}
+
+// ListDates globs the dataset directory for stored dates.
+func (s *FileStore) ListDates(dataset Dataset, area string) ([]string, error) {
+	// The date is the last 10 characters before ".json" in every layout
+	pattern := s.FilePath(dataset, area, "*")
+	matches, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s: %w", pattern, err)
+	}
+
+	prefix, suffix, _ := strings.Cut(filepath.Base(pattern), "*")
+	dates := make([]string, 0, len(matches))
+	for _, m := range matches {
+		date := strings.TrimSuffix(strings.TrimPrefix(filepath.Base(m), prefix), suffix)
+		if len(date) == len("2006-01-02") {
+			dates = append(dates, date)
+		}
+	}
+	sort.Strings(dates)
+
+	return dates, nil
+}
+
+// LoadRange reads every stored document between from and to.
+func (s *FileStore) LoadRange(dataset Dataset, area, from, to string) ([]Document, error) {
+	dates, err := s.ListDates(dataset, area)
+	if err != nil {
+		return nil, err
+	}
+
+	docs := make([]Document, 0)
+	for _, date := range dates {
+		// YYYY-MM-DD compares lexically
+		if date < from || date > to {
+			continue
+		}
+		data, err := s.Load(dataset, area, date)
+		if err != nil {
+			return nil, err
+		}
+		docs = append(docs, Document{Date: date, Data: data})
+	}
+	return docs, nil
+}
+
+// Count returns the number of JSON documents under Dir.
+func (s *FileStore) Count() (int64, error) {
+	var count int64
+	err := filepath.WalkDir(s.Dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && filepath.Ext(path) == ".json" {
+			count++
+		}
+		return nil
+	})
+	if errors.Is(err, fs.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to count files: %w", err)
+	}
+	return count, nil
+}
+
+// Backend returns "filesystem".
+func (s *FileStore) Backend() string {
+	return "filesystem"
+}
+
+// Close is a no-op for the filesystem store.
+func (s *FileStore) Close() error {
+	return nil
+}
@@ -0,0 +1,113 @@
+package storage
+
+import (
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestFileStore_FilePath(t *testing.T) {
+	s := NewFileStore("data")
+
+	tests := []struct {
+		dataset Dataset
+		area    string
+		want    string
+	}{
+		{DatasetDemand, "tokyo", filepath.Join("data", "tokyo", "demand-2025-10-24.json")},
+		{DatasetJEPX, "kansai", filepath.Join("data", "jepx", "spot-kansai-2025-10-24.json")},
+		{DatasetJEPXMarket, "", filepath.Join("data", "jepx", "market-2025-10-24.json")},
+		{DatasetIntraday, "", filepath.Join("data", "jepx", "intraday-2025-10-24.json")},
+		{DatasetReserve, "", filepath.Join("data", "system", "reserve-2025-10-24.json")},
+		{DatasetReserveNextDay, "", filepath.Join("data", "system", "reserve-nextday-2025-10-24.json")},
+		{DatasetReserveWeekly, "", filepath.Join("data", "system", "reserve-weekly-2025-10-24.json")},
+		{DatasetAlerts, "", filepath.Join("data", "system", "alerts-2025-10-24.json")},
+		{DatasetInterconnector, "", filepath.Join("data", "system", "interconnector-2025-10-24.json")},
+		{DatasetGeneration, "kyushu", filepath.Join("data", "kyushu", "generation-2025-10-24.json")},
+		{DatasetImbalance, "tokyo", filepath.Join("data", "tokyo", "imbalance-2025-10-24.json")},
+		{DatasetCurtailment, "kyushu", filepath.Join("data", "kyushu", "curtailment-2025-10-24.json")},
+		{DatasetMarginal, "kyushu", filepath.Join("data", "kyushu", "marginal-2025-10-24.json")},
+		{DatasetWeather, "tokyo", filepath.Join("data", "tokyo", "weather-2025-10-24.json")},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.dataset), func(t *testing.T) {
+			if got := s.FilePath(tt.dataset, tt.area, "2025-10-24"); got != tt.want {
+				t.Errorf("FilePath() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFileStore_Overwrite(t *testing.T) {
+	testOverwrite(t, NewFileStore(t.TempDir()))
+}
+
+func TestFileStore_SaveLoadList(t *testing.T) {
+	s := NewFileStore(t.TempDir())
+
+	for _, date := range []string{"2025-10-24", "2025-10-22", "2025-10-23"} {
+		if _, err := s.Save(DatasetJEPX, "tokyo", date, map[string]string{"date": date}); err != nil {
+			t.Fatalf("Save(%s) error = %v", date, err)
+		}
+	}
+	// Different area must not show up in tokyo's dates
+	if _, err := s.Save(DatasetJEPX, "kansai", "2025-10-25", map[string]string{}); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := s.Load(DatasetJEPX, "tokyo", "2025-10-23")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if want := "{\n  \"date\": \"2025-10-23\"\n}"; string(data) != want {
+		t.Errorf("Load() = %q, want %q", data, want)
+	}
+
+	if _, err := s.Load(DatasetJEPX, "tokyo", "2025-10-30"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Load(missing) error = %v, want ErrNotFound", err)
+	}
+
+	dates, err := s.ListDates(DatasetJEPX, "tokyo")
+	if err != nil {
+		t.Fatalf("ListDates() error = %v", err)
+	}
+	if want := []string{"2025-10-22", "2025-10-23", "2025-10-24"}; !reflect.DeepEqual(dates, want) {
+		t.Errorf("ListDates() = %v, want %v", dates, want)
+	}
+
+	docs, err := s.LoadRange(DatasetJEPX, "tokyo", "2025-10-23", "2025-10-30")
+	if err != nil {
+		t.Fatalf("LoadRange() error = %v", err)
+	}
+	var rangeDates []string
+	for _, d := range docs {
+		rangeDates = append(rangeDates, d.Date)
+	}
+	if want := []string{"2025-10-23", "2025-10-24"}; !reflect.DeepEqual(rangeDates, want) {
+		t.Errorf("LoadRange() dates = %v, want %v", rangeDates, want)
+	}
+
+	count, err := s.Count()
+	if err != nil {
+		t.Fatalf("Count() error = %v", err)
+	}
+	if count != 4 {
+		t.Errorf("Count() = %d, want 4", count)
+	}
+}
+
+func TestOpen(t *testing.T) {
+	s, err := Open(Config{Backend: "filesystem", DataDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("Open(filesystem) error = %v", err)
+	}
+	if s.Backend() != "filesystem" {
+		t.Errorf("Backend() = %q, want filesystem", s.Backend())
+	}
+
+	if _, err := Open(Config{Backend: "redis"}); err == nil {
+		t.Error("Open(redis) expected error, got nil")
+	}
+}
@@ -0,0 +1,107 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// MemoryStore keeps documents in memory, upserting by dataset, area and
+// date like the Postgres backend. Used by tests and short-lived tools.
+type MemoryStore struct {
+	mu   sync.RWMutex
+	docs map[memoryKey][]byte
+}
+
+type memoryKey struct {
+	dataset Dataset
+	area    string
+	date    string
+}
+
+// NewMemoryStore creates an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{docs: make(map[memoryKey][]byte)}
+}
+
+// Save stores doc as JSON, replacing any document with the same key.
+func (s *MemoryStore) Save(dataset Dataset, area, date string, doc any) (string, error) {
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.docs[memoryKey{dataset, area, date}] = data
+
+	if area == "" {
+		return fmt.Sprintf("memory://%s/%s", dataset, date), nil
+	}
+	return fmt.Sprintf("memory://%s/%s/%s", dataset, area, date), nil
+}
+
+// Load returns the stored document.
+func (s *MemoryStore) Load(dataset Dataset, area, date string) ([]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	data, ok := s.docs[memoryKey{dataset, area, date}]
+	if !ok {
+		return nil, fmt.Errorf("%w for %s/%s/%s", ErrNotFound, dataset, area, date)
+	}
+	return data, nil
+}
+
+// ListDates returns the stored dates in ascending order.
+func (s *MemoryStore) ListDates(dataset Dataset, area string) ([]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	dates := make([]string, 0)
+	for k := range s.docs {
+		if k.dataset == dataset && k.area == area {
+			dates = append(dates, k.date)
+		}
+	}
+	sort.Strings(dates)
+	return dates, nil
+}
+
+// LoadRange returns the stored documents between from and to.
+func (s *MemoryStore) LoadRange(dataset Dataset, area, from, to string) ([]Document, error) {
+	dates, err := s.ListDates(dataset, area)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	docs := make([]Document, 0)
+	for _, date := range dates {
+		// YYYY-MM-DD compares lexically
+		if date >= from && date <= to {
+			docs = append(docs, Document{Date: date, Data: s.docs[memoryKey{dataset, area, date}]})
+		}
+	}
+	return docs, nil
+}
+
+// Count returns the number of stored documents.
+func (s *MemoryStore) Count() (int64, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return int64(len(s.docs)), nil
+}
+
+// Backend returns "memory".
+func (s *MemoryStore) Backend() string {
+	return "memory"
+}
+
+// Close is a no-op.
+func (s *MemoryStore) Close() error {
+	return nil
+}
@@ -0,0 +1,101 @@
+package storage
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/teo/aversome/backend/pkg/database"
+)
+
+// PostgresStore stores documents in the energy_data table (JSONB).
+type PostgresStore struct {
+	db   *database.DB
+	data *DataStorage
+}
+
+// NewPostgresStore creates a PostgresStore on an open, migrated database.
+func NewPostgresStore(db *database.DB) *PostgresStore {
+	return &PostgresStore{db: db, data: NewDataStorage(db)}
+}
+
+// Save upserts doc.
+func (s *PostgresStore) Save(dataset Dataset, area, dateStr string, doc any) (string, error) {
+	date, err := time.Parse("2006-01-02", dateStr)
+	if err != nil {
+		return "", fmt.Errorf("invalid date format: %s", dateStr)
+	}
+
+	if err := s.data.SaveData(string(dataset), areaPtr(area), date, doc); err != nil {
+		return "", err
+	}
+
+	if area == "" {
+		return fmt.Sprintf("database://%s/%s", dataset, dateStr), nil
+	}
+	return fmt.Sprintf("database://%s/%s/%s", dataset, area, dateStr), nil
+}
+
+// Load returns the stored document.
+func (s *PostgresStore) Load(dataset Dataset, area, dateStr string) ([]byte, error) {
+	date, err := time.Parse("2006-01-02", dateStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid date format: %s", dateStr)
+	}
+
+	return s.data.GetData(string(dataset), areaPtr(area), date)
+}
+
+// ListDates returns the stored dates in ascending order.
+func (s *PostgresStore) ListDates(dataset Dataset, area string) ([]string, error) {
+	dates, err := s.data.ListDates(string(dataset), areaPtr(area), 10000)
+	if err != nil {
+		return nil, err
+	}
+
+	// DataStorage lists newest first
+	out := make([]string, len(dates))
+	for i, d := range dates {
+		out[len(dates)-1-i] = d.Format("2006-01-02")
+	}
+	return out, nil
+}
+
+// LoadRange returns the stored documents between from and to.
+func (s *PostgresStore) LoadRange(dataset Dataset, area, fromStr, toStr string) ([]Document, error) {
+	from, err := time.Parse("2006-01-02", fromStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid date format: %s", fromStr)
+	}
+	to, err := time.Parse("2006-01-02", toStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid date format: %s", toStr)
+	}
+
+	return s.data.GetDataRange(string(dataset), areaPtr(area), from, to)
+}
+
+// Count returns the number of rows in energy_data.
+func (s *PostgresStore) Count() (int64, error) {
+	return s.data.GetDataCount()
+}
+
+// Backend returns "postgresql".
+func (s *PostgresStore) Backend() string {
+	return "postgresql"
+}
+
+// HealthCheck pings the database.
+func (s *PostgresStore) HealthCheck() error {
+	return s.db.HealthCheck()
+}
+
+// Close closes the database connection.
+func (s *PostgresStore) Close() error {
+	return s.db.Close()
+}
+
+// areaPtr returns the area column value; system-wide data is stored as an
+// empty string (not NULL) so saves upsert on UNIQUE(data_type, area, date).
+func areaPtr(area string) *string {
+	return &area
+}
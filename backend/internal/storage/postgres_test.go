@@ -0,0 +1,34 @@
+package storage
+
+import (
+	"os"
+	"testing"
+
+	"github.com/teo/aversome/backend/pkg/database"
+)
+
+// TestPostgresStore_Overwrite runs against TEST_DATABASE_URL (a disposable
+// database; the energy_data table is emptied) and is skipped without it.
+func TestPostgresStore_Overwrite(t *testing.T) {
+	url := os.Getenv("TEST_DATABASE_URL")
+	if url == "" {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+
+	cfg := database.DefaultConfig()
+	cfg.URL = url
+	db, err := database.Connect(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.RunMigrations(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(`DELETE FROM energy_data`); err != nil {
+		t.Fatal(err)
+	}
+
+	s := NewPostgresStore(db)
+	defer s.Close()
+	testOverwrite(t, s)
+}
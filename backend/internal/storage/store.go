@@ -0,0 +1,54 @@
+package storage
+
+import "errors"
+
+// Dataset identifies a kind of normalized document.
+type Dataset string
+
+const (
+	DatasetDemand     Dataset = "demand"
+	DatasetJEPX       Dataset = "jepx"
+	DatasetJEPXMarket Dataset = "jepx_market"   // System price + every area price (no area)
+	DatasetIntraday   Dataset = "jepx_intraday" // JEPX intraday market, nationwide (no area)
+	DatasetReserve    Dataset = "reserve"       // System-wide (no area)
+	DatasetGeneration Dataset = "generation"
+	DatasetWeather    Dataset = "weather"
+	DatasetImbalance  Dataset = "imbalance" // Imbalance prices (インバランス料金) per area
+
+	DatasetReserveNextDay Dataset = "reserve_nextday" // OCCTO next-day outlook by target date (no area)
+	DatasetReserveWeekly  Dataset = "reserve_weekly"  // Latest OCCTO weekly outlook by target date (no area)
+	DatasetAlerts         Dataset = "alerts"          // Alerts fired per date, for de-duplication (no area)
+	DatasetInterconnector Dataset = "interconnector"  // OCCTO interconnector (連系線) flows, every line (no area)
+	DatasetCurtailment    Dataset = "curtailment"     // Renewable output curtailment (出力制御) events per area
+	DatasetMarginal       Dataset = "marginal"        // Marginal emission factors per area, estimated from generation
+)
+
+// ErrNotFound is returned (wrapped) when no document is stored for a key.
+var ErrNotFound = errors.New("data not found")
+
+// Document is a stored document returned by LoadRange.
+type Document struct {
+	Date string // YYYY-MM-DD
+	Data []byte
+}
+
+// Store is the repository for normalized JSON documents, keyed by
+// dataset, area ("" for system-wide data) and date (YYYY-MM-DD).
+type Store interface {
+	// Save stores doc and returns a human-readable location (file path or URI).
+	Save(dataset Dataset, area, date string, doc any) (string, error)
+	// Load returns the stored document, or an error wrapping ErrNotFound.
+	Load(dataset Dataset, area, date string) ([]byte, error)
+	// ListDates returns the stored dates for dataset/area in ascending order.
+	ListDates(dataset Dataset, area string) ([]string, error)
+	// LoadRange returns the documents stored for dataset/area between from and
+	// to (inclusive, YYYY-MM-DD) in ascending date order. Missing days are
+	// simply absent.
+	LoadRange(dataset Dataset, area, from, to string) ([]Document, error)
+	// Count returns the total number of stored documents.
+	Count() (int64, error)
+	// Backend names the implementation ("filesystem", "postgresql").
+	Backend() string
+	// Close releases resources held by the store.
+	Close() error
+}
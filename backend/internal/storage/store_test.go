@@ -0,0 +1,44 @@
+package storage
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// testOverwrite saves the same key twice, system-wide and per area, and
+// checks that reads return the second document and only one is stored.
+func testOverwrite(t *testing.T, s Store) {
+	t.Helper()
+
+	for _, key := range []struct {
+		dataset Dataset
+		area    string
+	}{
+		{DatasetReserve, ""},
+		{DatasetDemand, "tokyo"},
+	} {
+		for _, version := range []string{"first", "second"} {
+			if _, err := s.Save(key.dataset, key.area, "2025-10-24", map[string]string{"version": version}); err != nil {
+				t.Fatalf("Save(%s %q, %s) error = %v", key.dataset, key.area, version, err)
+			}
+		}
+
+		data, err := s.Load(key.dataset, key.area, "2025-10-24")
+		if err != nil {
+			t.Fatalf("Load(%s %q) error = %v", key.dataset, key.area, err)
+		}
+		var doc map[string]string
+		if err := json.Unmarshal(data, &doc); err != nil || doc["version"] != "second" {
+			t.Errorf("Load(%s %q) = %s, %v; want the second document", key.dataset, key.area, data, err)
+		}
+
+		docs, err := s.LoadRange(key.dataset, key.area, "2025-10-24", "2025-10-24")
+		if err != nil || len(docs) != 1 {
+			t.Errorf("LoadRange(%s %q) = %d documents, %v; want 1", key.dataset, key.area, len(docs), err)
+		}
+	}
+}
+
+func TestMemoryStore_Overwrite(t *testing.T) {
+	testOverwrite(t, NewMemoryStore())
+}
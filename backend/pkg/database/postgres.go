@@ -71,14 +71,23 @@ func (db *DB) RunMigrations() error {
 	migrations := []string{
 		`CREATE TABLE IF NOT EXISTS energy_data (
 			id SERIAL PRIMARY KEY,
-			data_type VARCHAR(50) NOT NULL,  -- 'demand', 'jepx', 'reserve'
-			area VARCHAR(50),                -- 'tokyo', 'kansai', NULL for system-wide
+			data_type VARCHAR(50) NOT NULL,  -- 'demand', 'jepx', 'jepx_market', 'jepx_intraday', 'reserve', 'reserve_nextday', 'reserve_weekly', 'generation', 'weather', 'imbalance', 'alerts', 'interconnector', 'curtailment', 'marginal'
+			area VARCHAR(50) NOT NULL DEFAULT '', -- 'tokyo', 'kansai', '' for system-wide
 			date DATE NOT NULL,
 			data JSONB NOT NULL,
 			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
 			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
 			UNIQUE(data_type, area, date)
 		)`,
+		// System-wide rows used to have a NULL area, which never conflicts on
+		// UNIQUE(data_type, area, date): keep the latest row per key, store ''
+		`DELETE FROM energy_data a USING energy_data b
+			WHERE a.area IS NULL AND b.area IS NULL
+			  AND a.data_type = b.data_type AND a.date = b.date
+			  AND (COALESCE(a.updated_at, 'epoch'), a.id) < (COALESCE(b.updated_at, 'epoch'), b.id)`,
+		`UPDATE energy_data SET area = '' WHERE area IS NULL`,
+		`ALTER TABLE energy_data ALTER COLUMN area SET DEFAULT ''`,
+		`ALTER TABLE energy_data ALTER COLUMN area SET NOT NULL`,
 		`CREATE INDEX IF NOT EXISTS idx_energy_data_lookup ON energy_data(data_type, area, date)`,
 		`CREATE INDEX IF NOT EXISTS idx_energy_data_date ON energy_data(date DESC)`,
 		`CREATE INDEX IF NOT EXISTS idx_energy_data_jsonb ON energy_data USING GIN (data)`,
@@ -0,0 +1,163 @@
+// Package mail sends multipart (plain text + HTML) email over SMTP and renders
+// the localized message templates of the alert and digest emails.
+package mail
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultPort is the SMTP submission port.
+const DefaultPort = 587
+
+// Config holds the SMTP server settings.
+type Config struct {
+	Host     string // SMTP server host
+	Port     int    // SMTP server port (default DefaultPort)
+	Username string // Optional; enables AUTH PLAIN (TLS or localhost only)
+	Password string
+	From     string // Sender address
+}
+
+// LoadConfig reads the SMTP configuration from environment variables:
+//   - SMTP_HOST: server host (required to send)
+//   - SMTP_PORT: server port (default 587; e.g., 1025 for a local Mailpit)
+//   - SMTP_USERNAME, SMTP_PASSWORD: optional credentials
+//   - SMTP_FROM: sender address (default SMTP_USERNAME)
+func LoadConfig() (Config, error) {
+	cfg := Config{
+		Host:     os.Getenv("SMTP_HOST"),
+		Port:     DefaultPort,
+		Username: os.Getenv("SMTP_USERNAME"),
+		Password: os.Getenv("SMTP_PASSWORD"),
+		From:     os.Getenv("SMTP_FROM"),
+	}
+	if s := os.Getenv("SMTP_PORT"); s != "" {
+		port, err := strconv.Atoi(s)
+		if err != nil || port <= 0 || port > 65535 {
+			return Config{}, fmt.Errorf("invalid SMTP_PORT %q", s)
+		}
+		cfg.Port = port
+	}
+	if cfg.From == "" {
+		cfg.From = cfg.Username
+	}
+	return cfg, nil
+}
+
+// Validate checks that the configuration can send.
+func (c Config) Validate() error {
+	if c.Host == "" {
+		return errors.New("SMTP host is required (SMTP_HOST)")
+	}
+	if c.From == "" {
+		return errors.New("sender address is required (SMTP_FROM)")
+	}
+	return nil
+}
+
+// Message is one email with plain-text and HTML alternatives.
+type Message struct {
+	To      []string
+	Subject string
+	Text    string
+	HTML    string
+}
+
+// Sender delivers messages through one SMTP server.
+type Sender struct {
+	cfg Config
+	now func() time.Time
+}
+
+// NewSender creates a Sender.
+func NewSender(cfg Config) *Sender {
+	if cfg.Port == 0 {
+		cfg.Port = DefaultPort
+	}
+	return &Sender{cfg: cfg, now: time.Now}
+}
+
+// Send delivers msg to its recipients. The server's STARTTLS is used when
+// offered.
+func (s *Sender) Send(msg Message) error {
+	if err := s.cfg.Validate(); err != nil {
+		return err
+	}
+	if len(msg.To) == 0 {
+		return errors.New("no recipients")
+	}
+
+	body, err := s.encode(msg)
+	if err != nil {
+		return fmt.Errorf("failed to encode message: %w", err)
+	}
+
+	var auth smtp.Auth
+	if s.cfg.Username != "" {
+		auth = smtp.PlainAuth("", s.cfg.Username, s.cfg.Password, s.cfg.Host)
+	}
+	addr := net.JoinHostPort(s.cfg.Host, strconv.Itoa(s.cfg.Port))
+	if err := smtp.SendMail(addr, auth, s.cfg.From, msg.To, body); err != nil {
+		return fmt.Errorf("SMTP delivery to %s failed: %w", addr, err)
+	}
+	return nil
+}
+
+// encode builds the RFC 5322 message: a multipart/alternative body with
+// quoted-printable UTF-8 parts, text first.
+func (s *Sender) encode(msg Message) ([]byte, error) {
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+
+	header := []string{
+		"From: " + s.cfg.From,
+		"To: " + strings.Join(msg.To, ", "),
+		"Subject: " + mime.BEncoding.Encode("UTF-8", msg.Subject),
+		"Date: " + s.now().Format(time.RFC1123Z),
+		"MIME-Version: 1.0",
+		`Content-Type: multipart/alternative; boundary="` + mw.Boundary() + `"`,
+	}
+	var out bytes.Buffer
+	out.WriteString(strings.Join(header, "\r\n") + "\r\n\r\n")
+
+	for _, part := range []struct{ contentType, body string }{
+		{"text/plain", msg.Text},
+		{"text/html", msg.HTML},
+	} {
+		if part.body == "" {
+			continue
+		}
+		w, err := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType + "; charset=UTF-8"},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		qp := quotedprintable.NewWriter(w)
+		if _, err := qp.Write([]byte(part.body)); err != nil {
+			return nil, err
+		}
+		if err := qp.Close(); err != nil {
+			return nil, err
+		}
+	}
+	if err := mw.Close(); err != nil {
+		return nil, err
+	}
+
+	out.Write(buf.Bytes())
+	return out.Bytes(), nil
+}
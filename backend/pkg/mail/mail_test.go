@@ -0,0 +1,189 @@
+package mail
+
+import (
+	"bufio"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	netmail "net/mail"
+	"strconv"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+// smtpStandIn is a minimal SMTP server that records one message per session.
+type smtpStandIn struct {
+	addr string
+	rcpt []string
+	data chan string
+}
+
+func newSMTPStandIn(t *testing.T) *smtpStandIn {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	s := &smtpStandIn{addr: ln.Addr().String(), data: make(chan string, 1)}
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		r := bufio.NewReader(conn)
+		reply := func(line string) { io.WriteString(conn, line+"\r\n") }
+		reply("220 localhost ESMTP stand-in")
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			cmd := strings.ToUpper(strings.TrimSpace(line))
+			switch {
+			case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+				reply("250 localhost")
+			case strings.HasPrefix(cmd, "RCPT TO:"):
+				s.rcpt = append(s.rcpt, strings.Trim(strings.TrimSpace(line)[8:], "<>"))
+				reply("250 OK")
+			case cmd == "DATA":
+				reply("354 End data with <CR><LF>.<CR><LF>")
+				var msg strings.Builder
+				for {
+					l, err := r.ReadString('\n')
+					if err != nil {
+						return
+					}
+					if l == ".\r\n" {
+						break
+					}
+					msg.WriteString(l)
+				}
+				s.data <- msg.String()
+				reply("250 OK")
+			case cmd == "QUIT":
+				reply("221 Bye")
+				return
+			default:
+				reply("250 OK")
+			}
+		}
+	}()
+	return s
+}
+
+func TestSender_Send(t *testing.T) {
+	server := newSMTPStandIn(t)
+	host, port, _ := net.SplitHostPort(server.addr)
+	portNum, _ := strconv.Atoi(port)
+	cfg := Config{Host: host, Port: portNum, From: "dashboard@example.com"}
+
+	msg := Message{
+		To:      []string{"ops@example.com", "manager@example.com"},
+		Subject: "需給日報 2025-10-23",
+		Text:    "東京 ピーク需要 41,234 MW\n",
+		HTML:    "<p>東京 ピーク需要 41,234 MW</p>",
+	}
+	if err := NewSender(cfg).Send(msg); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	if strings.Join(server.rcpt, ",") != "ops@example.com,manager@example.com" {
+		t.Errorf("recipients = %v", server.rcpt)
+	}
+
+	parsed, err := netmail.ReadMessage(strings.NewReader(<-server.data))
+	if err != nil {
+		t.Fatalf("failed to parse message: %v", err)
+	}
+	subject, err := new(mime.WordDecoder).DecodeHeader(parsed.Header.Get("Subject"))
+	if err != nil || subject != msg.Subject {
+		t.Errorf("Subject = %q (%v), want %q", subject, err, msg.Subject)
+	}
+
+	mediaType, params, err := mime.ParseMediaType(parsed.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/alternative" {
+		t.Fatalf("Content-Type = %q", parsed.Header.Get("Content-Type"))
+	}
+	mr := multipart.NewReader(parsed.Body, params["boundary"])
+	for _, want := range []struct{ contentType, body string }{
+		{"text/plain; charset=UTF-8", msg.Text},
+		{"text/html; charset=UTF-8", msg.HTML},
+	} {
+		part, err := mr.NextPart() // Decodes quoted-printable
+		if err != nil {
+			t.Fatalf("NextPart() error = %v", err)
+		}
+		body, _ := io.ReadAll(part)
+		got := strings.ReplaceAll(string(body), "\r\n", "\n")
+		if part.Header.Get("Content-Type") != want.contentType || got != want.body {
+			t.Errorf("part %s = %q, want %q", part.Header.Get("Content-Type"), got, want.body)
+		}
+	}
+}
+
+func TestSender_Errors(t *testing.T) {
+	if err := NewSender(Config{From: "a@example.com"}).Send(Message{To: []string{"b@example.com"}}); err == nil || !strings.Contains(err.Error(), "SMTP_HOST") {
+		t.Errorf("Send() without host error = %v", err)
+	}
+	if err := NewSender(Config{Host: "localhost", From: "a@example.com"}).Send(Message{}); err == nil || !strings.Contains(err.Error(), "no recipients") {
+		t.Errorf("Send() without recipients error = %v", err)
+	}
+}
+
+func TestTemplates_Render(t *testing.T) {
+	fsys := fstest.MapFS{
+		"hello.ja.txt":  {Data: []byte(`{{define "subject"}}こんにちは {{.Name}}{{end}}{{greet}} {{.Name}}`)},
+		"hello.ja.html": {Data: []byte(`<p>{{greet}} {{.Name}}</p>`)},
+		"hello.en.txt":  {Data: []byte(`{{define "subject"}}Hello {{.Name}}{{end}}{{greet}} {{.Name}}`)},
+		"hello.en.html": {Data: []byte(`<p>{{greet}} {{.Name}}</p>`)},
+	}
+	funcs := func(lang Lang) map[string]any {
+		return map[string]any{"greet": func() string {
+			if lang == LangJA {
+				return "やあ"
+			}
+			return "Hi"
+		}}
+	}
+
+	tmpl, err := ParseTemplates(fsys, "hello", funcs)
+	if err != nil {
+		t.Fatalf("ParseTemplates() error = %v", err)
+	}
+
+	msg, err := tmpl.Render(LangJA, map[string]string{"Name": "<東京>"})
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if msg.Subject != "こんにちは <東京>" || msg.Text != "やあ <東京>\n" || msg.HTML != "<p>やあ &lt;東京&gt;</p>" {
+		t.Errorf("Render(ja) = %+v", msg)
+	}
+
+	msg, _ = tmpl.Render(LangEN, map[string]string{"Name": "Tokyo"})
+	if msg.Subject != "Hello Tokyo" {
+		t.Errorf("Render(en) subject = %q", msg.Subject)
+	}
+
+	// Every language needs a subject
+	fsys["hello.en.txt"] = &fstest.MapFile{Data: []byte(`Hi`)}
+	if _, err := ParseTemplates(fsys, "hello", funcs); err == nil || !strings.Contains(err.Error(), "missing subject") {
+		t.Errorf("ParseTemplates() error = %v, want missing subject", err)
+	}
+}
+
+func TestParseLang(t *testing.T) {
+	for in, want := range map[string]Lang{"": LangJA, "ja": LangJA, "EN": LangEN} {
+		if got, err := ParseLang(in); err != nil || got != want {
+			t.Errorf("ParseLang(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	if _, err := ParseLang("fr"); err == nil {
+		t.Error("ParseLang(fr) should fail")
+	}
+}
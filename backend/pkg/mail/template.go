@@ -0,0 +1,109 @@
+package mail
+
+import (
+	"bytes"
+	"fmt"
+	htmltemplate "html/template"
+	"io/fs"
+	"path"
+	"strings"
+	texttemplate "text/template"
+)
+
+// Lang is the language of a rendered message.
+type Lang string
+
+const (
+	LangJA Lang = "ja" // Default
+	LangEN Lang = "en"
+)
+
+// Langs lists the supported languages.
+var Langs = []Lang{LangJA, LangEN}
+
+// ParseLang validates a language code; empty selects LangJA.
+func ParseLang(s string) (Lang, error) {
+	switch Lang(strings.ToLower(strings.TrimSpace(s))) {
+	case "", LangJA:
+		return LangJA, nil
+	case LangEN:
+		return LangEN, nil
+	default:
+		return "", fmt.Errorf("unsupported language %q (must be ja or en)", s)
+	}
+}
+
+// Templates holds one message in every language: "{name}.{lang}.txt" (text
+// body, with the subject in a "subject" block) and "{name}.{lang}.html".
+type Templates struct {
+	text map[Lang]*texttemplate.Template
+	html map[Lang]*htmltemplate.Template
+}
+
+// ParseTemplates parses the templates of name (a path in fsys). funcs returns
+// the template functions for a language (e.g., localized area names); it may
+// be nil.
+func ParseTemplates(fsys fs.FS, name string, funcs func(Lang) map[string]any) (*Templates, error) {
+	t := &Templates{
+		text: make(map[Lang]*texttemplate.Template, len(Langs)),
+		html: make(map[Lang]*htmltemplate.Template, len(Langs)),
+	}
+
+	for _, lang := range Langs {
+		var fm map[string]any
+		if funcs != nil {
+			fm = funcs(lang)
+		}
+
+		base := fmt.Sprintf("%s.%s", name, lang)
+		text, err := texttemplate.New(path.Base(base)+".txt").Funcs(fm).ParseFS(fsys, base+".txt")
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s.txt: %w", base, err)
+		}
+		if text.Lookup("subject") == nil {
+			return nil, fmt.Errorf("%s.txt: missing subject block", base)
+		}
+		html, err := htmltemplate.New(path.Base(base)+".html").Funcs(fm).ParseFS(fsys, base+".html")
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s.html: %w", base, err)
+		}
+
+		t.text[lang], t.html[lang] = text, html
+	}
+	return t, nil
+}
+
+// MustParseTemplates is ParseTemplates for embedded templates; it panics on error.
+func MustParseTemplates(fsys fs.FS, name string, funcs func(Lang) map[string]any) *Templates {
+	t, err := ParseTemplates(fsys, name, funcs)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// Render executes the templates of lang with data. The returned message has
+// no recipients.
+func (t *Templates) Render(lang Lang, data any) (Message, error) {
+	text, ok := t.text[lang]
+	if !ok {
+		return Message{}, fmt.Errorf("unsupported language %q", lang)
+	}
+
+	var subject, body, html bytes.Buffer
+	if err := text.ExecuteTemplate(&subject, "subject", data); err != nil {
+		return Message{}, fmt.Errorf("failed to render subject: %w", err)
+	}
+	if err := text.Execute(&body, data); err != nil {
+		return Message{}, fmt.Errorf("failed to render text body: %w", err)
+	}
+	if err := t.html[lang].Execute(&html, data); err != nil {
+		return Message{}, fmt.Errorf("failed to render HTML body: %w", err)
+	}
+
+	return Message{
+		Subject: strings.Join(strings.Fields(subject.String()), " "),
+		Text:    strings.TrimSpace(body.String()) + "\n",
+		HTML:    html.String(),
+	}, nil
+}
@@ -4,6 +4,8 @@ package timeutil
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -49,3 +51,85 @@ func HourlySlots(date time.Time) []time.Time {
 func FormatISO8601(t time.Time) string {
 	return t.In(TokyoLocation).Format("2006-01-02T15:04:05+09:00")
 }
+
+// Timescale identifiers shared by demand, JEPX and generation responses.
+const (
+	TimescaleHourly = "hourly" // 24 points per day
+	Timescale30Min  = "30min"  // 48 points per day (JEPX/OCCTO コマ)
+)
+
+// SlotsPerDay is the number of 30-minute settlement periods (コマ) in a day.
+const SlotsPerDay = 48
+
+// SlotDuration is the length of one settlement period.
+const SlotDuration = 30 * time.Minute
+
+// PointsPerDay returns the expected number of points per day for a timescale.
+// Returns 0 for unknown timescales.
+func PointsPerDay(timescale string) int {
+	switch timescale {
+	case TimescaleHourly:
+		return 24
+	case Timescale30Min:
+		return SlotsPerDay
+	default:
+		return 0
+	}
+}
+
+// ValidateTimescale returns an error unless timescale is "hourly" or "30min".
+func ValidateTimescale(timescale string) error {
+	if PointsPerDay(timescale) == 0 {
+		return fmt.Errorf("invalid timescale %q (expected %q or %q)", timescale, TimescaleHourly, Timescale30Min)
+	}
+	return nil
+}
+
+// HalfHourSlots generates 48 half-hourly timestamps for a given date in Asia/Tokyo.
+// Returns timestamps from 00:00 to 23:30 (inclusive); index i is koma i+1.
+func HalfHourSlots(date time.Time) []time.Time {
+	base := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, TokyoLocation)
+	slots := make([]time.Time, SlotsPerDay)
+	for i := 0; i < SlotsPerDay; i++ {
+		slots[i] = base.Add(time.Duration(i) * SlotDuration)
+	}
+	return slots
+}
+
+// SlotTime returns the start time of a 0-based half-hour slot on a given date.
+func SlotTime(date time.Time, slot int) time.Time {
+	base := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, TokyoLocation)
+	return base.Add(time.Duration(slot) * SlotDuration)
+}
+
+// SlotIndex returns the 0-based half-hour slot (0-47) containing t in Asia/Tokyo.
+func SlotIndex(t time.Time) int {
+	t = t.In(TokyoLocation)
+	return t.Hour()*2 + t.Minute()/30
+}
+
+// SlotFromPeriod converts a 1-based JEPX period number (コマ 1-48) to a 0-based slot.
+func SlotFromPeriod(period int) (int, error) {
+	if period < 1 || period > SlotsPerDay {
+		return 0, fmt.Errorf("invalid period %d (expected 1-%d)", period, SlotsPerDay)
+	}
+	return period - 1, nil
+}
+
+// ParseSlot converts a clock time ("00:00", "13:30", "9:30:00") to a 0-based
+// half-hour slot. Minutes are truncated to the start of their slot.
+func ParseSlot(clock string) (int, error) {
+	parts := strings.Split(strings.TrimSpace(clock), ":")
+	if len(parts) < 2 {
+		return 0, fmt.Errorf("invalid time format: %q", clock)
+	}
+	hour, errH := strconv.Atoi(parts[0])
+	minute, errM := strconv.Atoi(parts[1])
+	if errH != nil || errM != nil {
+		return 0, fmt.Errorf("invalid time format: %q", clock)
+	}
+	if hour < 0 || hour > 23 || minute < 0 || minute > 59 {
+		return 0, fmt.Errorf("time out of range: %q", clock)
+	}
+	return hour*2 + minute/30, nil
+}
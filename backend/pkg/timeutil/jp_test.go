@@ -0,0 +1,131 @@
+package timeutil
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHalfHourSlots(t *testing.T) {
+	date := time.Date(2025, 10, 24, 15, 45, 0, 0, time.UTC) // 2025-10-25 00:45 JST, the date part is used as is
+
+	slots := HalfHourSlots(date)
+	if len(slots) != SlotsPerDay {
+		t.Fatalf("len = %d, want %d", len(slots), SlotsPerDay)
+	}
+	if got := FormatISO8601(slots[0]); got != "2025-10-24T00:00:00+09:00" {
+		t.Errorf("slots[0] = %s, want 2025-10-24T00:00:00+09:00", got)
+	}
+	if got := FormatISO8601(slots[47]); got != "2025-10-24T23:30:00+09:00" {
+		t.Errorf("slots[47] = %s, want 2025-10-24T23:30:00+09:00", got)
+	}
+	for i := 1; i < len(slots); i++ {
+		if d := slots[i].Sub(slots[i-1]); d != SlotDuration {
+			t.Errorf("slots[%d] - slots[%d] = %v, want %v", i, i-1, d, SlotDuration)
+		}
+	}
+}
+
+func TestSlotTime(t *testing.T) {
+	date := time.Date(2025, 10, 24, 0, 0, 0, 0, TokyoLocation)
+
+	tests := []struct {
+		slot int
+		want string
+	}{
+		{0, "2025-10-24T00:00:00+09:00"},
+		{1, "2025-10-24T00:30:00+09:00"},
+		{26, "2025-10-24T13:00:00+09:00"},
+		{47, "2025-10-24T23:30:00+09:00"},
+		{48, "2025-10-25T00:00:00+09:00"}, // Next day's first slot
+	}
+
+	for _, tt := range tests {
+		if got := FormatISO8601(SlotTime(date, tt.slot)); got != tt.want {
+			t.Errorf("SlotTime(%d) = %s, want %s", tt.slot, got, tt.want)
+		}
+	}
+}
+
+func TestSlotIndex(t *testing.T) {
+	tests := []struct {
+		name string
+		t    time.Time
+		want int
+	}{
+		{"midnight", time.Date(2025, 10, 24, 0, 0, 0, 0, TokyoLocation), 0},
+		{"before half past", time.Date(2025, 10, 24, 0, 29, 59, 0, TokyoLocation), 0},
+		{"half past", time.Date(2025, 10, 24, 0, 30, 0, 0, TokyoLocation), 1},
+		{"13:45", time.Date(2025, 10, 24, 13, 45, 0, 0, TokyoLocation), 27},
+		{"23:30", time.Date(2025, 10, 24, 23, 30, 0, 0, TokyoLocation), 47},
+		{"23:59", time.Date(2025, 10, 24, 23, 59, 59, 0, TokyoLocation), 47},
+		{"UTC converted to JST", time.Date(2025, 10, 24, 14, 30, 0, 0, time.UTC), 47}, // 23:30 JST
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SlotIndex(tt.t); got != tt.want {
+				t.Errorf("SlotIndex(%v) = %d, want %d", tt.t, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSlotFromPeriod(t *testing.T) {
+	tests := []struct {
+		period    int
+		want      int
+		wantError bool
+	}{
+		{1, 0, false},
+		{27, 26, false},
+		{48, 47, false},
+		{0, 0, true},
+		{49, 0, true},
+		{-1, 0, true},
+	}
+
+	for _, tt := range tests {
+		got, err := SlotFromPeriod(tt.period)
+		if (err != nil) != tt.wantError {
+			t.Errorf("SlotFromPeriod(%d) error = %v, wantError %v", tt.period, err, tt.wantError)
+			continue
+		}
+		if !tt.wantError && got != tt.want {
+			t.Errorf("SlotFromPeriod(%d) = %d, want %d", tt.period, got, tt.want)
+		}
+	}
+}
+
+func TestParseSlot(t *testing.T) {
+	tests := []struct {
+		clock     string
+		want      int
+		wantError bool
+	}{
+		{"00:00", 0, false},
+		{"0:29", 0, false},
+		{"00:30", 1, false},
+		{"9:30:00", 19, false},
+		{" 13:30 ", 27, false},
+		{"23:30", 47, false},
+		{"23:59", 47, false},
+		{"24:00", 0, true}, // End of day, not a slot start
+		{"-1:00", 0, true},
+		{"12:60", 0, true},
+		{"12", 0, true},
+		{"ab:cd", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.clock, func(t *testing.T) {
+			got, err := ParseSlot(tt.clock)
+			if (err != nil) != tt.wantError {
+				t.Errorf("ParseSlot(%q) error = %v, wantError %v", tt.clock, err, tt.wantError)
+				return
+			}
+			if !tt.wantError && got != tt.want {
+				t.Errorf("ParseSlot(%q) = %d, want %d", tt.clock, got, tt.want)
+			}
+		})
+	}
+}
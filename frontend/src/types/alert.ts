@@ -0,0 +1,26 @@
+// Alert types matching backend/internal/alert/types.go
+
+export type AlertKind = 'reserve_margin' | 'spot_price' | 'spread' | 'demand_forecast'
+
+export type AlertSeverity = 'info' | 'warning' | 'critical'
+
+export interface Alert {
+  key: string // De-duplication key: rule/area/date
+  rule_id: string
+  kind: AlertKind
+  severity: AlertSeverity
+  area: string // Area code or "system"
+  date: string // YYYY-MM-DD
+  ts: string // Interval of the worst value, ISO8601
+  value: number
+  threshold: number
+  message: string
+  fired_at: string // ISO8601
+  pending?: boolean // Webhook delivery failed, retried on the next ingest
+}
+
+// GET /api/alerts/:date
+export interface AlertDay {
+  date: string
+  alerts: Alert[]
+}
@@ -0,0 +1,35 @@
+// Carbon intensity types matching backend/internal/generation/carbon.go
+
+import type { EmissionBasis, EmissionFuel } from './emission'
+import type { GenerationSource } from './generation'
+
+export interface CarbonPoint {
+  ts: string // Interval start, ISO8601
+  avg_gco2_kwh: number // Emissions / generated energy
+  merit_order_gco2_kwh: number // Factor of merit_order_fuel, a marginal intensity heuristic (measured: /api/marginal)
+  merit_order_fuel: EmissionFuel // Most expensive fuel generating (oil, lng, coal; hydro if none)
+  generated_mw: number // Area generation, without storage and imports
+  emissions_tco2_h: number
+}
+
+export interface CarbonSummary {
+  avg_gco2_kwh: number // Energy-weighted daily average
+  min_gco2_kwh: number
+  min_ts?: string
+  max_gco2_kwh: number
+  max_ts?: string
+  avg_merit_order_gco2_kwh: number
+}
+
+// GET /api/carbon/{area}/{date}?factors=&timescale=
+export interface CarbonResponse {
+  date: string // YYYY-MM-DD
+  area: string
+  timezone: string // Asia/Tokyo
+  timescale: 'hourly' | '30min'
+  emission_factor_set: string
+  emission_factor_basis: EmissionBasis
+  series: CarbonPoint[]
+  summary: CarbonSummary
+  source: GenerationSource
+}
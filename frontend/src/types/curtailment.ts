@@ -0,0 +1,47 @@
+// Curtailment types matching backend/internal/curtailment/types.go
+
+export type CurtailedFuel = 'solar' | 'wind'
+
+export type CurtailmentReason = 'oversupply' | 'grid_constraint' | 'other'
+
+export interface CurtailmentEvent {
+  start: string // ISO8601
+  end: string // ISO8601, exclusive
+  fuel: CurtailedFuel
+  curtailed_mw: number // 出力制御量
+  reason: CurtailmentReason
+  reason_text?: string // As published
+}
+
+export interface CurtailmentPoint {
+  ts: string // Interval start, ISO8601
+  solar_mw: number
+  wind_mw: number
+}
+
+export interface CurtailmentSummary {
+  events: number
+  curtailed_mwh: number
+  solar_mwh: number
+  wind_mwh: number
+  peak_curtailed_mw: number
+  peak_ts?: string
+  curtailed_intervals: number
+}
+
+// GET /api/curtailment/:area/:date
+export interface CurtailmentResponse {
+  date: string
+  area: string
+  timescale: '30min'
+  events: CurtailmentEvent[]
+  series: CurtailmentPoint[]
+  summary: CurtailmentSummary
+  source: {
+    name: string
+    url: string
+  }
+  meta?: {
+    warning?: string
+  }
+}
@@ -2,7 +2,7 @@
 
 export type Area = 'tokyo' | 'kansai'
 
-export type Timescale = 'hourly'
+export type Timescale = 'hourly' | '30min'
 
 export interface Source {
   name: string
@@ -0,0 +1,27 @@
+// Emission factor types matching backend/internal/emission/registry.go
+
+export type EmissionFuel =
+  | 'solar' | 'wind' | 'hydro' | 'geothermal' | 'biomass'
+  | 'nuclear' | 'lng' | 'coal' | 'oil' | 'other'
+
+export type EmissionBasis = 'combustion' | 'lifecycle'
+
+export type EmissionFactors = Partial<Record<EmissionFuel, number>> // gCO2/kWh, missing fuels are 0
+
+export interface EmissionFactorSet {
+  id: string // e.g. "criepi-2016-lifecycle", selected with ?factors=
+  name?: string
+  basis: EmissionBasis
+  source?: string // Publication the factors come from
+  note?: string
+  version: string // Version of the factor data file
+  factors: EmissionFactors
+  areas?: Record<string, EmissionFactors> // Per-area overrides of single fuels
+}
+
+// GET /api/emission-factors
+export interface EmissionFactorsResponse {
+  version: string
+  default: string // Set used without ?factors=
+  sets: EmissionFactorSet[]
+}
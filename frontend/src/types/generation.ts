@@ -5,12 +5,21 @@ export interface GenerationPoint {
   ts: string  // ISO8601 timestamp
   solar_mw: number
   wind_mw: number
-  hydro_mw: number
+  hydro_mw: number    // Conventional hydro, without pumped storage
   nuclear_mw: number
   lng_mw: number
   coal_mw: number
-  other_mw: number
-  total_mw: number
+  other_mw: number    // Other thermal and uncategorized
+  total_mw: number    // Sum of every category (storage and imports signed)
+  // OCCTO categories, omitted when zero (estimated data has none)
+  oil_mw?: number
+  biomass_mw?: number
+  geothermal_mw?: number
+  pumped_storage_mw?: number  // Generation minus pumping (negative while pumping)
+  battery_mw?: number         // Discharge minus charge
+  interconnector_mw?: number  // Net imports
+  curtailed_solar_mw?: number  // ?curtailment=true: output curtailed (出力制御), not in total_mw
+  curtailed_wind_mw?: number
 }
 
 export interface GenerationSource {
@@ -19,10 +28,15 @@ export interface GenerationSource {
 }
 
 export interface GenerationMeta {
-  avg_renewable_pct: number     // (Solar + Wind + Hydro) / Total * 100
-  avg_carbon_gco2_kwh: number   // Average carbon intensity
+  avg_renewable_pct: number     // Renewable / generated energy * 100 (storage and imports excluded)
+  avg_carbon_gco2_kwh: number   // Emissions / generated energy
+  pumping_mwh?: number          // Energy used for pumped-storage pumping
   peak_solar_mw: number
   peak_wind_mw: number
+  curtailed_mwh?: number        // ?curtailment=true: curtailed solar + wind energy
+  emission_factor_set?: string  // Factor set behind avg_carbon_gco2_kwh (?factors=)
+  emission_factor_basis?: 'combustion' | 'lifecycle'
+  emission_factor_version?: string
 }
 
 export interface GenerationResponse {
@@ -93,18 +107,29 @@ export function getCarbonLevel(gco2kwh: number): CarbonIntensity {
   }
 }
 
+// Helper: Renewable output (solar, wind, hydro, geothermal, biomass)
+export function renewableMW(point: GenerationPoint): number {
+  return point.solar_mw + point.wind_mw + point.hydro_mw + (point.geothermal_mw ?? 0) + (point.biomass_mw ?? 0)
+}
+
+// Helper: Output of the area's plants (storage and imports excluded)
+export function generatedMW(point: GenerationPoint): number {
+  return renewableMW(point) + point.nuclear_mw + point.lng_mw + point.coal_mw + (point.oil_mw ?? 0) + point.other_mw
+}
+
 // Helper: Calculate renewable percentage for a point
 export function calculateRenewablePct(point: GenerationPoint): number {
-  if (point.total_mw === 0) return 0
-  const renewable = point.solar_mw + point.wind_mw + point.hydro_mw
-  return (renewable / point.total_mw) * 100
+  const generated = generatedMW(point)
+  if (generated === 0) return 0
+  return (renewableMW(point) / generated) * 100
 }
 
 // Helper: Calculate carbon intensity for a point
 export function calculateCarbonIntensity(point: GenerationPoint): number {
-  if (point.total_mw === 0) return 0
-  // Emission factors (gCO2/kWh): LNG 350, Coal 850, Other 500
-  const carbon = (point.lng_mw * 350 + point.coal_mw * 850 + point.other_mw * 500) / point.total_mw
+  const generated = generatedMW(point)
+  if (generated === 0) return 0
+  // Emission factors of the backend's "default" set (gCO2/kWh): LNG 350, Coal 850, Oil 700, Other 500
+  const carbon = (point.lng_mw * 350 + point.coal_mw * 850 + (point.oil_mw ?? 0) * 700 + point.other_mw * 500) / generated
   return carbon
 }
 
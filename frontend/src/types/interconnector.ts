@@ -0,0 +1,49 @@
+// Interconnector types matching backend/internal/interconnector/types.go
+
+export type FlowDirection = 'forward' | 'reverse'
+
+// GET /api/interconnectors
+export interface InterconnectorLine {
+  id: string // e.g. "tohoku-tokyo"
+  name_ja: string // e.g. "東北東京間"
+  name_en: string
+  from: string // Area code at the forward end
+  to: string
+  hvdc: boolean // DC link or frequency converter
+}
+
+export interface FlowPoint {
+  ts: string // Interval start, ISO8601
+  planned_mw?: number // 計画潮流, signed (positive from → to)
+  actual_mw?: number // 潮流実績, absent until published
+  capacity_forward_mw: number
+  capacity_reverse_mw: number
+  direction: FlowDirection
+  margin_mw: number
+  utilization_pct: number
+  congested: boolean // Margin <= 2% of the capacity
+}
+
+export interface LineFlows {
+  line: string
+  name: string
+  from: string
+  to: string
+  series: FlowPoint[]
+  congested_intervals: number
+  max_utilization_pct: number
+}
+
+// GET /api/interconnectors/:date
+export interface InterconnectorResponse {
+  date: string
+  timescale: '30min'
+  lines: LineFlows[]
+  source: {
+    name: string
+    url: string
+  }
+  meta?: {
+    warning?: string
+  }
+}
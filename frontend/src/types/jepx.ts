@@ -1,9 +1,17 @@
 // JEPX (Japan Electric Power Exchange) spot price types
 // Based on backend/internal/jepx/types.go and AGENT_TECH_SPEC §3.3
 
+// Market-wide auction volume of one period (MWh), when the source publishes it
+export interface JEPXVolume {
+  sell_bid_mwh: number
+  buy_bid_mwh: number
+  contracted_mwh: number
+}
+
 export interface PricePoint {
   ts: string // ISO8601 timestamp with Asia/Tokyo offset
   price: number // JPY/kWh
+  volume?: JEPXVolume
 }
 
 export interface JEPXSource {
@@ -27,6 +35,68 @@ export interface JEPXResponse {
   meta?: JEPXMeta
 }
 
+// All-area day-ahead result: system price plus every area price per period
+// Based on backend/internal/jepx/market.go (GET /api/jepx/all/{date})
+export interface JEPXMarketPoint {
+  ts: string // ISO8601 timestamp with Asia/Tokyo offset
+  period?: number // JEPX コマ 1-48 (30min data)
+  system_price: number // JPY/kWh
+  area_prices: Record<string, number> // JPY/kWh by area code
+  volume?: JEPXVolume
+}
+
+export interface JEPXMarketResponse {
+  date: string // YYYY-MM-DD
+  timescale: string // "30min" | "hourly"
+  areas: string[] // Area codes in area_prices
+  series: JEPXMarketPoint[]
+  source: { name: string; url: string }
+  meta?: { warning?: string }
+}
+
+// JEPX liquidity (GET /api/liquidity/{area}/{date} and ?from=&to=)
+// Based on backend/internal/jepx/liquidity.go
+export interface LiquidityPoint extends JEPXVolume {
+  ts: string
+  period?: number
+  price: number // JPY/kWh
+  bid_imbalance: number // (buy − sell) / (buy + sell)
+  sell_filled_pct: number
+  buy_filled_pct: number
+}
+
+export interface LiquidityStats {
+  date?: string
+  periods: number
+  contracted_mwh: number
+  sell_bid_mwh: number
+  buy_bid_mwh: number
+  bid_imbalance: number
+  avg_price: number
+  vwap: number // Volume-weighted average price, JPY/kWh
+  min_contracted?: LiquidityPoint
+  max_contracted?: LiquidityPoint
+}
+
+export interface LiquidityResponse {
+  date: string
+  area: string // Area code or "system"
+  timescale: string
+  periods: LiquidityPoint[]
+  day: LiquidityStats
+  meta?: { warning?: string }
+}
+
+export interface LiquidityRangeResponse {
+  area: string
+  from: string
+  to: string
+  days: LiquidityStats[]
+  total: LiquidityStats
+  gaps: { from: string; to: string; days: number }[]
+  warnings?: string[]
+}
+
 // Helper to extract price values for charting
 export function extractPriceValues(response: JEPXResponse): number[] {
   return response.price_yen_per_kwh.map(p => p.price)
@@ -37,3 +107,102 @@ export function getPriceAtHour(response: JEPXResponse, hour: number): number | n
   const point = response.price_yen_per_kwh[hour]
   return point ? point.price : null
 }
+
+// Market splitting analytics (GET /api/spreads)
+// Based on backend/internal/spread/types.go
+export interface SpreadZone {
+  price: number // JPY/kWh
+  areas: string[]
+}
+
+export interface SpreadAreaStats {
+  area: string
+  split_periods: number
+  split_pct: number
+  avg_spread: number // area − system, JPY/kWh
+  avg_abs_spread: number
+  min_spread: number
+  max_spread: number
+}
+
+export interface SpreadStats {
+  key: string // YYYY-MM-DD, YYYY-MM or from..to
+  periods: number
+  split_periods: number
+  split_pct: number
+  avg_zones: number
+  avg_max_spread: number
+  areas: SpreadAreaStats[]
+}
+
+export interface SpreadPoint {
+  ts: string
+  spread: number // A − B, JPY/kWh
+}
+
+export interface SpreadResponse {
+  from: string
+  to: string
+  timescale: string
+  tolerance: number
+  areas: string[]
+  total: SpreadStats
+  daily: SpreadStats[]
+  monthly: SpreadStats[]
+  pair?: { a: string; b: string; series: SpreadPoint[]; avg: number; std_dev: number; min: SpreadPoint; max: SpreadPoint }
+  periods?: {
+    ts: string
+    period?: number
+    system_price: number
+    split: boolean
+    split_areas?: string[]
+    zones: SpreadZone[]
+    max_spread: number
+  }[]
+  gaps: { from: string; to: string; days: number }[]
+  source: { name: string; url: string }
+}
+
+// JEPX intraday market (GET /api/intraday/{date})
+// Based on backend/internal/jepx/intraday.go
+export interface IntradayPoint {
+  ts: string
+  period: number // 1-48
+  open: number | null // JPY/kWh, null if untraded
+  high: number | null
+  low: number | null
+  close: number | null
+  weighted_avg: number | null
+  volume_mwh: number
+  trades: number
+}
+
+export interface IntradayResponse {
+  date: string
+  timescale: '30min'
+  products: IntradayPoint[]
+  source: JEPXSource
+  meta?: JEPXMeta
+}
+
+// Intraday vs day-ahead (GET /api/intraday/{date}/compare?area=)
+export interface IntradayComparison {
+  date: string
+  area: string // Day-ahead price area, or "system"
+  periods: {
+    ts: string
+    period: number
+    day_ahead_price: number
+    intraday_avg: number | null
+    intraday_close: number | null
+    premium?: number // intraday_avg − day_ahead_price
+    volume_mwh: number
+  }[]
+  meta: {
+    traded_products: number
+    total_volume_mwh: number
+    avg_premium: number
+    volume_weighted_avg: number
+    volume_weighted_da: number
+  }
+}
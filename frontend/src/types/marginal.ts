@@ -0,0 +1,30 @@
+// Marginal emission factor types matching backend/internal/marginal/types.go
+
+import type { EmissionBasis, EmissionFuel } from './emission'
+
+export interface MarginalHour {
+  hour: number // 0-23
+  factor_gco2_kwh?: number // Absent with too few samples
+  lower_gco2_kwh?: number // Confidence bounds
+  upper_gco2_kwh?: number
+  samples: number // Interval pairs used
+  fuel_shares?: Partial<Record<EmissionFuel, number>> // Share of the supply change met per fuel
+}
+
+// GET /api/marginal/{area}/{date}
+export interface MarginalResponse {
+  date: string // Last day of the window
+  area: string
+  timezone: string // Asia/Tokyo
+  window_from: string
+  window_days: number
+  days_used: number // Days with a stored generation mix
+  estimated_days: number // Of those, estimated mixes (no OCCTO actuals)
+  confidence: number // e.g. 0.95
+  emission_factor_set: string
+  emission_factor_basis: EmissionBasis
+  hours: MarginalHour[] // 24 hours of day
+  meta?: {
+    warning?: string
+  }
+}
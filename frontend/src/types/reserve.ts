@@ -9,20 +9,77 @@ export interface Source {
   url: string
 }
 
+export interface ReserveTier {
+  name: string // e.g., "advisory"
+  label?: string // e.g., "需給ひっ迫注意報"
+  min_pct?: number // Inclusive lower bound; absent on the last tier
+  status: ReserveStatus
+}
+
+export interface ReservePolicy {
+  id: string // "default", "jp-alert" or a custom policy
+  name?: string
+  tiers: ReserveTier[] // Loosest first
+}
+
 export interface Meta {
   warning?: string
+  policy?: ReservePolicy
+}
+
+export interface ReservePoint {
+  ts: string // Interval start, ISO8601
+  demand_mw: number
+  capacity_mw: number
+  reserve_margin_pct: number
+  status: ReserveStatus
+  tier?: string
+  block?: number // Wide-area block of the interval (ブロックNo)
+  block_margin_pct?: number // Reserve margin of that block
+}
+
+export interface ReserveMinimum {
+  ts: string
+  hour: number // 0-23
+  reserve_margin_pct: number
+  status: ReserveStatus
+  tier?: string
+  block?: number
+  block_margin_pct?: number
+}
+
+// Areas coupled into one wide-area block (広域ブロック) for an interval
+export interface ReserveBlock {
+  block: number // ブロックNo
+  areas: Area[]
+  demand_mw: number
+  capacity_mw: number
+  reserve_mw: number
+  reserve_margin_pct: number // (capacity - demand) / capacity * 100
+  status: ReserveStatus
+  tier?: string
+}
+
+export interface ReserveBlockInterval {
+  ts: string // Interval start, ISO8601
+  blocks: ReserveBlock[]
 }
 
 export interface AreaReserve {
   area: Area
-  reserve_margin_pct: number
+  reserve_margin_pct: number // Daily average
   status: ReserveStatus
+  tier?: string // Policy tier name
+  minimum?: ReserveMinimum // Tightest interval of the day
+  series?: ReservePoint[] // 30-minute intervals
 }
 
 // Backend JSON response structure
 export interface ReserveResponse {
   date: string // YYYY-MM-DD
+  timescale?: string // "30min" when areas carry a series
   areas: AreaReserve[]
+  blocks?: ReserveBlockInterval[] // Wide-area blocks per interval
   source: Source
   meta?: Meta
 }
@@ -55,3 +112,37 @@ export const STATUS_CONFIGS: Record<ReserveStatus, StatusConfig> = {
     icon: '⚡'
   }
 }
+
+export type ReserveHorizon = 'next_day' | 'weekly'
+
+// GET /api/reserve/:date/forecast?horizon=
+export interface ReserveForecast extends ReserveResponse {
+  horizon: ReserveHorizon
+  issued_at?: string // Publication time, ISO8601
+}
+
+export interface ReserveComparisonPoint {
+  ts: string
+  forecast_pct: number
+  actual_pct: number
+  error_pct: number // Actual - forecast
+}
+
+export interface AreaReserveComparison {
+  area: Area
+  forecast: ReserveMinimum
+  actual: ReserveMinimum
+  error_pct: number // Actual - forecast minimum margin (percentage points)
+  mae_pct?: number // Next-day only
+  series?: ReserveComparisonPoint[]
+}
+
+// GET /api/reserve/:date/compare?horizon=
+export interface ReserveForecastComparison {
+  date: string
+  horizon: ReserveHorizon
+  issued_at?: string
+  areas: AreaReserveComparison[]
+  sources: Source[]
+  meta?: Meta
+}
@@ -11,10 +11,14 @@ export interface PricesRequest {
   date: string // YYYY-MM-DD
 }
 
+export type SettlementMode = 'spot' | 'imbalance'
+
 export interface SettlementRequest {
-  profile: ProfilePoint[] // Hourly consumption profile
-  prices: PricesRequest // JEPX price reference
+  profile: ProfilePoint[] // Hourly consumption profile (actual consumption in imbalance mode)
+  prices: PricesRequest // JEPX (or imbalance) price reference
   pv_offset_pct: number // PV offset percentage (0.0-1.0)
+  mode?: SettlementMode // Default "spot"
+  planned?: ProfilePoint[] // Nominated plan, imbalance mode only (30-min)
 }
 
 export interface Period {
@@ -34,9 +38,29 @@ export interface HourlyBreakdown {
   cost: number // Cost in JPY (kwh × price × (1 - pv%))
 }
 
+export interface ImbalanceBreakdown {
+  ts: string
+  planned_kwh: number
+  actual_kwh: number
+  imbalance_kwh: number // actual − planned: > 0 shortage, < 0 surplus
+  price: number // Shortage or surplus unit price applied, JPY/kWh
+  cost: number // JPY, negative is a credit
+}
+
+export interface ImbalanceTotals {
+  planned_kwh: number
+  actual_kwh: number
+  shortage_kwh: number
+  surplus_kwh: number
+  net_kwh: number
+  shortage_yen: number
+  surplus_yen: number
+}
+
 export interface Assumptions {
   pv_offset_pct: number // PV offset percentage
   area: string // Price area
+  mode?: SettlementMode
 }
 
 export interface SettlementSource {
@@ -47,7 +71,9 @@ export interface SettlementSource {
 export interface SettlementResponse {
   period: Period // Time range
   totals: Totals // Aggregated results
-  by_hour: HourlyBreakdown[] // Per-hour breakdown
+  by_hour: HourlyBreakdown[] // Per-hour breakdown (spot mode)
+  by_period?: ImbalanceBreakdown[] // Per-koma breakdown (imbalance mode)
+  imbalance_totals?: ImbalanceTotals
   assumptions: Assumptions // Calculation parameters
   source_prices: SettlementSource // Price data attribution
 }